@@ -0,0 +1,472 @@
+// Package e2e builds the goco binary and runs it against temporary git
+// repositories, with a fake provider standing in for the Groq API, to check
+// what users and scripts see: output, commits, and exit codes.
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Exit codes from main.go.
+const (
+	exitOK          = 0
+	exitFailure     = 1
+	exitUsage       = 2
+	exitInterrupted = 130
+)
+
+var (
+	buildOnce sync.Once
+	binPath   string
+	buildErr  error
+)
+
+// binary builds goco once per test run and returns its path.
+func binary(t *testing.T) string {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("builds the goco binary")
+	}
+	buildOnce.Do(func() {
+		dir, err := os.MkdirTemp("", "goco-e2e-")
+		if err != nil {
+			buildErr = err
+			return
+		}
+		name := "goco"
+		if runtime.GOOS == "windows" {
+			name += ".exe"
+		}
+		binPath = filepath.Join(dir, name)
+		cmd := exec.Command("go", "build", "-tags", "e2e", "-o", binPath, "..")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			buildErr = errors.New(string(out))
+		}
+	})
+	if buildErr != nil {
+		t.Fatalf("build goco: %v", buildErr)
+	}
+	return binPath
+}
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if binPath != "" {
+		os.RemoveAll(filepath.Dir(binPath))
+	}
+	os.Exit(code)
+}
+
+// fakeProvider serves the parts of the Groq API goco uses.
+type fakeProvider struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	reply   string
+	status  int
+	block   chan struct{}
+	prompts []string
+}
+
+func newFakeProvider(t *testing.T, reply string) *fakeProvider {
+	f := &fakeProvider{reply: reply, status: http.StatusOK}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /models", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{"object": "list", "data": []map[string]string{{"id": "llama-3.3-70b-versatile"}}})
+	})
+	mux.HandleFunc("POST /chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Messages []struct{ Content string } `json:"messages"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		f.mu.Lock()
+		for _, m := range req.Messages {
+			f.prompts = append(f.prompts, m.Content)
+		}
+		reply, status, block := f.reply, f.status, f.block
+		f.mu.Unlock()
+
+		if block != nil {
+			select {
+			case <-block:
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if status != http.StatusOK {
+			writeJSON(w, status, map[string]any{"error": map[string]string{"type": "invalid_request_error", "message": "fake failure"}})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"object":  "chat.completion",
+			"choices": []map[string]any{{"index": 0, "message": map[string]string{"role": "assistant", "content": reply}, "finish_reason": "stop"}},
+		})
+	})
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(func() {
+		f.mu.Lock()
+		if f.block != nil {
+			close(f.block)
+			f.block = nil
+		}
+		f.mu.Unlock()
+		f.Close()
+	})
+	return f
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// env is an isolated environment: goco's config, state, and cache live
+// under a temp home, and the Groq provider talks to the fake.
+type env struct {
+	t    *testing.T
+	bin  string
+	home string
+	repo string
+	vars []string
+}
+
+func newEnv(t *testing.T, provider *fakeProvider) *env {
+	e := &env{t: t, bin: binary(t), home: t.TempDir()}
+	// A space in the path catches quoting mistakes on every platform.
+	e.repo = filepath.Join(t.TempDir(), "my repo")
+	if err := os.Mkdir(e.repo, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, kv := range os.Environ() {
+		key, _, _ := strings.Cut(kv, "=")
+		if strings.HasPrefix(key, "GOCO_") || strings.HasPrefix(key, "XDG_") || strings.HasPrefix(key, "GIT_") {
+			continue
+		}
+		e.vars = append(e.vars, kv)
+	}
+	e.vars = append(e.vars,
+		"HOME="+e.home,
+		"USERPROFILE="+e.home,
+		"APPDATA="+filepath.Join(e.home, "AppData", "Roaming"),
+		"LOCALAPPDATA="+filepath.Join(e.home, "AppData", "Local"),
+		"XDG_CONFIG_HOME="+filepath.Join(e.home, ".config"),
+		"XDG_STATE_HOME="+filepath.Join(e.home, ".local", "state"),
+		"XDG_CACHE_HOME="+filepath.Join(e.home, ".cache"),
+		"XDG_RUNTIME_DIR="+filepath.Join(e.home, "run"),
+		"GIT_CONFIG_NOSYSTEM=1",
+		"GIT_AUTHOR_NAME=goco",
+		"GIT_AUTHOR_EMAIL=goco@example.com",
+		"GIT_COMMITTER_NAME=goco",
+		"GIT_COMMITTER_EMAIL=goco@example.com",
+		"NO_COLOR=1",
+		"GOCO_GROQ_KEY=test-key",
+	)
+	if provider != nil {
+		e.vars = append(e.vars, "GOCO_E2E_GROQ_BASE_URL="+provider.URL)
+	}
+
+	e.git("init", "-q")
+	e.write("main.go", "package main\n")
+	e.git("add", ".")
+	e.git("commit", "-qm", "chore: initial commit")
+	return e
+}
+
+func (e *env) write(name, content string) {
+	e.t.Helper()
+	if err := os.WriteFile(filepath.Join(e.repo, name), []byte(content), 0o644); err != nil {
+		e.t.Fatal(err)
+	}
+}
+
+func (e *env) git(args ...string) string {
+	e.t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = e.repo
+	cmd.Env = e.vars
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		e.t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// command returns goco with args, run from the repository.
+func (e *env) command(args ...string) (*exec.Cmd, *bytes.Buffer, *bytes.Buffer) {
+	cmd := exec.Command(e.bin, args...)
+	cmd.Dir = e.repo
+	cmd.Env = e.vars
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	return cmd, &stdout, &stderr
+}
+
+type result struct {
+	code           int
+	stdout, stderr string
+}
+
+func (e *env) run(args ...string) result {
+	e.t.Helper()
+	cmd, stdout, stderr := e.command(args...)
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		e.t.Fatalf("run goco %v: %v", args, err)
+	}
+	return result{code: cmd.ProcessState.ExitCode(), stdout: stdout.String(), stderr: stderr.String()}
+}
+
+func TestGenerate(t *testing.T) {
+	const message = "feat: add greeting"
+
+	tests := []struct {
+		name     string
+		args     []string
+		setup    func(e *env, f *fakeProvider)
+		wantCode int
+		// wantOut is expected in stdout or stderr.
+		wantOut string
+		// wantLog is the subject of HEAD afterwards.
+		wantLog string
+	}{
+		{
+			name:     "commits the generated message",
+			args:     []string{"generate", "--provider", "groq", "--yes"},
+			wantCode: exitOK,
+			wantLog:  message,
+		},
+		{
+			name:     "print only",
+			args:     []string{"generate", "--provider", "groq", "--print"},
+			wantCode: exitOK,
+			wantOut:  message,
+			wantLog:  "chore: initial commit",
+		},
+		{
+			name:     "repository flag",
+			args:     []string{"generate", "--provider", "groq", "--yes", "-C", "."},
+			wantCode: exitOK,
+			wantLog:  message,
+		},
+		{
+			name: "invalid message",
+			args: []string{"generate", "--provider", "groq", "--yes"},
+			setup: func(_ *env, f *fakeProvider) {
+				f.reply = "Added a greeting"
+			},
+			wantCode: exitFailure,
+			wantOut:  "Conventional Commit",
+			wantLog:  "chore: initial commit",
+		},
+		{
+			name: "provider error",
+			args: []string{"generate", "--provider", "groq", "--yes"},
+			setup: func(_ *env, f *fakeProvider) {
+				f.status = http.StatusUnauthorized
+			},
+			wantCode: exitFailure,
+			wantOut:  "fake failure",
+			wantLog:  "chore: initial commit",
+		},
+		{
+			name: "no changes",
+			args: []string{"generate", "--provider", "groq", "--yes"},
+			setup: func(e *env, _ *fakeProvider) {
+				e.git("checkout", "--", ".")
+			},
+			wantCode: exitFailure,
+			wantLog:  "chore: initial commit",
+		},
+		{
+			name:     "offline",
+			args:     []string{"generate", "--provider", "groq", "--yes", "--offline"},
+			wantCode: exitFailure,
+			wantOut:  "--offline",
+			wantLog:  "chore: initial commit",
+		},
+		{
+			name:     "missing repository directory",
+			args:     []string{"generate", "--yes", "-C", filepath.Join("no", "such", "dir")},
+			wantCode: exitFailure,
+			wantOut:  "not a directory",
+			wantLog:  "chore: initial commit",
+		},
+		{
+			name:     "unknown flag",
+			args:     []string{"generate", "--no-such-flag"},
+			wantCode: exitUsage,
+			wantLog:  "chore: initial commit",
+		},
+		{
+			name:     "unknown command",
+			args:     []string{"no-such-command"},
+			wantCode: exitUsage,
+			wantLog:  "chore: initial commit",
+		},
+		{
+			name:     "unexpected argument",
+			args:     []string{"generate", "extra"},
+			wantCode: exitUsage,
+			wantLog:  "chore: initial commit",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeProvider(t, message)
+			e := newEnv(t, f)
+			e.write("main.go", "package main\n\nfunc greet() string { return \"hi\" }\n")
+			if tt.setup != nil {
+				tt.setup(e, f)
+			}
+
+			r := e.run(tt.args...)
+			if r.code != tt.wantCode {
+				t.Fatalf("exit code = %d, want %d\nstdout:\n%s\nstderr:\n%s", r.code, tt.wantCode, r.stdout, r.stderr)
+			}
+			if !strings.Contains(r.stdout+r.stderr, tt.wantOut) {
+				t.Errorf("output does not contain %q\nstdout:\n%s\nstderr:\n%s", tt.wantOut, r.stdout, r.stderr)
+			}
+			if got := e.git("log", "-1", "--format=%s"); got != tt.wantLog {
+				t.Errorf("HEAD subject = %q, want %q", got, tt.wantLog)
+			}
+		})
+	}
+}
+
+func TestGenerateSendsDiff(t *testing.T) {
+	f := newFakeProvider(t, "feat: add greeting")
+	e := newEnv(t, f)
+	e.write("main.go", "package main\n\nfunc greet() string { return \"hi\" }\n")
+
+	if r := e.run("generate", "--provider", "groq", "--print"); r.code != exitOK {
+		t.Fatalf("exit code = %d\n%s", r.code, r.stderr)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.prompts) == 0 || !strings.Contains(strings.Join(f.prompts, "\n"), "func greet()") {
+		t.Fatalf("the prompt did not include the diff: %q", f.prompts)
+	}
+}
+
+func TestInterrupt(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("os.Interrupt cannot be sent to a process on Windows")
+	}
+	f := newFakeProvider(t, "feat: add greeting")
+	f.block = make(chan struct{})
+	e := newEnv(t, f)
+	e.write("main.go", "package main\n\nfunc greet() string { return \"hi\" }\n")
+
+	cmd, _, stderr := e.command("generate", "--provider", "groq", "--yes")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	// Interrupt once the request is waiting on the provider.
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		f.mu.Lock()
+		sent := len(f.prompts) > 0
+		f.mu.Unlock()
+		if sent {
+			break
+		}
+		if time.Now().After(deadline) {
+			_ = cmd.Process.Kill()
+			t.Fatalf("goco never called the provider\n%s", stderr)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err := cmd.Process.Signal(os.Interrupt); err != nil {
+		t.Fatal(err)
+	}
+	_ = cmd.Wait()
+	if code := cmd.ProcessState.ExitCode(); code != exitInterrupted {
+		t.Fatalf("exit code = %d, want %d\n%s", code, exitInterrupted, stderr)
+	}
+	if got := e.git("log", "-1", "--format=%s"); got != "chore: initial commit" {
+		t.Errorf("HEAD subject = %q, want no new commit", got)
+	}
+}
+
+func TestDefaultCommandAndAlias(t *testing.T) {
+	f := newFakeProvider(t, "feat: add greeting")
+	e := newEnv(t, f)
+	e.write("main.go", "package main\n\nfunc greet() string { return \"hi\" }\n")
+	configDir := filepath.Join(e.home, ".config", "goco")
+	if err := os.MkdirAll(configDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	config := "[General]\ndefault_command = \"quick\"\n\n[alias]\nquick = \"generate --provider groq\"\nloop = \"loop\"\n"
+	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if r := e.run("loop"); r.code != exitUsage || !strings.Contains(r.stderr, "alias loop") {
+		t.Fatalf("loop: exit code = %d, want %d\n%s", r.code, exitUsage, r.stderr)
+	}
+	// Bare goco runs the default command, an alias, with the flags given.
+	if r := e.run("--yes"); r.code != exitOK {
+		t.Fatalf("goco --yes: exit code = %d, want %d\n%s", r.code, exitOK, r.stderr)
+	}
+	if got := e.git("log", "-1", "--format=%s"); got != "feat: add greeting" {
+		t.Errorf("HEAD subject = %q, want the generated message", got)
+	}
+}
+
+func TestJSONErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		status       int
+		wantCode     int
+		wantCategory string
+	}{
+		{name: "provider error", args: []string{"generate", "--provider", "groq", "--yes", "--output", "json"}, status: http.StatusUnauthorized, wantCode: exitFailure, wantCategory: "provider"},
+		{name: "unknown flag", args: []string{"--output=json", "generate", "--no-such-flag"}, wantCode: exitUsage, wantCategory: "usage"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeProvider(t, "feat: add greeting")
+			if tt.status != 0 {
+				f.status = tt.status
+			}
+			e := newEnv(t, f)
+			e.write("main.go", "package main\n\nfunc greet() string { return \"hi\" }\n")
+
+			r := e.run(tt.args...)
+			if r.code != tt.wantCode {
+				t.Fatalf("exit code = %d, want %d\n%s", r.code, tt.wantCode, r.stderr)
+			}
+			var report struct {
+				Code     int    `json:"code"`
+				Category string `json:"category"`
+				Message  string `json:"message"`
+				Hint     string `json:"hint"`
+			}
+			// Progress and warnings may come first; the error is the last line.
+			lines := strings.Split(strings.TrimSpace(r.stderr), "\n")
+			if err := json.Unmarshal([]byte(lines[len(lines)-1]), &report); err != nil {
+				t.Fatalf("stderr is not a JSON error: %v\n%s", err, r.stderr)
+			}
+			if report.Code != tt.wantCode || report.Category != tt.wantCategory || report.Message == "" || report.Hint == "" {
+				t.Errorf("report = %+v", report)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// awsCredentials sign requests to AWS.
+type awsCredentials struct {
+	AccessKeyID     string `json:"AccessKeyId"`
+	SecretAccessKey string `json:"SecretAccessKey"`
+	SessionToken    string `json:"SessionToken"`
+}
+
+// errNoAWSCredentials is returned when no link of the credential chain has
+// credentials.
+var errNoAWSCredentials = errors.New("no AWS credentials found; set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY, add the profile to ~/.aws/credentials, or sign in with the AWS CLI")
+
+// loadAWSCredentials follows the AWS SDK credential chain: the environment,
+// unless a profile is named, then the shared credentials file, then the AWS
+// CLI, which resolves SSO, assumed roles, credential_process, and instance
+// roles as the SDK would.
+func loadAWSCredentials(ctx context.Context, profile string) (awsCredentials, error) {
+	if profile == "" {
+		c := awsCredentials{
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		}
+		if c.AccessKeyID != "" && c.SecretAccessKey != "" {
+			return c, nil
+		}
+	}
+	name := awsProfile(profile)
+
+	if section, err := readINISection(awsFile("AWS_SHARED_CREDENTIALS_FILE", "credentials"), name); err == nil {
+		c := awsCredentials{
+			AccessKeyID:     section["aws_access_key_id"],
+			SecretAccessKey: section["aws_secret_access_key"],
+			SessionToken:    section["aws_session_token"],
+		}
+		if c.AccessKeyID != "" && c.SecretAccessKey != "" {
+			return c, nil
+		}
+	}
+
+	return awsCLICredentials(ctx, name)
+}
+
+// awsCLICredentials asks the AWS CLI for the profile's credentials.
+func awsCLICredentials(ctx context.Context, profile string) (awsCredentials, error) {
+	if _, err := exec.LookPath("aws"); err != nil {
+		return awsCredentials{}, errNoAWSCredentials
+	}
+	cmd := exec.CommandContext(ctx, "aws", "configure", "export-credentials", "--format", "process", "--profile", profile)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return awsCredentials{}, fmt.Errorf("%w (aws configure export-credentials: %s)", errNoAWSCredentials, strings.TrimSpace(stderr.String()))
+	}
+	var c awsCredentials
+	if err := json.Unmarshal(out, &c); err != nil || c.AccessKeyID == "" {
+		return awsCredentials{}, fmt.Errorf("read AWS CLI credentials: unexpected output")
+	}
+	return c, nil
+}
+
+// awsRegion returns region, or the region the environment or the profile's
+// shared config names, or us-east-1.
+func awsRegion(region, profile string) string {
+	if region != "" {
+		return region
+	}
+	for _, env := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+		if r := os.Getenv(env); r != "" {
+			return r
+		}
+	}
+	name := awsProfile(profile)
+	if name != "default" {
+		name = "profile " + name
+	}
+	if section, err := readINISection(awsFile("AWS_CONFIG_FILE", "config"), name); err == nil && section["region"] != "" {
+		return section["region"]
+	}
+	return "us-east-1"
+}
+
+// awsProfile returns profile, or AWS_PROFILE, or "default".
+func awsProfile(profile string) string {
+	if profile != "" {
+		return profile
+	}
+	if p := os.Getenv("AWS_PROFILE"); p != "" {
+		return p
+	}
+	return "default"
+}
+
+// awsFile returns the path env names, or ~/.aws/name.
+func awsFile(env, name string) string {
+	if path := os.Getenv(env); path != "" {
+		return path
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".aws", name)
+}
+
+// readINISection reads the keys of one [section] of an AWS shared config or
+// credentials file.
+func readINISection(path, section string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var keys map[string]string
+	inSection := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			inSection = strings.TrimSpace(line[1:len(line)-1]) == section
+			if inSection && keys == nil {
+				keys = make(map[string]string)
+			}
+		case inSection:
+			if key, value, ok := strings.Cut(line, "="); ok {
+				keys[strings.TrimSpace(key)] = strings.TrimSpace(value)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if keys == nil {
+		return nil, fmt.Errorf("%s has no [%s] section", path, section)
+	}
+	return keys, nil
+}
+
+// signV4 signs req, whose body is body, for service in region with AWS
+// Signature Version 4.
+func signV4(req *http.Request, body []byte, creds awsCredentials, region, service string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	signed := []string{"host", "x-amz-date"}
+	headers := "host:" + host + "\nx-amz-date:" + amzDate + "\n"
+	if creds.SessionToken != "" {
+		signed = append(signed, "x-amz-security-token")
+		headers += "x-amz-security-token:" + creds.SessionToken + "\n"
+	}
+	signedHeaders := strings.Join(signed, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	payload := sha256.Sum256(body)
+	canonical := strings.Join([]string{
+		req.Method,
+		// Services other than S3 sign the already escaped path escaped again.
+		awsEscape(path, true),
+		canonicalQuery(req.URL.Query()),
+		headers,
+		signedHeaders,
+		hex.EncodeToString(payload[:]),
+	}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	hashed := sha256.Sum256([]byte(canonical))
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hashed[:])
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), date)
+	for _, part := range []string{region, service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, toSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", creds.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// canonicalQuery sorts and escapes query as Signature Version 4 expects.
+func canonicalQuery(query url.Values) string {
+	var pairs []string
+	for key, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, awsEscape(key, false)+"="+awsEscape(value, false))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// awsEscape percent-encodes every byte but the unreserved characters, and
+// slashes when keepSlash is set.
+func awsEscape(s string, keepSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && keepSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
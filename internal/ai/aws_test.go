@@ -0,0 +1,106 @@
+package ai
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSignV4(t *testing.T) {
+	// Vectors from the AWS Signature Version 4 test suite.
+	creds := awsCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+	tests := []struct {
+		url           string
+		wantSignature string
+	}{
+		{url: "https://example.amazonaws.com/", wantSignature: "5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"},
+		{url: "https://example.amazonaws.com/?Param2=value2&Param1=value1", wantSignature: "b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, tt.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			signV4(req, nil, creds, "us-east-1", "service", now)
+
+			want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=" + tt.wantSignature
+			if got := req.Header.Get("Authorization"); got != want {
+				t.Errorf("Authorization = %q, want %q", got, want)
+			}
+			if got := req.Header.Get("X-Amz-Date"); got != "20150830T123600Z" {
+				t.Errorf("X-Amz-Date = %q", got)
+			}
+		})
+	}
+}
+
+func TestLoadAWSCredentials(t *testing.T) {
+	dir := t.TempDir()
+	credentials := filepath.Join(dir, "credentials")
+	if err := os.WriteFile(credentials, []byte("[default]\naws_access_key_id = AKIDDEFAULT\naws_secret_access_key = default-secret\n\n[work]\naws_access_key_id=AKIDWORK\naws_secret_access_key=work-secret\naws_session_token=work-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", credentials)
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDENV")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "env-secret")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+
+	tests := []struct {
+		name    string
+		profile string
+		noEnv   bool
+		want    awsCredentials
+	}{
+		{name: "environment", want: awsCredentials{AccessKeyID: "AKIDENV", SecretAccessKey: "env-secret"}},
+		{name: "default profile", noEnv: true, want: awsCredentials{AccessKeyID: "AKIDDEFAULT", SecretAccessKey: "default-secret"}},
+		{name: "named profile over the environment", profile: "work", want: awsCredentials{AccessKeyID: "AKIDWORK", SecretAccessKey: "work-secret", SessionToken: "work-token"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.noEnv {
+				t.Setenv("AWS_ACCESS_KEY_ID", "")
+			}
+			got, err := loadAWSCredentials(context.Background(), tt.profile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("loadAWSCredentials(%q) = %+v, want %+v", tt.profile, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAWSRegion(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(config, []byte("[default]\nregion = eu-west-1\n\n[profile work]\nregion = ap-south-1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("AWS_CONFIG_FILE", config)
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+
+	if got := awsRegion("us-west-2", "work"); got != "us-west-2" {
+		t.Errorf("a configured region = %q", got)
+	}
+	if got := awsRegion("", ""); got != "eu-west-1" {
+		t.Errorf("the default profile's region = %q", got)
+	}
+	if got := awsRegion("", "work"); got != "ap-south-1" {
+		t.Errorf("a named profile's region = %q", got)
+	}
+	if got := awsRegion("", "missing"); got != "us-east-1" {
+		t.Errorf("the fallback region = %q", got)
+	}
+	t.Setenv("AWS_REGION", "ca-central-1")
+	if got := awsRegion("", "work"); got != "ca-central-1" {
+		t.Errorf("AWS_REGION = %q", got)
+	}
+}
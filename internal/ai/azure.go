@@ -0,0 +1,130 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+const (
+	// DefaultAzureAPIVersion is the api-version requests carry unless the
+	// settings name another.
+	DefaultAzureAPIVersion = "2024-10-21"
+	// azureListAPIVersion is the newest api-version that still lists a
+	// resource's deployments.
+	azureListAPIVersion = "2022-12-01"
+)
+
+// AzureSettings locate an Azure OpenAI resource. Azure serves models
+// through deployments the resource's owner names, so the model a provider
+// is asked for is a deployment name, or a name Deployments maps to one.
+type AzureSettings struct {
+	// Endpoint is the resource URL, e.g. https://my-resource.openai.azure.com.
+	Endpoint   string `json:"endpoint,omitempty"`
+	APIVersion string `json:"api_version,omitempty"`
+	// Deployment is used when no model is given.
+	Deployment  string            `json:"deployment,omitempty"`
+	Deployments map[string]string `json:"deployments,omitempty"`
+}
+
+// deployment returns the deployment that serves model.
+func (s AzureSettings) deployment(model string) string {
+	if d := s.Deployments[model]; d != "" {
+		return d
+	}
+	return model
+}
+
+// AzureProvider sends requests to deployments of an Azure OpenAI resource.
+type AzureProvider struct {
+	client   *chatClient
+	settings AzureSettings
+	model    string
+}
+
+func NewAzureProvider(_ context.Context, apiKey, model string, settings AzureSettings, retry RetryPolicy) (*AzureProvider, error) {
+	if settings.Endpoint == "" {
+		return nil, errors.New("no Azure OpenAI endpoint; set endpoint in the [Azure] config table")
+	}
+	model = withDefault(model, settings.Deployment)
+	if model == "" {
+		return nil, errors.New("no Azure OpenAI deployment; pass --model or set deployment in the [Azure] config table")
+	}
+
+	version := withDefault(settings.APIVersion, DefaultAzureAPIVersion)
+	return &AzureProvider{
+		client: &chatClient{
+			http:    httpClient(retry),
+			baseURL: strings.TrimSuffix(settings.Endpoint, "/") + "/openai",
+			label:   "Azure OpenAI",
+			// Azure takes its key in api-key rather than as a bearer token.
+			header:      http.Header{"Api-Key": {apiKey}},
+			completions: "/deployments/" + url.PathEscape(settings.deployment(model)) + "/chat/completions?api-version=" + url.QueryEscape(version),
+		},
+		settings: settings,
+		model:    model,
+	}, nil
+}
+
+func (a *AzureProvider) Name() string {
+	return ProviderAzure
+}
+
+func (a *AzureProvider) DefaultModel() string {
+	return a.settings.Deployment
+}
+
+func (a *AzureProvider) GenerateCommitMessage(ctx context.Context, input PromptInput) (string, error) {
+	prompt, err := BuildPrompt(input)
+	if err != nil {
+		return "", err
+	}
+	return a.client.complete(ctx, a.model, prompt)
+}
+
+// ListModels lists the names of the resource's deployments.
+func (a *AzureProvider) ListModels(ctx context.Context) ([]string, error) {
+	var resp struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := a.client.do(ctx, http.MethodGet, "/deployments?api-version="+azureListAPIVersion, nil, &resp); err != nil {
+		return nil, fmt.Errorf("list Azure OpenAI deployments: %w", err)
+	}
+	deployments := make([]string, 0, len(resp.Data))
+	for _, d := range resp.Data {
+		if d.ID != "" {
+			deployments = append(deployments, d.ID)
+		}
+	}
+	return deployments, nil
+}
+
+// ValidateModel checks that model names a deployment. The deployments the
+// settings configure are trusted as they are. Others are looked up on the
+// resource, but only the deprecated azureListAPIVersion lists them, and
+// resources that no longer answer it cannot be checked; then model is
+// allowed, and a deployment that does not exist fails the first request.
+func (a *AzureProvider) ValidateModel(ctx context.Context, model string) error {
+	deployment := a.settings.deployment(model)
+	if deployment == a.settings.Deployment || slices.Contains(slices.Collect(maps.Values(a.settings.Deployments)), deployment) {
+		return nil
+	}
+
+	deployments, err := a.ListModels(ctx)
+	if err != nil {
+		return ctx.Err()
+	}
+
+	if !slices.Contains(deployments, deployment) {
+		return fmt.Errorf("deployment %q does not exist on the Azure OpenAI resource %s", deployment, a.settings.Endpoint)
+	}
+
+	return nil
+}
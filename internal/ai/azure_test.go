@@ -0,0 +1,105 @@
+package ai
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func newTestAzure(t *testing.T, model string, handler http.HandlerFunc) *AzureProvider {
+	t.Helper()
+	srv := newTestServer(t, handler)
+	settings := AzureSettings{
+		Endpoint:    srv.URL + "/",
+		Deployment:  "prod-mini",
+		Deployments: map[string]string{"gpt-4o": "prod-gpt4o"},
+	}
+	provider, err := NewAzureProvider(context.Background(), "az-key", model, settings, RetryPolicy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return provider
+}
+
+func TestAzureGenerateCommitMessage(t *testing.T) {
+	tests := []struct {
+		model    string
+		wantPath string
+	}{
+		{model: "", wantPath: "/openai/deployments/prod-mini/chat/completions"},
+		{model: "gpt-4o", wantPath: "/openai/deployments/prod-gpt4o/chat/completions"},
+		{model: "staging", wantPath: "/openai/deployments/staging/chat/completions"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.wantPath, func(t *testing.T) {
+			provider := newTestAzure(t, tt.model, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != tt.wantPath || r.URL.Query().Get("api-version") != DefaultAzureAPIVersion {
+					t.Errorf("unexpected request %s", r.URL)
+				}
+				if r.Header.Get("Api-Key") != "az-key" || r.Header.Get("Authorization") != "" {
+					t.Errorf("expected the key in api-key, got %v", r.Header)
+				}
+				w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "chore: bump x"}}]}`))
+			})
+
+			msg, err := provider.GenerateCommitMessage(context.Background(), PromptInput{Diff: "diff"})
+			if err != nil || msg != "chore: bump x" {
+				t.Fatalf("GenerateCommitMessage() = %q, %v", msg, err)
+			}
+		})
+	}
+}
+
+func TestAzureValidateModel(t *testing.T) {
+	listed := `{"data": [{"id": "prod-mini", "model": "gpt-4o-mini"}, {"id": "prod-other", "model": "gpt-4.1"}]}`
+	tests := []struct {
+		name     string
+		model    string
+		status   int
+		wantList bool
+		wantErr  string
+	}{
+		{name: "configured deployment", model: "prod-mini"},
+		{name: "mapped model", model: "gpt-4o"},
+		{name: "listed deployment", model: "prod-other", status: http.StatusOK, wantList: true},
+		{name: "missing deployment", model: "gpt-5", status: http.StatusOK, wantList: true, wantErr: `deployment "gpt-5" does not exist`},
+		{name: "listing retired", model: "gpt-5", status: http.StatusNotFound, wantList: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var listedCount int
+			provider := newTestAzure(t, "", func(w http.ResponseWriter, r *http.Request) {
+				listedCount++
+				if r.URL.Path != "/openai/deployments" || r.URL.Query().Get("api-version") != azureListAPIVersion {
+					t.Errorf("unexpected request %s", r.URL)
+				}
+				w.WriteHeader(tt.status)
+				if tt.status == http.StatusOK {
+					w.Write([]byte(listed))
+				}
+			})
+
+			err := provider.ValidateModel(context.Background(), tt.model)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("ValidateModel(%q) = %v", tt.model, err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("ValidateModel(%q) = %v, want %s", tt.model, err, tt.wantErr)
+			}
+			if (listedCount > 0) != tt.wantList {
+				t.Errorf("listed the deployments %d times, want listing %v", listedCount, tt.wantList)
+			}
+		})
+	}
+}
+
+func TestNewAzureProviderNeedsSettings(t *testing.T) {
+	if _, err := NewAzureProvider(context.Background(), "key", "prod", AzureSettings{}, RetryPolicy{}); err == nil {
+		t.Error("expected an error without an endpoint")
+	}
+	settings := AzureSettings{Endpoint: "https://example.openai.azure.com"}
+	if _, err := NewAzureProvider(context.Background(), "key", "", settings, RetryPolicy{}); err == nil {
+		t.Error("expected an error without a deployment")
+	}
+}
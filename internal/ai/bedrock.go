@@ -0,0 +1,149 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// BedrockSettings choose the AWS region and credentials profile of the
+// Bedrock provider; empty fields fall back as the AWS CLI does.
+type BedrockSettings struct {
+	Region  string `json:"region,omitempty"`
+	Profile string `json:"profile,omitempty"`
+}
+
+// BedrockProvider runs Claude, Llama, and the other text models AWS
+// Bedrock hosts through its Converse API. It signs requests with the AWS
+// credential chain instead of an API key.
+type BedrockProvider struct {
+	// runtime serves generation and control lists models; they are
+	// separate Bedrock endpoints.
+	runtime *chatClient
+	control *chatClient
+	model   string
+}
+
+func NewBedrockProvider(_ context.Context, model string, settings BedrockSettings, retry RetryPolicy) (*BedrockProvider, error) {
+	region := awsRegion(settings.Region, settings.Profile)
+	sign := bedrockSigner(settings.Profile, region)
+	return &BedrockProvider{
+		runtime: &chatClient{http: httpClient(retry), baseURL: bedrockRuntimeURL(region), label: "Bedrock", sign: sign},
+		control: &chatClient{http: httpClient(retry), baseURL: "https://bedrock." + region + ".amazonaws.com", label: "Bedrock", sign: sign},
+		model:   model,
+	}, nil
+}
+
+// bedrockSigner signs each request with credentials loaded for it, so a
+// long-lived provider picks up rotated keys and renewed session tokens.
+func bedrockSigner(profile, region string) func(*http.Request, []byte) error {
+	return func(req *http.Request, body []byte) error {
+		creds, err := loadAWSCredentials(req.Context(), profile)
+		if err != nil {
+			return err
+		}
+		signV4(req, body, creds, region, "bedrock", time.Now())
+		return nil
+	}
+}
+
+func bedrockRuntimeURL(region string) string {
+	return "https://bedrock-runtime." + region + ".amazonaws.com"
+}
+
+func (b *BedrockProvider) Name() string {
+	return ProviderBedrock
+}
+
+func (b *BedrockProvider) DefaultModel() string {
+	return DefaultBedrockModel
+}
+
+type converseMessage struct {
+	Role    string            `json:"role"`
+	Content []converseContent `json:"content"`
+}
+
+type converseContent struct {
+	Text string `json:"text"`
+}
+
+type converseResponse struct {
+	Output struct {
+		Message converseMessage `json:"message"`
+	} `json:"output"`
+	Usage struct {
+		InputTokens  int `json:"inputTokens"`
+		OutputTokens int `json:"outputTokens"`
+	} `json:"usage"`
+}
+
+func (b *BedrockProvider) GenerateCommitMessage(ctx context.Context, input PromptInput) (string, error) {
+	prompt, err := BuildPrompt(input)
+	if err != nil {
+		return "", err
+	}
+
+	message := converseMessage{Role: "user", Content: []converseContent{{Text: prompt}}}
+	body, err := json.Marshal(map[string]any{"messages": []converseMessage{message}})
+	if err != nil {
+		return "", err
+	}
+
+	// Model IDs such as "anthropic.claude-3-haiku-20240307-v1:0" are one
+	// path segment; the colon must arrive escaped for the signature to match.
+	path := "/model/" + awsEscape(b.model, false) + "/converse"
+	var resp converseResponse
+	if err := b.runtime.do(ctx, http.MethodPost, path, body, &resp); err != nil {
+		return "", fmt.Errorf("Bedrock API error: %w", err)
+	}
+	var text []string
+	for _, c := range resp.Output.Message.Content {
+		text = append(text, c.Text)
+	}
+	if len(text) == 0 {
+		return "", fmt.Errorf("Bedrock API returned no content")
+	}
+	RecordUsage(ctx, Usage{PromptTokens: resp.Usage.InputTokens, CompletionTokens: resp.Usage.OutputTokens})
+	return strings.TrimSpace(strings.Join(text, "")), nil
+}
+
+// ListModels lists the text models ListFoundationModels reports for the
+// region.
+func (b *BedrockProvider) ListModels(ctx context.Context) ([]string, error) {
+	var resp struct {
+		ModelSummaries []struct {
+			ModelID string `json:"modelId"`
+		} `json:"modelSummaries"`
+	}
+	if err := b.control.do(ctx, http.MethodGet, "/foundation-models?byOutputModality=TEXT", nil, &resp); err != nil {
+		return nil, fmt.Errorf("list Bedrock models: %w", err)
+	}
+	models := make([]string, 0, len(resp.ModelSummaries))
+	for _, m := range resp.ModelSummaries {
+		if m.ModelID != "" {
+			models = append(models, m.ModelID)
+		}
+	}
+	return models, nil
+}
+
+func (b *BedrockProvider) ValidateModel(ctx context.Context, model string) error {
+	models, err := b.ListModels(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Cross-region inference profiles, such as "us.anthropic.claude-…",
+	// prefix a listed model with a geography.
+	_, base, _ := strings.Cut(model, ".")
+	if !slices.Contains(models, model) && !slices.Contains(models, base) {
+		return fmt.Errorf("model %q is not available on Bedrock in this region", model)
+	}
+
+	return nil
+}
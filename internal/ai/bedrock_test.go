@@ -0,0 +1,77 @@
+package ai
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestBedrock(t *testing.T, handler http.HandlerFunc) *BedrockProvider {
+	t.Helper()
+	srv := newTestServer(t, handler)
+
+	creds := awsCredentials{AccessKeyID: "AKIDTEST", SecretAccessKey: "secret"}
+	sign := func(req *http.Request, body []byte) error {
+		signV4(req, body, creds, "us-west-2", "bedrock", time.Now())
+		return nil
+	}
+	client := &chatClient{http: srv.Client(), baseURL: srv.URL, label: "Bedrock", sign: sign}
+	return &BedrockProvider{runtime: client, control: client, model: DefaultBedrockModel}
+}
+
+func TestBedrockGenerateCommitMessage(t *testing.T) {
+	provider := newTestBedrock(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/model/anthropic.claude-3-haiku-20240307-v1%3A0/converse" {
+			t.Errorf("unexpected path %s", r.URL.EscapedPath())
+		}
+		if auth := r.Header.Get("Authorization"); !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIDTEST/") || !strings.Contains(auth, "/us-west-2/bedrock/aws4_request") {
+			t.Errorf("expected a SigV4 signature, got %q", auth)
+		}
+		w.Write([]byte(`{"output": {"message": {"role": "assistant", "content": [{"text": "fix: handle nil"}]}}, "usage": {"inputTokens": 12, "outputTokens": 4}}`))
+	})
+
+	meter := &UsageMeter{}
+	ctx := WithUsageMeter(context.Background(), meter)
+	msg, err := provider.GenerateCommitMessage(ctx, PromptInput{Diff: "diff"})
+	if err != nil || msg != "fix: handle nil" {
+		t.Fatalf("GenerateCommitMessage() = %q, %v", msg, err)
+	}
+	if usage, ok := meter.Usage(); !ok || usage.PromptTokens != 12 || usage.CompletionTokens != 4 {
+		t.Errorf("usage = %+v, %v", usage, ok)
+	}
+}
+
+func TestBedrockValidateModel(t *testing.T) {
+	provider := newTestBedrock(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/foundation-models" || r.URL.Query().Get("byOutputModality") != "TEXT" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`{"modelSummaries": [{"modelId": "anthropic.claude-3-haiku-20240307-v1:0"}, {"modelId": "meta.llama3-8b-instruct-v1:0"}]}`))
+	})
+
+	for _, model := range []string{"meta.llama3-8b-instruct-v1:0", "us.anthropic.claude-3-haiku-20240307-v1:0"} {
+		if err := provider.ValidateModel(context.Background(), model); err != nil {
+			t.Errorf("ValidateModel(%q) = %v", model, err)
+		}
+	}
+	if err := provider.ValidateModel(context.Background(), "amazon.titan-text-express-v1"); err == nil {
+		t.Error("expected an unlisted model to be rejected")
+	}
+}
+
+func TestBedrockSignerReloadsCredentials(t *testing.T) {
+	sign := bedrockSigner("", "us-west-2")
+	for _, key := range []string{"AKIDFIRST", "AKIDRENEWED"} {
+		t.Setenv("AWS_ACCESS_KEY_ID", key)
+		t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+		req, _ := http.NewRequest(http.MethodPost, "https://bedrock-runtime.us-west-2.amazonaws.com/model/m/converse", nil)
+		if err := sign(req, nil); err != nil {
+			t.Fatalf("sign: %v", err)
+		}
+		if auth := req.Header.Get("Authorization"); !strings.Contains(auth, "Credential="+key+"/") {
+			t.Errorf("request signed with %q, want the current key %s", auth, key)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package ai
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/razobeckett/goco/internal/git"
+)
+
+// Body styles a BodyFormat can enforce.
+const (
+	BodyBullets    = "bullets"
+	BodyParagraphs = "paragraphs"
+)
+
+var (
+	listItem       = regexp.MustCompile(`^\s*([-*+•]|\d+[.)])\s+(.*)$`)
+	bodyBullets    = []string{"-", "*", "+", "•"}
+	bodySpacings   = []string{"", "tight", "loose"}
+	bodyStyleNames = []string{"", BodyBullets, BodyParagraphs}
+)
+
+// BodyFormat is how message bodies are laid out. The zero value leaves
+// messages exactly as the model wrote them.
+type BodyFormat struct {
+	// Style is BodyBullets, BodyParagraphs, or empty to keep the model's
+	// mix of lists and paragraphs.
+	Style string
+	// Bullet marks unordered list items; "-" when empty.
+	Bullet string
+	// Loose separates list items with blank lines.
+	Loose bool
+}
+
+// NewBodyFormat validates the [Body] settings; spacing is "tight" (the
+// default) or "loose".
+func NewBodyFormat(style, bullet, spacing string) (BodyFormat, error) {
+	if !slices.Contains(bodyStyleNames, style) {
+		return BodyFormat{}, fmt.Errorf("style must be %q or %q, got %q", BodyBullets, BodyParagraphs, style)
+	}
+	if bullet != "" && !slices.Contains(bodyBullets, bullet) {
+		return BodyFormat{}, fmt.Errorf("bullet must be one of %s, got %q", strings.Join(bodyBullets, " "), bullet)
+	}
+	if !slices.Contains(bodySpacings, spacing) {
+		return BodyFormat{}, fmt.Errorf("bullet_spacing must be \"tight\" or \"loose\", got %q", spacing)
+	}
+	return BodyFormat{Style: style, Bullet: bullet, Loose: spacing == "loose"}, nil
+}
+
+// Instructions describes the format to the model; empty when no style is
+// enforced.
+func (f BodyFormat) Instructions() string {
+	switch f.Style {
+	case BodyBullets:
+		return fmt.Sprintf("Write the body as a list of %q bullets, one per change, with no prose paragraphs.", f.bullet())
+	case BodyParagraphs:
+		return "Write the body as prose paragraphs, without bullet lists."
+	}
+	return ""
+}
+
+// bodyBlock is a paragraph (lines) or a list (items).
+type bodyBlock struct {
+	lines   []string
+	items   []string
+	ordered bool
+}
+
+// Apply parses msg into its subject, body blocks, and trailers, converts
+// the blocks to f.Style, and renders them with one blank line between
+// sections. Trailers are kept as they are.
+func (f BodyFormat) Apply(msg string) string {
+	if f == (BodyFormat{}) {
+		return msg
+	}
+	subject, rest, _ := strings.Cut(strings.TrimSpace(msg), "\n")
+	paragraphs := splitParagraphs(rest)
+
+	var trailers string
+	if n := len(paragraphs); n > 0 && git.IsTrailerBlock(paragraphs[n-1]) {
+		trailers = strings.Join(paragraphs[n-1], "\n")
+		paragraphs = paragraphs[:n-1]
+	}
+
+	var blocks []bodyBlock
+	for _, para := range paragraphs {
+		blocks = append(blocks, parseBlocks(para)...)
+	}
+	blocks = mergeLists(convertBlocks(mergeLists(blocks), f.Style))
+
+	sections := []string{strings.TrimSpace(subject)}
+	for _, b := range blocks {
+		sections = append(sections, f.render(b))
+	}
+	if trailers != "" {
+		sections = append(sections, trailers)
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+func splitParagraphs(text string) [][]string {
+	var paragraphs [][]string
+	var current []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRightFunc(line, unicode.IsSpace)
+		if line == "" {
+			if len(current) > 0 {
+				paragraphs = append(paragraphs, current)
+				current = nil
+			}
+			continue
+		}
+		current = append(current, line)
+	}
+	if len(current) > 0 {
+		paragraphs = append(paragraphs, current)
+	}
+	return paragraphs
+}
+
+// parseBlocks splits one paragraph into leading prose and a list; lines
+// after a list item continue it, as models wrap long items.
+func parseBlocks(lines []string) []bodyBlock {
+	var blocks []bodyBlock
+	var prose, items []string
+	ordered := false
+	for _, line := range lines {
+		if m := listItem.FindStringSubmatch(line); m != nil {
+			if len(items) == 0 {
+				ordered = m[1][0] >= '0' && m[1][0] <= '9'
+			}
+			items = append(items, strings.TrimSpace(m[2]))
+			continue
+		}
+		if len(items) > 0 {
+			items[len(items)-1] += " " + strings.TrimSpace(line)
+			continue
+		}
+		prose = append(prose, line)
+	}
+	if len(prose) > 0 {
+		blocks = append(blocks, bodyBlock{lines: prose})
+	}
+	if len(items) > 0 {
+		blocks = append(blocks, bodyBlock{items: items, ordered: ordered})
+	}
+	return blocks
+}
+
+func convertBlocks(blocks []bodyBlock, style string) []bodyBlock {
+	out := make([]bodyBlock, 0, len(blocks))
+	for _, b := range blocks {
+		switch {
+		case style == BodyBullets && b.items == nil:
+			b = bodyBlock{items: []string{joinLines(b.lines)}}
+		case style == BodyParagraphs && b.items != nil:
+			sentences := make([]string, len(b.items))
+			for i, item := range b.items {
+				sentences[i] = sentence(item)
+			}
+			b = bodyBlock{lines: []string{strings.Join(sentences, " ")}}
+		}
+		out = append(out, b)
+	}
+	return out
+}
+
+// mergeLists joins adjacent lists of the same kind, which a loose list or
+// converted paragraphs leave split.
+func mergeLists(blocks []bodyBlock) []bodyBlock {
+	var out []bodyBlock
+	for _, b := range blocks {
+		if n := len(out); n > 0 && b.items != nil && out[n-1].items != nil && out[n-1].ordered == b.ordered {
+			out[n-1].items = append(out[n-1].items, b.items...)
+			continue
+		}
+		out = append(out, b)
+	}
+	return out
+}
+
+func (f BodyFormat) render(b bodyBlock) string {
+	if b.items == nil {
+		return strings.Join(b.lines, "\n")
+	}
+	lines := make([]string, len(b.items))
+	for i, item := range b.items {
+		marker := f.bullet()
+		if b.ordered {
+			marker = fmt.Sprintf("%d.", i+1)
+		}
+		lines[i] = marker + " " + item
+	}
+	sep := "\n"
+	if f.Loose {
+		sep = "\n\n"
+	}
+	return strings.Join(lines, sep)
+}
+
+func (f BodyFormat) bullet() string {
+	if f.Bullet == "" {
+		return "-"
+	}
+	return f.Bullet
+}
+
+func joinLines(lines []string) string {
+	trimmed := make([]string, len(lines))
+	for i, line := range lines {
+		trimmed[i] = strings.TrimSpace(line)
+	}
+	return strings.Join(trimmed, " ")
+}
+
+// sentence capitalizes item and ends it with a period unless it already
+// ends in punctuation.
+func sentence(item string) string {
+	r := []rune(item)
+	if len(r) == 0 {
+		return item
+	}
+	r[0] = unicode.ToUpper(r[0])
+	if last := r[len(r)-1]; unicode.IsLetter(last) || unicode.IsDigit(last) || last == ')' || last == '`' {
+		r = append(r, '.')
+	}
+	return string(r)
+}
+
+// trailerOrder is the canonical footer order. Other trailers keep their
+// relative order between Refs and Co-authored-by.
+var trailerOrder = []string{"BREAKING CHANGE", "Closes", "Refs", "Co-authored-by", "Signed-off-by"}
+
+// NormalizeTrailers sorts the closing trailer block of msg into
+// trailerOrder, spells known keys canonically, and drops repeated lines.
+// A message without a trailer block is returned unchanged.
+func NormalizeTrailers(msg string) string {
+	msg = strings.TrimRight(msg, "\n")
+	i := strings.LastIndex(msg, "\n\n")
+	if i < 0 {
+		return msg
+	}
+	lines := strings.Split(msg[i+2:], "\n")
+	if !git.IsTrailerBlock(lines) {
+		return msg
+	}
+
+	type trailer struct {
+		line string
+		rank int
+	}
+	// Known keys rank at even positions so the others slot in after Refs.
+	otherRank := 2*slices.Index(trailerOrder, "Refs") + 1
+	var trailers []trailer
+	for _, line := range lines {
+		key, value, _ := strings.Cut(line, ": ")
+		if strings.EqualFold(key, "BREAKING-CHANGE") {
+			key = "BREAKING CHANGE"
+		}
+		rank := otherRank
+		if known := slices.IndexFunc(trailerOrder, func(k string) bool { return strings.EqualFold(k, key) }); known >= 0 {
+			key, rank = trailerOrder[known], 2*known
+		}
+		line = key + ": " + strings.TrimSpace(value)
+		if slices.ContainsFunc(trailers, func(t trailer) bool { return strings.EqualFold(t.line, line) }) {
+			continue
+		}
+		trailers = append(trailers, trailer{line, rank})
+	}
+	slices.SortStableFunc(trailers, func(a, b trailer) int { return a.rank - b.rank })
+
+	out := make([]string, len(trailers))
+	for j, t := range trailers {
+		out[j] = t.line
+	}
+	return msg[:i+2] + strings.Join(out, "\n")
+}
@@ -0,0 +1,91 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBodyFormat(t *testing.T) {
+	msg := "feat: add export\n\nExport reports as CSV\nfrom the dashboard.\n* stream rows\n  instead of buffering\n\n* add a download button\n\nRefs: ABC-1\nBREAKING CHANGE: drops XLS"
+	tests := []struct {
+		name   string
+		format BodyFormat
+		want   string
+	}{
+		{"zero value leaves message alone", BodyFormat{}, msg},
+		{
+			"normalize bullets and sections",
+			BodyFormat{Bullet: "-"},
+			"feat: add export\n\nExport reports as CSV\nfrom the dashboard.\n\n- stream rows instead of buffering\n- add a download button\n\nRefs: ABC-1\nBREAKING CHANGE: drops XLS",
+		},
+		{
+			"bullets",
+			BodyFormat{Style: BodyBullets, Bullet: "•", Loose: true},
+			"feat: add export\n\n• Export reports as CSV from the dashboard.\n\n• stream rows instead of buffering\n\n• add a download button\n\nRefs: ABC-1\nBREAKING CHANGE: drops XLS",
+		},
+		{
+			"paragraphs",
+			BodyFormat{Style: BodyParagraphs},
+			"feat: add export\n\nExport reports as CSV\nfrom the dashboard.\n\nStream rows instead of buffering. Add a download button.\n\nRefs: ABC-1\nBREAKING CHANGE: drops XLS",
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.format.Apply(msg); got != tt.want {
+			t.Errorf("%s: Apply() =\n%s\nwant\n%s", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := NewBodyFormat("bullets", "#", ""); err == nil {
+		t.Error("expected an unknown bullet to be rejected")
+	}
+	if _, err := NewBodyFormat("prose", "", ""); err == nil {
+		t.Error("expected an unknown style to be rejected")
+	}
+}
+
+func TestNormalizeTrailers(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{
+			"feat: add export\n\nBody.\n\nSigned-off-by: A <a@example.com>\nrefs: ABC-1\nChangelog: added\nco-authored-by: B <b@example.com>\nBREAKING-CHANGE: drops XLS\nRefs: ABC-1\nCloses: #12",
+			"feat: add export\n\nBody.\n\nBREAKING CHANGE: drops XLS\nCloses: #12\nRefs: ABC-1\nChangelog: added\nCo-authored-by: B <b@example.com>\nSigned-off-by: A <a@example.com>",
+		},
+		{"fix: no trailers\n\nJust a body.", "fix: no trailers\n\nJust a body."},
+		{"fix: subject only", "fix: subject only"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeTrailers(tt.in); got != tt.want {
+			t.Errorf("NormalizeTrailers(%q) =\n%s\nwant\n%s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func FuzzBodyFormatApply(f *testing.F) {
+	f.Add("feat: add export\n\nExport reports\n* stream rows\n\n1. add a button\n\nRefs: ABC-1", 0)
+	f.Add("fix: x\n\n- a\n  b\n-\n\n", 1)
+	f.Add("  \n\n•", 2)
+	formats := []BodyFormat{
+		{Bullet: "-"},
+		{Style: BodyBullets, Bullet: "•", Loose: true},
+		{Style: BodyParagraphs},
+	}
+	f.Fuzz(func(t *testing.T, msg string, n int) {
+		format := formats[uint(n)%uint(len(formats))]
+		got := format.Apply(msg)
+		subject, _, _ := strings.Cut(strings.TrimSpace(msg), "\n")
+		if gotSubject, _, _ := strings.Cut(got, "\n"); gotSubject != strings.TrimSpace(subject) {
+			t.Fatalf("Apply(%q) changed the subject to %q", msg, gotSubject)
+		}
+	})
+}
+
+func FuzzNormalizeTrailers(f *testing.F) {
+	f.Add("feat: add export\n\nBody.\n\nSigned-off-by: A <a@example.com>\nrefs: ABC-1\nBREAKING-CHANGE: drops XLS\nRefs: ABC-1")
+	f.Add("fix: subject only")
+	f.Add("\n\nKey: value\n")
+	f.Fuzz(func(t *testing.T, msg string) {
+		once := NormalizeTrailers(msg)
+		if twice := NormalizeTrailers(once); twice != once {
+			t.Fatalf("NormalizeTrailers is not idempotent for %q:\n%q\n%q", msg, once, twice)
+		}
+	})
+}
@@ -0,0 +1,168 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// chatClient talks to an OpenAI-compatible chat completions API, which
+// several providers serve under their own base URL.
+type chatClient struct {
+	http *http.Client
+	// baseURL is the API root the /chat/completions and /models paths are
+	// joined to, without a trailing slash.
+	baseURL string
+	apiKey  string
+	// label names the provider in errors, e.g. "OpenRouter".
+	label string
+	// header is sent with every request besides the API key.
+	header http.Header
+	// completions is the path of the chat completions endpoint;
+	// empty means /chat/completions.
+	completions string
+	// sign, when set, authenticates each request in place of apiKey.
+	sign func(req *http.Request, body []byte) error
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model    string        `json:"model"`
+	Messages []chatMessage `json:"messages"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+	Usage Usage `json:"usage"`
+}
+
+// complete sends prompt as the only user message and returns the reply.
+func (c *chatClient) complete(ctx context.Context, model, prompt string) (string, error) {
+	body, err := json.Marshal(chatRequest{Model: model, Messages: []chatMessage{{Role: "user", Content: prompt}}})
+	if err != nil {
+		return "", err
+	}
+	path := c.completions
+	if path == "" {
+		path = "/chat/completions"
+	}
+	var resp chatResponse
+	if err := c.do(ctx, http.MethodPost, path, body, &resp); err != nil {
+		return "", fmt.Errorf("%s API error: %w", c.label, err)
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("%s API returned no choices", c.label)
+	}
+	RecordUsage(ctx, resp.Usage)
+	return finalAnswer(resp.Choices[0].Message.Content), nil
+}
+
+// finalAnswer returns a reply without the chain of thought reasoning
+// models such as DeepSeek-R1 write in <think> tags ahead of the answer.
+// APIs that return the reasoning in a field of its own, as DeepSeek's
+// reasoning_content, need nothing stripped.
+func finalAnswer(content string) string {
+	content = strings.TrimSpace(content)
+	for strings.HasPrefix(content, "<think>") {
+		_, after, ok := strings.Cut(content, "</think>")
+		if !ok {
+			// The reply stopped mid-thought and holds no answer.
+			return ""
+		}
+		content = strings.TrimSpace(after)
+	}
+	return content
+}
+
+// models lists the IDs the /models endpoint returns.
+func (c *chatClient) models(ctx context.Context) ([]string, error) {
+	var resp struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/models", nil, &resp); err != nil {
+		return nil, fmt.Errorf("list %s models: %w", c.label, err)
+	}
+	models := make([]string, 0, len(resp.Data))
+	for _, model := range resp.Data {
+		if model.ID != "" {
+			models = append(models, model.ID)
+		}
+	}
+	return models, nil
+}
+
+// do sends a request to path and decodes the JSON reply into v. A reply
+// that is not 2xx becomes an error carrying the status, such as "429 Too
+// Many Requests", so IsTransient recognizes it.
+func (c *chatClient) do(ctx context.Context, method, path string, body []byte, v any) error {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	for key, values := range c.header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	if c.sign != nil {
+		if err := c.sign(req, body); err != nil {
+			return err
+		}
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("%s: %s", resp.Status, apiErrorMessage(data))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	return nil
+}
+
+// apiErrorMessage returns the message of an OpenAI- or AWS-style error body,
+// or the body itself when it has none.
+func apiErrorMessage(data []byte) string {
+	var body struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(data, &body) == nil {
+		if body.Error.Message != "" {
+			return body.Error.Message
+		}
+		if body.Message != "" {
+			return body.Message
+		}
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,34 @@
+package ai
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTestServer serves handler for the length of the test, standing in for
+// a provider's API.
+func newTestServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFinalAnswer(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{content: " feat: add x \n", want: "feat: add x"},
+		{content: "<think>\nThe diff adds x.\n</think>\n\nfeat: add x", want: "feat: add x"},
+		{content: "<think>a</think><think>b</think>feat: add x", want: "feat: add x"},
+		{content: "<think>\nThe diff adds", want: ""},
+		{content: "docs: explain <think> tags", want: "docs: explain <think> tags"},
+	}
+	for _, tt := range tests {
+		if got := finalAnswer(tt.content); got != tt.want {
+			t.Errorf("finalAnswer(%q) = %q, want %q", tt.content, got, tt.want)
+		}
+	}
+}
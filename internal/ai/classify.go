@@ -0,0 +1,293 @@
+package ai
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/razobeckett/goco/internal/git"
+)
+
+// Kinds of change Classify recognizes.
+const (
+	KindRename  = "rename-only"
+	KindVersion = "version-bump"
+	KindDeps    = "deps-only"
+	KindDocs    = "docs-only"
+	KindTests   = "test-only"
+)
+
+// Classification is a message written locally for a trivial change.
+type Classification struct {
+	Kind  string
+	Type  string
+	Scope string
+	// Subject is the description after "type(scope): ".
+	Subject string
+	// Bullets list the individual changes when there is more than one.
+	Bullets []string
+}
+
+// Message renders the classification as a Conventional Commits message.
+func (c Classification) Message() string {
+	prefix := c.Type
+	if c.Scope != "" {
+		prefix += "(" + c.Scope + ")"
+	}
+	msg := prefix + ": " + c.Subject
+	if len(c.Bullets) > 1 {
+		msg += "\n\n- " + strings.Join(c.Bullets, "\n- ")
+	}
+	return msg
+}
+
+var (
+	versionLine    = regexp.MustCompile(`^\s*"?version"?\s*[:=]\s*"([^"]+)",?\s*$`)
+	bareVersion    = regexp.MustCompile(`^\s*v?(\d+\.\d+\.\d+\S*)\s*$`)
+	goRequire      = regexp.MustCompile(`^\s*(?:require\s+)?([\w.\-/~]+\.[\w.\-/~]+)\s+(v\S+)(?:\s*//.*)?$`)
+	quotedDep      = regexp.MustCompile(`^\s*"([^"]+)"\s*:\s*"([^"]+)",?\s*$`)
+	tomlDep        = regexp.MustCompile(`^\s*([\w-]+)\s*=\s*"([^"]+)"\s*$`)
+	requirementDep = regexp.MustCompile(`^\s*([\w.\-\[\]]+)\s*==\s*(\S+)\s*$`)
+)
+
+// versionFiles hold nothing but a version string.
+var versionFiles = []string{"VERSION", "version.txt"}
+
+// lockfiles are generated from the manifests; their changes are not read.
+var lockfiles = []string{"go.sum", "package-lock.json", "yarn.lock", "pnpm-lock.yaml", "Cargo.lock", "poetry.lock", "Pipfile.lock", "composer.lock", "Gemfile.lock", "uv.lock"}
+
+// manifests declare dependencies one per line in a form parseDependency reads.
+var manifests = []string{"go.mod", "package.json", "Cargo.toml", "pyproject.toml", "requirements.txt"}
+
+// manifestKeys are package metadata fields that look like dependencies.
+var manifestKeys = []string{"name", "version", "description", "main", "module", "license", "edition", "type", "private"}
+
+// diffFile is one file's section of a unified diff.
+type diffFile struct {
+	path, oldPath string
+	added         []string
+	removed       []string
+	created       bool
+	deleted       bool
+	renamed       bool
+}
+
+// Classify recognizes diffs simple enough to describe without a model:
+// pure renames, version bumps, dependency updates, and changes confined to
+// documentation or tests (as judged by TypeHints with hints). It reports
+// false for anything else, including mixed changes.
+func Classify(diff string, hints map[string]string) (Classification, bool) {
+	files := parseDiffFiles(diff)
+	if len(files) == 0 {
+		return Classification{}, false
+	}
+	for _, classify := range []func([]diffFile, map[string]string) (Classification, bool){
+		classifyRename, classifyVersion, classifyDeps, classifyDocs, classifyTests,
+	} {
+		if c, ok := classify(files, hints); ok {
+			return c, true
+		}
+	}
+	return Classification{}, false
+}
+
+func parseDiffFiles(diff string) []diffFile {
+	var files []diffFile
+	var f *diffFile
+	inHunk := false
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			files = append(files, diffFile{})
+			f = &files[len(files)-1]
+			inHunk = false
+			f.oldPath, f.path, _ = git.DiffHeaderPaths(line)
+			continue
+		}
+		if f == nil {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+		case inHunk && strings.HasPrefix(line, "+"):
+			f.added = append(f.added, line[1:])
+		case inHunk && strings.HasPrefix(line, "-"):
+			f.removed = append(f.removed, line[1:])
+		case inHunk:
+		case strings.HasPrefix(line, "new file mode"):
+			f.created = true
+		case strings.HasPrefix(line, "deleted file mode"):
+			f.deleted = true
+		case strings.HasPrefix(line, "rename from "):
+			f.renamed = true
+			f.oldPath = strings.TrimPrefix(line, "rename from ")
+		case strings.HasPrefix(line, "rename to "):
+			f.path = strings.TrimPrefix(line, "rename to ")
+		}
+	}
+	return files
+}
+
+func classifyRename(files []diffFile, _ map[string]string) (Classification, bool) {
+	c := Classification{Kind: KindRename, Type: "refactor"}
+	for _, f := range files {
+		if !f.renamed || len(f.added)+len(f.removed) > 0 {
+			return Classification{}, false
+		}
+		c.Bullets = append(c.Bullets, fmt.Sprintf("rename %s to %s", f.oldPath, f.path))
+	}
+	c.Subject = c.Bullets[0]
+	if len(files) > 1 {
+		c.Subject = fmt.Sprintf("rename %d files", len(files))
+	}
+	return c, true
+}
+
+func classifyVersion(files []diffFile, _ map[string]string) (Classification, bool) {
+	c := Classification{Kind: KindVersion, Type: "chore", Scope: "release"}
+	version := ""
+	for _, f := range files {
+		if len(f.added) != 1 || len(f.removed) != 1 {
+			return Classification{}, false
+		}
+		pattern := versionLine
+		if slices.Contains(versionFiles, path.Base(f.path)) {
+			pattern = bareVersion
+		}
+		from, to := pattern.FindStringSubmatch(f.removed[0]), pattern.FindStringSubmatch(f.added[0])
+		if from == nil || to == nil || (version != "" && to[1] != version) {
+			return Classification{}, false
+		}
+		version = to[1]
+		c.Bullets = append(c.Bullets, fmt.Sprintf("%s: %s to %s", f.path, from[1], to[1]))
+	}
+	c.Subject = "bump version to " + version
+	return c, true
+}
+
+func classifyDeps(files []diffFile, _ map[string]string) (Classification, bool) {
+	from, to := map[string]string{}, map[string]string{}
+	var names []string
+	var locks []string
+	for _, f := range files {
+		base := path.Base(f.path)
+		switch {
+		case slices.Contains(lockfiles, base):
+			locks = append(locks, f.path)
+			continue
+		case !slices.Contains(manifests, base) || f.created || f.deleted:
+			return Classification{}, false
+		}
+		for _, side := range []struct {
+			lines    []string
+			versions map[string]string
+		}{{f.removed, from}, {f.added, to}} {
+			for _, line := range side.lines {
+				if strings.TrimSpace(line) == "" {
+					continue
+				}
+				name, version, ok := parseDependency(base, line)
+				if !ok {
+					return Classification{}, false
+				}
+				if _, seen := from[name]; !seen {
+					if _, seen := to[name]; !seen {
+						names = append(names, name)
+					}
+				}
+				side.versions[name] = version
+			}
+		}
+	}
+
+	c := Classification{Kind: KindDeps, Type: "build", Scope: "deps"}
+	for _, name := range names {
+		oldVersion, hadOld := from[name]
+		newVersion, hasNew := to[name]
+		switch {
+		case hadOld && hasNew && oldVersion != newVersion:
+			c.Bullets = append(c.Bullets, fmt.Sprintf("bump %s from %s to %s", name, oldVersion, newVersion))
+		case hasNew && !hadOld:
+			c.Bullets = append(c.Bullets, fmt.Sprintf("add %s %s", name, newVersion))
+		case hadOld && !hasNew:
+			c.Bullets = append(c.Bullets, "remove "+name)
+		}
+	}
+	switch {
+	case len(c.Bullets) == 1:
+		c.Subject = c.Bullets[0]
+	case len(c.Bullets) > 1:
+		c.Subject = fmt.Sprintf("update %d dependencies", len(c.Bullets))
+	case len(locks) > 0:
+		c.Subject = "update " + strings.Join(locks, ", ")
+	default:
+		return Classification{}, false
+	}
+	return c, true
+}
+
+// parseDependency reads one manifest line as a dependency and its version.
+func parseDependency(manifest, line string) (name, version string, ok bool) {
+	var m []string
+	switch manifest {
+	case "go.mod":
+		if strings.TrimSpace(line) == "require (" || strings.TrimSpace(line) == ")" {
+			return "", "", false
+		}
+		m = goRequire.FindStringSubmatch(line)
+	case "package.json":
+		m = quotedDep.FindStringSubmatch(line)
+	case "Cargo.toml", "pyproject.toml":
+		m = tomlDep.FindStringSubmatch(line)
+	case "requirements.txt":
+		m = requirementDep.FindStringSubmatch(line)
+	}
+	if m == nil || slices.Contains(manifestKeys, m[1]) {
+		return "", "", false
+	}
+	return m[1], m[2], true
+}
+
+func classifyDocs(files []diffFile, hints map[string]string) (Classification, bool) {
+	return classifyByHint(files, hints, "docs", KindDocs)
+}
+
+func classifyTests(files []diffFile, hints map[string]string) (Classification, bool) {
+	return classifyByHint(files, hints, "test", KindTests)
+}
+
+// classifyByHint matches when every file's type hint is commitType.
+func classifyByHint(files []diffFile, hints map[string]string, commitType, kind string) (Classification, bool) {
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.path
+	}
+	if matched := TypeHints(paths, hints)[commitType]; len(matched) != len(paths) {
+		return Classification{}, false
+	}
+
+	c := Classification{Kind: kind, Type: commitType}
+	for _, f := range files {
+		verb := "update"
+		switch {
+		case f.created:
+			verb = "add"
+		case f.deleted:
+			verb = "remove"
+		case f.renamed:
+			verb = "move " + f.oldPath + " to"
+		}
+		c.Bullets = append(c.Bullets, verb+" "+f.path)
+	}
+	c.Subject = c.Bullets[0]
+	if len(files) > 1 {
+		noun := "documentation files"
+		if commitType == "test" {
+			noun = "test files"
+		}
+		c.Subject = fmt.Sprintf("update %d %s", len(files), noun)
+	}
+	return c, true
+}
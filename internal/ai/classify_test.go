@@ -0,0 +1,53 @@
+package ai
+
+import "testing"
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name, diff, want string
+	}{
+		{
+			"rename",
+			"diff --git a/old.go b/new.go\nsimilarity index 100%\nrename from old.go\nrename to new.go\n",
+			"refactor: rename old.go to new.go",
+		},
+		{
+			"version bump",
+			"diff --git a/package.json b/package.json\n--- a/package.json\n+++ b/package.json\n@@ -2,3 +2,3 @@\n   \"name\": \"app\",\n-  \"version\": \"1.2.0\",\n+  \"version\": \"1.3.0\",\n",
+			"chore(release): bump version to 1.3.0",
+		},
+		{
+			"go deps",
+			"diff --git a/go.mod b/go.mod\n--- a/go.mod\n+++ b/go.mod\n@@ -3,3 +3,3 @@\n require (\n-\tgithub.com/spf13/cobra v1.9.0\n+\tgithub.com/spf13/cobra v1.10.1\n )\n" +
+				"diff --git a/go.sum b/go.sum\n--- a/go.sum\n+++ b/go.sum\n@@ -1,2 +1,2 @@\n-github.com/spf13/cobra v1.9.0 h1:x\n+github.com/spf13/cobra v1.10.1 h1:y\n",
+			"build(deps): bump github.com/spf13/cobra from v1.9.0 to v1.10.1",
+		},
+		{
+			"docs",
+			"diff --git a/README.md b/README.md\n--- a/README.md\n+++ b/README.md\n@@ -1 +1 @@\n-# Old\n+# New\n" +
+				"diff --git a/docs/guide.md b/docs/guide.md\nnew file mode 100644\n--- /dev/null\n+++ b/docs/guide.md\n@@ -0,0 +1 @@\n+# Guide\n",
+			"docs: update 2 documentation files\n\n- update README.md\n- add docs/guide.md",
+		},
+		{
+			"tests",
+			"diff --git a/x_test.go b/x_test.go\n--- a/x_test.go\n+++ b/x_test.go\n@@ -1 +1 @@\n-a\n+b\n",
+			"test: update x_test.go",
+		},
+		{
+			"mixed",
+			"diff --git a/x_test.go b/x_test.go\n--- a/x_test.go\n+++ b/x_test.go\n@@ -1 +1 @@\n-a\n+b\n" +
+				"diff --git a/x.go b/x.go\n--- a/x.go\n+++ b/x.go\n@@ -1 +1 @@\n-a\n+b\n",
+			"",
+		},
+	}
+	for _, tt := range tests {
+		c, ok := Classify(tt.diff, nil)
+		got := ""
+		if ok {
+			got = c.Message()
+		}
+		if got != tt.want {
+			t.Errorf("%s: Classify() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// compatibleSpec describes a provider served through an OpenAI-compatible
+// API, which is all goco needs to know to talk to it. The environment
+// variable holding its key is configured with the other providers' in
+// config.
+type compatibleSpec struct {
+	// label names the provider in errors, e.g. "Mistral".
+	label        string
+	baseURL      string
+	defaultModel string
+	// textModel, when set, reports whether a listed model writes text, so
+	// the embedding and image models some providers list are not offered.
+	textModel func(id string) bool
+	// listModels, when set, lists the models in place of the /models
+	// endpoint, for APIs that are compatible only for chat.
+	listModels func(ctx context.Context, c *chatClient) ([]string, error)
+}
+
+// compatibleProviders are the providers CompatibleProvider serves.
+var compatibleProviders = map[string]compatibleSpec{
+	ProviderMistral: {
+		label:        "Mistral",
+		baseURL:      "https://api.mistral.ai/v1",
+		defaultModel: DefaultMistralModel,
+		textModel: func(id string) bool {
+			return !strings.Contains(id, "embed") && !strings.Contains(id, "moderation")
+		},
+	},
+	ProviderGrok: {
+		label:        "Grok",
+		baseURL:      "https://api.x.ai/v1",
+		defaultModel: DefaultGrokModel,
+		textModel: func(id string) bool {
+			return !strings.Contains(id, "image")
+		},
+	},
+	// deepseek-reasoner answers after its chain of thought, which
+	// finalAnswer strips.
+	ProviderDeepSeek: {
+		label:        "DeepSeek",
+		baseURL:      "https://api.deepseek.com",
+		defaultModel: DefaultDeepSeekModel,
+	},
+	ProviderCohere: {
+		label:        "Cohere",
+		baseURL:      "https://api.cohere.com/compatibility/v1",
+		defaultModel: DefaultCohereModel,
+		listModels:   cohereModels,
+	},
+	// GitHub Models takes a GitHub token with the models scope; its model
+	// IDs name the publisher, e.g. "openai/gpt-4.1".
+	ProviderGitHub: {
+		label:        "GitHub Models",
+		baseURL:      "https://models.github.ai/inference",
+		defaultModel: DefaultGitHubModel,
+		listModels:   githubModels,
+	},
+	// Together AI serves open-weight models beside image, embedding, and
+	// rerank models, which its listing tells apart by type.
+	ProviderTogether: {
+		label:        "Together AI",
+		baseURL:      "https://api.together.xyz/v1",
+		defaultModel: DefaultTogetherModel,
+		listModels:   togetherModels,
+	},
+}
+
+// cohereModels lists the Command models Cohere's own API offers for chat;
+// its compatibility API has no /models.
+func cohereModels(ctx context.Context, c *chatClient) ([]string, error) {
+	native := *c
+	native.baseURL = strings.TrimSuffix(c.baseURL, "/compatibility/v1") + "/v1"
+	var resp struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := native.do(ctx, http.MethodGet, "/models?endpoint=chat&page_size=1000", nil, &resp); err != nil {
+		return nil, fmt.Errorf("list Cohere models: %w", err)
+	}
+	models := make([]string, 0, len(resp.Models))
+	for _, m := range resp.Models {
+		if m.Name != "" {
+			models = append(models, m.Name)
+		}
+	}
+	return models, nil
+}
+
+// githubModels lists the text models in the GitHub Models catalog, which
+// lives beside the inference API rather than under it.
+func githubModels(ctx context.Context, c *chatClient) ([]string, error) {
+	catalog := *c
+	catalog.baseURL = strings.TrimSuffix(c.baseURL, "/inference") + "/catalog"
+	var resp []struct {
+		ID      string   `json:"id"`
+		Outputs []string `json:"supported_output_modalities"`
+	}
+	if err := catalog.do(ctx, http.MethodGet, "/models", nil, &resp); err != nil {
+		return nil, fmt.Errorf("list GitHub Models: %w", err)
+	}
+	models := make([]string, 0, len(resp))
+	for _, m := range resp {
+		if m.ID != "" && slices.Contains(m.Outputs, "text") {
+			models = append(models, m.ID)
+		}
+	}
+	return models, nil
+}
+
+// togetherModels lists Together AI's chat models. Its /models returns a
+// bare array rather than OpenAI's list object.
+func togetherModels(ctx context.Context, c *chatClient) ([]string, error) {
+	var resp []struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/models", nil, &resp); err != nil {
+		return nil, fmt.Errorf("list Together AI models: %w", err)
+	}
+	models := make([]string, 0, len(resp))
+	for _, m := range resp {
+		if m.ID != "" && m.Type == "chat" {
+			models = append(models, m.ID)
+		}
+	}
+	return models, nil
+}
+
+// CompatibleProvider serves a provider from compatibleProviders, such as
+// Mistral, xAI Grok, Cohere, GitHub Models, or Together AI.
+type CompatibleProvider struct {
+	name   string
+	spec   compatibleSpec
+	client *chatClient
+	model  string
+}
+
+func NewCompatibleProvider(_ context.Context, providerName, apiKey, model string, retry RetryPolicy) (*CompatibleProvider, error) {
+	spec, ok := compatibleProviders[providerName]
+	if !ok {
+		return nil, fmt.Errorf("%q is not an OpenAI-compatible provider", providerName)
+	}
+	return &CompatibleProvider{
+		name: providerName,
+		spec: spec,
+		client: &chatClient{
+			http:    httpClient(retry),
+			baseURL: spec.baseURL,
+			apiKey:  apiKey,
+			label:   spec.label,
+		},
+		model: withDefault(model, spec.defaultModel),
+	}, nil
+}
+
+func (c *CompatibleProvider) Name() string {
+	return c.name
+}
+
+func (c *CompatibleProvider) DefaultModel() string {
+	return c.spec.defaultModel
+}
+
+func (c *CompatibleProvider) GenerateCommitMessage(ctx context.Context, input PromptInput) (string, error) {
+	prompt, err := BuildPrompt(input)
+	if err != nil {
+		return "", err
+	}
+	return c.client.complete(ctx, c.model, prompt)
+}
+
+func (c *CompatibleProvider) ListModels(ctx context.Context) ([]string, error) {
+	var models []string
+	var err error
+	if c.spec.listModels != nil {
+		models, err = c.spec.listModels(ctx, c.client)
+	} else {
+		models, err = c.client.models(ctx)
+	}
+	if err != nil || c.spec.textModel == nil {
+		return models, err
+	}
+	return slices.DeleteFunc(models, func(id string) bool { return !c.spec.textModel(id) }), nil
+}
+
+func (c *CompatibleProvider) ValidateModel(ctx context.Context, model string) error {
+	models, err := c.ListModels(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(models, model) {
+		return fmt.Errorf("model %q is not available for %s", model, c.spec.label)
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package ai
+
+import (
+	"cmp"
+	"context"
+	"net/http"
+	"net/url"
+	"slices"
+	"testing"
+)
+
+func TestCompatibleProviders(t *testing.T) {
+	answer := `{"choices": [{"message": {"role": "assistant", "content": "fix: handle empty input"}}]}`
+	tests := []struct {
+		provider string
+		reply    string
+		// modelsPath is where the models are listed; empty means /models
+		// under the base URL.
+		modelsPath string
+		listed     string
+		want       []string
+	}{
+		{
+			provider: ProviderMistral,
+			listed:   `{"object": "list", "data": [{"id": "mistral-small-latest"}, {"id": "codestral-latest"}, {"id": "mistral-embed"}, {"id": "mistral-moderation-latest"}]}`,
+			want:     []string{"mistral-small-latest", "codestral-latest"},
+		},
+		{
+			provider: ProviderGrok,
+			listed:   `{"object": "list", "data": [{"id": "grok-3-mini"}, {"id": "grok-4"}, {"id": "grok-2-image-1212"}]}`,
+			want:     []string{"grok-3-mini", "grok-4"},
+		},
+		{
+			// deepseek-reasoner returns its reasoning beside the answer.
+			provider: ProviderDeepSeek,
+			reply:    `{"choices": [{"message": {"role": "assistant", "reasoning_content": "The diff guards a nil slice.", "content": "fix: handle empty input"}}]}`,
+			listed:   `{"object": "list", "data": [{"id": "deepseek-chat"}, {"id": "deepseek-reasoner"}]}`,
+			want:     []string{"deepseek-chat", "deepseek-reasoner"},
+		},
+		{
+			// Cohere lists its models from its own API, not the
+			// compatibility one.
+			provider:   ProviderCohere,
+			modelsPath: "/v1/models",
+			listed:     `{"models": [{"name": "command-r-08-2024", "endpoints": ["chat"]}, {"name": "command-a-03-2025", "endpoints": ["chat"]}]}`,
+			want:       []string{"command-r-08-2024", "command-a-03-2025"},
+		},
+		{
+			// The GitHub Models catalog sits beside the inference API and
+			// lists embedding models too.
+			provider:   ProviderGitHub,
+			modelsPath: "/catalog/models",
+			listed:     `[{"id": "openai/gpt-4.1-mini", "supported_output_modalities": ["text"]}, {"id": "openai/text-embedding-3-small", "supported_output_modalities": ["embeddings"]}, {"id": "meta/llama-3.3-70b-instruct", "supported_output_modalities": ["text"]}]`,
+			want:       []string{"openai/gpt-4.1-mini", "meta/llama-3.3-70b-instruct"},
+		},
+		{
+			// Together AI lists models of every type as a bare array.
+			provider: ProviderTogether,
+			listed:   `[{"id": "meta-llama/Llama-3.3-70B-Instruct-Turbo", "type": "chat"}, {"id": "black-forest-labs/FLUX.1-schnell", "type": "image"}, {"id": "BAAI/bge-large-en-v1.5", "type": "embedding"}, {"id": "Qwen/Qwen2.5-Coder-32B-Instruct", "type": "chat"}]`,
+			want:     []string{"meta-llama/Llama-3.3-70B-Instruct-Turbo", "Qwen/Qwen2.5-Coder-32B-Instruct"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			base, err := url.Parse(compatibleProviders[tt.provider].baseURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			srv := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != "Bearer test-key" {
+					t.Errorf("unexpected auth %q", r.Header.Get("Authorization"))
+				}
+				switch r.URL.Path {
+				case base.Path + "/chat/completions":
+					w.Write([]byte(cmp.Or(tt.reply, answer)))
+				case cmp.Or(tt.modelsPath, base.Path+"/models"):
+					w.Write([]byte(tt.listed))
+				default:
+					t.Errorf("unexpected request %s", r.URL.Path)
+				}
+			})
+			provider, err := NewCompatibleProvider(context.Background(), tt.provider, "test-key", "", RetryPolicy{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			provider.client.http, provider.client.baseURL = srv.Client(), srv.URL+base.Path
+
+			if provider.Name() != tt.provider || provider.model != DefaultModelFor(tt.provider, Options{}) {
+				t.Errorf("provider = %s with %s, want %s with its default model", provider.Name(), provider.model, tt.provider)
+			}
+			msg, err := provider.GenerateCommitMessage(context.Background(), PromptInput{Diff: "diff"})
+			if err != nil || msg != "fix: handle empty input" {
+				t.Fatalf("GenerateCommitMessage() = %q, %v", msg, err)
+			}
+			models, err := provider.ListModels(context.Background())
+			if err != nil || !slices.Equal(models, tt.want) {
+				t.Fatalf("ListModels() = %v, %v; want %v", models, err, tt.want)
+			}
+			if err := provider.ValidateModel(context.Background(), tt.want[1]); err != nil {
+				t.Errorf("ValidateModel(%s) = %v", tt.want[1], err)
+			}
+			if err := provider.ValidateModel(context.Background(), "unlisted-2099"); err == nil {
+				t.Error("expected an unlisted model to be rejected")
+			}
+		})
+	}
+
+	if _, err := NewCompatibleProvider(context.Background(), ProviderGemini, "key", "", RetryPolicy{}); err == nil {
+		t.Error("expected Gemini to be rejected as an OpenAI-compatible provider")
+	}
+}
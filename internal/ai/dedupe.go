@@ -0,0 +1,105 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// subjectSimilarity is the share of words two subject descriptions must
+// have in common to count as near-duplicates.
+const subjectSimilarity = 0.7
+
+// Overlap is what a message repeats from earlier messages in a series.
+type Overlap struct {
+	// Subject is the earlier subject this message's subject resembles.
+	Subject string
+	// Bullets are body list items that already appeared earlier.
+	Bullets []string
+}
+
+// Empty reports whether nothing was repeated.
+func (o Overlap) Empty() bool {
+	return o.Subject == "" && len(o.Bullets) == 0
+}
+
+// Feedback tells the model how to make its message distinct.
+func (o Overlap) Feedback() string {
+	var parts []string
+	if o.Subject != "" {
+		parts = append(parts, fmt.Sprintf("Your subject is nearly the same as an earlier commit's in this series (%q); say what is specific to this commit.", o.Subject))
+	}
+	if len(o.Bullets) > 0 {
+		parts = append(parts, "These body bullets already appear in an earlier commit of this series; drop them or describe only what this commit changes:\n- "+strings.Join(o.Bullets, "\n- "))
+	}
+	return strings.Join(parts, "\n")
+}
+
+// FindOverlap compares msg with the earlier messages of a series. Subjects
+// are compared without their "type(scope): " prefix, since split commits
+// often differ only in scope.
+func FindOverlap(msg string, earlier []string) Overlap {
+	var o Overlap
+	subject, body, _ := strings.Cut(msg, "\n")
+	words := subjectWords(subject)
+	bullets := listItems(body)
+
+	seen := map[string]bool{}
+	for _, prev := range earlier {
+		prevSubject, prevBody, _ := strings.Cut(prev, "\n")
+		if o.Subject == "" && similarWords(words, subjectWords(prevSubject)) {
+			o.Subject = strings.TrimSpace(prevSubject)
+		}
+		for _, b := range listItems(prevBody) {
+			seen[normalizeBullet(b)] = true
+		}
+	}
+	for _, b := range bullets {
+		if seen[normalizeBullet(b)] {
+			o.Bullets = append(o.Bullets, b)
+		}
+	}
+	return o
+}
+
+func subjectWords(subject string) map[string]bool {
+	subject = strings.TrimSpace(subject)
+	if m := subjectPrefix.FindStringSubmatch(subject); m != nil {
+		subject = m[2] + m[3]
+	}
+	words := map[string]bool{}
+	for _, w := range strings.FieldsFunc(strings.ToLower(subject), func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		words[w] = true
+	}
+	return words
+}
+
+// similarWords reports whether the Jaccard similarity of a and b reaches
+// subjectSimilarity.
+func similarWords(a, b map[string]bool) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return false
+	}
+	shared := 0
+	for w := range a {
+		if b[w] {
+			shared++
+		}
+	}
+	union := len(a) + len(b) - shared
+	return float64(shared) >= subjectSimilarity*float64(union)
+}
+
+func listItems(body string) []string {
+	var bullets []string
+	for _, line := range strings.Split(body, "\n") {
+		if m := listItem.FindStringSubmatch(line); m != nil {
+			bullets = append(bullets, strings.TrimSpace(m[2]))
+		}
+	}
+	return bullets
+}
+
+func normalizeBullet(b string) string {
+	return strings.Join(strings.Fields(strings.ToLower(strings.TrimRight(b, "."))), " ")
+}
@@ -0,0 +1,19 @@
+package ai
+
+import "testing"
+
+func TestFindOverlap(t *testing.T) {
+	earlier := []string{"feat(api): add rate limiting to requests\n\n- Add token bucket limiter\n- Update config docs."}
+
+	o := FindOverlap("feat(web): add rate limiting to requests\n\n- add token bucket limiter\n- Show retry banner", earlier)
+	if o.Subject != "feat(api): add rate limiting to requests" {
+		t.Errorf("expected subject overlap, got %q", o.Subject)
+	}
+	if len(o.Bullets) != 1 || o.Bullets[0] != "add token bucket limiter" {
+		t.Errorf("expected one repeated bullet, got %q", o.Bullets)
+	}
+
+	if o := FindOverlap("feat(web): show retry banner on 429\n\n- Show retry banner", earlier); !o.Empty() {
+		t.Errorf("expected no overlap, got %+v", o)
+	}
+}
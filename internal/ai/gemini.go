@@ -3,6 +3,8 @@ package ai
 import (
 	"context"
 	"fmt"
+	"os"
+	"path"
 	"regexp"
 	"slices"
 	"strings"
@@ -10,16 +12,68 @@ import (
 	"google.golang.org/genai"
 )
 
+// Gemini backends.
+const (
+	GeminiBackendAPI    = "gemini-api"
+	GeminiBackendVertex = "vertex-ai"
+)
+
+// GeminiSettings choose how the Gemini provider reaches Gemini: the Gemini
+// API with an API key, or Vertex AI with Application Default Credentials
+// and a Google Cloud project and location.
+type GeminiSettings struct {
+	// Backend is GeminiBackendAPI or GeminiBackendVertex; empty is the
+	// Gemini API.
+	Backend string `json:"backend,omitempty"`
+	// Project and Location are used on Vertex AI; empty fields fall back to
+	// GOOGLE_CLOUD_PROJECT and GOOGLE_CLOUD_LOCATION.
+	Project  string `json:"project,omitempty"`
+	Location string `json:"location,omitempty"`
+}
+
+// vertex reports whether s selects Vertex AI.
+func (s GeminiSettings) vertex() bool {
+	return s.Backend == GeminiBackendVertex
+}
+
+// endpoint is the base URL of the backend s selects.
+func (s GeminiSettings) endpoint() string {
+	if !s.vertex() {
+		return "https://generativelanguage.googleapis.com"
+	}
+	location := withDefault(s.Location, os.Getenv("GOOGLE_CLOUD_LOCATION"))
+	if location == "" || location == "global" {
+		return "https://aiplatform.googleapis.com"
+	}
+	return "https://" + location + "-aiplatform.googleapis.com"
+}
+
 type GeminiProvider struct {
 	client *genai.Client
 	model  string
 }
 
-func NewGeminiProvider(ctx context.Context, apiKey, model string) (*GeminiProvider, error) {
-	client, err := genai.NewClient(ctx, &genai.ClientConfig{
-		APIKey:  apiKey,
-		Backend: genai.BackendGeminiAPI,
-	})
+func NewGeminiProvider(ctx context.Context, apiKey, model string, settings GeminiSettings, retry RetryPolicy) (*GeminiProvider, error) {
+	config := &genai.ClientConfig{
+		APIKey:     apiKey,
+		Backend:    genai.BackendGeminiAPI,
+		HTTPClient: httpClient(retry),
+	}
+	switch settings.Backend {
+	case "", GeminiBackendAPI:
+	case GeminiBackendVertex:
+		// Without an HTTP client, the SDK builds one that authenticates
+		// with Application Default Credentials.
+		config = &genai.ClientConfig{
+			Backend:  genai.BackendVertexAI,
+			Project:  settings.Project,
+			Location: settings.Location,
+		}
+	default:
+		return nil, fmt.Errorf("unknown Gemini backend %q; use %q or %q", settings.Backend, GeminiBackendAPI, GeminiBackendVertex)
+	}
+
+	client, err := genai.NewClient(ctx, config)
 	if err != nil {
 		return nil, fmt.Errorf("create Gemini client: %w", err)
 	}
@@ -38,16 +92,25 @@ func (g *GeminiProvider) DefaultModel() string {
 	return DefaultGeminiModel
 }
 
-func (g *GeminiProvider) GenerateCommitMessage(ctx context.Context, gitStatus, gitDiff, customInstructions, recentLog string) (string, error) {
+func (g *GeminiProvider) GenerateCommitMessage(ctx context.Context, input PromptInput) (string, error) {
+	prompt, err := BuildPrompt(input)
+	if err != nil {
+		return "", err
+	}
+
 	resp, err := g.client.Models.GenerateContent(
 		ctx,
 		g.model,
-		genai.Text(buildPrompt(gitStatus, gitDiff, customInstructions, recentLog)),
+		genai.Text(prompt),
 		nil,
 	)
 	if err != nil {
 		return "", fmt.Errorf("Gemini API error: %w", err)
 	}
+	if u := resp.UsageMetadata; u != nil {
+		// Thinking tokens are billed as output.
+		RecordUsage(ctx, Usage{PromptTokens: int(u.PromptTokenCount), CompletionTokens: int(u.CandidatesTokenCount + u.ThoughtsTokenCount)})
+	}
 
 	return strings.TrimSpace(resp.Text()), nil
 }
@@ -62,7 +125,8 @@ func (g *GeminiProvider) ListModels(ctx context.Context) ([]string, error) {
 	re := regexp.MustCompile(`^gemini-`)
 
 	for _, m := range page.Items {
-		name := strings.TrimPrefix(m.Name, "models/")
+		// Vertex AI names models publishers/google/models/<name>.
+		name := path.Base(m.Name)
 		if re.MatchString(name) {
 			filtered = append(filtered, name)
 		}
@@ -70,7 +134,7 @@ func (g *GeminiProvider) ListModels(ctx context.Context) ([]string, error) {
 
 	if len(filtered) == 0 {
 		for _, m := range page.Items {
-			filtered = append(filtered, strings.TrimPrefix(m.Name, "models/"))
+			filtered = append(filtered, path.Base(m.Name))
 		}
 	}
 
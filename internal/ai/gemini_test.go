@@ -70,3 +70,51 @@ func TestListModelsFallbackWhenEmpty(t *testing.T) {
 		t.Fatalf("expected %v, got %v", expected, result)
 	}
 }
+
+func TestListModelsVertexNames(t *testing.T) {
+	original := geminiListModelsFunc
+	defer func() { geminiListModelsFunc = original }()
+
+	geminiListModelsFunc = func(g *GeminiProvider, ctx context.Context) (genai.Page[genai.Model], error) {
+		return genai.Page[genai.Model]{
+			Items: []*genai.Model{
+				{Name: "publishers/google/models/gemini-2.5-flash"},
+				{Name: "publishers/google/models/imagen-3.0-generate-002"},
+			},
+		}, nil
+	}
+
+	result, err := (&GeminiProvider{}).ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"gemini-2.5-flash"}; !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expected %v, got %v", expected, result)
+	}
+}
+
+func TestGeminiSettingsEndpoint(t *testing.T) {
+	t.Setenv("GOOGLE_CLOUD_LOCATION", "")
+	tests := []struct {
+		settings GeminiSettings
+		env      string
+		want     string
+	}{
+		{settings: GeminiSettings{}, want: "https://generativelanguage.googleapis.com"},
+		{settings: GeminiSettings{Backend: GeminiBackendVertex, Location: "us-central1"}, want: "https://us-central1-aiplatform.googleapis.com"},
+		{settings: GeminiSettings{Backend: GeminiBackendVertex, Location: "global"}, want: "https://aiplatform.googleapis.com"},
+		{settings: GeminiSettings{Backend: GeminiBackendVertex}, env: "europe-west4", want: "https://europe-west4-aiplatform.googleapis.com"},
+	}
+	for _, tt := range tests {
+		t.Setenv("GOOGLE_CLOUD_LOCATION", tt.env)
+		if got := tt.settings.endpoint(); got != tt.want {
+			t.Errorf("%+v.endpoint() = %q, want %q", tt.settings, got, tt.want)
+		}
+	}
+}
+
+func TestNewGeminiProviderUnknownBackend(t *testing.T) {
+	if _, err := NewGeminiProvider(context.Background(), "key", DefaultGeminiModel, GeminiSettings{Backend: "vertex"}, RetryPolicy{}); err == nil {
+		t.Fatal("expected an unknown backend to be rejected")
+	}
+}
@@ -4,19 +4,27 @@ import (
 	"context"
 	"fmt"
 	"slices"
-	"strings"
 
 	"github.com/algolyzer/groq-go"
 )
 
+// groqTestBaseURL points the Groq provider at a fake API. Only builds with
+// the e2e tag set it; every other build talks to api.groq.com, so the API
+// key is never sent anywhere else.
+var groqTestBaseURL string
+
 type GroqProvider struct {
 	client *groq.Client
 	model  string
 }
 
-func NewGroqProvider(_ context.Context, apiKey, model string) (*GroqProvider, error) {
+func NewGroqProvider(_ context.Context, apiKey, model string, retry RetryPolicy) (*GroqProvider, error) {
+	opts := []groq.Option{groq.WithHTTPClient(httpClient(retry))}
+	if groqTestBaseURL != "" {
+		opts = append(opts, groq.WithBaseURL(groqTestBaseURL))
+	}
 	return &GroqProvider{
-		client: groq.NewClient(apiKey),
+		client: groq.NewClient(apiKey, opts...),
 		model:  model,
 	}, nil
 }
@@ -29,13 +37,18 @@ func (g *GroqProvider) DefaultModel() string {
 	return DefaultGroqModel
 }
 
-func (g *GroqProvider) GenerateCommitMessage(ctx context.Context, gitStatus, gitDiff, customInstructions, recentLog string) (string, error) {
+func (g *GroqProvider) GenerateCommitMessage(ctx context.Context, input PromptInput) (string, error) {
+	prompt, err := BuildPrompt(input)
+	if err != nil {
+		return "", err
+	}
+
 	resp, err := g.client.CreateChatCompletion(ctx, groq.ChatCompletionRequest{
 		Model: g.model,
 		Messages: []groq.ChatMessage{
 			{
 				Role:    groq.RoleUser,
-				Content: buildPrompt(gitStatus, gitDiff, customInstructions, recentLog),
+				Content: prompt,
 			},
 		},
 	})
@@ -45,8 +58,9 @@ func (g *GroqProvider) GenerateCommitMessage(ctx context.Context, gitStatus, git
 	if len(resp.Choices) == 0 {
 		return "", fmt.Errorf("Groq API returned no choices")
 	}
+	RecordUsage(ctx, Usage{PromptTokens: resp.Usage.PromptTokens, CompletionTokens: resp.Usage.CompletionTokens})
 
-	return strings.TrimSpace(resp.Choices[0].Message.Content), nil
+	return finalAnswer(resp.Choices[0].Message.Content), nil
 }
 
 func (g *GroqProvider) ListModels(ctx context.Context) ([]string, error) {
@@ -0,0 +1,14 @@
+//go:build e2e
+
+package ai
+
+import (
+	"os"
+	"strings"
+)
+
+// The end-to-end suite builds goco with -tags e2e and points it at a fake
+// Groq API.
+func init() {
+	groqTestBaseURL = strings.TrimSuffix(os.Getenv("GOCO_E2E_GROQ_BASE_URL"), "/")
+}
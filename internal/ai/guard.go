@@ -0,0 +1,137 @@
+package ai
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// injectionPatterns match phrases commonly used to hijack a model through
+// content it was only meant to read.
+var injectionPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\b(ignore|disregard|forget)\s+(all\s+|any\s+)?(the\s+)?(previous|prior|above|earlier|preceding)\s+(instructions|prompts?|rules|context)`),
+	regexp.MustCompile(`(?i)\byou\s+are\s+now\s+(a|an|the)\b`),
+	regexp.MustCompile(`(?i)\b(new|updated|override)\s+(system\s+)?instructions\s*:`),
+	regexp.MustCompile(`(?i)\b(reveal|print|output|repeat)\s+(your|the)\s+(system\s+)?prompt\b`),
+}
+
+// preamblePrefixes open a line the model wrote to the user before the
+// commit message itself.
+var preamblePrefixes = []string{
+	"sure",
+	"certainly",
+	"here is",
+	"here's",
+	"commit message:",
+}
+
+// refusalPrefixes open a response in which the model declined to write a
+// commit message.
+var refusalPrefixes = []string{
+	"i cannot",
+	"i can't",
+	"i'm sorry",
+	"as an ai",
+}
+
+// secretPatterns match credentials that should never be written to disk.
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`AIza[0-9A-Za-z_\-]{35}`),
+	regexp.MustCompile(`gsk_[0-9A-Za-z]{20,}`),
+	regexp.MustCompile(`sk-[0-9A-Za-z_\-]{20,}`),
+	regexp.MustCompile(`gh[pousr]_[0-9A-Za-z]{36,}`),
+	regexp.MustCompile(`AKIA[0-9A-Z]{16}`),
+	regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`),
+	regexp.MustCompile(`(?i)((?:api[_-]?key|secret|token|password|passwd)["']?\s*[:=]\s*["']?)[^\s"']{8,}`),
+}
+
+// RedactSecrets replaces likely credentials in text with a placeholder.
+func RedactSecrets(text string) string {
+	for _, pattern := range secretPatterns {
+		if pattern.NumSubexp() > 0 {
+			text = pattern.ReplaceAllString(text, "${1}[REDACTED]")
+			continue
+		}
+		text = pattern.ReplaceAllString(text, "[REDACTED]")
+	}
+	return text
+}
+
+// ScanInjection returns the suspicious phrases found in text, one per match.
+func ScanInjection(text string) []string {
+	var found []string
+	for _, line := range strings.Split(text, "\n") {
+		for _, pattern := range injectionPatterns {
+			if match := pattern.FindString(line); match != "" {
+				found = append(found, strings.TrimSpace(match))
+			}
+		}
+	}
+	return found
+}
+
+// fenceData wraps repository-derived content in markers keyed by its own
+// checksum. Content cannot forge the closing marker without changing the
+// checksum it would need to match.
+func fenceData(label, content string) string {
+	sum := sha256.Sum256([]byte(content))
+	id := hex.EncodeToString(sum[:8])
+	return fmt.Sprintf("<<<BEGIN %s DATA %s>>>\n%s\n<<<END %s DATA %s>>>", label, id, content, label, id)
+}
+
+// CleanOutput strips the wrappers models commonly put around a message: a
+// leading "Here is the commit message:" line and a code fence around the
+// whole reply. Code blocks inside the body are kept. It rejects a reply that
+// is still not a message: a refusal, nothing but a preamble, or one that
+// echoes the prompt's data markers.
+func CleanOutput(msg string) (string, error) {
+	text := strings.TrimSpace(msg)
+	if hasAnyPrefix(text, refusalPrefixes) {
+		return "", fmt.Errorf("AI provider returned commentary instead of a commit message: %q", firstLine(text))
+	}
+
+	if line, rest, ok := strings.Cut(text, "\n"); hasAnyPrefix(line, preamblePrefixes) {
+		// "Commit message: feat: x" keeps what follows the label.
+		if i := strings.Index(strings.ToLower(line), "message:"); i >= 0 {
+			rest = line[i+len("message:"):] + "\n" + rest
+		} else if !ok {
+			return "", fmt.Errorf("AI provider returned commentary instead of a commit message: %q", line)
+		}
+		text = strings.TrimSpace(rest)
+	}
+
+	if strings.HasPrefix(text, "```") {
+		_, text, _ = strings.Cut(text, "\n")
+		text = strings.TrimSpace(text)
+		if strings.HasSuffix(text, "```") {
+			text = strings.TrimSpace(strings.TrimSuffix(text, "```"))
+		}
+	}
+
+	switch {
+	case text == "" && strings.TrimSpace(msg) != "":
+		return "", fmt.Errorf("AI provider returned commentary instead of a commit message: %q", firstLine(strings.TrimSpace(msg)))
+	case hasAnyPrefix(text, preamblePrefixes) || hasAnyPrefix(text, refusalPrefixes) || strings.HasPrefix(text, "```"):
+		return "", fmt.Errorf("AI provider returned commentary instead of a commit message: %q", firstLine(text))
+	case strings.Contains(text, "<<<BEGIN ") || strings.Contains(text, "<<<END "):
+		return "", fmt.Errorf("AI provider echoed prompt data instead of writing a commit message")
+	}
+	return text, nil
+}
+
+func hasAnyPrefix(text string, prefixes []string) bool {
+	lower := strings.ToLower(text)
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
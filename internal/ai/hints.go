@@ -0,0 +1,81 @@
+package ai
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// DefaultTypeHints map common file patterns to the commit type they usually imply.
+var DefaultTypeHints = map[string]string{
+	"*.md":            "docs",
+	"docs/**":         "docs",
+	"LICENSE":         "docs",
+	"*_test.go":       "test",
+	"testdata/**":     "test",
+	".github/**":      "ci",
+	".gitlab-ci.yml":  "ci",
+	".circleci/**":    "ci",
+	"Dockerfile":      "build",
+	"Makefile":        "build",
+	"go.mod":          "build",
+	"go.sum":          "build",
+	"package.json":    "build",
+	".goreleaser.yml": "build",
+}
+
+// TypeHints groups paths by the commit type their patterns suggest. Rules
+// from overrides are consulted before the defaults; an empty type in
+// overrides suppresses a default. The longest matching pattern wins.
+func TypeHints(paths []string, overrides map[string]string) map[string][]string {
+	hints := make(map[string][]string)
+	for _, p := range paths {
+		commitType, ok := matchTypeHint(overrides, p)
+		if !ok {
+			commitType, _ = matchTypeHint(DefaultTypeHints, p)
+		}
+		if commitType != "" {
+			hints[commitType] = append(hints[commitType], p)
+		}
+	}
+	return hints
+}
+
+// FormatTypeHints renders hints as one "type: path, path" line per type.
+func FormatTypeHints(hints map[string][]string) string {
+	types := make([]string, 0, len(hints))
+	for t := range hints {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	lines := make([]string, 0, len(types))
+	for _, t := range types {
+		lines = append(lines, t+": "+strings.Join(hints[t], ", "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func matchTypeHint(rules map[string]string, p string) (string, bool) {
+	best, bestType, found := "", "", false
+	for pattern, commitType := range rules {
+		if matchPathPattern(pattern, p) && len(pattern) > len(best) {
+			best, bestType, found = pattern, commitType, true
+		}
+	}
+	return bestType, found
+}
+
+// matchPathPattern matches p against a glob. Patterns without a slash match
+// the base name anywhere; a trailing "/**" matches everything below a directory.
+func matchPathPattern(pattern, p string) bool {
+	if dir, ok := strings.CutSuffix(pattern, "/**"); ok {
+		return p == dir || strings.HasPrefix(p, dir+"/")
+	}
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(p))
+		return ok
+	}
+	ok, _ := path.Match(pattern, p)
+	return ok
+}
@@ -0,0 +1,17 @@
+package ai
+
+import (
+	"testing"
+)
+
+func TestTypeHints(t *testing.T) {
+	hints := TypeHints(
+		[]string{"README.md", "docs/guide.md", ".github/workflows/release.yml", "internal/cli/root.go", "CHANGELOG.md", "Dockerfile"},
+		map[string]string{"CHANGELOG.md": "chore", "Dockerfile": ""},
+	)
+
+	expected := "chore: CHANGELOG.md\nci: .github/workflows/release.yml\ndocs: README.md, docs/guide.md"
+	if got := FormatTypeHints(hints); got != expected {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+}
@@ -12,6 +12,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/razobeckett/goco/internal/cache"
 )
 
 // modelsdev.go — models.dev registry integration for model listing.
@@ -29,8 +31,16 @@ const modelsDevStaleGrace = 5 * time.Minute
 
 // providerToModelsDev maps GoCo provider names to models.dev provider IDs.
 var providerToModelsDev = map[string]string{
-	ProviderGemini: "google",
-	ProviderGroq:   "groq",
+	ProviderGemini:     "google",
+	ProviderGroq:       "groq",
+	ProviderOpenRouter: "openrouter",
+	ProviderMistral:    "mistral",
+	ProviderBedrock:    "amazon-bedrock",
+	ProviderGrok:       "xai",
+	ProviderDeepSeek:   "deepseek",
+	ProviderCohere:     "cohere",
+	ProviderGitHub:     "github-models",
+	ProviderTogether:   "togetherai",
 }
 
 // Patterns for non-agentic / noise models to exclude.
@@ -44,9 +54,10 @@ var (
 	modelsDevTime  time.Time
 )
 
-// FetchModelsDev returns the full models.dev provider registry.
+// FetchModelsDev returns the full models.dev provider registry, fetching it
+// with retry when it is not cached.
 // Cache hierarchy: in-memory → disk → network → stale disk fallback.
-func FetchModelsDev() (map[string]json.RawMessage, error) {
+func FetchModelsDev(retry RetryPolicy) (map[string]json.RawMessage, error) {
 	modelsDevMu.RLock()
 	if modelsDevCache != nil && time.Since(modelsDevTime) < modelsDevCacheTTL {
 		data := modelsDevCache
@@ -65,6 +76,7 @@ func FetchModelsDev() (map[string]json.RawMessage, error) {
 
 	// Try disk cache.
 	if data := loadModelsDevDiskCache(); data != nil {
+		cache.Record("models", true)
 		modelsDevCache = data
 		modelsDevTime = diskCacheMTime()
 		if modelsDevTime.IsZero() {
@@ -74,7 +86,8 @@ func FetchModelsDev() (map[string]json.RawMessage, error) {
 	}
 
 	// Network fetch.
-	data, err := fetchModelsDevNetwork()
+	cache.Record("models", false)
+	data, err := fetchModelsDevNetwork(retry)
 	if err == nil && data != nil {
 		modelsDevCache = data
 		modelsDevTime = time.Now()
@@ -94,13 +107,13 @@ func FetchModelsDev() (map[string]json.RawMessage, error) {
 
 // ListModelsFromDev returns model IDs for a GoCo provider from models.dev.
 // Returns nil if the provider is unknown or models.dev is unreachable.
-func ListModelsFromDev(providerName string) ([]string, error) {
+func ListModelsFromDev(providerName string, retry RetryPolicy) ([]string, error) {
 	mdevID, ok := providerToModelsDev[providerName]
 	if !ok {
 		return nil, nil
 	}
 
-	data, err := FetchModelsDev()
+	data, err := FetchModelsDev(retry)
 	if err != nil {
 		return nil, err
 	}
@@ -162,15 +175,7 @@ func shouldHideModel(provider, modelID string) bool {
 // --- Disk cache ---
 
 func modelsDevCachePath() string {
-	configDir := os.Getenv("XDG_CACHE_HOME")
-	if configDir == "" {
-		homeDir, _ := os.UserHomeDir()
-		if homeDir == "" {
-			return ""
-		}
-		configDir = filepath.Join(homeDir, ".cache")
-	}
-	return filepath.Join(configDir, "goco", "models-dev-cache.json")
+	return cache.Path("models-dev-cache.json")
 }
 
 func loadModelsDevDiskCache() map[string]json.RawMessage {
@@ -229,7 +234,7 @@ func saveModelsDevDiskCache(data map[string]json.RawMessage) {
 
 // --- Network fetch ---
 
-func fetchModelsDevNetwork() (map[string]json.RawMessage, error) {
+func fetchModelsDevNetwork(retry RetryPolicy) (map[string]json.RawMessage, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
@@ -239,7 +244,7 @@ func fetchModelsDevNetwork() (map[string]json.RawMessage, error) {
 	}
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := httpClient(retry).Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("fetch models.dev: %w", err)
 	}
@@ -0,0 +1,151 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+
+	"github.com/razobeckett/goco/internal/cache"
+)
+
+const openRouterBaseURL = "https://openrouter.ai/api/v1"
+
+// OpenRouterProvider reaches the many models OpenRouter routes to through
+// its OpenAI-compatible API, with one key.
+type OpenRouterProvider struct {
+	client *chatClient
+	model  string
+}
+
+func NewOpenRouterProvider(_ context.Context, apiKey, model string, retry RetryPolicy) (*OpenRouterProvider, error) {
+	return &OpenRouterProvider{
+		client: &chatClient{
+			http:    httpClient(retry),
+			baseURL: openRouterBaseURL,
+			apiKey:  apiKey,
+			label:   "OpenRouter",
+			// OpenRouter attributes requests to the app that names itself.
+			header: http.Header{"X-Title": {"goco"}},
+		},
+		model: model,
+	}, nil
+}
+
+func (o *OpenRouterProvider) Name() string {
+	return ProviderOpenRouter
+}
+
+func (o *OpenRouterProvider) DefaultModel() string {
+	return DefaultOpenRouterModel
+}
+
+func (o *OpenRouterProvider) GenerateCommitMessage(ctx context.Context, input PromptInput) (string, error) {
+	prompt, err := BuildPrompt(input)
+	if err != nil {
+		return "", err
+	}
+	return o.client.complete(ctx, o.model, prompt)
+}
+
+// openRouterModel is the part of an OpenRouter models API entry goco reads.
+type openRouterModel struct {
+	ID            string `json:"id"`
+	ContextLength int    `json:"context_length"`
+	// Pricing is in US dollars per token, as decimal strings.
+	Pricing struct {
+		Prompt     string `json:"prompt"`
+		Completion string `json:"completion"`
+	} `json:"pricing"`
+}
+
+// openRouterCatalogFile caches the models the last listing returned, by
+// ID, for prices and context windows without another request.
+const openRouterCatalogFile = "openrouter-models.json"
+
+func (o *OpenRouterProvider) ListModels(ctx context.Context) ([]string, error) {
+	var resp struct {
+		Data []openRouterModel `json:"data"`
+	}
+	if err := o.client.do(ctx, http.MethodGet, "/models", nil, &resp); err != nil {
+		return nil, fmt.Errorf("list OpenRouter models: %w", err)
+	}
+
+	catalog := make(map[string]openRouterModel, len(resp.Data))
+	models := make([]string, 0, len(resp.Data))
+	for _, model := range resp.Data {
+		if model.ID != "" {
+			catalog[model.ID] = model
+			models = append(models, model.ID)
+		}
+	}
+	saveOpenRouterCatalog(catalog)
+
+	return models, nil
+}
+
+func (o *OpenRouterProvider) ValidateModel(ctx context.Context, model string) error {
+	models, err := o.ListModels(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(models, model) {
+		return fmt.Errorf("model %q is not available for OpenRouter", model)
+	}
+
+	return nil
+}
+
+// cachedOpenRouterModel looks model up in the last OpenRouter listing.
+func cachedOpenRouterModel(model string) (openRouterModel, bool) {
+	path := cache.Path(openRouterCatalogFile)
+	if path == "" {
+		return openRouterModel{}, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return openRouterModel{}, false
+	}
+	var catalog map[string]openRouterModel
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return openRouterModel{}, false
+	}
+	m, ok := catalog[model]
+	return m, ok
+}
+
+// saveOpenRouterCatalog replaces the cached listing. Failing to write it
+// only costs the prices.
+func saveOpenRouterCatalog(catalog map[string]openRouterModel) {
+	path := cache.Path(openRouterCatalogFile)
+	if path == "" {
+		return
+	}
+	data, err := json.Marshal(catalog)
+	if err != nil || os.MkdirAll(filepath.Dir(path), 0o755) != nil {
+		return
+	}
+	// Write atomically via temp file + rename.
+	tmpPath := path + ".tmp"
+	if os.WriteFile(tmpPath, data, 0o644) != nil {
+		os.Remove(tmpPath)
+		return
+	}
+	os.Rename(tmpPath, path)
+}
+
+// price converts the per-token pricing to a Price, and false when
+// OpenRouter lists none.
+func (m openRouterModel) price() (Price, bool) {
+	input, err1 := strconv.ParseFloat(m.Pricing.Prompt, 64)
+	output, err2 := strconv.ParseFloat(m.Pricing.Completion, 64)
+	if err1 != nil || err2 != nil {
+		return Price{}, false
+	}
+	return Price{Input: input * 1e6, Output: output * 1e6}, true
+}
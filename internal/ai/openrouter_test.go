@@ -0,0 +1,77 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func newTestOpenRouter(t *testing.T, handler http.HandlerFunc) *OpenRouterProvider {
+	t.Helper()
+	srv := newTestServer(t, handler)
+	return &OpenRouterProvider{
+		client: &chatClient{http: srv.Client(), baseURL: srv.URL, apiKey: "sk-or", label: "OpenRouter"},
+		model:  "openai/gpt-4o-mini",
+	}
+}
+
+func TestOpenRouterGenerateCommitMessage(t *testing.T) {
+	provider := newTestOpenRouter(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat/completions" || r.Header.Get("Authorization") != "Bearer sk-or" {
+			t.Errorf("unexpected request %s %v", r.URL.Path, r.Header)
+		}
+		var req chatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Model != "openai/gpt-4o-mini" || len(req.Messages) != 1 {
+			t.Errorf("unexpected request body %+v, %v", req, err)
+		}
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": " feat: add x \n"}}], "usage": {"prompt_tokens": 10, "completion_tokens": 3}}`))
+	})
+
+	meter := &UsageMeter{}
+	msg, err := provider.GenerateCommitMessage(WithUsageMeter(context.Background(), meter), PromptInput{Diff: "diff"})
+	if err != nil || msg != "feat: add x" {
+		t.Fatalf("GenerateCommitMessage() = %q, %v", msg, err)
+	}
+	if u, ok := meter.Usage(); !ok || u.Total() != 13 {
+		t.Errorf("expected the reported usage to be recorded, got %+v", u)
+	}
+}
+
+func TestOpenRouterErrorIsTransient(t *testing.T) {
+	provider := newTestOpenRouter(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error": {"message": "slow down"}}`))
+	})
+
+	_, err := provider.GenerateCommitMessage(context.Background(), PromptInput{Diff: "diff"})
+	if err == nil || !strings.Contains(err.Error(), "slow down") || !IsTransient(err) {
+		t.Fatalf("expected a transient error with the API message, got %v", err)
+	}
+}
+
+func TestOpenRouterListModels(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	provider := newTestOpenRouter(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": [
+			{"id": "openai/gpt-4o-mini", "context_length": 128000, "pricing": {"prompt": "0.00000015", "completion": "0.0000006"}},
+			{"id": "meta-llama/llama-3.3-70b-instruct:free", "context_length": 65536, "pricing": {"prompt": "0", "completion": "0"}}
+		]}`))
+	})
+
+	models, err := provider.ListModels(context.Background())
+	if err != nil || len(models) != 2 {
+		t.Fatalf("ListModels() = %v, %v", models, err)
+	}
+	price, ok := ModelPrice(ProviderOpenRouter, "openai/gpt-4o-mini")
+	if !ok || price.Input < 0.1499 || price.Input > 0.1501 || price.Output < 0.5999 || price.Output > 0.6001 {
+		t.Errorf("ModelPrice() = %+v, %v; want 0.15 and 0.60 per million tokens", price, ok)
+	}
+	if got := ContextWindow(ProviderOpenRouter, "meta-llama/llama-3.3-70b-instruct:free"); got != 65536 {
+		t.Errorf("ContextWindow() = %d, want 65536", got)
+	}
+	if err := provider.ValidateModel(context.Background(), "unknown/model"); err == nil {
+		t.Error("expected an unlisted model to be rejected")
+	}
+}
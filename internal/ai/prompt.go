@@ -1,71 +1,188 @@
 package ai
 
 import (
+	"bytes"
+	_ "embed"
 	"fmt"
 	"strings"
+	"sync"
+	"text/template"
 )
 
-const conventionalCommitsSpec = `
-Conventional Commits specification:
-
-The commit message MUST be structured as:
-  <type>[optional scope]: <description>
-  [blank line]
-  [optional body]
-
-Types MUST be one of:
-  feat     — a new feature
-  fix      — a bug fix
-  docs     — documentation only changes
-  style    — formatting, missing semi-colons, etc; no code change
-  refactor — a code change that neither fixes a bug nor adds a feature
-  perf     — a code change that improves performance
-  test     — adding missing tests or correcting existing tests
-  chore    — changes to the build process or auxiliary tools
-  ci       — changes to CI configuration files and scripts
-  build    — changes that affect the build system or external dependencies
-
-Rules:
-  - type and description are mandatory
-  - scope is optional and MUST be in parentheses after the type
-  - description MUST start with a lowercase letter
-  - description MUST NOT end with a period
-  - subject line (type + scope + description) MUST be <= 72 characters
-  - body is optional, separated from subject by a blank line
-  - breaking changes MUST append ! before the colon, e.g. feat!: drop support
-  - breaking changes MAY include BREAKING CHANGE: footer in the body
-`
-
-func buildPrompt(gitStatus, gitDiff, customInstructions, recentLog string) string {
-	var recentLogSection string
-	if strings.TrimSpace(recentLog) != "" {
-		recentLogSection = fmt.Sprintf("Recent Commits (for context):\n%s\n\n", recentLog)
+// defaultSpec is the bundled Conventional Commits summary inlined into every
+// prompt unless the config supplies its own spec text.
+//
+//go:embed conventional_commits.txt
+var defaultSpec string
+
+// defaultTemplateText is the built-in prompt, used when no override exists.
+//
+//go:embed prompt.tmpl
+var defaultTemplateText string
+
+var defaultTemplate = template.Must(ParsePromptTemplate(defaultTemplateText))
+
+//go:embed cover_letter.tmpl
+var coverLetterTemplateText string
+
+// CoverLetterTemplate asks for a patch series cover letter instead of a
+// commit message; Status carries the series shortlog and diffstat.
+var CoverLetterTemplate = template.Must(ParsePromptTemplate(coverLetterTemplateText))
+
+//go:embed pull_request.tmpl
+var pullRequestTemplateText string
+
+// PullRequestTemplate asks for a pull request title and description; Status
+// carries the branch's commit messages.
+var PullRequestTemplate = template.Must(ParsePromptTemplate(pullRequestTemplateText))
+
+//go:embed standup.tmpl
+var standupTemplateText string
+
+// StandupTemplate asks for a first-person work summary; Status carries the
+// commits grouped by repository.
+var StandupTemplate = template.Must(ParsePromptTemplate(standupTemplateText))
+
+//go:embed chunk.tmpl
+var chunkTemplateText string
+
+// ChunkTemplate asks for a summary of one part of a diff that is too large
+// to send whole; Status names the part.
+var ChunkTemplate = template.Must(ParsePromptTemplate(chunkTemplateText))
+
+//go:embed outline.tmpl
+var outlineTemplateText string
+
+// OutlineTemplate asks for a file-by-file outline of a change's intent, the
+// first pass of two-pass generation.
+var OutlineTemplate = template.Must(ParsePromptTemplate(outlineTemplateText))
+
+//go:embed refine.tmpl
+var refineTemplateText string
+
+// RefineTemplate asks the model to critique and correct a message; Status
+// carries the message and Diff what it describes. ParseRefinement reads the
+// reply.
+var RefineTemplate = template.Must(ParsePromptTemplate(refineTemplateText))
+
+//go:embed empty.tmpl
+var emptyTemplateText string
+
+// EmptyCommitTemplate asks for the message of a commit that records no
+// changes; Status carries the author's description of its purpose.
+var EmptyCommitTemplate = template.Must(ParsePromptTemplate(emptyTemplateText))
+
+// PromptInput carries everything a provider needs to build its prompt.
+type PromptInput struct {
+	Status             string
+	Diff               string
+	CustomInstructions string
+	RecentLog          string
+	// Spec replaces the bundled Conventional Commits rules when non-empty.
+	Spec string
+	// TypeHints suggests commit types derived from file patterns.
+	TypeHints string
+	// History lists the prior commits that last touched the changed lines.
+	History string
+	// Issue is the ticket the change implements.
+	Issue string
+	// Template replaces the built-in prompt template when non-nil.
+	Template *template.Template
+	// Vars are what a custom template can use besides the change.
+	Vars TemplateVars
+}
+
+// TemplateVars are the git config, branch, and environment values prompt
+// templates and the [Message] footer can use, e.g. {{.Branch}} or
+// {{.Env.TICKET}}.
+type TemplateVars struct {
+	GitUserName  string
+	GitUserEmail string
+	Branch       string
+	// Env holds the GOCO_VAR_ environment variables, keyed without the
+	// prefix; an unset variable is "".
+	Env map[string]string
+}
+
+// PromptData is the value prompt templates are executed against. Status,
+// Diff, Examples, Hints, History, and Issue arrive wrapped in checksum-keyed
+// data markers; the TemplateVars do not.
+type PromptData struct {
+	Status       string
+	Diff         string
+	Instructions string
+	Examples     string
+	Hints        string
+	History      string
+	Issue        string
+	Constraints  string
+	TemplateVars
+}
+
+// templateSources maps every template ParsePromptTemplate returned to the
+// text it was parsed from.
+var templateSources sync.Map
+
+// ParsePromptTemplate parses a user-supplied prompt template. An unset
+// {{.Env.NAME}} renders empty rather than as "<no value>".
+func ParsePromptTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("prompt").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse prompt template: %w", err)
+	}
+	templateSources.Store(tmpl, text)
+	return tmpl, nil
+}
+
+// TemplateSource returns the text ParsePromptTemplate parsed tmpl from, so
+// the template can be sent elsewhere and parsed again with its definitions
+// intact.
+func TemplateSource(tmpl *template.Template) (string, bool) {
+	text, ok := templateSources.Load(tmpl)
+	if !ok {
+		return "", false
+	}
+	return text.(string), true
+}
+
+// BuildPrompt renders the prompt sent to providers for the given input.
+func BuildPrompt(in PromptInput) (string, error) {
+	spec := defaultSpec
+	if strings.TrimSpace(in.Spec) != "" {
+		spec = in.Spec
+	}
+
+	// Everything read from the repository is fenced so the model can tell
+	// data apart from instructions.
+	data := PromptData{
+		Status:       fenceData("STATUS", in.Status),
+		Diff:         fenceData("DIFF", in.Diff),
+		Instructions: in.CustomInstructions,
+		Constraints:  strings.TrimSpace(spec),
+		TemplateVars: in.Vars,
+	}
+	if strings.TrimSpace(in.RecentLog) != "" {
+		data.Examples = fenceData("LOG", in.RecentLog)
+	}
+	if strings.TrimSpace(in.TypeHints) != "" {
+		data.Hints = fenceData("HINTS", in.TypeHints)
+	}
+	if strings.TrimSpace(in.History) != "" {
+		data.History = fenceData("HISTORY", in.History)
+	}
+	if strings.TrimSpace(in.Issue) != "" {
+		data.Issue = fenceData("ISSUE", in.Issue)
+	}
+
+	tmpl := in.Template
+	if tmpl == nil {
+		tmpl = defaultTemplate
 	}
 
-	prompt := fmt.Sprintf(
-		"Generate a Conventional Commit based strictly on the following:\n\n"+
-			"Git Status:\n%s\n\n"+
-			"Git Diff:\n%s\n\n"+
-			"%s"+
-			"%s"+
-			"Before responding, you MUST:\n"+
-			"- ONLY output the commit message and description.\n"+
-			"- There must be a commit summary (one line) at the top, then an empty line, then the commit description below.\n"+
-			"- DO NOT include markdown, code blocks, quotes, or any formatting.\n"+
-			"- Output MUST be plain text only.\n"+
-			"- Do not add extra explanations, notes, or commentary.\n"+
-			"- The first line is the commit summary, the rest is the description.\n"+
-			"- Follow the specification above exactly.\n"+
-			"- No extra lines before or after the commit message.\n",
-		gitStatus,
-		gitDiff,
-		recentLogSection,
-		conventionalCommitsSpec,
-	)
-
-	if customInstructions != "" {
-		prompt += fmt.Sprintf("\nAdditional Instructions:\n%s\n", customInstructions)
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("render prompt template: %w", err)
 	}
 
-	return prompt
+	return buf.String(), nil
 }
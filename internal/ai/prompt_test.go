@@ -0,0 +1,88 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildPromptCustomTemplate(t *testing.T) {
+	tmpl, err := ParsePromptTemplate("{{.Status}}|{{.Diff}}|{{.Instructions}}|{{.Examples}}|{{.Constraints}}")
+	if err != nil {
+		t.Fatalf("parse template: %v", err)
+	}
+
+	prompt, err := BuildPrompt(PromptInput{
+		Status:             "M. main.go",
+		Diff:               "+added",
+		CustomInstructions: "be brief",
+		RecentLog:          "feat: earlier",
+		Spec:               "  custom rules  ",
+		Template:           tmpl,
+	})
+	if err != nil {
+		t.Fatalf("BuildPrompt failed: %v", err)
+	}
+
+	expected := fenceData("STATUS", "M. main.go") + "|" +
+		fenceData("DIFF", "+added") + "|be brief|" +
+		fenceData("LOG", "feat: earlier") + "|custom rules"
+	if prompt != expected {
+		t.Fatalf("expected %q, got %q", expected, prompt)
+	}
+}
+
+func TestBuildPromptTemplateVars(t *testing.T) {
+	tmpl, err := ParsePromptTemplate("{{.GitUserName}} <{{.GitUserEmail}}> on {{.Branch}}: {{.Env.TICKET}}[{{.Env.UNSET}}]")
+	if err != nil {
+		t.Fatalf("parse template: %v", err)
+	}
+
+	prompt, err := BuildPrompt(PromptInput{
+		Template: tmpl,
+		Vars: TemplateVars{
+			GitUserName:  "Ada",
+			GitUserEmail: "ada@example.com",
+			Branch:       "feature/login",
+			Env:          map[string]string{"TICKET": "PROJ-7"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("BuildPrompt failed: %v", err)
+	}
+	if expected := "Ada <ada@example.com> on feature/login: PROJ-7[]"; prompt != expected {
+		t.Fatalf("expected %q, got %q", expected, prompt)
+	}
+}
+
+func TestBuildPromptDefaultTemplate(t *testing.T) {
+	prompt, err := BuildPrompt(PromptInput{Status: "M. main.go", Diff: "+added", RecentLog: "  \n"})
+	if err != nil {
+		t.Fatalf("BuildPrompt failed: %v", err)
+	}
+
+	if !strings.Contains(prompt, "Conventional Commits specification:") {
+		t.Fatal("expected bundled spec in default prompt")
+	}
+	if strings.Contains(prompt, "Recent Commits") {
+		t.Fatal("expected blank recent log to be omitted")
+	}
+	if strings.Contains(prompt, "Additional Instructions") {
+		t.Fatal("expected instructions section to be omitted")
+	}
+	if strings.Contains(prompt, "Last Touched") {
+		t.Fatal("expected blame history section to be omitted")
+	}
+	if strings.Contains(prompt, "Issue Being Implemented") {
+		t.Fatal("expected issue section to be omitted")
+	}
+}
+
+func TestBuildPromptHistory(t *testing.T) {
+	prompt, err := BuildPrompt(PromptInput{Diff: "-old\n+new", History: "main.go:\n  abc1234 feat: add retries"})
+	if err != nil {
+		t.Fatalf("BuildPrompt failed: %v", err)
+	}
+	if !strings.Contains(prompt, "Commits That Last Touched the Changed Lines") || !strings.Contains(prompt, "abc1234 feat: add retries") {
+		t.Fatalf("expected blame history in prompt, got %q", prompt)
+	}
+}
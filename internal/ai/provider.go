@@ -8,29 +8,146 @@ import (
 )
 
 const (
-	ProviderGemini = "gemini"
-	ProviderGroq   = "groq"
+	ProviderGemini     = "gemini"
+	ProviderGroq       = "groq"
+	ProviderOpenRouter = "openrouter"
+	ProviderMistral    = "mistral"
+	ProviderAzure      = "azure"
+	ProviderBedrock    = "bedrock"
+	ProviderGrok       = "grok"
+	ProviderDeepSeek   = "deepseek"
+	ProviderCohere     = "cohere"
+	ProviderGitHub     = "github"
+	ProviderTogether   = "together"
 
-	DefaultGeminiModel = "gemini-2.5-flash"
-	DefaultGroqModel   = "llama-3.3-70b-versatile"
+	DefaultGeminiModel     = "gemini-2.5-flash"
+	DefaultGroqModel       = "llama-3.3-70b-versatile"
+	DefaultOpenRouterModel = "openai/gpt-4o-mini"
+	DefaultMistralModel    = "mistral-small-latest"
+	DefaultBedrockModel    = "anthropic.claude-3-haiku-20240307-v1:0"
+	DefaultGrokModel       = "grok-3-mini"
+	DefaultDeepSeekModel   = "deepseek-chat"
+	DefaultCohereModel     = "command-r-08-2024"
+	DefaultGitHubModel     = "openai/gpt-4.1-mini"
+	DefaultTogetherModel   = "meta-llama/Llama-3.3-70B-Instruct-Turbo"
 )
 
+// Providers lists the supported provider names.
+var Providers = []string{ProviderGemini, ProviderGroq, ProviderOpenRouter, ProviderMistral, ProviderAzure, ProviderBedrock, ProviderGrok, ProviderDeepSeek, ProviderCohere, ProviderGitHub, ProviderTogether}
+
+// Options configure the providers goco creates: how their HTTP transport
+// retries, and the backends of the providers that need more than an API key.
+type Options struct {
+	Retry   RetryPolicy     `json:"retry"`
+	Gemini  GeminiSettings  `json:"gemini"`
+	Azure   AzureSettings   `json:"azure"`
+	Bedrock BedrockSettings `json:"bedrock"`
+}
+
 type Provider interface {
 	Name() string
 	DefaultModel() string
-	GenerateCommitMessage(ctx context.Context, gitStatus, gitDiff, customInstructions, recentLog string) (string, error)
+	GenerateCommitMessage(ctx context.Context, input PromptInput) (string, error)
 	ListModels(ctx context.Context) ([]string, error)
 	ValidateModel(ctx context.Context, model string) error
 }
 
-func NewProvider(ctx context.Context, providerName, apiKey, model string) (Provider, error) {
+func NewProvider(ctx context.Context, providerName, apiKey, model string, opts Options) (Provider, error) {
+	if _, ok := compatibleProviders[providerName]; ok {
+		return NewCompatibleProvider(ctx, providerName, apiKey, model, opts.Retry)
+	}
+	switch providerName {
+	case ProviderGroq:
+		return NewGroqProvider(ctx, apiKey, withDefault(model, DefaultGroqModel), opts.Retry)
+	case ProviderGemini:
+		return NewGeminiProvider(ctx, apiKey, withDefault(model, DefaultGeminiModel), opts.Gemini, opts.Retry)
+	case ProviderOpenRouter:
+		return NewOpenRouterProvider(ctx, apiKey, withDefault(model, DefaultOpenRouterModel), opts.Retry)
+	case ProviderAzure:
+		// Azure's default is the configured deployment.
+		return NewAzureProvider(ctx, apiKey, model, opts.Azure, opts.Retry)
+	case ProviderBedrock:
+		// Bedrock signs requests with AWS credentials; apiKey is unused.
+		return NewBedrockProvider(ctx, withDefault(model, DefaultBedrockModel), opts.Bedrock, opts.Retry)
+	default:
+		return nil, fmt.Errorf("unsupported provider %q (supported: %s)", providerName, strings.Join(Providers, ", "))
+	}
+}
+
+// DefaultModelFor returns the recommended model for a provider, or "" if the
+// provider is unknown. Azure's is the deployment opts configure.
+func DefaultModelFor(providerName string, opts Options) string {
+	if spec, ok := compatibleProviders[providerName]; ok {
+		return spec.defaultModel
+	}
+	switch providerName {
+	case ProviderGroq:
+		return DefaultGroqModel
+	case ProviderGemini:
+		return DefaultGeminiModel
+	case ProviderOpenRouter:
+		return DefaultOpenRouterModel
+	case ProviderAzure:
+		return opts.Azure.Deployment
+	case ProviderBedrock:
+		return DefaultBedrockModel
+	default:
+		return ""
+	}
+}
+
+// ModelProvider returns the provider that serves model, when its name or
+// the cached models.dev registry tells, without the network. Only Gemini
+// serves gemini- models; a model several providers list is not decided.
+func ModelProvider(model string) (string, bool) {
+	if strings.HasPrefix(model, "gemini-") {
+		return ProviderGemini, true
+	}
+	var owners []string
+	for _, name := range Providers {
+		if _, ok := cachedModelInfo(name, model); ok {
+			owners = append(owners, name)
+		}
+	}
+	if len(owners) != 1 {
+		return "", false
+	}
+	return owners[0], true
+}
+
+// Endpoint returns the API base URL a provider configured by opts sends
+// requests to.
+func Endpoint(providerName string, opts Options) string {
+	if spec, ok := compatibleProviders[providerName]; ok {
+		return spec.baseURL
+	}
 	switch providerName {
 	case ProviderGroq:
-		return NewGroqProvider(ctx, apiKey, withDefault(model, DefaultGroqModel))
+		return "https://api.groq.com"
 	case ProviderGemini:
-		return NewGeminiProvider(ctx, apiKey, withDefault(model, DefaultGeminiModel))
+		return opts.Gemini.endpoint()
+	case ProviderOpenRouter:
+		return openRouterBaseURL
+	case ProviderAzure:
+		return opts.Azure.Endpoint
+	case ProviderBedrock:
+		return bedrockRuntimeURL(awsRegion(opts.Bedrock.Region, opts.Bedrock.Profile))
 	default:
-		return nil, fmt.Errorf("unsupported provider %q (supported: gemini, groq)", providerName)
+		return ""
+	}
+}
+
+// NeedsAPIKey reports whether a provider authenticates with an API key.
+// Bedrock uses the AWS credential chain instead, and Gemini on Vertex AI
+// Application Default Credentials.
+func NeedsAPIKey(providerName string, opts Options) bool {
+	switch providerName {
+	case ProviderBedrock:
+		return false
+	case ProviderGemini:
+		return !opts.Gemini.vertex()
+	default:
+		return true
 	}
 }
 
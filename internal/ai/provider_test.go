@@ -0,0 +1,48 @@
+package ai
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestModelProvider(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	modelsDevMu.Lock()
+	saved := modelsDevCache
+	modelsDevCache = map[string]json.RawMessage{
+		"google": json.RawMessage(`{"models": {"gemma-3-27b-it": {}, "shared-model": {}}}`),
+		"groq":   json.RawMessage(`{"models": {"llama-3.3-70b-versatile": {}, "shared-model": {}}}`),
+	}
+	modelsDevMu.Unlock()
+	defer func() {
+		modelsDevMu.Lock()
+		modelsDevCache = saved
+		modelsDevMu.Unlock()
+	}()
+
+	tests := []struct {
+		model  string
+		want   string
+		wantOK bool
+	}{
+		{model: "gemini-2.5-pro", want: ProviderGemini, wantOK: true},
+		{model: "gemma-3-27b-it", want: ProviderGemini, wantOK: true},
+		{model: "llama-3.3-70b-versatile", want: ProviderGroq, wantOK: true},
+		{model: "shared-model"},
+		{model: "unknown-model"},
+	}
+	for _, tt := range tests {
+		if got, ok := ModelProvider(tt.model); got != tt.want || ok != tt.wantOK {
+			t.Errorf("ModelProvider(%q) = %q, %v; want %q, %v", tt.model, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestNeedsAPIKey(t *testing.T) {
+	if !NeedsAPIKey(ProviderGemini, Options{}) || !NeedsAPIKey(ProviderGroq, Options{}) || NeedsAPIKey(ProviderBedrock, Options{}) {
+		t.Error("expected only Bedrock to need no key by default")
+	}
+	if NeedsAPIKey(ProviderGemini, Options{Gemini: GeminiSettings{Backend: GeminiBackendVertex}}) {
+		t.Error("expected Gemini on Vertex AI to need no key")
+	}
+}
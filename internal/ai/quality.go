@@ -0,0 +1,139 @@
+package ai
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// qualityPart is the most each of the four quality measures contributes, so
+// a perfect message scores 100.
+const qualityPart = 25
+
+// Quality scores a commit message locally, without asking the model.
+type Quality struct {
+	// Specificity penalizes vague descriptions such as "update stuff".
+	Specificity int
+	// Length rewards a description long enough to say something and a
+	// subject within MaxSubjectLength.
+	Length int
+	// Mood rewards an imperative subject.
+	Mood int
+	// Coverage is the share of changed files the message names, by file or
+	// directory.
+	Coverage int
+	// Problems says what lost points, as instructions for a rewrite.
+	Problems []string
+}
+
+// Score is the total quality, from 0 to 100.
+func (q Quality) Score() int {
+	return q.Specificity + q.Length + q.Mood + q.Coverage
+}
+
+// Feedback asks the model to rewrite msg without the problems that lowered
+// its score.
+func (q Quality) Feedback(msg string) string {
+	return fmt.Sprintf("Your previous message scored %d/100 on quality. Write a better one. %s\nPrevious message:\n%s",
+		q.Score(), strings.Join(q.Problems, " "), msg)
+}
+
+// vagueWords describe a change without saying what it is.
+var vagueWords = wordSet(`bug bugs changes code various file files issue issues minor misc
+	miscellaneous some stuff thing things tweaks wip`)
+
+// minDescription is the description length below which a subject loses
+// Length points.
+const minDescription = 20
+
+// ScoreMessage scores msg as a description of changes to paths. Unless
+// imperative is set, any mood scores full marks.
+func ScoreMessage(msg string, paths []string, imperative bool) Quality {
+	var q Quality
+	subject, _, _ := strings.Cut(strings.TrimSpace(msg), "\n")
+	description := subject
+	if m := subjectPrefix.FindStringSubmatch(subject); m != nil {
+		description = strings.TrimPrefix(subject, m[1])
+	}
+	words := strings.Fields(strings.ToLower(description))
+
+	q.Specificity = qualityPart
+	var vague []string
+	for _, w := range words {
+		if w = strings.Trim(w, `"'.,;:()`); vagueWords[w] {
+			vague = append(vague, w)
+		}
+	}
+	if len(vague) > 0 {
+		q.Specificity -= 10 * len(vague)
+		q.Problems = append(q.Problems, fmt.Sprintf("Replace vague words (%s) with what actually changed.", strings.Join(vague, ", ")))
+	}
+	if len(words) < 3 {
+		q.Specificity -= 10
+		q.Problems = append(q.Problems, "Describe the change in more than two words.")
+	}
+	q.Specificity = max(q.Specificity, 0)
+
+	switch {
+	case SubjectLength(subject) > MaxSubjectLength:
+		q.Problems = append(q.Problems, fmt.Sprintf("Keep the subject within %d characters.", MaxSubjectLength))
+	case len(description) < minDescription:
+		q.Length = qualityPart * len(description) / minDescription
+		q.Problems = append(q.Problems, "Make the subject say what changed and why it matters, not just name it.")
+	default:
+		q.Length = qualityPart
+	}
+
+	switch mood := SubjectMood(subject); {
+	case mood == "imperative" || !imperative:
+		q.Mood = qualityPart
+	case mood == "":
+		// An unknown verb is not necessarily wrong.
+		q.Mood = qualityPart * 3 / 5
+	default:
+		q.Problems = append(q.Problems, `Write the subject in the imperative mood: "add", not "added" or "adds".`)
+	}
+
+	q.Coverage = qualityPart
+	if missed := uncovered(msg, paths); len(missed) > 0 {
+		q.Coverage = qualityPart * (len(paths) - len(missed)) / len(paths)
+		q.Problems = append(q.Problems, "Mention what changed in "+strings.Join(limitList(missed, 5), ", ")+".")
+	}
+	return q
+}
+
+// uncovered returns the paths none of whose file or directory name words
+// appear as words in msg.
+func uncovered(msg string, paths []string) []string {
+	words := make(map[string]bool)
+	for _, w := range nameWords(msg) {
+		words[w] = true
+	}
+	var missed []string
+	for _, p := range paths {
+		file := path.Base(p)
+		names := nameWords(strings.TrimSuffix(file, path.Ext(file)) + " " + path.Base(path.Dir(p)))
+		if !slices.ContainsFunc(names, func(w string) bool { return words[w] }) {
+			missed = append(missed, p)
+		}
+	}
+	return missed
+}
+
+// nameWords splits s into lowercase words at anything but letters and
+// digits, so "route_test" is "route" and "test".
+func nameWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// limitList shortens items to n, noting how many were left out.
+func limitList(items []string, n int) []string {
+	if len(items) <= n {
+		return items
+	}
+	return append(items[:n:n], fmt.Sprintf("%d more", len(items)-n))
+}
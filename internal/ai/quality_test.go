@@ -0,0 +1,72 @@
+package ai
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestScoreMessage(t *testing.T) {
+	tests := []struct {
+		name       string
+		msg        string
+		paths      []string
+		imperative bool
+		want       Quality
+		problem    string
+	}{
+		{
+			name:       "specific",
+			msg:        "feat(config): add routes by repository remote\n\nBody.",
+			paths:      []string{"internal/config/route.go", "README.md"},
+			imperative: true,
+			want:       Quality{Specificity: 25, Length: 25, Mood: 25, Coverage: 12},
+			problem:    "Mention what changed in README.md.",
+		},
+		{
+			name:       "vague",
+			msg:        "fix: stuff",
+			paths:      []string{"main.go"},
+			imperative: true,
+			want:       Quality{Specificity: 5, Length: 6, Mood: 15},
+			problem:    "Replace vague words (stuff)",
+		},
+		{
+			name:       "past tense",
+			msg:        "fix(route): added matching of remote patterns",
+			paths:      []string{"internal/config/route.go"},
+			imperative: true,
+			want:       Quality{Specificity: 25, Length: 25, Coverage: 25},
+			problem:    "imperative mood",
+		},
+		{
+			name:    "past tense allowed",
+			msg:     "fix(route): added matching of remote patterns",
+			paths:   []string{"internal/config/route.go"},
+			want:    Quality{Specificity: 25, Length: 25, Mood: 25, Coverage: 25},
+			problem: "",
+		},
+		{
+			name:       "overlong",
+			msg:        "feat(cli): " + strings.Repeat("add ", 20),
+			paths:      []string{"internal/cli/root.go"},
+			imperative: true,
+			want:       Quality{Specificity: 25, Mood: 25, Coverage: 25},
+			problem:    "within 72 characters",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ScoreMessage(tt.msg, tt.paths, tt.imperative)
+			problems := strings.Join(got.Problems, " ")
+			got.Problems = nil
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+			if tt.problem == "" && problems != "" || !strings.Contains(problems, tt.problem) {
+				t.Errorf("problems %q, want %q", problems, tt.problem)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package ai
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// refineMessageLine opens the corrected message in a refinement reply.
+var refineMessageLine = regexp.MustCompile(`(?m)^Message:[ \t]*$`)
+
+// ParseRefinement splits a reply to RefineTemplate into the model's critique
+// and its corrected message.
+func ParseRefinement(reply string) (critique, message string, err error) {
+	loc := refineMessageLine.FindStringIndex(reply)
+	if loc == nil {
+		return "", "", errors.New(`refinement has no "Message:" line`)
+	}
+	critique = strings.TrimSpace(reply[:loc[0]])
+	critique = strings.TrimSpace(strings.TrimPrefix(critique, "Critique:"))
+	message = strings.TrimSpace(reply[loc[1]:])
+	if message == "" {
+		return "", "", errors.New("refinement has an empty message")
+	}
+	return critique, message, nil
+}
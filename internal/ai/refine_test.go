@@ -0,0 +1,40 @@
+package ai
+
+import "testing"
+
+func TestParseRefinement(t *testing.T) {
+	tests := []struct {
+		name         string
+		reply        string
+		wantCritique string
+		wantMessage  string
+		wantErr      bool
+	}{
+		{
+			name:         "corrected",
+			reply:        "Critique:\n- the subject says fix, but the diff adds a flag\n\nMessage:\nfeat(cli): add --refine\n\nBody.\n",
+			wantCritique: "- the subject says fix, but the diff adds a flag",
+			wantMessage:  "feat(cli): add --refine\n\nBody.",
+		},
+		{
+			name:         "no problems",
+			reply:        "Critique:\n- none\nMessage:\nfix: handle nil config",
+			wantCritique: "- none",
+			wantMessage:  "fix: handle nil config",
+		},
+		{name: "no message line", reply: "feat: add --refine", wantErr: true},
+		{name: "empty message", reply: "Critique:\n- none\nMessage:\n\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			critique, message, err := ParseRefinement(tt.reply)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if critique != tt.wantCritique || message != tt.wantMessage {
+				t.Errorf("got %q, %q; want %q, %q", critique, message, tt.wantCritique, tt.wantMessage)
+			}
+		})
+	}
+}
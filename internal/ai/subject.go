@@ -0,0 +1,223 @@
+package ai
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxSubjectLength is the longest commit subject goco accepts.
+const MaxSubjectLength = 72
+
+// SubjectLength counts the characters of subject rather than its bytes, so a
+// subject in Japanese or with an emoji is held to the same limit as one in
+// English.
+func SubjectLength(subject string) int {
+	return utf8.RuneCountInString(subject)
+}
+
+// SplitLongSubject repairs a message whose first line is a paragraph: the
+// first sentence that fits MaxSubjectLength becomes the subject and the rest
+// of the line opens the body. A clause break is not enough: the rest of the
+// sentence would open the body mid-thought. Messages it cannot repair are
+// returned unchanged so validation can report them.
+func SplitLongSubject(msg string) string {
+	subject, body, _ := strings.Cut(msg, "\n")
+	subject = strings.TrimSpace(subject)
+	if SubjectLength(subject) <= MaxSubjectLength {
+		return msg
+	}
+
+	cut := sentenceBreak(subject)
+	if cut < 0 {
+		return msg
+	}
+
+	head := strings.TrimRight(subject[:cut], ".!? ")
+	rest := strings.TrimSpace(subject[cut:])
+	body = strings.TrimLeft(body, "\n")
+	if body != "" {
+		rest += "\n\n" + body
+	}
+	return head + "\n\n" + rest
+}
+
+// sentenceBreak returns the index just past the first sentence end (".",
+// "!", or "?" followed by a space) that leaves a subject within
+// MaxSubjectLength, or -1. Abbreviations like "e.g." are skipped so they are
+// not mistaken for sentence ends, and the break must come after the
+// Conventional Commit "type: " prefix.
+func sentenceBreak(subject string) int {
+	start := strings.Index(subject, ": ") + 2 // 1 when there is no prefix
+	for i := start; i < len(subject)-1 && SubjectLength(subject[:i]) <= MaxSubjectLength; i++ {
+		if !strings.ContainsRune(".!?", rune(subject[i])) || subject[i+1] != ' ' {
+			continue
+		}
+		if isAbbreviation(subject[:i+1]) {
+			continue
+		}
+		return i + 1
+	}
+	return -1
+}
+
+// isAbbreviation reports whether text ends in a dotted word such as "e.g."
+// or "v1.2.".
+func isAbbreviation(text string) bool {
+	word := text[strings.LastIndex(text, " ")+1:]
+	return strings.Count(word, ".") > 1
+}
+
+// subjectPrefix splits a subject into its Conventional Commit "type(scope)!: "
+// prefix, first word, and remainder.
+var subjectPrefix = regexp.MustCompile(`^(\w+(?:\([^)]*\))?!?: )?([A-Za-z]+)(.*)$`)
+
+// imperativeVerbs are the verbs commit subjects usually open with. Their
+// past-tense, third-person, and gerund forms are derived by inflect.
+var imperativeVerbs = strings.Fields(`add adjust allow apply avoid bump cache change check clarify clean
+	commit configure convert correct create define delete deprecate disable document drop emit enable
+	ensure expand expose extract fetch fix format handle ignore implement improve include increase
+	inline install introduce limit load log map mark merge migrate move normalize optimize parse pass
+	pin prefer prevent print reduce refactor release remove rename reorder replace report require
+	resolve restore retry return reuse revert rework sanitize save simplify skip sort stop store strip
+	support switch tag test tidy tighten track trim tweak unify update upgrade use validate wire wrap`)
+
+// irregularVerbs maps inflections the suffix rules get wrong to their base form.
+var irregularVerbs = map[string]string{
+	"built": "build", "made": "make", "wrote": "write", "written": "write", "rewrote": "rewrite",
+	"rewritten": "rewrite", "ran": "run", "hid": "hide", "hidden": "hide", "sent": "send",
+	"threw": "throw", "thrown": "throw", "shown": "show", "splits": "split",
+	"splitting": "split", "running": "run", "builds": "build", "building": "build", "makes": "make",
+	"making": "make", "writes": "write", "writing": "write", "rewrites": "rewrite", "sets": "set",
+	"setting": "set", "shows": "show", "showed": "show", "showing": "show",
+}
+
+// doubledVerbs double their final consonant before -ed and -ing.
+var doubledVerbs = map[string]bool{
+	"commit": true, "drop": true, "emit": true, "log": true, "map": true, "pin": true,
+	"skip": true, "stop": true, "strip": true, "tag": true, "trim": true, "wrap": true,
+}
+
+// nonImperative maps every known non-imperative verb form to its base.
+var nonImperative = func() map[string]string {
+	forms := make(map[string]string, len(irregularVerbs)+3*len(imperativeVerbs))
+	for _, verb := range imperativeVerbs {
+		for _, form := range inflect(verb) {
+			forms[form] = verb
+		}
+	}
+	for form, verb := range irregularVerbs {
+		forms[form] = verb
+	}
+	return forms
+}()
+
+// inflect returns the third-person, past-tense, and gerund forms of verb.
+func inflect(verb string) []string {
+	last := verb[len(verb)-1]
+	consonantY := last == 'y' && !strings.ContainsRune("aeiou", rune(verb[len(verb)-2]))
+
+	third := verb + "s"
+	switch {
+	case consonantY:
+		third = verb[:len(verb)-1] + "ies"
+	case strings.HasSuffix(verb, "s") || strings.HasSuffix(verb, "x") || strings.HasSuffix(verb, "ch") || strings.HasSuffix(verb, "sh"):
+		third = verb + "es"
+	}
+
+	stem := verb
+	switch {
+	case doubledVerbs[verb]:
+		stem = verb + string(last)
+	case last == 'e':
+		stem = verb[:len(verb)-1]
+	}
+	past := stem + "ed"
+	if consonantY {
+		past = verb[:len(verb)-1] + "ied"
+	}
+	return []string{third, past, stem + "ing"}
+}
+
+// objectStarters open a noun phrase, so a gerund before one is acting as a
+// verb: "adding the exporter", not "caching layer".
+var objectStarters = wordSet(`a all an any each every its my new our some that the their these this those`)
+
+// connectives follow plural nouns that look like verbs: "tests for the
+// parser", "updates to the readme".
+var connectives = wordSet(`about across after and as at before by for from in into of on or over to via when with`)
+
+func wordSet(words string) map[string]bool {
+	m := make(map[string]bool)
+	for _, w := range strings.Fields(words) {
+		m[w] = true
+	}
+	return m
+}
+
+// ImperativeSubject rewrites a subject that opens with a known verb in the
+// past tense, third person, or gerund ("added", "adds", "adding") to the
+// imperative ("add"), keeping its capitalization. The -s and -ing forms are
+// also nouns ("tests", "logging"), so they are only rewritten when the next
+// word reads as the verb's object. It reports whether the message changed;
+// unknown verbs are left alone rather than guessed at.
+func ImperativeSubject(msg string) (string, bool) {
+	subject, rest, hasRest := strings.Cut(msg, "\n")
+	m := subjectPrefix.FindStringSubmatch(subject)
+	if m == nil {
+		return msg, false
+	}
+	word := m[2]
+	base, ok := nonImperative[strings.ToLower(word)]
+	if !ok || !takesObject(strings.ToLower(word), base, m[3]) {
+		return msg, false
+	}
+	if word[0] >= 'A' && word[0] <= 'Z' {
+		base = strings.ToUpper(base[:1]) + base[1:]
+	}
+	subject = m[1] + base + m[3]
+	if hasRest {
+		return subject + "\n" + rest, true
+	}
+	return subject, true
+}
+
+// takesObject reports whether form, an inflection of base, is used as a
+// verb given the rest of the subject after it. Past tenses always are.
+func takesObject(form, base, rest string) bool {
+	next, _, _ := strings.Cut(strings.TrimSpace(rest), " ")
+	next = strings.ToLower(strings.Trim(next, `"'.,;:()`))
+	switch {
+	case strings.HasSuffix(form, "ing"):
+		return objectStarters[next]
+	case strings.HasSuffix(form, "s") && form != base:
+		return next != "" && !connectives[next]
+	default:
+		return true
+	}
+}
+
+// SubjectMood classifies the verb opening subject as "imperative", "past",
+// "third-person", or "gerund"; "" when it does not open with a known verb.
+func SubjectMood(subject string) string {
+	m := subjectPrefix.FindStringSubmatch(strings.TrimSpace(subject))
+	if m == nil {
+		return ""
+	}
+	word := strings.ToLower(m[2])
+	base, ok := nonImperative[word]
+	switch {
+	case !ok:
+		if slices.Contains(imperativeVerbs, word) {
+			return "imperative"
+		}
+		return ""
+	case strings.HasSuffix(word, "ing"):
+		return "gerund"
+	case strings.HasSuffix(word, "s") && word != base:
+		return "third-person"
+	default:
+		return "past"
+	}
+}
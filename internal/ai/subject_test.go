@@ -0,0 +1,139 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSplitLongSubject(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"short subject untouched", "fix: handle nil config\n\nBody.", "fix: handle nil config\n\nBody."},
+		{
+			"split at first sentence",
+			"feat(auth): add OAuth login for GitHub accounts. This replaces the legacy token flow and keeps sessions.\n\nMore detail.",
+			"feat(auth): add OAuth login for GitHub accounts\n\nThis replaces the legacy token flow and keeps sessions.\n\nMore detail.",
+		},
+		{
+			"abbreviation is not a sentence end",
+			"docs: explain providers, e.g. Gemini and Groq, in the README. Also document the config file layout in detail.",
+			"docs: explain providers, e.g. Gemini and Groq, in the README\n\nAlso document the config file layout in detail.",
+		},
+		{
+			"clause break is not a sentence end",
+			"refactor: move the git helpers into their own package, which keeps the CLI layer thin and focused on wiring",
+			"refactor: move the git helpers into their own package, which keeps the CLI layer thin and focused on wiring",
+		},
+		{
+			"semicolon is not a sentence end",
+			"fix: retry transient provider errors with backoff; give up after three attempts and report the last error",
+			"fix: retry transient provider errors with backoff; give up after three attempts and report the last error",
+		},
+		{
+			// 40 characters, but 110 bytes.
+			"characters are counted, not bytes",
+			"feat(i18n): ログイン画面の入力検証を追加し、エラーメッセージを日本語で表示する 🎉",
+			"feat(i18n): ログイン画面の入力検証を追加し、エラーメッセージを日本語で表示する 🎉",
+		},
+		{
+			"no break leaves message for validation",
+			"chore: " + strings.Repeat("word", 20),
+			"chore: " + strings.Repeat("word", 20),
+		},
+	}
+	for _, tt := range tests {
+		if got := SplitLongSubject(tt.in); got != tt.want {
+			t.Errorf("%s: SplitLongSubject() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestImperativeSubject(t *testing.T) {
+	tests := []struct {
+		in, want string
+		changed  bool
+	}{
+		{"feat(auth): added OAuth login\n\nBody.", "feat(auth): add OAuth login\n\nBody.", true},
+		{"fix: Fixes nil config", "fix: Fix nil config", true},
+		{"refactor!: moving the helpers", "refactor!: move the helpers", true},
+		{"chore: bumped deps", "chore: bump deps", true},
+		{"docs: clarifies install steps", "docs: clarify install steps", true},
+		{"perf: dropped allocations", "perf: drop allocations", true},
+		{"feat: wrote the exporter", "feat: write the exporter", true},
+		{"Updated README", "Update README", true},
+		{"fix: handle nil config", "fix: handle nil config", false},
+		{"feat: frobnicated widgets", "feat: frobnicated widgets", false},
+		{"feat(cli): logging improvements", "feat(cli): logging improvements", false},
+		{"feat: caching layer", "feat: caching layer", false},
+		{"test: tests for the parser", "test: tests for the parser", false},
+		{"docs: updates to the readme", "docs: updates to the readme", false},
+		{"refactor: moving helpers", "refactor: moving helpers", false},
+		{"chore: updates", "chore: updates", false},
+	}
+	for _, tt := range tests {
+		got, changed := ImperativeSubject(tt.in)
+		if got != tt.want || changed != tt.changed {
+			t.Errorf("ImperativeSubject(%q) = %q, %v; want %q, %v", tt.in, got, changed, tt.want, tt.changed)
+		}
+	}
+}
+
+func TestSubjectMood(t *testing.T) {
+	tests := map[string]string{
+		"feat(auth): add OAuth login": "imperative",
+		"Added OAuth login":           "past",
+		"fix: wrote the exporter":     "past",
+		"fix: fixes nil config":       "third-person",
+		"refactor: moving helpers":    "gerund",
+		"chore: deps":                 "",
+		"🎨 tidy imports":              "",
+	}
+	for subject, want := range tests {
+		if got := SubjectMood(subject); got != want {
+			t.Errorf("SubjectMood(%q) = %q, want %q", subject, got, want)
+		}
+	}
+}
+
+func FuzzSplitLongSubject(f *testing.F) {
+	f.Add("feat: add retries. The client now retries transient failures with backoff so flaky networks stop failing commits")
+	f.Add("fix: handle e.g. empty input. More text follows here to push the subject well past the limit of the line")
+	f.Add("docs: short subject\n\nbody")
+	f.Add(strings.Repeat("a. ", 40))
+	f.Fuzz(func(t *testing.T, msg string) {
+		got := SplitLongSubject(msg)
+		if got == msg {
+			return
+		}
+		subject, _, _ := strings.Cut(got, "\n")
+		if len(strings.TrimSpace(subject)) > MaxSubjectLength {
+			t.Fatalf("split subject is %d bytes: %q", len(subject), subject)
+		}
+		if again := SplitLongSubject(got); again != got {
+			t.Fatalf("SplitLongSubject is not idempotent:\n%q\n%q", got, again)
+		}
+	})
+}
+
+func FuzzImperativeSubject(f *testing.F) {
+	f.Add("feat: added retries to the client\n\nbody")
+	f.Add("Adds tests for the parser")
+	f.Add("fix(ui): adding logging")
+	f.Add("refactor: tests pass")
+	f.Fuzz(func(t *testing.T, msg string) {
+		got, changed := ImperativeSubject(msg)
+		if changed == (got == msg) {
+			t.Fatalf("ImperativeSubject(%q) = %q, changed %v", msg, got, changed)
+		}
+		_, body, _ := strings.Cut(msg, "\n")
+		_, gotBody, _ := strings.Cut(got, "\n")
+		if body != gotBody {
+			t.Fatalf("body changed: %q -> %q", body, gotBody)
+		}
+		if utf8.ValidString(msg) && !utf8.ValidString(got) {
+			t.Fatalf("ImperativeSubject(%q) = %q, not valid UTF-8", msg, got)
+		}
+	})
+}
@@ -0,0 +1,119 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy is how the HTTP transport under every provider and the
+// models.dev registry retries failed requests. It is separate from the
+// pipeline's generation retries, and never sends a generation the provider
+// may have received twice, so a flaky network cannot double-bill it.
+type RetryPolicy struct {
+	// Connect is how often a request whose connection could not be made is
+	// sent again. The server never saw it, so this is safe for any request.
+	Connect int `json:"connect"`
+	// Idempotent is how often a GET, such as a model list, is sent again
+	// after a dropped connection or a 429 or 5xx response.
+	Idempotent int `json:"idempotent"`
+}
+
+// DefaultRetryPolicy retries each kind of failure twice.
+var DefaultRetryPolicy = RetryPolicy{Connect: 2, Idempotent: 2}
+
+// retryBackoff is the wait before the first transport retry; it doubles
+// for each one after.
+var retryBackoff = 250 * time.Millisecond
+
+// httpClient returns a client that retries as policy says.
+func httpClient(policy RetryPolicy) *http.Client {
+	return &http.Client{Transport: &retryTransport{base: http.DefaultTransport, policy: policy}}
+}
+
+type retryTransport struct {
+	base   http.RoundTripper
+	policy RetryPolicy
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	idempotent := req.Method == http.MethodGet || req.Method == http.MethodHead
+	var connects, others int
+	for attempt := 0; ; attempt++ {
+		sent := req
+		if attempt > 0 {
+			var err error
+			if sent, err = rewound(req); err != nil {
+				return nil, err
+			}
+		}
+		resp, err := t.base.RoundTrip(sent)
+
+		var retry bool
+		switch {
+		case err != nil && isConnectError(err):
+			connects++
+			retry = connects <= t.policy.Connect
+		case err != nil:
+			others++
+			retry = idempotent && others <= t.policy.Idempotent
+		case idempotent && retryableStatus(resp.StatusCode):
+			others++
+			retry = others <= t.policy.Idempotent
+		}
+		if !retry || req.Context().Err() != nil {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		if err := sleepCtx(req.Context(), retryBackoff<<attempt); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// rewound returns a copy of req, with its body from the start, to send
+// again.
+func rewound(req *http.Request) (*http.Request, error) {
+	r := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return r, nil
+	}
+	if req.GetBody == nil {
+		return nil, errors.New("request body cannot be sent again")
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	r.Body = body
+	return r, nil
+}
+
+// isConnectError reports whether err happened before the request was
+// sent: resolving the host or dialing it failed.
+func isConnectError(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code == http.StatusBadGateway ||
+		code == http.StatusServiceUnavailable || code == http.StatusGatewayTimeout
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,94 @@
+package ai
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRetryTransport(t *testing.T) {
+	saved := retryBackoff
+	retryBackoff = 0
+	t.Cleanup(func() { retryBackoff = saved })
+
+	var hits atomic.Int32
+	flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if hits.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(body)
+	}))
+	defer flaky.Close()
+
+	// A port nothing listens on fails to connect.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed := "http://" + ln.Addr().String()
+	ln.Close()
+
+	client := &http.Client{Transport: &retryTransport{base: http.DefaultTransport, policy: RetryPolicy{Connect: 1, Idempotent: 1}}}
+
+	t.Run("GET retried after a 503", func(t *testing.T) {
+		hits.Store(0)
+		resp, err := client.Get(flaky.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || hits.Load() != 2 {
+			t.Fatalf("status %d after %d requests, want 200 after 2", resp.StatusCode, hits.Load())
+		}
+	})
+
+	t.Run("POST not resent after a response", func(t *testing.T) {
+		hits.Store(0)
+		resp, err := client.Post(flaky.URL, "text/plain", strings.NewReader("prompt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusServiceUnavailable || hits.Load() != 1 {
+			t.Fatalf("status %d after %d requests, want the 503 from one", resp.StatusCode, hits.Load())
+		}
+	})
+
+	t.Run("POST retried when the connection fails", func(t *testing.T) {
+		var dials atomic.Int32
+		client := &http.Client{Transport: &retryTransport{base: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			if dials.Add(1) == 1 {
+				return http.DefaultTransport.RoundTrip(r)
+			}
+			r.URL.Host = strings.TrimPrefix(flaky.URL, "http://")
+			return http.DefaultTransport.RoundTrip(r)
+		}), policy: RetryPolicy{Connect: 1}}}
+		hits.Store(1)
+		resp, err := client.Post(closed, "text/plain", strings.NewReader("prompt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if dials.Load() != 2 || string(body) != "prompt" {
+			t.Fatalf("%d attempts returned %q, want the rewound body from the second", dials.Load(), body)
+		}
+	})
+
+	t.Run("no retries", func(t *testing.T) {
+		client := &http.Client{Transport: &retryTransport{base: http.DefaultTransport}}
+		if _, err := client.Get(closed); err == nil || !isConnectError(err) {
+			t.Fatalf("expected the connect error, got %v", err)
+		}
+	})
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
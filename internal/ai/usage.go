@@ -0,0 +1,90 @@
+package ai
+
+import (
+	"context"
+	"sync"
+)
+
+// Usage is the tokens provider requests consumed, as the provider reported
+// them.
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+// Total returns the prompt and completion tokens together.
+func (u Usage) Total() int {
+	return u.PromptTokens + u.CompletionTokens
+}
+
+// UsageMeter totals the usage that providers report for requests made with a
+// context from WithUsageMeter. It is safe for concurrent use.
+type UsageMeter struct {
+	mu       sync.Mutex
+	usage    Usage
+	reported bool
+}
+
+// Record adds u to the total.
+func (m *UsageMeter) Record(u Usage) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.usage.PromptTokens += u.PromptTokens
+	m.usage.CompletionTokens += u.CompletionTokens
+	m.reported = true
+}
+
+// Usage returns the total, and false when no provider reported any.
+func (m *UsageMeter) Usage() (Usage, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.usage, m.reported
+}
+
+type usageMeterKey struct{}
+
+// WithUsageMeter returns a context whose provider requests report their
+// usage to m.
+func WithUsageMeter(ctx context.Context, m *UsageMeter) context.Context {
+	return context.WithValue(ctx, usageMeterKey{}, m)
+}
+
+// RecordUsage reports u to the meter in ctx, if any. Providers call it for
+// each response that states its usage.
+func RecordUsage(ctx context.Context, u Usage) {
+	if m, ok := ctx.Value(usageMeterKey{}).(*UsageMeter); ok {
+		m.Record(u)
+	}
+}
+
+// Price is what a model charges, in US dollars per million tokens.
+type Price struct {
+	Input  float64
+	Output float64
+}
+
+// ModelPrice returns model's price from the last OpenRouter listing or the
+// cached models.dev registry, and false when neither lists one. Like
+// ContextWindow, it never reads the network.
+func ModelPrice(providerName, model string) (Price, bool) {
+	if providerName == ProviderOpenRouter {
+		if m, ok := cachedOpenRouterModel(model); ok {
+			return m.price()
+		}
+	}
+	info, ok := cachedModelInfo(providerName, model)
+	if !ok || (info.Cost.Input == 0 && info.Cost.Output == 0) {
+		return Price{}, false
+	}
+	return Price{Input: info.Cost.Input, Output: info.Cost.Output}, true
+}
+
+// Cost returns what usage cost in US dollars at model's price, and false
+// when none is known.
+func Cost(providerName, model string, u Usage) (float64, bool) {
+	price, ok := ModelPrice(providerName, model)
+	if !ok {
+		return 0, false
+	}
+	return (float64(u.PromptTokens)*price.Input + float64(u.CompletionTokens)*price.Output) / 1e6, true
+}
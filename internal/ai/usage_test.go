@@ -0,0 +1,57 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+)
+
+func TestUsageMeter(t *testing.T) {
+	var m UsageMeter
+	if _, ok := m.Usage(); ok {
+		t.Fatal("an unused meter reported usage")
+	}
+	// Requests without a meter are not counted anywhere.
+	RecordUsage(context.Background(), Usage{PromptTokens: 1})
+
+	ctx := WithUsageMeter(context.Background(), &m)
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() { RecordUsage(ctx, Usage{PromptTokens: 100, CompletionTokens: 5}) })
+	}
+	wg.Wait()
+	if u, ok := m.Usage(); !ok || u.PromptTokens != 1000 || u.CompletionTokens != 50 || u.Total() != 1050 {
+		t.Fatalf("Usage() = %+v, %v", u, ok)
+	}
+}
+
+func TestCost(t *testing.T) {
+	modelsDevMu.Lock()
+	saved := modelsDevCache
+	modelsDevCache = map[string]json.RawMessage{
+		"groq": json.RawMessage(`{"models": {
+			"priced": {"cost": {"input": 0.5, "output": 2}},
+			"free": {"limit": {"context": 8192}}
+		}}`),
+	}
+	modelsDevMu.Unlock()
+	t.Cleanup(func() {
+		modelsDevMu.Lock()
+		modelsDevCache = saved
+		modelsDevMu.Unlock()
+	})
+
+	u := Usage{PromptTokens: 2_000_000, CompletionTokens: 1_000_000}
+	if cost, ok := Cost(ProviderGroq, "priced", u); !ok || cost != 3 {
+		t.Errorf("Cost(priced) = %v, %v; want 3", cost, ok)
+	}
+	for _, model := range []string{"free", "unlisted"} {
+		if _, ok := Cost(ProviderGroq, model, u); ok {
+			t.Errorf("Cost(%s) has a price", model)
+		}
+	}
+	if got := ContextWindow(ProviderGroq, "free"); got != 8192 {
+		t.Errorf("ContextWindow(free) = %d, want 8192", got)
+	}
+}
@@ -0,0 +1,73 @@
+package ai
+
+import (
+	"encoding/json"
+)
+
+// Context windows assumed when neither the config nor the cached models.dev
+// registry knows the model's.
+const (
+	defaultGeminiContextWindow = 1 << 20
+	defaultGroqContextWindow   = 128 << 10
+	// MinContextWindow is smaller than any supported model's window; a
+	// prompt under it needs no lookup.
+	MinContextWindow = 8 << 10
+)
+
+// ReplyTokens is how much of the context window is left for the reply.
+const ReplyTokens = 1024
+
+// ContextWindow returns how many tokens model accepts. It reads the last
+// OpenRouter listing and models.dev from memory or the disk cache only,
+// never the network, and falls back to the window of the provider's default
+// model.
+func ContextWindow(providerName, model string) int {
+	if m, ok := cachedOpenRouterModel(model); ok && providerName == ProviderOpenRouter && m.ContextLength > 0 {
+		return m.ContextLength
+	}
+	if info, ok := cachedModelInfo(providerName, model); ok && info.Limit.Context > 0 {
+		return info.Limit.Context
+	}
+	switch providerName {
+	case ProviderGemini:
+		return defaultGeminiContextWindow
+	default:
+		return defaultGroqContextWindow
+	}
+}
+
+// modelInfo is the part of a models.dev model entry goco reads.
+type modelInfo struct {
+	Limit struct {
+		Context int `json:"context"`
+	} `json:"limit"`
+	// Cost is in US dollars per million tokens.
+	Cost struct {
+		Input  float64 `json:"input"`
+		Output float64 `json:"output"`
+	} `json:"cost"`
+}
+
+// cachedModelInfo looks model up in the models.dev registry held in memory
+// or on disk, without fetching it.
+func cachedModelInfo(providerName, model string) (modelInfo, bool) {
+	mdevID, ok := providerToModelsDev[providerName]
+	if !ok {
+		return modelInfo{}, false
+	}
+	modelsDevMu.RLock()
+	data := modelsDevCache
+	modelsDevMu.RUnlock()
+	if data == nil {
+		data = loadModelsDevDiskCache()
+	}
+
+	var providerData struct {
+		Models map[string]modelInfo `json:"models"`
+	}
+	if raw, ok := data[mdevID]; !ok || json.Unmarshal(raw, &providerData) != nil {
+		return modelInfo{}, false
+	}
+	info, ok := providerData.Models[model]
+	return info, ok
+}
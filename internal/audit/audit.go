@@ -0,0 +1,100 @@
+// Package audit writes an append-only JSONL record of what goco sent to which
+// AI provider. Only hashes of the prompt and response are stored.
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"time"
+
+	"github.com/razobeckett/goco/internal/state"
+)
+
+// Entry is a single audit record.
+type Entry struct {
+	Time          time.Time `json:"time"`
+	Event         string    `json:"event"`
+	User          string    `json:"user"`
+	Repo          string    `json:"repo"`
+	Provider      string    `json:"provider"`
+	Model         string    `json:"model"`
+	PromptSHA256  string    `json:"prompt_sha256"`
+	PromptBytes   int       `json:"prompt_bytes"`
+	MessageSHA256 string    `json:"message_sha256,omitempty"`
+	// PromptTokens and CompletionTokens are the usage the provider reported.
+	PromptTokens     int    `json:"prompt_tokens,omitempty"`
+	CompletionTokens int    `json:"completion_tokens,omitempty"`
+	Error            string `json:"error,omitempty"`
+}
+
+// Log appends entries to a JSONL file.
+type Log struct {
+	path string
+}
+
+// Open returns a log writing to path, or nil when path is empty. A nil *Log
+// discards entries, so callers need not check whether auditing is enabled.
+func Open(path string) *Log {
+	if path == "" {
+		return nil
+	}
+	return &Log{path: path}
+}
+
+// DefaultPath returns $XDG_STATE_HOME/goco/audit.jsonl.
+func DefaultPath() string {
+	return state.Path("audit.jsonl")
+}
+
+// Append writes e as one line. Time and User are filled in when empty.
+func (l *Log) Append(e Entry) error {
+	if l == nil {
+		return nil
+	}
+
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	if e.User == "" {
+		e.User = currentUser()
+	}
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encode audit entry: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return fmt.Errorf("create audit log directory: %w", err)
+	}
+
+	// O_APPEND keeps concurrent goco runs from interleaving partial lines.
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open audit log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+	return nil
+}
+
+// Hash returns the hex-encoded SHA-256 of s.
+func Hash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func currentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
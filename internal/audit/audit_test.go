@@ -0,0 +1,57 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLogAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "audit.jsonl")
+	log := Open(path)
+
+	for _, model := range []string{"gemini-2.5-flash", "gemini-2.5-pro"} {
+		if err := log.Append(Entry{Event: "generate", Provider: "gemini", Model: model, PromptSHA256: Hash("prompt")}); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open log: %v", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("decode line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, e)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[1].Model != "gemini-2.5-pro" || entries[0].Time.IsZero() || entries[0].User == "" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat log: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Fatalf("expected 0600 permissions, got %o", perm)
+	}
+}
+
+func TestNilLogDiscards(t *testing.T) {
+	if err := Open("").Append(Entry{Event: "generate"}); err != nil {
+		t.Fatalf("expected nil log to discard, got %v", err)
+	}
+}
@@ -0,0 +1,202 @@
+// Package cache manages the files goco keeps under its cache directory. Any
+// of them can be deleted at any time; goco fetches them again when needed.
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Kind is one family of cached files.
+type Kind struct {
+	Name        string
+	Description string
+	// Pattern matches the kind's file names inside Dir.
+	Pattern string
+}
+
+// Kinds lists every cache goco writes.
+var Kinds = []Kind{
+	{Name: "models", Description: "models.dev model registry", Pattern: "models-dev-cache.json"},
+	{Name: "openrouter", Description: "OpenRouter model listing with prices", Pattern: "openrouter-models.json"},
+	{Name: "remote-config", Description: "signed org-managed remote configs", Pattern: "remote-config-*"},
+	{Name: "scopes", Description: "commit scopes used in each repository's history, for --scope completion", Pattern: "scopes-*.json"},
+}
+
+// statsFile records hits and misses per kind.
+const statsFile = "stats.json"
+
+// Dir returns goco's cache directory, or "" if it cannot be determined.
+func Dir() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(cacheDir, "goco")
+}
+
+// Path returns the location of name inside the cache directory, or "".
+func Path(name string) string {
+	dir := Dir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, name)
+}
+
+// Lookup returns the kind named name.
+func Lookup(name string) (Kind, error) {
+	for _, k := range Kinds {
+		if k.Name == name {
+			return k, nil
+		}
+	}
+	names := make([]string, len(Kinds))
+	for i, k := range Kinds {
+		names[i] = k.Name
+	}
+	return Kind{}, fmt.Errorf("unknown cache %q (known: %v)", name, names)
+}
+
+// Entry is one cached file.
+type Entry struct {
+	Path    string
+	Size    int64
+	ModTime time.Time
+}
+
+// Entries lists the kind's files, oldest first.
+func (k Kind) Entries() ([]Entry, error) {
+	dir := Dir()
+	if dir == "" {
+		return nil, nil
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, k.Pattern))
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		entries = append(entries, Entry{Path: path, Size: info.Size(), ModTime: info.ModTime()})
+	}
+	slices.SortFunc(entries, func(a, b Entry) int { return a.ModTime.Compare(b.ModTime) })
+	return entries, nil
+}
+
+// Remove deletes the kind's files last modified before cutoff, or all of
+// them when cutoff is zero, and returns how many files and bytes it freed.
+func (k Kind) Remove(cutoff time.Time) (files int, bytes int64, err error) {
+	entries, err := k.Entries()
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, e := range entries {
+		if !cutoff.IsZero() && !e.ModTime.Before(cutoff) {
+			continue
+		}
+		if err := os.Remove(e.Path); err != nil && !os.IsNotExist(err) {
+			return files, bytes, err
+		}
+		files++
+		bytes += e.Size
+	}
+	return files, bytes, nil
+}
+
+// Usage counts lookups of one kind since its stats were last reset.
+type Usage struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+}
+
+// HitRate returns the fraction of lookups served from the cache, and false
+// when there were none.
+func (u Usage) HitRate() (float64, bool) {
+	total := u.Hits + u.Misses
+	if total == 0 {
+		return 0, false
+	}
+	return float64(u.Hits) / float64(total), true
+}
+
+var statsMu sync.Mutex
+
+// Record counts a lookup of kind as a hit or a miss. Stats are a debugging
+// aid, so failures to write them are ignored.
+func Record(kind string, hit bool) {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+
+	path := Path(statsFile)
+	if path == "" {
+		return
+	}
+	stats := loadStats(path)
+	u := stats[kind]
+	if hit {
+		u.Hits++
+	} else {
+		u.Misses++
+	}
+	stats[kind] = u
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err == nil {
+		_ = os.WriteFile(path, data, 0o644)
+	}
+}
+
+// Stats returns the recorded usage of every kind.
+func Stats() map[string]Usage {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	return loadStats(Path(statsFile))
+}
+
+// ResetStats forgets the recorded usage of the named kinds.
+func ResetStats(kinds ...string) error {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+
+	path := Path(statsFile)
+	if path == "" {
+		return nil
+	}
+	stats := loadStats(path)
+	for _, kind := range kinds {
+		delete(stats, kind)
+	}
+	if len(stats) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func loadStats(path string) map[string]Usage {
+	stats := make(map[string]Usage)
+	if path == "" {
+		return stats
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &stats)
+	}
+	return stats
+}
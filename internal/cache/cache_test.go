@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestKindEntriesAndRemove(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	dir := Dir()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-48 * time.Hour)
+	for name, mtime := range map[string]time.Time{
+		"remote-config-aa.toml":     old,
+		"remote-config-aa.toml.sig": old,
+		"remote-config-bb.toml":     time.Now(),
+		"models-dev-cache.json":     time.Now(),
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("data"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	remote, err := Lookup("remote-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := remote.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 || !entries[0].ModTime.Equal(old) {
+		t.Fatalf("expected 3 entries, oldest first, got %+v", entries)
+	}
+
+	files, bytes, err := remote.Remove(time.Now().Add(-24 * time.Hour))
+	if err != nil || files != 2 || bytes != 8 {
+		t.Fatalf("Remove(cutoff) = %d, %d, %v; want 2, 8", files, bytes, err)
+	}
+	if files, _, _ := remote.Remove(time.Time{}); files != 1 {
+		t.Fatalf("Remove(zero) removed %d files, want 1", files)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "models-dev-cache.json")); err != nil {
+		t.Fatal("another kind's file was removed")
+	}
+
+	if _, err := Lookup("responses"); err == nil {
+		t.Fatal("expected an unknown cache to fail")
+	}
+}
+
+func TestRecordStats(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	Record("models", true)
+	Record("models", true)
+	Record("models", false)
+	Record("remote-config", false)
+
+	stats := Stats()
+	if rate, ok := stats["models"].HitRate(); !ok || rate < 0.66 || rate > 0.67 {
+		t.Fatalf("models hit rate = %v, %v; want 2/3", rate, ok)
+	}
+	if _, ok := (Usage{}).HitRate(); ok {
+		t.Fatal("expected no hit rate without lookups")
+	}
+
+	if err := ResetStats("models"); err != nil {
+		t.Fatal(err)
+	}
+	stats = Stats()
+	if _, ok := stats["models"]; ok || stats["remote-config"].Misses != 1 {
+		t.Fatalf("unexpected stats after reset: %+v", stats)
+	}
+	if err := ResetStats("remote-config"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(Path(statsFile)); !os.IsNotExist(err) {
+		t.Fatalf("expected the stats file removed, got %v", err)
+	}
+}
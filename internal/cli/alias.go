@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/razobeckett/goco/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// reservedCommands are added by cobra and fang while executing, so they are
+// not yet among root's subcommands when arguments are expanded.
+var reservedCommands = []string{"help", "completion", "man", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd}
+
+// ExpandArgs rewrites goco's arguments before cobra parses them, so flags
+// reach the command that owns them. A bare `goco`, or one given only flags,
+// runs default_command from [General]. A user-defined alias from the [alias]
+// table is replaced with the arguments it stands for, so `goco quick -v`
+// runs as if the expansion had been typed; built-in commands always win over
+// aliases of the same name. A config that cannot be read leaves args alone;
+// the command that loads it reports the problem.
+func ExpandArgs(root *cobra.Command, args []string) ([]string, error) {
+	cl, err := config.NewLoader().LocalCommandLine()
+	if err != nil {
+		return args, nil
+	}
+	return expandArgs(root, cl, args)
+}
+
+func expandArgs(root *cobra.Command, cl config.CommandLine, args []string) ([]string, error) {
+	if cl.DefaultCommand != "" && commandIndex(root, args) < 0 && !asksForHelp(args) {
+		words, err := splitCommandLine(cl.DefaultCommand)
+		if err != nil {
+			return nil, fmt.Errorf("default_command: %w", err)
+		}
+		if len(words) == 0 || (!isBuiltinCommand(root, words[0]) && cl.Aliases[words[0]] == "") {
+			return nil, fmt.Errorf("default_command %q does not start with a goco command or alias", cl.DefaultCommand)
+		}
+		args = slices.Concat(words, args)
+	}
+	return expandAliases(root, cl.Aliases, args)
+}
+
+// asksForHelp reports whether args ask root itself for help or its version.
+func asksForHelp(args []string) bool {
+	for _, arg := range args {
+		switch arg {
+		case "--":
+			return false
+		case "-h", "--help", "-v", "--version":
+			return true
+		}
+	}
+	return false
+}
+
+func expandAliases(root *cobra.Command, aliases map[string]string, args []string) ([]string, error) {
+	var chain []string
+	for {
+		// An expansion may start with flags, such as "-C ~/work generate".
+		i := commandIndex(root, args)
+		if i < 0 {
+			return args, nil
+		}
+		name := args[i]
+		value, ok := aliases[name]
+		if !ok || isBuiltinCommand(root, name) {
+			return args, nil
+		}
+		chain = append(chain, name)
+		if slices.Contains(chain[:len(chain)-1], name) {
+			return nil, fmt.Errorf("alias loop: %s", strings.Join(chain, " -> "))
+		}
+
+		words, err := splitCommandLine(value)
+		if err != nil {
+			return nil, fmt.Errorf("alias %q: %w", name, err)
+		}
+		if len(words) == 0 {
+			return nil, fmt.Errorf("alias %q is empty", name)
+		}
+		args = slices.Concat(args[:i], words, args[i+1:])
+	}
+}
+
+// commandIndex returns the index in args of the subcommand name, skipping
+// root's persistent flags, or -1 when there is none.
+func commandIndex(root *cobra.Command, args []string) int {
+	flags := root.PersistentFlags()
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			return -1
+		case strings.HasPrefix(arg, "--"):
+			name, _, hasValue := strings.Cut(arg[2:], "=")
+			f := flags.Lookup(name)
+			if f == nil {
+				return -1
+			}
+			if !hasValue && f.NoOptDefVal == "" {
+				i++
+			}
+		case strings.HasPrefix(arg, "-") && len(arg) > 1:
+			f := flags.ShorthandLookup(arg[1:2])
+			if f == nil {
+				return -1
+			}
+			if len(arg) == 2 && f.NoOptDefVal == "" {
+				i++
+			}
+		default:
+			return i
+		}
+	}
+	return -1
+}
+
+func isBuiltinCommand(root *cobra.Command, name string) bool {
+	if slices.Contains(reservedCommands, name) {
+		return true
+	}
+	for _, cmd := range root.Commands() {
+		if cmd.Name() == name || cmd.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitCommandLine splits s into words like a POSIX shell would, without
+// expanding anything: single quotes keep their contents literally, double
+// quotes allow backslash escapes, and a backslash outside quotes escapes
+// the next character.
+func splitCommandLine(s string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\\':
+			escaped, inWord = true, true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash")
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/razobeckett/goco/internal/config"
+)
+
+func TestExpandAliases(t *testing.T) {
+	aliases := map[string]string{
+		"quick":   "generate --yes --model 'llama 3'",
+		"q":       "quick -V",
+		"there":   "-C ~/work generate",
+		"models":  "generate",
+		"loop":    "again",
+		"again":   "loop",
+		"empty":   "  ",
+		"unclose": `generate "oops`,
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr string
+	}{
+		{name: "no arguments", args: nil, want: ""},
+		{name: "expands", args: []string{"quick", "-s"}, want: "generate|--yes|--model|llama 3|-s"},
+		{name: "after persistent flags", args: []string{"--offline", "-C", "dir", "quick"}, want: "--offline|-C|dir|generate|--yes|--model|llama 3"},
+		{name: "after joined flag values", args: []string{"--repo=dir", "-Cdir", "quick"}, want: "--repo=dir|-Cdir|generate|--yes|--model|llama 3"},
+		{name: "chained", args: []string{"q"}, want: "generate|--yes|--model|llama 3|-V"},
+		{name: "expansion starts with flags", args: []string{"there"}, want: "-C|~/work|generate"},
+		{name: "built-in wins", args: []string{"models"}, want: "models"},
+		{name: "reserved command wins", args: []string{"help", "quick"}, want: "help|quick"},
+		{name: "unknown command", args: []string{"nope"}, want: "nope"},
+		{name: "alias as argument", args: []string{"generate", "quick"}, want: "generate|quick"},
+		{name: "after unknown flag", args: []string{"--bogus", "quick"}, want: "--bogus|quick"},
+		{name: "after double dash", args: []string{"--", "quick"}, want: "--|quick"},
+		{name: "loop", args: []string{"loop"}, wantErr: "alias loop: loop -> again -> loop"},
+		{name: "empty", args: []string{"empty"}, wantErr: `alias "empty" is empty`},
+		{name: "bad quoting", args: []string{"unclose"}, wantErr: "unterminated"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandAliases(NewRootCmd(), aliases, tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expandAliases() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(got, "|") != tt.want {
+				t.Fatalf("expandAliases() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandArgsDefaultCommand(t *testing.T) {
+	aliases := map[string]string{"quick": "generate --yes"}
+	tests := []struct {
+		name           string
+		defaultCommand string
+		args           []string
+		want           string
+		wantErr        string
+	}{
+		{name: "unset", args: nil, want: ""},
+		{name: "bare", defaultCommand: "generate", args: nil, want: "generate"},
+		{name: "with arguments", defaultCommand: "generate --staged", args: nil, want: "generate|--staged"},
+		{name: "flags pass through", defaultCommand: "generate", args: []string{"-s", "--model", "fast"}, want: "generate|-s|--model|fast"},
+		{name: "persistent flags pass through", defaultCommand: "generate", args: []string{"-C", "dir"}, want: "generate|-C|dir"},
+		{name: "alias", defaultCommand: "quick", args: []string{"-V"}, want: "generate|--yes|-V"},
+		{name: "explicit command", defaultCommand: "generate", args: []string{"models"}, want: "models"},
+		{name: "help", defaultCommand: "generate", args: []string{"--help"}, want: "--help"},
+		{name: "version", defaultCommand: "generate", args: []string{"-v"}, want: "-v"},
+		{name: "unknown command", defaultCommand: "nope", args: nil, wantErr: "does not start with a goco command"},
+		{name: "flags only", defaultCommand: "--yes", args: nil, wantErr: "does not start with a goco command"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cl := config.CommandLine{DefaultCommand: tt.defaultCommand, Aliases: aliases}
+			got, err := expandArgs(NewRootCmd(), cl, tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expandArgs() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(got, "|") != tt.want {
+				t.Fatalf("expandArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitCommandLine(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "", want: ""},
+		{in: "  generate   --yes ", want: "generate|--yes"},
+		{in: `-c "focus on the API" --model 'a b'`, want: "-c|focus on the API|--model|a b"},
+		{in: `a\ b "say \"hi\"" 'it''s'`, want: `a b|say "hi"|its`},
+		{in: `""`, want: ""},
+		{in: `'open`, wantErr: true},
+		{in: `trailing\`, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := splitCommandLine(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("splitCommandLine(%q) error = %v", tt.in, err)
+		}
+		if strings.Join(got, "|") != tt.want {
+			t.Errorf("splitCommandLine(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
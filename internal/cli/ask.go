@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/razobeckett/goco/internal/ai"
+	"github.com/razobeckett/goco/internal/git"
+)
+
+// commitTypes are the Conventional Commits types, the most common first.
+var commitTypes = []string{"feat", "fix", "docs", "refactor", "test", "chore", "build", "ci", "perf", "style"}
+
+// Scope options that are not scopes themselves.
+const (
+	noScope    = "(none)"
+	otherScope = "(other…)"
+)
+
+// askKind asks for the commit type and scope before generation, seeded by
+// what goco infers from the diff, and holds the model to the answers.
+func (p *Pipeline) askKind(_ context.Context) error {
+	if !p.opts.ask {
+		return nil
+	}
+	commitType, scope := p.inferKind()
+	if p.opts.scope != "" {
+		scope = p.opts.scope
+	}
+
+	commitType, err := p.prompter.pick("Commit type", commitTypes, commitType)
+	if err != nil {
+		return askErr(err)
+	}
+
+	scopes := append([]string{noScope}, p.scopeCandidates(scope)...)
+	seed := noScope
+	if scope != "" {
+		seed = scope
+	}
+	scope, err = p.prompter.pick("Commit scope", append(scopes, otherScope), seed)
+	if err != nil {
+		return askErr(err)
+	}
+	switch scope {
+	case noScope:
+		scope = ""
+	case otherScope:
+		if scope, err = p.prompter.text("Commit scope", "A short name for the part of the code this commit changes.", "", "Scope cannot be empty"); err != nil {
+			return askErr(err)
+		}
+	}
+
+	p.askedType, p.askedScope = commitType, scope
+	return nil
+}
+
+// askErr treats leaving a prompt as declining the commit.
+func askErr(err error) error {
+	if errors.Is(err, tea.ErrProgramKilled) || errors.Is(err, tea.ErrInterrupted) {
+		return ErrCancelled
+	}
+	return fmt.Errorf("ask for the commit type: %w", err)
+}
+
+// inferKind guesses the type and scope of the commit: the fast-path
+// classification when it recognizes the change, otherwise the type hint
+// covering the most files and the one package or directory they share. An
+// empty commit is a chore.
+func (p *Pipeline) inferKind() (string, string) {
+	if p.opts.allowEmpty {
+		return "chore", ""
+	}
+	if c, ok := ai.Classify(p.diff, p.typeHints); ok {
+		return c.Type, c.Scope
+	}
+
+	paths := p.commitPaths()
+	commitType, most := "feat", 0
+	for t, matched := range ai.TypeHints(paths, p.typeHints) {
+		if len(matched) > most || len(matched) == most && t < commitType {
+			commitType, most = t, len(matched)
+		}
+	}
+
+	var scope string
+	if candidates := p.scopeCandidates(""); len(candidates) == 1 {
+		scope = candidates[0]
+	}
+	return commitType, scope
+}
+
+// scopeCandidates lists, sorted, the names of the packages and directories
+// the commit touches, and seed when it is not among them.
+func (p *Pipeline) scopeCandidates(seed string) []string {
+	paths := p.commitPaths()
+	var candidates []string
+	for _, pkg := range git.GroupByPackage(p.root, paths) {
+		if name := pkg.Name(); name != "" {
+			candidates = append(candidates, name)
+		}
+	}
+	if len(candidates) == 0 {
+		for _, f := range paths {
+			if dir := path.Dir(f); dir != "." {
+				candidates = append(candidates, path.Base(dir))
+			}
+		}
+	}
+	if seed != "" {
+		candidates = append(candidates, seed)
+	}
+	slices.Sort(candidates)
+	return slices.Compact(candidates)
+}
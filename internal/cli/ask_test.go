@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/razobeckett/goco/internal/git"
+)
+
+func TestAskKind(t *testing.T) {
+	docs := "diff --git a/README.md b/README.md\n--- a/README.md\n+++ b/README.md\n@@ -1 +1 @@\n-a\n+b\n"
+	code := "diff --git a/internal/cli/a.go b/internal/cli/a.go\n--- a/internal/cli/a.go\n+++ b/internal/cli/a.go\n@@ -1,2 +1,2 @@\n package cli\n-var A = 1\n+var A = 2\n"
+
+	tests := []struct {
+		name       string
+		ask        bool
+		scope      string
+		diff       string
+		paths      []string
+		picked     []string
+		texts      []string
+		wantType   string
+		wantScope  string
+		wantPrefix string
+	}{
+		{name: "not asked", diff: code, paths: []string{"internal/cli/a.go"}},
+		{name: "seeded by the fast path", ask: true, diff: docs, paths: []string{"README.md"}, wantType: "docs", wantPrefix: `"docs: "`},
+		{name: "seeded by the directory", ask: true, diff: code, paths: []string{"internal/cli/a.go"}, wantType: "feat", wantScope: "cli", wantPrefix: `"feat(cli): "`},
+		{name: "picked", ask: true, diff: code, paths: []string{"internal/cli/a.go"}, picked: []string{"fix", noScope}, wantType: "fix", wantPrefix: `"fix: "`},
+		{name: "scope flag", scope: "parser", diff: code, paths: []string{"internal/cli/a.go"}, wantPrefix: `use "parser" as the commit scope`},
+		{name: "seeded by the scope flag", ask: true, scope: "parser", diff: code, paths: []string{"internal/cli/a.go"}, wantType: "feat", wantScope: "parser", wantPrefix: `"feat(parser): "`},
+		{name: "other scope", ask: true, diff: code, paths: []string{"internal/cli/a.go"}, picked: []string{"perf", otherScope}, texts: []string{"parser"}, wantType: "perf", wantScope: "parser", wantPrefix: `"perf(parser): "`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPipeline(dependencies{}, &generateOptions{ask: tt.ask, scope: tt.scope})
+			p.root = t.TempDir()
+			p.diff = tt.diff
+			p.status = &git.Status{}
+			for _, path := range tt.paths {
+				p.status.Entries = append(p.status.Entries, git.StatusEntry{Index: '.', Worktree: 'M', Path: path})
+			}
+			prompter := &scriptedPrompter{picked: tt.picked, texts: tt.texts}
+			p.prompter = prompter
+
+			if err := p.askKind(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if p.askedType != tt.wantType || p.askedScope != tt.wantScope {
+				t.Errorf("asked = %q, %q; want %q, %q", p.askedType, p.askedScope, tt.wantType, tt.wantScope)
+			}
+			if !tt.ask && len(prompter.asked) > 0 {
+				t.Errorf("asked %v without --ask", prompter.asked)
+			}
+			if tt.wantPrefix != "" && !strings.Contains(p.instructions(), tt.wantPrefix) {
+				t.Errorf("instructions %q do not ask for %s", p.instructions(), tt.wantPrefix)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/razobeckett/goco/internal/style"
+	"github.com/spf13/cobra"
+)
+
+type auditOptions struct {
+	scopes  bool
+	commits int
+	json    bool
+}
+
+func newAuditCmd(deps dependencies) *cobra.Command {
+	opts := &auditOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "audit",
+		Short:   "Report on how the repository's history uses commit conventions",
+		Long:    "Survey recent commits. --scopes counts Conventional Commits types and scopes, flags scopes that are probably spelled two ways (\"api\" and \"apis\"), and suggests a canonical scope list to adopt in a preset.",
+		GroupID: "inspect",
+		Args:    cobra.NoArgs,
+		Example: "  goco audit --scopes\n  goco audit --scopes --commits 1000 --json",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if !opts.scopes {
+				return cmd.Help()
+			}
+			return runAuditScopes(cmd.Context(), deps, opts)
+		},
+	}
+
+	fs := cmd.Flags()
+	fs.BoolVar(&opts.scopes, "scopes", false, "Report commit type and scope usage")
+	fs.IntVarP(&opts.commits, "commits", "n", 1000, "Number of recent commits to survey")
+	fs.BoolVar(&opts.json, "json", false, "Print the report as JSON")
+	return cmd
+}
+
+func runAuditScopes(ctx context.Context, deps dependencies, opts *auditOptions) error {
+	if opts.commits < 1 {
+		return fmt.Errorf("--commits must be at least 1")
+	}
+	entries, err := deps.repo.History(ctx, opts.commits)
+	if err != nil {
+		return err
+	}
+	v := style.Survey(entries)
+
+	if opts.json {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+
+	if v.Conventional == 0 {
+		fmt.Println(noteStyle.Render(fmt.Sprintf("None of the last %d commits use Conventional Commits.", v.Commits)))
+		return nil
+	}
+
+	fmt.Println(modelProviderStyle.Render(fmt.Sprintf("Commit Types (%d of %d commits)", v.Conventional, v.Commits)))
+	printCounts(v.Types, "TYPE")
+
+	if len(v.Scopes) > 0 {
+		fmt.Println()
+		fmt.Println(modelProviderStyle.Render("Scopes"))
+		printCounts(v.Scopes, "SCOPE")
+	}
+
+	if len(v.Duplicates) > 0 {
+		fmt.Println()
+		fmt.Println(modelProviderStyle.Render("Near-Duplicate Scopes"))
+		for _, group := range v.Duplicates {
+			fmt.Println("  " + strings.Join(group, ", "))
+		}
+	}
+
+	if len(v.Canonical) > 0 {
+		quoted := make([]string, len(v.Canonical))
+		for i, s := range v.Canonical {
+			quoted[i] = fmt.Sprintf("%q", s)
+		}
+		fmt.Println()
+		fmt.Println(modelProviderStyle.Render("Suggested Scopes"))
+		fmt.Println(noteStyle.Render("Add to .goco/preset.toml to steer generated messages:"))
+		fmt.Printf("scopes = [%s]\n", strings.Join(quoted, ", "))
+	}
+	return nil
+}
+
+func printCounts(counts []style.Count, heading string) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\tCOMMITS\n", heading)
+	for _, c := range counts {
+		fmt.Fprintf(w, "%s\t%d\n", c.Name, c.Count)
+	}
+	w.Flush()
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/razobeckett/goco/internal/ai"
+	"github.com/razobeckett/goco/internal/audit"
+)
+
+// auditedProvider records every generation in the audit log, for commands
+// such as bench and experiment that call a provider outside the pipeline.
+type auditedProvider struct {
+	ai.Provider
+	log   *audit.Log
+	repo  string
+	model string
+}
+
+// withAudit wraps provider so its requests are audited; it returns provider
+// unchanged when auditing is off.
+func withAudit(provider ai.Provider, log *audit.Log, repo, model string) ai.Provider {
+	if log == nil {
+		return provider
+	}
+	return &auditedProvider{Provider: provider, log: log, repo: repo, model: model}
+}
+
+func (p *auditedProvider) GenerateCommitMessage(ctx context.Context, input ai.PromptInput) (string, error) {
+	prompt, err := ai.BuildPrompt(input)
+	if err != nil {
+		return "", err
+	}
+	if err := p.log.Append(newAuditEntry("request", p.repo, p.Name(), p.model, prompt, "", nil)); err != nil {
+		return "", fmt.Errorf("audit log: %w", err)
+	}
+
+	msg, err := p.Provider.GenerateCommitMessage(ctx, input)
+	if auditErr := p.log.Append(newAuditEntry("response", p.repo, p.Name(), p.model, prompt, msg, err)); auditErr != nil {
+		fmt.Fprintf(os.Stderr, "warning: could not write audit log: %v\n", auditErr)
+	}
+	return msg, err
+}
+
+func newAuditEntry(event, repo, provider, model, prompt, msg string, err error) audit.Entry {
+	entry := audit.Entry{
+		Event:        event,
+		Repo:         repo,
+		Provider:     provider,
+		Model:        model,
+		PromptSHA256: audit.Hash(prompt),
+		PromptBytes:  len(prompt),
+	}
+	if msg != "" {
+		entry.MessageSHA256 = audit.Hash(msg)
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	return entry
+}
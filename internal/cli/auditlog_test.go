@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/razobeckett/goco/internal/ai"
+	"github.com/razobeckett/goco/internal/audit"
+)
+
+type stubProvider struct {
+	msg string
+	err error
+}
+
+func (s *stubProvider) Name() string         { return "stub" }
+func (s *stubProvider) DefaultModel() string { return "stub-model" }
+
+func (s *stubProvider) GenerateCommitMessage(context.Context, ai.PromptInput) (string, error) {
+	return s.msg, s.err
+}
+
+func (s *stubProvider) ListModels(context.Context) ([]string, error) {
+	return []string{"stub-model"}, nil
+}
+
+func (s *stubProvider) ValidateModel(context.Context, string) error { return nil }
+
+func TestWithAudit(t *testing.T) {
+	provider := &stubProvider{msg: "feat: add x"}
+	if got := withAudit(provider, nil, "/repo", "stub-model"); got != provider {
+		t.Fatal("expected the provider unchanged when auditing is off")
+	}
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	audited := withAudit(provider, audit.Open(path), "/repo", "stub-model")
+	msg, err := audited.GenerateCommitMessage(context.Background(), ai.PromptInput{Diff: "diff --git a/x b/x"})
+	if err != nil || msg != "feat: add x" {
+		t.Fatalf("GenerateCommitMessage() = %q, %v", msg, err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var events []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry audit.Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatal(err)
+		}
+		if entry.Repo != "/repo" || entry.Provider != "stub" || entry.Model != "stub-model" {
+			t.Fatalf("unexpected entry: %+v", entry)
+		}
+		events = append(events, entry.Event)
+	}
+	if len(events) != 2 || events[0] != "request" || events[1] != "response" {
+		t.Fatalf("expected request and response entries, got %v", events)
+	}
+}
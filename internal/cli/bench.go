@@ -0,0 +1,247 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/razobeckett/goco/internal/ai"
+	"github.com/razobeckett/goco/internal/audit"
+	"github.com/razobeckett/goco/internal/git"
+	"github.com/razobeckett/goco/internal/policy"
+	"github.com/spf13/cobra"
+)
+
+// syntheticDiff is used when the working tree has no changes or --synthetic is set,
+// so results are comparable across machines.
+const syntheticDiff = `diff --git a/internal/cache/cache.go b/internal/cache/cache.go
+index 3b18e51..a9c2f04 100644
+--- a/internal/cache/cache.go
++++ b/internal/cache/cache.go
+@@ -12,9 +12,17 @@ type Cache struct {
+ 	mu      sync.Mutex
+ 	entries map[string]entry
++	ttl     time.Duration
+ }
+
+-func New() *Cache {
+-	return &Cache{entries: make(map[string]entry)}
++// New returns a cache whose entries expire after ttl.
++func New(ttl time.Duration) *Cache {
++	return &Cache{entries: make(map[string]entry), ttl: ttl}
++}
++
++func (c *Cache) expired(e entry) bool {
++	return c.ttl > 0 && time.Since(e.stored) > c.ttl
+ }
+`
+
+const syntheticStatus = "branch: main\nM. internal/cache/cache.go"
+
+type benchOptions struct {
+	providers []string
+	runs      int
+	synthetic bool
+	staged    bool
+}
+
+type benchResult struct {
+	provider string
+	model    string
+	runs     int
+	failed   int
+	total    time.Duration
+	min      time.Duration
+	max      time.Duration
+	tokens   int
+}
+
+// avg returns the mean latency of the successful runs, or 0 without any.
+func (r benchResult) avg() time.Duration {
+	if r.runs == 0 {
+		return 0
+	}
+	return r.total / time.Duration(r.runs)
+}
+
+func newBenchCmd(deps dependencies) *cobra.Command {
+	opts := &benchOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "bench",
+		Short:   "Measure commit generation latency per provider and model",
+		Long:    "Generate a commit message repeatedly for the current diff (or a built-in synthetic diff) and compare latency and approximate output token throughput across providers and models. Nothing is committed.",
+		GroupID: "tools",
+		Args:    cobra.NoArgs,
+		Example: "  goco bench --providers gemini,groq --runs 5\n  goco bench --providers groq:llama-3.1-8b-instant,groq:llama-3.3-70b-versatile --synthetic",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runBench(cmd, deps, opts)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&opts.providers, "providers", nil, "Comma-separated providers to compare, optionally as provider:model (defaults to the configured provider)")
+	cmd.Flags().IntVarP(&opts.runs, "runs", "n", 3, "Number of generations per provider")
+	cmd.Flags().BoolVar(&opts.synthetic, "synthetic", false, "Use a built-in synthetic diff instead of the working tree")
+	cmd.Flags().BoolVarP(&opts.staged, "staged", "s", false, "Use staged changes instead of the working tree diff")
+	return cmd
+}
+
+func runBench(cmd *cobra.Command, deps dependencies, opts *benchOptions) error {
+	ctx := cmd.Context()
+	if err := deps.requireNetwork("benchmarking providers"); err != nil {
+		return err
+	}
+
+	if opts.runs < 1 {
+		return fmt.Errorf("--runs must be at least 1")
+	}
+
+	cfg, err := loadConfig(ctx, deps)
+	if err != nil {
+		return err
+	}
+
+	targets := opts.providers
+	if len(targets) == 0 {
+		target := cfg.DefaultProviderName()
+		if model := cfg.ModelFor(target); model != "" {
+			target += ":" + model
+		}
+		targets = []string{target}
+	}
+
+	input, source, err := benchInput(ctx, deps.repo, opts)
+	if err != nil {
+		return err
+	}
+
+	pol, err := policy.Load()
+	if err != nil {
+		return err
+	}
+
+	auditLog := audit.Open(cfg.AuditPath(audit.DefaultPath()))
+	root, _ := deps.repo.Root(ctx)
+
+	var results []benchResult
+	for _, target := range targets {
+		providerName, model, _ := strings.Cut(target, ":")
+		if err := checkProviderName(providerName); err != nil {
+			return err
+		}
+		if err := pol.Check(policyRequest(providerOptions(cfg), providerName, model)); err != nil {
+			return fmt.Errorf("%w (policy file: %s)", err, pol.Path())
+		}
+
+		apiKey, err := resolveAPIKey(cfg, providerName, "")
+		if err != nil {
+			return err
+		}
+
+		result, err := benchModel(ctx, providerOptions(cfg), providerName, apiKey, model, input, opts.runs, auditLog, root)
+		if err != nil {
+			return err
+		}
+		results = append(results, result)
+	}
+
+	printBenchResults(results, source)
+	return nil
+}
+
+// benchModel generates a message for input runs times with one provider and
+// model, the provider's recommended one when model is "". Failed runs are
+// counted, not returned.
+func benchModel(ctx context.Context, providerOpts ai.Options, providerName, apiKey, model string, input ai.PromptInput, runs int, auditLog *audit.Log, root string) (benchResult, error) {
+	provider, err := ai.NewProvider(ctx, providerName, apiKey, model, providerOpts)
+	if err != nil {
+		return benchResult{}, err
+	}
+	if model == "" {
+		model = provider.DefaultModel()
+	}
+	provider = withAudit(provider, auditLog, root, model)
+
+	result := benchResult{provider: providerName, model: model}
+	for run := 1; run <= runs; run++ {
+		start := time.Now()
+		msg, err := spin(ctx, fmt.Sprintf("%s %s run %d/%d...", providerDisplayName(providerName), model, run, runs), func(ctx context.Context) (string, error) {
+			return provider.GenerateCommitMessage(ctx, input)
+		})
+		elapsed := time.Since(start)
+		if err != nil {
+			if ctx.Err() != nil {
+				return benchResult{}, ctx.Err()
+			}
+			result.failed++
+			continue
+		}
+
+		result.runs++
+		result.total += elapsed
+		result.tokens += estimateTokens(msg)
+		if result.min == 0 || elapsed < result.min {
+			result.min = elapsed
+		}
+		if elapsed > result.max {
+			result.max = elapsed
+		}
+	}
+	return result, nil
+}
+
+// benchInput picks the diff to benchmark with, falling back to the synthetic
+// diff when the repository has nothing to describe.
+func benchInput(ctx context.Context, repo *git.Repository, opts *benchOptions) (ai.PromptInput, string, error) {
+	synthetic := ai.PromptInput{Status: syntheticStatus, Diff: syntheticDiff}
+	if opts.synthetic {
+		return synthetic, "synthetic diff", nil
+	}
+
+	status, err := repo.EnsureChanges(ctx)
+	if errors.Is(err, git.ErrNoChanges) {
+		return synthetic, "synthetic diff (no local changes)", nil
+	}
+	if err != nil {
+		return ai.PromptInput{}, "", err
+	}
+
+	diff, err := repo.Diff(ctx, opts.staged)
+	if err != nil {
+		return ai.PromptInput{}, "", fmt.Errorf("read git diff: %w", err)
+	}
+	if strings.TrimSpace(diff) == "" {
+		return synthetic, "synthetic diff (empty diff)", nil
+	}
+
+	return ai.PromptInput{Status: status.String(), Diff: diff}, "current diff", nil
+}
+
+// estimateTokens approximates output tokens at four characters per token.
+func estimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+func printBenchResults(results []benchResult, source string) {
+	fmt.Println(modelProviderStyle.Render(fmt.Sprintf("Benchmark Results (%s)", source)))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROVIDER\tMODEL\tRUNS\tAVG\tMIN\tMAX\t~TOK/S\tERRORS")
+	for _, r := range results {
+		avg, minimum, maximum, throughput := "-", "-", "-", "-"
+		if r.runs > 0 {
+			avg = r.avg().Round(time.Millisecond).String()
+			minimum = r.min.Round(time.Millisecond).String()
+			maximum = r.max.Round(time.Millisecond).String()
+			throughput = fmt.Sprintf("%.1f", float64(r.tokens)/r.total.Seconds())
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\t%s\t%d\n", r.provider, r.model, r.runs, avg, minimum, maximum, throughput, r.failed)
+	}
+	w.Flush()
+
+	fmt.Println(noteStyle.Render("Throughput is estimated from output length (about four characters per token)."))
+}
@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/razobeckett/goco/internal/git"
+)
+
+// initTestRepo creates a repository with one committed file, a.txt.
+func initTestRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("one\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "init", "-q")
+	runGit(t, dir, "add", "a.txt")
+	runGit(t, dir, "-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "-qm", "init")
+	return dir
+}
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+}
+
+func TestBenchInput(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       benchOptions
+		setup      func(t *testing.T, dir string)
+		wantSource string
+		wantDiff   string
+	}{
+		{
+			name:       "synthetic flag",
+			opts:       benchOptions{synthetic: true},
+			setup:      func(t *testing.T, dir string) { modify(t, dir) },
+			wantSource: "synthetic diff",
+			wantDiff:   syntheticDiff,
+		},
+		{
+			name:       "clean tree",
+			wantSource: "synthetic diff (no local changes)",
+			wantDiff:   syntheticDiff,
+		},
+		{
+			name:       "only staged changes without --staged",
+			setup:      func(t *testing.T, dir string) { modify(t, dir); runGit(t, dir, "add", "a.txt") },
+			wantSource: "synthetic diff (empty diff)",
+			wantDiff:   syntheticDiff,
+		},
+		{
+			name:       "working tree changes",
+			setup:      func(t *testing.T, dir string) { modify(t, dir) },
+			wantSource: "current diff",
+			wantDiff:   "+two",
+		},
+		{
+			name:       "staged changes with --staged",
+			opts:       benchOptions{staged: true},
+			setup:      func(t *testing.T, dir string) { modify(t, dir); runGit(t, dir, "add", "a.txt") },
+			wantSource: "current diff",
+			wantDiff:   "+two",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := initTestRepo(t)
+			if tt.setup != nil {
+				tt.setup(t, dir)
+			}
+
+			input, source, err := benchInput(context.Background(), git.NewRepository(dir), &tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if source != tt.wantSource {
+				t.Errorf("source = %q, want %q", source, tt.wantSource)
+			}
+			if !strings.Contains(input.Diff, tt.wantDiff) {
+				t.Errorf("diff does not contain %q:\n%s", tt.wantDiff, input.Diff)
+			}
+		})
+	}
+}
+
+func modify(t *testing.T, dir string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("one\ntwo\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"a", 1},
+		{"abcd", 1},
+		{"abcde", 2},
+		{strings.Repeat("x", 400), 100},
+	}
+	for _, tt := range tests {
+		if got := estimateTokens(tt.text); got != tt.want {
+			t.Errorf("estimateTokens(%d chars) = %d, want %d", len(tt.text), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/razobeckett/goco/internal/cache"
+	"github.com/razobeckett/goco/internal/git"
+	"github.com/spf13/cobra"
+)
+
+type cacheOptions struct {
+	json      bool
+	olderThan time.Duration
+}
+
+func newCacheCmd() *cobra.Command {
+	opts := &cacheOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "cache",
+		Short:   "Inspect and prune goco's cache",
+		Long:    "goco caches the models.dev model registry and signed remote configs under its cache directory. Everything there can be deleted safely; goco fetches it again when needed.",
+		GroupID: "tools",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+
+	stats := &cobra.Command{
+		Use:     "stats",
+		Short:   "Show each cache's size, age, and hit rate",
+		Args:    cobra.NoArgs,
+		Example: "  goco cache stats\n  goco cache stats --json",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runCacheStats(opts)
+		},
+	}
+	stats.Flags().BoolVar(&opts.json, "json", false, "Print the stats as JSON")
+
+	clearCmd := &cobra.Command{
+		Use:     "clear [cache...]",
+		Short:   "Delete all cached files, or only the named caches",
+		Example: "  goco cache clear\n  goco cache clear models",
+		ValidArgsFunction: func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+			names := make([]string, len(cache.Kinds))
+			for i, k := range cache.Kinds {
+				names[i] = k.Name
+			}
+			return names, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runCacheClear(args)
+		},
+	}
+
+	gc := &cobra.Command{
+		Use:     "gc",
+		Short:   "Delete cached files not refreshed recently",
+		Args:    cobra.NoArgs,
+		Example: "  goco cache gc\n  goco cache gc --older-than 168h",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runCacheGC(opts)
+		},
+	}
+	gc.Flags().DurationVar(&opts.olderThan, "older-than", 30*24*time.Hour, "Delete files last written longer ago than this")
+
+	cmd.AddCommand(stats, clearCmd, gc)
+	return cmd
+}
+
+// cacheStats is one row of `goco cache stats`.
+type cacheStats struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Files       int        `json:"files"`
+	Bytes       int64      `json:"bytes"`
+	Oldest      *time.Time `json:"oldest,omitempty"`
+	Hits        int64      `json:"hits"`
+	Misses      int64      `json:"misses"`
+}
+
+func collectCacheStats() ([]cacheStats, error) {
+	usage := cache.Stats()
+	rows := make([]cacheStats, 0, len(cache.Kinds))
+	for _, k := range cache.Kinds {
+		entries, err := k.Entries()
+		if err != nil {
+			return nil, fmt.Errorf("read %s cache: %w", k.Name, err)
+		}
+		row := cacheStats{Name: k.Name, Description: k.Description, Files: len(entries), Hits: usage[k.Name].Hits, Misses: usage[k.Name].Misses}
+		for _, e := range entries {
+			row.Bytes += e.Size
+		}
+		if len(entries) > 0 {
+			row.Oldest = &entries[0].ModTime
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func runCacheStats(opts *cacheOptions) error {
+	rows, err := collectCacheStats()
+	if err != nil {
+		return err
+	}
+	if opts.json {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	}
+
+	fmt.Println(modelProviderStyle.Render("Cache " + cache.Dir()))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CACHE\tFILES\tSIZE\tOLDEST\tHIT RATE")
+	for _, r := range rows {
+		oldest := "-"
+		if r.Oldest != nil {
+			oldest = time.Since(*r.Oldest).Round(time.Minute).String() + " ago"
+		}
+		rate := "-"
+		if hitRate, ok := (cache.Usage{Hits: r.Hits, Misses: r.Misses}).HitRate(); ok {
+			rate = fmt.Sprintf("%.0f%% (%d/%d)", 100*hitRate, r.Hits, r.Hits+r.Misses)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\n", r.Name, r.Files, git.FormatSize(r.Bytes), oldest, rate)
+	}
+	return w.Flush()
+}
+
+func runCacheClear(names []string) error {
+	kinds := cache.Kinds
+	if len(names) > 0 {
+		kinds = nil
+		for _, name := range names {
+			k, err := cache.Lookup(name)
+			if err != nil {
+				return err
+			}
+			kinds = append(kinds, k)
+		}
+	}
+
+	var files int
+	var bytes int64
+	cleared := make([]string, 0, len(kinds))
+	for _, k := range kinds {
+		n, size, err := k.Remove(time.Time{})
+		if err != nil {
+			return fmt.Errorf("clear %s cache: %w", k.Name, err)
+		}
+		files += n
+		bytes += size
+		cleared = append(cleared, k.Name)
+	}
+	if err := cache.ResetStats(cleared...); err != nil {
+		return fmt.Errorf("reset cache stats: %w", err)
+	}
+	fmt.Println(noteStyle.Render(fmt.Sprintf("Deleted %d cached files (%s).", files, git.FormatSize(bytes))))
+	return nil
+}
+
+func runCacheGC(opts *cacheOptions) error {
+	if opts.olderThan <= 0 {
+		return fmt.Errorf("--older-than must be positive")
+	}
+	cutoff := time.Now().Add(-opts.olderThan)
+
+	var files int
+	var bytes int64
+	for _, k := range cache.Kinds {
+		n, size, err := k.Remove(cutoff)
+		if err != nil {
+			return fmt.Errorf("prune %s cache: %w", k.Name, err)
+		}
+		files += n
+		bytes += size
+	}
+	fmt.Println(noteStyle.Render(fmt.Sprintf("Deleted %d cached files older than %s (%s).", files, opts.olderThan, git.FormatSize(bytes))))
+	return nil
+}
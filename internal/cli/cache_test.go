@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/razobeckett/goco/internal/cache"
+)
+
+func TestCollectCacheStats(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	if err := os.MkdirAll(cache.Dir(), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(cache.Dir(), "models-dev-cache.json"), []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cache.Record("models", true)
+
+	rows, err := collectCacheStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != len(cache.Kinds) {
+		t.Fatalf("expected a row per cache, got %+v", rows)
+	}
+	models := rows[0]
+	if models.Name != "models" || models.Files != 1 || models.Bytes != 2 || models.Oldest == nil || models.Hits != 1 {
+		t.Fatalf("unexpected models row: %+v", models)
+	}
+	if remote := rows[1]; remote.Files != 0 || remote.Oldest != nil {
+		t.Fatalf("unexpected remote-config row: %+v", remote)
+	}
+
+	if err := runCacheClear([]string{"models"}); err != nil {
+		t.Fatal(err)
+	}
+	if rows, _ := collectCacheStats(); rows[0].Files != 0 || rows[0].Hits != 0 {
+		t.Fatalf("expected models cleared, got %+v", rows[0])
+	}
+	if err := runCacheClear([]string{"responses"}); err == nil {
+		t.Fatal("expected an unknown cache to fail")
+	}
+}
@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/razobeckett/goco/internal/ai"
+	"github.com/razobeckett/goco/internal/config"
+	"github.com/razobeckett/goco/internal/git"
+)
+
+const (
+	defaultChunkSize        = 64 << 10
+	defaultChunkConcurrency = 4
+)
+
+// summariesIntro opens the chunk summaries sent in place of the diff.
+const summariesIntro = "The diff was too large to send whole; these are summaries of its %d parts.\n"
+
+// chunking is how a diff too large for one request is summarized in parts.
+type chunking struct {
+	enabled bool
+	size    int
+	workers int
+	limiter *rateLimiter
+}
+
+func newChunking(cfg config.Prompt, providerName string) chunking {
+	c := chunking{
+		enabled: cfg.ChunkDiff,
+		size:    cfg.ChunkSize,
+		workers: cfg.ChunkConcurrency,
+		limiter: newRateLimiter(cfg.ChunkRateLimits[providerName]),
+	}
+	if c.size <= 0 {
+		c.size = defaultChunkSize
+	}
+	if c.workers <= 0 {
+		c.workers = defaultChunkConcurrency
+	}
+	return c
+}
+
+// chunkedDiff returns diff unchanged when it fits in one part; otherwise it
+// summarizes each part concurrently and returns the summaries in diff order.
+func (p *Pipeline) chunkedDiff(ctx context.Context, diff string) (string, error) {
+	if !p.chunking.enabled || len(diff) <= p.chunking.size {
+		return diff, nil
+	}
+	return p.summarizeParts(ctx, diff, p.chunking.size)
+}
+
+// summarizeParts splits diff into parts of at most size bytes and summarizes
+// them concurrently, returning the summaries in diff order.
+func (p *Pipeline) summarizeParts(ctx context.Context, diff string, size int) (string, error) {
+	parts := git.GroupSections(diff, size)
+	if len(parts) < 2 {
+		return diff, nil
+	}
+	// Regenerating the message reuses the summaries of an unchanged diff.
+	if p.chunkedFor == diff {
+		return p.chunked, nil
+	}
+	if err := p.connect(ctx); err != nil {
+		return "", err
+	}
+
+	message := fmt.Sprintf("Summarizing %d parts of the diff...", len(parts))
+	var summaries []string
+	_, err := spin(ctx, message, func(ctx context.Context) (string, error) {
+		var err error
+		summaries, err = runConcurrently(ctx, parts, p.chunking.workers, p.chunking.limiter, func(ctx context.Context, i int, part string) (string, error) {
+			input := partInput(i, len(parts), part)
+			prompt, err := ai.BuildPrompt(input)
+			if err != nil {
+				return "", err
+			}
+			if err := p.audit.Append(p.auditEntry("request", prompt, "", nil)); err != nil {
+				return "", fmt.Errorf("audit log: %w", err)
+			}
+			summary, err := p.call(ctx, input, prompt, "")
+			if err == nil {
+				summary, err = ai.CleanOutput(summary)
+			}
+			return strings.TrimSpace(summary), err
+		})
+		return "", err
+	})
+	if err != nil {
+		return "", fmt.Errorf("summarize diff: %w", err)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, summariesIntro, len(parts))
+	for i, summary := range summaries {
+		fmt.Fprintf(&b, "\nPart %d (%s):\n%s\n", i+1, strings.Join(sectionPaths(parts[i]), ", "), summary)
+	}
+	p.chunkedFor, p.chunked = diff, b.String()
+	p.debug("Diff Summaries", p.chunked)
+	return p.chunked, nil
+}
+
+// partInput asks for a summary of part i of n.
+func partInput(i, n int, part string) ai.PromptInput {
+	return ai.PromptInput{
+		Status:   fmt.Sprintf("Part %d of %d: %s", i+1, n, strings.Join(sectionPaths(part), ", ")),
+		Diff:     part,
+		Template: ai.ChunkTemplate,
+	}
+}
+
+// sectionPaths lists the files a diff part touches.
+func sectionPaths(part string) []string {
+	var paths []string
+	for _, line := range strings.Split(part, "\n") {
+		if _, path, ok := git.DiffHeaderPaths(line); ok {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// runConcurrently calls fn for every item with at most workers calls in
+// flight, each waiting its turn on limiter. Results keep the order of items;
+// every failure is reported, not just the first.
+func runConcurrently(ctx context.Context, items []string, workers int, limiter *rateLimiter, fn func(ctx context.Context, i int, item string) (string, error)) ([]string, error) {
+	results := make([]string, len(items))
+	errs := make([]error, len(items))
+	slots := make(chan struct{}, max(workers, 1))
+
+	var wg sync.WaitGroup
+	for i, item := range items {
+		wg.Add(1)
+		go func() {
+			defer restoreOnPanic()
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			err := limiter.wait(ctx)
+			if err == nil {
+				results[i], err = fn(ctx, i, item)
+			}
+			if err != nil {
+				errs[i] = fmt.Errorf("part %d of %d: %w", i+1, len(items), err)
+			}
+		}()
+	}
+	wg.Wait()
+	return results, errors.Join(errs...)
+}
+
+// rateLimiter spaces requests evenly to stay under a per-minute limit. A nil
+// limiter allows any rate.
+type rateLimiter struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+func newRateLimiter(perMinute int) *rateLimiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &rateLimiter{interval: time.Minute / time.Duration(perMinute)}
+}
+
+// wait blocks until the caller may send its request.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	at := l.next
+	if now := time.Now(); at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.interval)
+	l.mu.Unlock()
+
+	timer := time.NewTimer(time.Until(at))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/razobeckett/goco/internal/config"
+)
+
+func TestRunConcurrently(t *testing.T) {
+	items := []string{"a", "b", "c", "d", "e"}
+	var running, peak atomic.Int32
+	results, err := runConcurrently(context.Background(), items, 2, nil, func(_ context.Context, i int, item string) (string, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		if item == "b" || item == "d" {
+			return "", errors.New("boom")
+		}
+		return strings.ToUpper(item), nil
+	})
+
+	if got := strings.Join(results, ","); got != "A,,C,,E" {
+		t.Errorf("results = %q, want them in input order", got)
+	}
+	if peak.Load() > 2 {
+		t.Errorf("%d calls ran at once, want at most 2", peak.Load())
+	}
+	if err == nil || !strings.Contains(err.Error(), "part 2 of 5: boom") || !strings.Contains(err.Error(), "part 4 of 5: boom") {
+		t.Errorf("expected both failures reported, got %v", err)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	if newRateLimiter(0) != nil {
+		t.Fatal("expected no limiter without a limit")
+	}
+
+	l := newRateLimiter(3000) // one request per 20ms
+	start := time.Now()
+	for range 3 {
+		if err := l.wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Fatalf("three requests took %v, want at least 40ms", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	l = newRateLimiter(1)
+	_ = l.wait(ctx) // the first request goes at once
+	if err := l.wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation, got %v", err)
+	}
+}
+
+func TestChunkedDiff(t *testing.T) {
+	section := func(name string) string {
+		return fmt.Sprintf("diff --git a/%s b/%s\n--- a/%s\n+++ b/%s\n@@ -1 +1 @@\n-a\n+b\n", name, name, name, name)
+	}
+	diff := section("a.go") + section("b.go") + section("c.go")
+
+	p := NewPipeline(dependencies{}, &generateOptions{})
+	p.provider = &stubProvider{msg: "- changed things\n"}
+
+	if got, err := p.chunkedDiff(context.Background(), diff); err != nil || got != diff {
+		t.Fatalf("chunking off: chunkedDiff() = %q, %v; want the diff unchanged", got, err)
+	}
+
+	p.chunking = newChunking(config.Prompt{ChunkDiff: true, ChunkSize: 2 * len(section("a.go"))}, "stub")
+	got, err := p.chunkedDiff(context.Background(), diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"summaries of its 2 parts", "Part 1 (a.go, b.go):\n- changed things\n", "Part 2 (c.go):\n- changed things\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+
+	p.provider = &stubProvider{err: errors.New("quota exceeded")}
+	if again, err := p.chunkedDiff(context.Background(), diff); err != nil || again != got {
+		t.Fatalf("expected cached summaries for the same diff, got %q, %v", again, err)
+	}
+	if _, err := p.chunkedDiff(context.Background(), diff+section("d.go")); err == nil || !strings.Contains(err.Error(), "quota exceeded") {
+		t.Fatalf("expected the provider error, got %v", err)
+	}
+}
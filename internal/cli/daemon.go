@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/razobeckett/goco/internal/ai"
+	"github.com/razobeckett/goco/internal/daemon"
+	"github.com/spf13/cobra"
+)
+
+type daemonOptions struct {
+	idleTimeout time.Duration
+}
+
+func newDaemonCmd(deps dependencies) *cobra.Command {
+	opts := &daemonOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "daemon",
+		Short:   "Keep provider clients warm in a background process",
+		Long:    "The daemon holds authenticated provider clients and cached model lists, so each goco run skips client setup and the TLS handshake. While it is running, goco sends generation requests through it over a unix socket only you can open; when it is not, goco talks to the provider directly.",
+		GroupID: "tools",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+
+	start := &cobra.Command{
+		Use:     "start",
+		Short:   "Run the daemon in the foreground until stopped or idle",
+		Args:    cobra.NoArgs,
+		Example: "  goco daemon start &\n  goco daemon start --idle-timeout 2h",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runDaemonStart(cmd.Context(), deps, opts)
+		},
+	}
+	start.Flags().DurationVar(&opts.idleTimeout, "idle-timeout", daemon.DefaultIdleTimeout, "Exit after this long without a request (0 keeps running)")
+	cmd.AddCommand(start)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "stop",
+		Short: "Stop the running daemon",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := daemon.NewClient(daemon.SocketPath()).Stop(cmd.Context()); err != nil {
+				return err
+			}
+			fmt.Println(noteStyle.Render("Daemon stopped."))
+			return nil
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "status",
+		Short: "Show whether the daemon is running",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runDaemonStatus(cmd.Context())
+		},
+	})
+
+	return cmd
+}
+
+func runDaemonStart(ctx context.Context, deps dependencies, opts *daemonOptions) error {
+	if err := deps.requireNetwork("running the daemon"); err != nil {
+		return err
+	}
+	if opts.idleTimeout < 0 {
+		return fmt.Errorf("--idle-timeout must not be negative")
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	path := daemon.SocketPath()
+	fmt.Fprintln(os.Stderr, noteStyle.Render("goco daemon listening on "+path))
+	return daemon.NewServer(path, opts.idleTimeout, ai.NewProvider).Serve(ctx)
+}
+
+func runDaemonStatus(ctx context.Context) error {
+	path := daemon.SocketPath()
+	status, err := daemon.NewClient(path).Status(ctx)
+	if errors.Is(err, daemon.ErrNotRunning) {
+		fmt.Println(noteStyle.Render("The daemon is not running; goco talks to providers directly."))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Println(modelProviderStyle.Render("goco daemon"))
+	fmt.Printf("socket:    %s\n", path)
+	fmt.Printf("pid:       %d\n", status.PID)
+	fmt.Printf("started:   %s\n", status.Started.Format(time.DateTime))
+	fmt.Printf("last used: %s\n", status.LastUsed.Format(time.DateTime))
+	fmt.Printf("clients:   %d\n", status.Clients)
+	return nil
+}
+
+// newProvider goes through the daemon when one is running and creates a
+// client directly otherwise, configured by opts.
+func newProvider(ctx context.Context, opts ai.Options, providerName, apiKey, model string) (ai.Provider, error) {
+	client := daemon.NewClient(daemon.SocketPath())
+	if client.Running(ctx) {
+		return client.Provider(providerName, apiKey, withDefaultModel(providerName, model, opts), opts), nil
+	}
+	return ai.NewProvider(ctx, providerName, apiKey, model, opts)
+}
+
+// withDefaultModel fills in the provider's recommended model, as
+// ai.NewProvider does, so the daemon keys clients by the model actually used.
+func withDefaultModel(providerName, model string, opts ai.Options) string {
+	if model == "" {
+		return ai.DefaultModelFor(providerName, opts)
+	}
+	return model
+}
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/razobeckett/goco/internal/config"
+)
+
+const (
+	localSpinnerInterval  = 80 * time.Millisecond
+	remoteSpinnerInterval = 250 * time.Millisecond
+	// remoteFPS caps how often bubbletea redraws in a remote terminal; it
+	// redraws up to 60 times a second otherwise.
+	remoteFPS = 15
+)
+
+// display is how goco draws in this terminal, set before the command runs.
+var display = struct {
+	remote bool
+	// spinnerInterval is the time between spinner frames.
+	spinnerInterval time.Duration
+}{spinnerInterval: localSpinnerInterval}
+
+// sshSession reports whether goco runs in an SSH session, whose every
+// frame crosses the network.
+func sshSession() bool {
+	return os.Getenv("SSH_CONNECTION") != "" || os.Getenv("SSH_CLIENT") != "" || os.Getenv("SSH_TTY") != ""
+}
+
+// announcesTrueColor reports whether the terminal says it draws 24-bit
+// color. SSH passes COLORTERM on only when both ends are set up to.
+func announcesTrueColor() bool {
+	colorterm := strings.ToLower(os.Getenv("COLORTERM"))
+	return colorterm == "truecolor" || colorterm == "24bit"
+}
+
+// configureDisplay applies [Terminal]: remote terminals get slower
+// animations and, unless they announce 24-bit color, at most 256 colors.
+func configureDisplay(t config.Terminal) error {
+	remote, err := t.IsRemote(sshSession())
+	if err != nil {
+		return err
+	}
+	colors, err := t.ColorSetting()
+	if err != nil {
+		return err
+	}
+
+	display.remote = remote
+	display.spinnerInterval = localSpinnerInterval
+	if remote {
+		display.spinnerInterval = remoteSpinnerInterval
+	}
+	if profile, ok := colorProfile(colors, remote, announcesTrueColor(), lipgloss.ColorProfile()); ok {
+		lipgloss.SetColorProfile(profile)
+	}
+	return nil
+}
+
+// colorProfile returns the profile to draw with, or false to keep the
+// detected one.
+func colorProfile(colors string, remote, trueColor bool, detected termenv.Profile) (termenv.Profile, bool) {
+	switch colors {
+	case config.ColorsTrueColor:
+		return termenv.TrueColor, true
+	case config.Colors256:
+		return termenv.ANSI256, true
+	case config.Colors16:
+		return termenv.ANSI, true
+	case config.ColorsNone:
+		return termenv.Ascii, true
+	}
+	// Profiles with fewer colors compare greater.
+	if remote && !trueColor && detected < termenv.ANSI256 {
+		return termenv.ANSI256, true
+	}
+	return detected, false
+}
+
+// colorsName names profile as [Terminal] colors does.
+func colorsName(profile termenv.Profile) string {
+	switch profile {
+	case termenv.TrueColor:
+		return config.ColorsTrueColor
+	case termenv.ANSI256:
+		return config.Colors256
+	case termenv.ANSI:
+		return config.Colors16
+	default:
+		return config.ColorsNone
+	}
+}
+
+// programOptions adds the options every bubbletea program gets in this
+// terminal to opts.
+func programOptions(opts ...tea.ProgramOption) []tea.ProgramOption {
+	if display.remote {
+		opts = append(opts, tea.WithFPS(remoteFPS))
+	}
+	return opts
+}
+
+// warnDisplay reports a [Terminal] setting goco cannot apply, which leaves
+// the detected behavior in place.
+func warnDisplay(err error) {
+	fmt.Fprintln(os.Stderr, noteStyle.Render(fmt.Sprintf("Warning: %v; detecting the terminal instead.", err)))
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/muesli/termenv"
+	"github.com/razobeckett/goco/internal/config"
+)
+
+func TestColorProfile(t *testing.T) {
+	tests := []struct {
+		name      string
+		colors    string
+		remote    bool
+		trueColor bool
+		detected  termenv.Profile
+		want      termenv.Profile
+		wantSet   bool
+	}{
+		{name: "local keeps truecolor", colors: config.TerminalAuto, detected: termenv.TrueColor, want: termenv.TrueColor},
+		{name: "remote drops truecolor", colors: config.TerminalAuto, remote: true, detected: termenv.TrueColor, want: termenv.ANSI256, wantSet: true},
+		{name: "remote announcing truecolor", colors: config.TerminalAuto, remote: true, trueColor: true, detected: termenv.TrueColor, want: termenv.TrueColor},
+		{name: "remote keeps fewer colors", colors: config.TerminalAuto, remote: true, detected: termenv.ANSI, want: termenv.ANSI},
+		{name: "remote keeps NO_COLOR", colors: config.TerminalAuto, remote: true, detected: termenv.Ascii, want: termenv.Ascii},
+		{name: "configured truecolor over ssh", colors: config.ColorsTrueColor, remote: true, detected: termenv.ANSI256, want: termenv.TrueColor, wantSet: true},
+		{name: "configured none", colors: config.ColorsNone, detected: termenv.TrueColor, want: termenv.Ascii, wantSet: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, set := colorProfile(tt.colors, tt.remote, tt.trueColor, tt.detected)
+			if got != tt.want || set != tt.wantSet {
+				t.Fatalf("colorProfile() = %v, %v, want %v, %v", got, set, tt.want, tt.wantSet)
+			}
+		})
+	}
+}
+
+func TestConfigureDisplay(t *testing.T) {
+	t.Cleanup(func() { _ = configureDisplay(config.Terminal{Remote: config.TerminalOff}) })
+	tests := []struct {
+		name     string
+		ssh      string
+		terminal config.Terminal
+		want     time.Duration
+		wantErr  bool
+	}{
+		{name: "local", want: localSpinnerInterval},
+		{name: "ssh", ssh: "10.0.0.2 52100 10.0.0.1 22", want: remoteSpinnerInterval},
+		{name: "ssh turned off", ssh: "10.0.0.2 52100 10.0.0.1 22", terminal: config.Terminal{Remote: "off"}, want: localSpinnerInterval},
+		{name: "forced remote", terminal: config.Terminal{Remote: "on"}, want: remoteSpinnerInterval},
+		{name: "bad setting", terminal: config.Terminal{Colors: "millions"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SSH_CONNECTION", tt.ssh)
+			t.Setenv("SSH_CLIENT", "")
+			t.Setenv("SSH_TTY", "")
+			err := configureDisplay(tt.terminal)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("configureDisplay() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (display.spinnerInterval != tt.want || display.remote != (tt.want == remoteSpinnerInterval)) {
+				t.Fatalf("spinner interval %v and remote %v, want %v", display.spinnerInterval, display.remote, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/razobeckett/goco/internal/ai"
+)
+
+// inspectEmpty prepares an --allow-empty commit. There is no diff to
+// describe, so the message is written from the purpose the user gives,
+// with --describe or when asked.
+func (p *Pipeline) inspectEmpty(ctx context.Context) error {
+	status, err := p.deps.repo.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("read git status: %w", err)
+	}
+	// git would commit whatever is staged along with the empty commit.
+	if staged := status.Paths(true); len(staged) > 0 {
+		return fmt.Errorf("--allow-empty records no changes, but %d file(s) are staged; commit them first or unstage them with `git restore --staged`", len(staged))
+	}
+
+	state, err := p.deps.repo.State(ctx)
+	if err != nil {
+		return err
+	}
+	if err := checkRepoState(state, status); err != nil {
+		return err
+	}
+
+	purpose := strings.TrimSpace(p.opts.describe)
+	if purpose == "" {
+		purpose, err = p.prompter.text("Commit purpose", "There are no changes; the message is written from what this commit is for.", "", "Purpose cannot be empty")
+		if errors.Is(err, tea.ErrProgramKilled) || errors.Is(err, tea.ErrInterrupted) {
+			return ErrCancelled
+		}
+		if err != nil {
+			return fmt.Errorf("ask for the commit's purpose: %w; pass --describe instead", err)
+		}
+		purpose = strings.TrimSpace(purpose)
+	}
+	if purpose == "" {
+		return fmt.Errorf("an empty commit needs a purpose to write its message from; pass --describe")
+	}
+
+	p.status = status
+	p.state = state
+	p.purpose = purpose
+	if log, err := p.deps.repo.RecentLog(ctx, 3); err == nil {
+		p.recentLog = log
+	}
+	return nil
+}
+
+// emptyPromptInput asks for the message of an --allow-empty commit.
+func (p *Pipeline) emptyPromptInput() ai.PromptInput {
+	return ai.PromptInput{
+		Status:             p.purpose,
+		CustomInstructions: p.instructions(),
+		RecentLog:          p.recentLog,
+		Spec:               p.spec,
+		Issue:              p.issue,
+		Template:           ai.EmptyCommitTemplate,
+	}
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/razobeckett/goco/internal/ai"
+	"github.com/razobeckett/goco/internal/config"
+	"github.com/razobeckett/goco/internal/git"
+	"github.com/razobeckett/goco/internal/policy"
+)
+
+// inputProvider records what it is asked.
+type inputProvider struct {
+	scriptedProvider
+	inputs []ai.PromptInput
+}
+
+func (s *inputProvider) GenerateCommitMessage(ctx context.Context, input ai.PromptInput) (string, error) {
+	s.inputs = append(s.inputs, input)
+	return s.scriptedProvider.GenerateCommitMessage(ctx, input)
+}
+
+func TestPipelineRunAllowEmpty(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        generateOptions
+		texts       []string
+		stage       bool
+		wantPurpose string
+		wantErr     string
+	}{
+		{name: "described", opts: generateOptions{describe: "rebuild with the new runner image"}, wantPurpose: "rebuild with the new runner image"},
+		{name: "asked", texts: []string{"  redeploy staging  "}, wantPurpose: "redeploy staging"},
+		{name: "not described", wantErr: "pass --describe"},
+		{name: "staged changes", opts: generateOptions{describe: "rebuild"}, stage: true, wantErr: "1 file(s) are staged"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "config"))
+			t.Setenv("XDG_STATE_HOME", filepath.Join(home, "state"))
+			t.Setenv("XDG_CACHE_HOME", filepath.Join(home, "cache"))
+			t.Setenv(policy.EnvVar, "")
+			dir := initTestRepo(t)
+			// Unstaged changes stay out of an empty commit.
+			modify(t, dir)
+			if tt.stage {
+				runGit(t, dir, "add", "a.txt")
+			}
+
+			opts := tt.opts
+			opts.allowEmpty = true
+			opts.noConfirm = true
+			p := NewPipeline(dependencies{configLoader: config.NewLoader(), repo: git.NewRepository(dir)}, &opts)
+			provider := &inputProvider{scriptedProvider: scriptedProvider{replies: []scriptedReply{{msg: "ci: rebuild"}}}}
+			committer := &recordingCommitter{}
+			p.provider = provider
+			p.prompter = &scriptedPrompter{texts: tt.texts}
+			p.committer = committer
+
+			err := p.Run(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				if len(committer.messages) > 0 {
+					t.Errorf("committed %q", committer.messages)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(provider.inputs) != 1 {
+				t.Fatalf("provider asked %d times", len(provider.inputs))
+			}
+			input := provider.inputs[0]
+			if input.Status != tt.wantPurpose || input.Diff != "" || input.Template != ai.EmptyCommitTemplate {
+				t.Errorf("asked with status %q, diff %q", input.Status, input.Diff)
+			}
+			if len(committer.messages) != 1 || committer.messages[0] != "ci: rebuild" || !committer.overrides.AllowEmpty {
+				t.Errorf("committed %q with %+v", committer.messages, committer.overrides)
+			}
+			if plan, err := p.planCommit(context.Background()); err != nil || plan.stageTracked || plan.files != nil {
+				t.Errorf("plan = %+v, %v; want nothing staged", plan, err)
+			}
+		})
+	}
+}
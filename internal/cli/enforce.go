@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/razobeckett/goco/internal/ai"
+	"github.com/razobeckett/goco/internal/git"
+	"github.com/razobeckett/goco/internal/policy"
+)
+
+// ruleRegenerations is how many times a message that misses a required
+// pattern is regenerated before the user is asked for the missing piece.
+const ruleRegenerations = 2
+
+// differentiate regenerates once when the message repeats the subject or
+// body bullets of an earlier commit in the series, as --per-package splits
+// often produce. A message that still overlaps is kept.
+func (p *Pipeline) differentiate(ctx context.Context) error {
+	overlap := ai.FindOverlap(p.commitMsg, p.series)
+	if overlap.Empty() {
+		return nil
+	}
+	fmt.Fprintln(os.Stderr, noteStyle.Render("Generated message repeats an earlier commit in this series; regenerating."))
+	p.ruleFeedback = overlap.Feedback()
+	err := p.generate(ctx)
+	p.ruleFeedback = ""
+	return err
+}
+
+// enforceRules makes the message match the [Message] patterns: it first
+// regenerates with feedback on what was missing, then, when a terminal is
+// attached, asks the user for each missing piece.
+func (p *Pipeline) enforceRules(ctx context.Context) error {
+	for attempt := 0; ; attempt++ {
+		violations := p.messageRules.Check(p.commitMsg)
+		if len(violations) == 0 {
+			return nil
+		}
+
+		if attempt < ruleRegenerations {
+			fmt.Fprintln(os.Stderr, noteStyle.Render(fmt.Sprintf("Generated message lacks %s; regenerating.", describeViolations(violations))))
+			p.ruleFeedback = fmt.Sprintf("Your previous message was rejected because it lacked %s:\n%s", describeViolations(violations), p.commitMsg)
+			err := p.generate(ctx)
+			p.ruleFeedback = ""
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if p.opts.noConfirm || p.opts.printOnly {
+			return fmt.Errorf("%w: commit message still lacks %s after %d regenerations", policy.ErrDenied, describeViolations(violations), ruleRegenerations)
+		}
+		return p.fillMissing(violations)
+	}
+}
+
+// fillMissing asks for a corrected subject or an extra footer line for each
+// violation, then checks the result.
+func (p *Pipeline) fillMissing(violations []policy.Violation) error {
+	for _, v := range violations {
+		switch v.Part {
+		case policy.PartSubject:
+			subject, body, _ := strings.Cut(p.commitMsg, "\n")
+			fixed, err := p.prompter.text(
+				"Fix the commit subject",
+				"It must match "+v.Pattern,
+				subject,
+				"Subject cannot be empty",
+			)
+			if err != nil {
+				return promptError(err, v)
+			}
+			p.commitMsg = strings.TrimRight(fixed+"\n"+body, "\n")
+		case policy.PartFooter:
+			line, err := p.prompter.text(
+				"Add a footer line",
+				"It must match "+v.Pattern,
+				"",
+				"Footer cannot be empty",
+			)
+			if err != nil {
+				return promptError(err, v)
+			}
+			p.commitMsg = appendFooter(p.commitMsg, line)
+		}
+	}
+	return p.messageRules.Err(p.commitMsg)
+}
+
+// promptError maps a prompt closed by the user to ErrCancelled; any other
+// failure, such as having no terminal to prompt on, is returned as is.
+func promptError(err error, v policy.Violation) error {
+	if errors.Is(err, tea.ErrProgramKilled) || errors.Is(err, tea.ErrInterrupted) {
+		return ErrCancelled
+	}
+	return fmt.Errorf("ask for %s: %w", v, err)
+}
+
+// appendFooter adds line to the message's trailer block, or as a closing
+// paragraph when it is not a "Key: value" trailer.
+func appendFooter(msg, line string) string {
+	if key, value, ok := strings.Cut(line, ": "); ok && key != "" && !strings.ContainsAny(key, " \t") {
+		return git.WithTrailer(msg, key, value)
+	}
+	return strings.TrimRight(msg, "\n") + "\n\n" + line
+}
+
+func describeViolations(violations []policy.Violation) string {
+	parts := make([]string, len(violations))
+	for i, v := range violations {
+		parts[i] = v.String()
+	}
+	return strings.Join(parts, " and ")
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/razobeckett/goco/internal/policy"
+)
+
+func TestPromptError(t *testing.T) {
+	v := policy.Violation{Part: policy.PartFooter, Pattern: "^Refs: "}
+	noTTY := errors.New("open /dev/tty: no such device or address")
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"closed by the user", tea.ErrProgramKilled, ErrCancelled},
+		{"interrupted", fmt.Errorf("run: %w", tea.ErrInterrupted), ErrCancelled},
+		{"no terminal", noTTY, noTTY},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := promptError(tt.err, v)
+			if !errors.Is(got, tt.want) {
+				t.Fatalf("promptError() = %v, want %v", got, tt.want)
+			}
+			if tt.want != ErrCancelled && errors.Is(got, ErrCancelled) {
+				t.Fatalf("a real failure was reported as a cancellation: %v", got)
+			}
+		})
+	}
+}
+
+func TestAppendFooter(t *testing.T) {
+	tests := []struct {
+		msg, line, want string
+	}{
+		{"feat: x", "Refs: PROJ-1", "feat: x\n\nRefs: PROJ-1"},
+		{"feat: x\n\nSigned-off-by: A <a@example.com>", "Refs: PROJ-1", "feat: x\n\nSigned-off-by: A <a@example.com>\nRefs: PROJ-1"},
+		{"feat: x", "Reviewed in the design doc", "feat: x\n\nReviewed in the design doc"},
+	}
+	for _, tt := range tests {
+		if got := appendFooter(tt.msg, tt.line); got != tt.want {
+			t.Errorf("appendFooter(%q, %q) = %q, want %q", tt.msg, tt.line, got, tt.want)
+		}
+	}
+}
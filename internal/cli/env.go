@@ -0,0 +1,205 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/razobeckett/goco/internal/audit"
+	"github.com/razobeckett/goco/internal/cache"
+	"github.com/razobeckett/goco/internal/config"
+	"github.com/razobeckett/goco/internal/forge"
+	"github.com/razobeckett/goco/internal/policy"
+	"github.com/razobeckett/goco/internal/preset"
+	"github.com/razobeckett/goco/internal/state"
+	"github.com/razobeckett/goco/internal/style"
+	"github.com/spf13/cobra"
+)
+
+type envOptions struct {
+	json bool
+}
+
+// envVar is one line of `goco env` output.
+type envVar struct {
+	name  string
+	value string
+}
+
+func newEnvCmd(deps dependencies) *cobra.Command {
+	opts := &envOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "env [name...]",
+		Short:   "Print goco's resolved directories and environment",
+		Long:    "Print the config, cache, and state locations goco resolves on this machine, the repository's hooks path, and which required tools and credentials are present, as shell-quoted NAME='value' lines or JSON. Credentials are reported as set or unset, never printed. With names, print just those values, one per line.",
+		GroupID: "tools",
+		Example: "  goco env\n  goco env GOCO_CONFIG GOCO_STATE_DIR\n  goco env --json",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runEnv(cmd.Context(), deps, opts, args)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Print a JSON object")
+	return cmd
+}
+
+func runEnv(ctx context.Context, deps dependencies, opts *envOptions, names []string) error {
+	vars := collectEnv(ctx, deps)
+
+	if len(names) > 0 {
+		values := make(map[string]string, len(vars))
+		for _, v := range vars {
+			values[v.name] = v.value
+		}
+		for _, name := range names {
+			value, ok := values[name]
+			if !ok {
+				return fmt.Errorf("unknown variable %q; run goco env to list them", name)
+			}
+			fmt.Println(value)
+		}
+		return nil
+	}
+
+	if opts.json {
+		out := make(map[string]string, len(vars))
+		for _, v := range vars {
+			out[v.name] = v.value
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	for _, v := range vars {
+		fmt.Printf("%s=%s\n", v.name, shellQuote(v.value))
+	}
+	return nil
+}
+
+// collectEnv resolves every variable. Failures become empty values (or
+// GOCO_CONFIG_ERROR) because env is most useful when something is broken.
+func collectEnv(ctx context.Context, deps dependencies) []envVar {
+	configPath := deps.configLoader.Path()
+	vars := []envVar{
+		{"GOCO_CONFIG", configPath},
+		{"GOCO_CONFIG_DIR", dirOf(configPath)},
+		{"GOCO_CACHE_DIR", cache.Dir()},
+		{"GOCO_STATE_DIR", state.Dir()},
+		{"GOCO_USER_PRESET", userPresetPath(configPath)},
+		{"GOCO_POLICY_FILE", os.Getenv(policy.EnvVar)},
+	}
+
+	cfg, err := deps.configLoader.Load()
+	if err == nil {
+		// Show the provider and key this repository's [[Route]] selects.
+		err = routeConfig(ctx, deps, cfg)
+	}
+	if err != nil {
+		vars = append(vars, envVar{"GOCO_CONFIG_ERROR", err.Error()})
+		cfg = &config.Config{}
+	}
+	vars = append(vars,
+		envVar{"GOCO_AUDIT_LOG", cfg.AuditPath(audit.DefaultPath())},
+		envVar{"GOCO_DEFAULT_PROVIDER", cfg.DefaultProviderName()},
+		envVar{"GOCO_DEFAULT_MODEL", cfg.General.DefaultModel},
+		envVar{"GOCO_GEMINI_KEY_ENV", cfg.APIKeyEnv("gemini")},
+		envVar{"GOCO_GEMINI_KEY_STATUS", setOrUnset(cfg.APIKey("gemini"))},
+		envVar{"GOCO_GROQ_KEY_ENV", cfg.APIKeyEnv("groq")},
+		envVar{"GOCO_GROQ_KEY_STATUS", setOrUnset(cfg.APIKey("groq"))},
+		envVar{"GOCO_OPENROUTER_KEY_ENV", cfg.APIKeyEnv("openrouter")},
+		envVar{"GOCO_OPENROUTER_KEY_STATUS", setOrUnset(cfg.APIKey("openrouter"))},
+		envVar{"GOCO_MISTRAL_KEY_ENV", cfg.APIKeyEnv("mistral")},
+		envVar{"GOCO_MISTRAL_KEY_STATUS", setOrUnset(cfg.APIKey("mistral"))},
+		envVar{"GOCO_AZURE_OPENAI_KEY_ENV", cfg.APIKeyEnv("azure")},
+		envVar{"GOCO_AZURE_OPENAI_KEY_STATUS", setOrUnset(cfg.APIKey("azure"))},
+		envVar{"GOCO_GROK_KEY_ENV", cfg.APIKeyEnv("grok")},
+		envVar{"GOCO_GROK_KEY_STATUS", setOrUnset(cfg.APIKey("grok"))},
+		envVar{"GOCO_DEEPSEEK_KEY_ENV", cfg.APIKeyEnv("deepseek")},
+		envVar{"GOCO_DEEPSEEK_KEY_STATUS", setOrUnset(cfg.APIKey("deepseek"))},
+		envVar{"GOCO_COHERE_KEY_ENV", cfg.APIKeyEnv("cohere")},
+		envVar{"GOCO_COHERE_KEY_STATUS", setOrUnset(cfg.APIKey("cohere"))},
+		envVar{"GOCO_TOGETHER_KEY_ENV", cfg.APIKeyEnv("together")},
+		envVar{"GOCO_TOGETHER_KEY_STATUS", setOrUnset(cfg.APIKey("together"))},
+		envVar{"GOCO_GITHUB_MODELS_KEY_ENV", cfg.APIKeyEnv("github")},
+		envVar{"GOCO_GITHUB_MODELS_KEY_STATUS", setOrUnset(providerAPIKey(cfg, "github"))},
+	)
+
+	var root, gitDir, hooks string
+	if d, err := deps.repo.GitDir(ctx); err == nil {
+		gitDir = d
+		root, _ = deps.repo.Root(ctx)
+		hooks, _ = deps.repo.HooksDir(ctx)
+	}
+	var repoPreset, repoStyle string
+	if root != "" {
+		repoPreset = preset.RepoPath(root)
+		repoStyle = style.Path(root)
+	}
+	vars = append(vars,
+		envVar{"GOCO_REPO_ROOT", root},
+		envVar{"GOCO_GIT_DIR", gitDir},
+		envVar{"GOCO_HOOKS_PATH", hooks},
+		envVar{"GOCO_REPO_PRESET", repoPreset},
+		envVar{"GOCO_REPO_STYLE", repoStyle},
+	)
+
+	gitPath, _ := exec.LookPath(deps.repo.Binary())
+	gitVersion, _ := deps.repo.Version(ctx)
+	editor, _ := resolveEditor()
+	vars = append(vars,
+		envVar{"GOCO_GIT", gitPath},
+		envVar{"GOCO_GIT_VERSION", gitVersion},
+		envVar{"GOCO_EDITOR", editor},
+		envVar{"GOCO_TERMINAL_REMOTE", strconv.FormatBool(display.remote)},
+		envVar{"GOCO_TERMINAL_COLORS", colorsName(lipgloss.ColorProfile())},
+	)
+
+	for _, kind := range []string{forge.KindGitHub, forge.KindGitLab, forge.KindBitbucket, forge.KindAzureDevOps, forge.KindJira} {
+		// A _STATUS suffix keeps eval'd output from clobbering the real token variables.
+		name := "GOCO_" + strings.ToUpper(strings.ReplaceAll(kind, "-", "_")) + "_TOKEN_STATUS"
+		vars = append(vars, envVar{name, setOrUnset(forge.Token(kind))})
+	}
+	return vars
+}
+
+func dirOf(path string) string {
+	if path == "" {
+		return ""
+	}
+	return filepath.Dir(path)
+}
+
+func userPresetPath(configPath string) string {
+	if configPath == "" {
+		return ""
+	}
+	return preset.UserPath(configPath)
+}
+
+func setOrUnset(secret string) string {
+	if secret == "" {
+		return "unset"
+	}
+	return "set"
+}
+
+// shellQuote single-quotes s for POSIX shells, so the output can be eval'd.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// shellArg quotes s only when a POSIX shell would otherwise split or expand it.
+func shellArg(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./=:@+,") == "" {
+		return s
+	}
+	return shellQuote(s)
+}
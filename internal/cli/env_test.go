@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/razobeckett/goco/internal/config"
+	"github.com/razobeckett/goco/internal/git"
+)
+
+func TestCollectEnv(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "config"))
+	t.Setenv("XDG_STATE_HOME", filepath.Join(home, "state"))
+	t.Setenv("GOCO_GITHUB_TOKEN", "secret-token")
+	for _, name := range []string{"GITHUB_TOKEN", "GH_TOKEN", "GOCO_GITLAB_TOKEN", "GITLAB_TOKEN"} {
+		t.Setenv(name, "")
+	}
+
+	configFile := filepath.Join(home, "config", "goco", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(configFile), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(configFile, []byte("[General]\ndefault_provider = \"groq\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := initTestRepo(t)
+	deps := dependencies{configLoader: config.NewLoader(), repo: git.NewRepository(dir)}
+	vars := make(map[string]string)
+	for _, v := range collectEnv(context.Background(), deps) {
+		vars[v.name] = v.value
+	}
+
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"GOCO_CONFIG":              configFile,
+		"GOCO_CONFIG_DIR":          filepath.Dir(configFile),
+		"GOCO_STATE_DIR":           filepath.Join(home, "state", "goco"),
+		"GOCO_AUDIT_LOG":           "",
+		"GOCO_DEFAULT_PROVIDER":    "groq",
+		"GOCO_REPO_ROOT":           root,
+		"GOCO_GITHUB_TOKEN_STATUS": "set",
+		// GitHub Models takes the GitHub token.
+		"GOCO_GITHUB_MODELS_KEY_STATUS": "set",
+		"GOCO_GITLAB_TOKEN_STATUS":      "unset",
+	}
+	for name, value := range want {
+		got, ok := vars[name]
+		if !ok {
+			t.Errorf("%s missing from goco env", name)
+			continue
+		}
+		if name == "GOCO_REPO_ROOT" {
+			got, _ = filepath.EvalSymlinks(got)
+		}
+		if got != value {
+			t.Errorf("%s = %q, want %q", name, got, value)
+		}
+	}
+	if _, ok := vars["GOCO_CONFIG_ERROR"]; ok {
+		t.Errorf("unexpected config error: %s", vars["GOCO_CONFIG_ERROR"])
+	}
+	for name, value := range vars {
+		if strings.Contains(value, "secret-token") {
+			t.Errorf("%s leaks a credential", name)
+		}
+	}
+}
+
+func TestCollectEnvRoute(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "config"))
+	t.Setenv("XDG_STATE_HOME", filepath.Join(home, "state"))
+
+	configFile := filepath.Join(home, "config", "goco", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(configFile), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	routes := `[[Route]]
+remote = "github.com/acme/*"
+provider = "groq"
+model = "llama-3.1-8b-instant"
+api_key_env = "ACME_GROQ_KEY"
+`
+	if err := os.WriteFile(configFile, []byte(routes), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := initTestRepo(t)
+	runGit(t, dir, "remote", "add", "origin", "git@github.com:acme/api.git")
+	deps := dependencies{configLoader: config.NewLoader(), repo: git.NewRepository(dir)}
+	vars := make(map[string]string)
+	for _, v := range collectEnv(context.Background(), deps) {
+		vars[v.name] = v.value
+	}
+
+	want := map[string]string{
+		"GOCO_DEFAULT_PROVIDER": "groq",
+		"GOCO_DEFAULT_MODEL":    "llama-3.1-8b-instant",
+		"GOCO_GROQ_KEY_ENV":     "ACME_GROQ_KEY",
+	}
+	for name, value := range want {
+		if got := vars[name]; got != value {
+			t.Errorf("%s = %q, want %q", name, got, value)
+		}
+	}
+}
+
+func TestProviderAPIKey(t *testing.T) {
+	gh := ghToken
+	ghToken = func() string { return "gh-token" }
+	t.Cleanup(func() { ghToken = gh })
+
+	cfg := &config.Config{General: config.General{GroqAPIKeyEnv: "TEST_GROQ_KEY", GitHubAPIKeyEnv: "TEST_GITHUB_MODELS_KEY"}}
+	tests := []struct {
+		name     string
+		provider string
+		env      map[string]string
+		want     string
+	}{
+		{name: "configured variable", provider: "github", env: map[string]string{"TEST_GITHUB_MODELS_KEY": "models-key", "GITHUB_TOKEN": "github-token"}, want: "models-key"},
+		{name: "GitHub token", provider: "github", env: map[string]string{"GITHUB_TOKEN": "github-token"}, want: "github-token"},
+		{name: "gh CLI", provider: "github", want: "gh-token"},
+		// Only GitHub Models takes a GitHub token.
+		{name: "other provider", provider: "groq", env: map[string]string{"GITHUB_TOKEN": "github-token"}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{"TEST_GROQ_KEY", "TEST_GITHUB_MODELS_KEY", "GOCO_GITHUB_TOKEN", "GITHUB_TOKEN", "GH_TOKEN"} {
+				t.Setenv(name, tt.env[name])
+			}
+			if got := providerAPIKey(cfg, tt.provider); got != tt.want {
+				t.Errorf("providerAPIKey(%q) = %q, want %q", tt.provider, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunEnvUnknownName(t *testing.T) {
+	deps := dependencies{configLoader: config.NewLoader(), repo: git.NewRepository(t.TempDir())}
+	if err := runEnv(context.Background(), deps, &envOptions{}, []string{"GOCO_NOPE"}); err == nil || !strings.Contains(err.Error(), "GOCO_NOPE") {
+		t.Fatalf("expected an unknown variable error, got %v", err)
+	}
+}
+
+func TestShellQuoting(t *testing.T) {
+	tests := []struct {
+		in, quote, arg string
+	}{
+		{"", "''", "''"},
+		{"/usr/bin/git", "'/usr/bin/git'", "/usr/bin/git"},
+		{"it's here", `'it'\''s here'`, `'it'\''s here'`},
+		{"$HOME", "'$HOME'", "'$HOME'"},
+	}
+	for _, tt := range tests {
+		if got := shellQuote(tt.in); got != tt.quote {
+			t.Errorf("shellQuote(%q) = %s, want %s", tt.in, got, tt.quote)
+		}
+		if got := shellArg(tt.in); got != tt.arg {
+			t.Errorf("shellArg(%q) = %s, want %s", tt.in, got, tt.arg)
+		}
+	}
+}
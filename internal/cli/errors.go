@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/razobeckett/goco/internal/policy"
+)
+
+// Output formats for --output.
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// Error categories reported with --output json. They are stable, so tools
+// can branch on them rather than on the message.
+const (
+	categoryUsage       = "usage"       // a bad command line
+	categoryInterrupted = "interrupted" // Ctrl-C or SIGTERM
+	categoryPolicy      = "policy"      // denied by the organization policy
+	categoryConfig      = "config"      // the config, provider, or API key could not be resolved
+	categoryRepository  = "repository"  // reading or committing to the repository failed
+	categoryProvider    = "provider"    // the AI provider failed to write the message
+	categoryMessage     = "message"     // the message broke the repository's rules
+	categoryFailure     = "failure"     // anything else
+)
+
+// stageCategories maps pipeline stages to the category of their failures.
+var stageCategories = map[string]string{
+	"resolve":  categoryConfig,
+	"inspect":  categoryRepository,
+	"collect":  categoryRepository,
+	"apply":    categoryRepository,
+	"generate": categoryProvider,
+	"enforce":  categoryMessage,
+	"validate": categoryMessage,
+}
+
+// categoryHints suggests a next step for each category.
+var categoryHints = map[string]string{
+	categoryUsage:      "run goco help for the commands and flags",
+	categoryPolicy:     "the policy file named by GOCO_POLICY_FILE lists the allowed providers and models",
+	categoryConfig:     "run goco env to check the config file and which API keys are set",
+	categoryRepository: "run git status to check the repository",
+	categoryProvider:   "check the API key and model with goco status, or try another --provider",
+	categoryMessage:    "edit the message, or adjust the rules under [Message]",
+}
+
+// stageError is the failure of a pipeline stage.
+type stageError struct {
+	stage string
+	err   error
+}
+
+func (e stageError) Error() string { return e.stage + ": " + e.err.Error() }
+func (e stageError) Unwrap() error { return e.err }
+
+// errorReport is an error as --output json prints it.
+type errorReport struct {
+	// Code is the exit code goco returns.
+	Code     int    `json:"code"`
+	Category string `json:"category"`
+	Message  string `json:"message"`
+	Hint     string `json:"hint,omitempty"`
+}
+
+// errorCategory classifies err into one of the error categories.
+func errorCategory(err error) string {
+	switch {
+	case IsUsageError(err):
+		return categoryUsage
+	case errors.Is(err, context.Canceled):
+		return categoryInterrupted
+	case errors.Is(err, policy.ErrDenied):
+		return categoryPolicy
+	default:
+		return stageCategory(err)
+	}
+}
+
+// stageCategory returns the category of the innermost stage err failed in
+// that has one, as stages such as "packages" run stages of their own.
+func stageCategory(err error) string {
+	category := categoryFailure
+	var stage stageError
+	for errors.As(err, &stage) {
+		if c, ok := stageCategories[stage.stage]; ok {
+			category = c
+		}
+		err = stage.err
+	}
+	return category
+}
+
+// WriteJSONError writes err to w as a one-line JSON object with the exit
+// code, category, message, and a hint for what to do next.
+func WriteJSONError(w io.Writer, err error, code int) {
+	category := errorCategory(err)
+	_ = json.NewEncoder(w).Encode(errorReport{
+		Code:     code,
+		Category: category,
+		Message:  err.Error(),
+		Hint:     categoryHints[category],
+	})
+}
+
+// JSONOutput reports whether args ask for --output json. The arguments are
+// scanned rather than parsed so that errors in parsing them are reported as
+// JSON too; the last --output wins.
+func JSONOutput(args []string) bool {
+	format := outputText
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+		if value, ok := strings.CutPrefix(arg, "--output="); ok {
+			format = value
+		} else if arg == "--output" && i+1 < len(args) {
+			i++
+			format = args[i]
+		}
+	}
+	return format == outputJSON
+}
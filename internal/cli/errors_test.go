@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/razobeckett/goco/internal/policy"
+)
+
+func TestErrorCategory(t *testing.T) {
+	failure := errors.New("boom")
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "usage", err: usageError{failure}, want: categoryUsage},
+		{name: "interrupted", err: stageError{"generate", context.Canceled}, want: categoryInterrupted},
+		{name: "policy", err: stageError{"resolve", fmt.Errorf("%w: provider groq", policy.ErrDenied)}, want: categoryPolicy},
+		{name: "stage", err: stageError{"resolve", failure}, want: categoryConfig},
+		{name: "innermost stage", err: stageError{"packages", fmt.Errorf("commit cli: %w", stageError{"apply", failure})}, want: categoryRepository},
+		{name: "uncategorized stage", err: stageError{"issue", failure}, want: categoryFailure},
+		{name: "no stage", err: failure, want: categoryFailure},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorCategory(tt.err); got != tt.want {
+				t.Errorf("errorCategory(%v) = %q, want %q", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteJSONError(t *testing.T) {
+	var buf bytes.Buffer
+	WriteJSONError(&buf, stageError{"generate", errors.New("401 Unauthorized: bad key")}, 1)
+
+	var got errorReport
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("not JSON: %q: %v", buf.String(), err)
+	}
+	want := errorReport{Code: 1, Category: categoryProvider, Message: "generate: 401 Unauthorized: bad key", Hint: categoryHints[categoryProvider]}
+	if got != want {
+		t.Errorf("report = %+v, want %+v", got, want)
+	}
+	if bytes.Count(buf.Bytes(), []byte("\n")) != 1 {
+		t.Errorf("report spans lines: %q", buf.String())
+	}
+}
+
+func TestJSONOutput(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{args: nil, want: false},
+		{args: []string{"generate", "--output", "json"}, want: true},
+		{args: []string{"--output=json", "status"}, want: true},
+		{args: []string{"--output", "json", "--output=text"}, want: false},
+		{args: []string{"generate", "--", "--output=json"}, want: false},
+		{args: []string{"format-patch", "--output-directory", "json"}, want: false},
+	}
+	for _, tt := range tests {
+		if got := JSONOutput(tt.args); got != tt.want {
+			t.Errorf("JSONOutput(%q) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
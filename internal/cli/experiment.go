@@ -0,0 +1,263 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"github.com/razobeckett/goco/internal/ai"
+	"github.com/razobeckett/goco/internal/audit"
+	"github.com/razobeckett/goco/internal/policy"
+	"github.com/spf13/cobra"
+)
+
+// defaultTemplateName selects the built-in prompt in --prompts.
+const defaultTemplateName = "default"
+
+type experimentOptions struct {
+	provider string
+	apiKey   string
+	models   []string
+	prompts  []string
+	fixtures string
+}
+
+// experimentFixture is a recorded diff, with an optional status summary
+// stored next to it as <name>.status.
+type experimentFixture struct {
+	name   string
+	status string
+	diff   string
+}
+
+type experimentResult struct {
+	template   string
+	model      string
+	runs       int
+	passed     int
+	failed     int
+	subjectLen int
+	messageLen int
+}
+
+func newExperimentCmd(deps dependencies) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "experiment",
+		Short:   "Compare prompt templates and models on recorded diffs",
+		GroupID: "tools",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newExperimentRunCmd(deps))
+	return cmd
+}
+
+func newExperimentRunCmd(deps dependencies) *cobra.Command {
+	opts := &experimentOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "run",
+		Short:   "Replay fixture diffs through prompt templates and report lint results",
+		Long:    "Replay every *.diff file in the fixtures directory through each prompt template and model, then report the Conventional Commit lint pass rate and message length statistics.",
+		Args:    cobra.NoArgs,
+		Example: "  goco experiment run --prompts default,terse.tmpl --fixtures testdata/diffs\n  goco experiment run --prompts a.tmpl,b.tmpl --fixtures diffs/ --provider groq --models llama-3.3-70b-versatile,llama-3.1-8b-instant",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runExperiment(cmd, deps, opts)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&opts.prompts, "prompts", []string{defaultTemplateName}, "Comma-separated prompt template files (\"default\" for the built-in prompt)")
+	cmd.Flags().StringVar(&opts.fixtures, "fixtures", "", "Directory of recorded *.diff fixtures")
+	cmd.Flags().StringVarP(&opts.provider, "provider", "p", "", providerUsage("AI provider to use"))
+	cmd.Flags().StringVarP(&opts.apiKey, "api-key", "k", "", "API key for the selected provider")
+	cmd.Flags().StringSliceVar(&opts.models, "models", nil, "Comma-separated models to compare (defaults to the provider's recommended model)")
+	_ = cmd.MarkFlagRequired("fixtures")
+	return cmd
+}
+
+func runExperiment(cmd *cobra.Command, deps dependencies, opts *experimentOptions) error {
+	ctx := cmd.Context()
+	if err := deps.requireNetwork("comparing prompts"); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(ctx, deps)
+	if err != nil {
+		return err
+	}
+
+	providerName := opts.provider
+	if providerName == "" {
+		providerName = cfg.DefaultProviderName()
+	}
+	if err := checkProviderName(providerName); err != nil {
+		return err
+	}
+
+	fixtures, err := loadExperimentFixtures(opts.fixtures)
+	if err != nil {
+		return err
+	}
+
+	templates, err := loadExperimentTemplates(opts.prompts)
+	if err != nil {
+		return err
+	}
+
+	spec, err := cfg.Spec()
+	if err != nil {
+		return err
+	}
+
+	models := opts.models
+	if len(models) == 0 {
+		models = []string{cfg.ModelFor(providerName)}
+	}
+
+	pol, err := policy.Load()
+	if err != nil {
+		return err
+	}
+	for _, model := range models {
+		if err := pol.Check(policyRequest(providerOptions(cfg), providerName, model)); err != nil {
+			return fmt.Errorf("%w (policy file: %s)", err, pol.Path())
+		}
+	}
+
+	apiKey, err := resolveAPIKey(cfg, providerName, opts.apiKey)
+	if err != nil {
+		return err
+	}
+
+	auditLog := audit.Open(cfg.AuditPath(audit.DefaultPath()))
+
+	var results []experimentResult
+	for _, model := range models {
+		provider, err := ai.NewProvider(ctx, providerName, apiKey, model, providerOptions(cfg))
+		if err != nil {
+			return err
+		}
+		if model == "" {
+			model = provider.DefaultModel()
+		}
+		provider = withAudit(provider, auditLog, opts.fixtures, model)
+
+		for i, tmpl := range templates {
+			result := experimentResult{template: opts.prompts[i], model: model}
+			for _, fixture := range fixtures {
+				msg, err := spin(ctx, fmt.Sprintf("%s × %s × %s", result.template, model, fixture.name), func(ctx context.Context) (string, error) {
+					return provider.GenerateCommitMessage(ctx, ai.PromptInput{
+						Status:   fixture.status,
+						Diff:     fixture.diff,
+						Spec:     spec,
+						Template: tmpl,
+					})
+				})
+				if err != nil {
+					if ctx.Err() != nil {
+						return ctx.Err()
+					}
+					result.failed++
+					continue
+				}
+
+				result.record(msg)
+			}
+			results = append(results, result)
+		}
+	}
+
+	printExperimentResults(results, len(fixtures))
+	return nil
+}
+
+// record adds one generated message to the result's statistics.
+func (r *experimentResult) record(msg string) {
+	msg = strings.TrimSpace(msg)
+	r.runs++
+	r.messageLen += len(msg)
+	r.subjectLen += len(strings.SplitN(msg, "\n", 2)[0])
+	if lintCommitMessage(msg) == nil {
+		r.passed++
+	}
+}
+
+// summary formats the pass rate and average subject and message lengths;
+// each is "-" when no message was generated.
+func (r experimentResult) summary() (pass, avgSubject, avgLength string) {
+	if r.runs == 0 {
+		return "-", "-", "-"
+	}
+	pass = fmt.Sprintf("%d/%d (%.0f%%)", r.passed, r.runs, 100*float64(r.passed)/float64(r.runs))
+	avgSubject = fmt.Sprintf("%.1f", float64(r.subjectLen)/float64(r.runs))
+	avgLength = fmt.Sprintf("%.1f", float64(r.messageLen)/float64(r.runs))
+	return pass, avgSubject, avgLength
+}
+
+func loadExperimentFixtures(dir string) ([]experimentFixture, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.diff"))
+	if err != nil {
+		return nil, fmt.Errorf("list fixtures: %w", err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no *.diff fixtures found in %q", dir)
+	}
+
+	fixtures := make([]experimentFixture, 0, len(paths))
+	for _, path := range paths {
+		diff, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read fixture: %w", err)
+		}
+
+		name := strings.TrimSuffix(filepath.Base(path), ".diff")
+		fixture := experimentFixture{name: name, diff: string(diff)}
+		if status, err := os.ReadFile(strings.TrimSuffix(path, ".diff") + ".status"); err == nil {
+			fixture.status = string(status)
+		}
+		fixtures = append(fixtures, fixture)
+	}
+
+	return fixtures, nil
+}
+
+// loadExperimentTemplates parses each template; a nil entry means the built-in prompt.
+func loadExperimentTemplates(names []string) ([]*template.Template, error) {
+	templates := make([]*template.Template, 0, len(names))
+	for _, name := range names {
+		if name == defaultTemplateName {
+			templates = append(templates, nil)
+			continue
+		}
+
+		text, err := os.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("read prompt template: %w", err)
+		}
+		tmpl, err := ai.ParsePromptTemplate(string(text))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		templates = append(templates, tmpl)
+	}
+	return templates, nil
+}
+
+func printExperimentResults(results []experimentResult, fixtures int) {
+	fmt.Println(modelProviderStyle.Render(fmt.Sprintf("Experiment Results (%d fixtures)", fixtures)))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TEMPLATE\tMODEL\tPASS\tAVG SUBJECT\tAVG LENGTH\tERRORS")
+	for _, r := range results {
+		pass, avgSubject, avgLength := r.summary()
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\n", r.template, r.model, pass, avgSubject, avgLength, r.failed)
+	}
+	w.Flush()
+}
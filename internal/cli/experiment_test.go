@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadExperimentFixtures(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		want    []experimentFixture
+		wantErr string
+	}{
+		{
+			name: "diffs with optional status",
+			files: map[string]string{
+				"b.diff":   "diff b",
+				"a.diff":   "diff a",
+				"a.status": "branch: main",
+				"notes.md": "ignored",
+			},
+			want: []experimentFixture{
+				{name: "a", status: "branch: main", diff: "diff a"},
+				{name: "b", diff: "diff b"},
+			},
+		},
+		{
+			name:    "no fixtures",
+			files:   map[string]string{"a.status": "branch: main"},
+			wantErr: "no *.diff fixtures",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := loadExperimentFixtures(dir)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %d fixtures, got %+v", len(tt.want), got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("fixture %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLoadExperimentTemplates(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "terse.tmpl")
+	bad := filepath.Join(dir, "bad.tmpl")
+	if err := os.WriteFile(good, []byte("Describe:\n{{.Diff}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(bad, []byte("{{.Diff"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	templates, err := loadExperimentTemplates([]string{defaultTemplateName, good})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != 2 || templates[0] != nil || templates[1] == nil {
+		t.Fatalf("expected the built-in prompt then a parsed template, got %v", templates)
+	}
+
+	for _, names := range [][]string{{bad}, {filepath.Join(dir, "missing.tmpl")}} {
+		if _, err := loadExperimentTemplates(names); err == nil {
+			t.Errorf("expected %v to fail", names)
+		}
+	}
+}
+
+func TestExperimentResultSummary(t *testing.T) {
+	tests := []struct {
+		name                           string
+		messages                       []string
+		failed                         int
+		wantPass, wantSubject, wantLen string
+	}{
+		{
+			name:        "no messages",
+			failed:      2,
+			wantPass:    "-",
+			wantSubject: "-",
+			wantLen:     "-",
+		},
+		{
+			name:        "one of two passes lint",
+			messages:    []string{"feat: add x", "  Added y and z  "},
+			wantPass:    "1/2 (50%)",
+			wantSubject: "12.0",
+			wantLen:     "12.0",
+		},
+		{
+			name:        "body counts toward length only",
+			messages:    []string{"fix: y\n\nBody."},
+			wantPass:    "1/1 (100%)",
+			wantSubject: "6.0",
+			wantLen:     "13.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := experimentResult{failed: tt.failed}
+			for _, msg := range tt.messages {
+				r.record(msg)
+			}
+			pass, subject, length := r.summary()
+			if pass != tt.wantPass || subject != tt.wantSubject || length != tt.wantLen {
+				t.Fatalf("summary() = %q, %q, %q; want %q, %q, %q", pass, subject, length, tt.wantPass, tt.wantSubject, tt.wantLen)
+			}
+		})
+	}
+}
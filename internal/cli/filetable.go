@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/razobeckett/goco/internal/git"
+)
+
+// How much of a file's diff the prompt carries.
+const (
+	promptIncluded   = "included"
+	promptMinimized  = "minimized"
+	promptTruncated  = "truncated"
+	promptExcluded   = "excluded"
+	promptSummarized = "summarized"
+	promptCountsOnly = "counts only"
+)
+
+// fileAnnotation is one row of the verbose file table.
+type fileAnnotation struct {
+	path     string
+	language string
+	added    int
+	deleted  int
+	scope    string
+	prompt   string
+}
+
+// annotateFiles describes each file of the diff goco read and how much of it
+// reaches the provider when the message is written from sent.
+func (p *Pipeline) annotateFiles(sent string) []fileAnnotation {
+	files := git.FileChanges(p.diff)
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.Path
+	}
+	scopes := make(map[string]string)
+	for _, pkg := range git.GroupByPackage(p.root, paths) {
+		for _, path := range pkg.Paths {
+			scopes[path] = pkg.Name()
+		}
+	}
+	sentFiles := make(map[string]string)
+	for _, f := range git.FileChanges(sent) {
+		sentFiles[f.Path] = f.Section
+	}
+	summariesLead, _, _ := strings.Cut(summariesIntro, "%d")
+
+	annotations := make([]fileAnnotation, len(files))
+	for i, f := range files {
+		a := fileAnnotation{path: f.Path, language: git.Language(f.Path), added: f.Added, deleted: f.Deleted, scope: scopes[f.Path]}
+		section, ok := sentFiles[f.Path]
+		switch {
+		case strings.HasPrefix(sent, summariesLead):
+			a.prompt = promptSummarized
+		case strings.HasPrefix(sent, statOnlyIntro):
+			a.prompt = promptCountsOnly
+		case !ok:
+			a.prompt = promptExcluded
+		case strings.Contains(f.Section, "more bytes of diff omitted") || strings.Contains(section, "more bytes of diff omitted"):
+			a.prompt = promptTruncated
+		case strings.Contains(section, "diff omitted"):
+			// Vendored files, LFS pointers, and files past the read limit
+			// are sent as a one-line note.
+			a.prompt = promptExcluded
+		case section != f.Section:
+			a.prompt = promptMinimized
+		default:
+			a.prompt = promptIncluded
+		}
+		annotations[i] = a
+	}
+	return annotations
+}
+
+// formatFileTable renders annotations as a table for verbose output. The
+// columns are padded to the width the terminal draws, as a tabwriter would
+// misalign them after paths in CJK or with emoji, which take two cells.
+func formatFileTable(annotations []fileAnnotation) string {
+	rows := [][]string{{"FILE", "LANGUAGE", "+/-", "SCOPE", "PROMPT"}}
+	for _, a := range annotations {
+		rows = append(rows, []string{a.path, orDash(a.language), fmt.Sprintf("+%d -%d", a.added, a.deleted), orDash(a.scope), a.prompt})
+	}
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], lipgloss.Width(cell))
+		}
+	}
+
+	var b strings.Builder
+	for _, row := range rows {
+		last := len(row) - 1
+		for i, cell := range row[:last] {
+			b.WriteString(cell + strings.Repeat(" ", widths[i]-lipgloss.Width(cell)+2))
+		}
+		b.WriteString(row[last] + "\n")
+	}
+	return b.String()
+}
+
+// orDash returns s, or "-" for an empty table cell.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
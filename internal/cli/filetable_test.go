@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/razobeckett/goco/internal/git"
+)
+
+func TestAnnotateFiles(t *testing.T) {
+	small := "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-a\n+b\n"
+	vendored := "diff --git a/vendor/x/x.go b/vendor/x/x.go\n--- a/vendor/x/x.go\n+++ b/vendor/x/x.go\n@@ -1 +1,2 @@\n package x\n+var X = 1\n"
+	large := "diff --git a/b.py b/b.py\n--- a/b.py\n+++ b/b.py\n@@ -0,0 +1,3 @@\n+a = 1\n+b = 2\n+c = 3\n"
+	diff := small + vendored + large
+
+	p := NewPipeline(dependencies{}, &generateOptions{})
+	p.root = t.TempDir()
+	p.diff = diff
+
+	kept, _ := git.TruncateDiff(diff, len(small)+10)
+
+	tests := []struct {
+		name string
+		sent string
+		want []string
+	}{
+		{name: "whole", sent: diff, want: []string{promptIncluded, promptIncluded, promptIncluded}},
+		{name: "minimized", sent: git.MinimizeDiff(diff, git.DefaultMinimize), want: []string{promptIncluded, promptExcluded, promptIncluded}},
+		{name: "cut", sent: kept, want: []string{promptIncluded, promptExcluded, promptExcluded}},
+		{name: "stat only", sent: statOnlyIntro + git.DiffStat(diff), want: []string{promptCountsOnly, promptCountsOnly, promptCountsOnly}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := p.annotateFiles(tt.sent)
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %d files, got %+v", len(tt.want), got)
+			}
+			for i, a := range got {
+				if a.prompt != tt.want[i] {
+					t.Errorf("%s: prompt = %q, want %q", a.path, a.prompt, tt.want[i])
+				}
+			}
+		})
+	}
+
+	table := formatFileTable(p.annotateFiles(diff))
+	for _, want := range []string{"FILE", "a.go", "Go", "+1 -1", "b.py", "Python", "+3 -0"} {
+		if !strings.Contains(table, want) {
+			t.Errorf("file table %q does not contain %q", table, want)
+		}
+	}
+}
+
+func TestFormatFileTableWideCharacters(t *testing.T) {
+	table := formatFileTable([]fileAnnotation{
+		{path: "文档/说明.md", language: "Markdown", added: 1, prompt: promptIncluded},
+		{path: "🎉 party.txt", added: 2, prompt: promptIncluded},
+		{path: "main.go", language: "Go", added: 3, deleted: 1, prompt: promptIncluded},
+	})
+	// Every row's LANGUAGE column starts in the same terminal cell.
+	var starts []int
+	for _, line := range strings.Split(strings.TrimSuffix(table, "\n"), "\n") {
+		path, _, _ := strings.Cut(line, "  ")
+		rest := strings.TrimLeft(line[len(path):], " ")
+		starts = append(starts, lipgloss.Width(line[:len(line)-len(rest)]))
+	}
+	for i, start := range starts {
+		if start != starts[0] {
+			t.Errorf("row %d's second column starts at cell %d, want %d:\n%s", i, start, starts[0], table)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// flagAliases maps misspellings of flags, some of which goco once accepted,
+// to the flags they mean. They keep working, hidden from help, and pflag
+// warns on every use.
+var flagAliases = map[string]string{
+	"stagged":            "staged",
+	"custom-instruction": "custom-instructions",
+	"apikey":             "api-key",
+}
+
+// maxFlagSuggestionDistance is how many edits away from an unknown flag a
+// known one may be and still be suggested.
+const maxFlagSuggestionDistance = 2
+
+// addFlagAliases adds the aliases of every flag cmd and its subcommands
+// define.
+func addFlagAliases(cmd *cobra.Command) {
+	fs := cmd.Flags()
+	for alias, name := range flagAliases {
+		f := fs.Lookup(name)
+		if f == nil || fs.Lookup(alias) != nil {
+			continue
+		}
+		// Sharing Value makes the alias set the same variable.
+		fs.AddFlag(&pflag.Flag{
+			Name:        alias,
+			Usage:       f.Usage,
+			Value:       f.Value,
+			DefValue:    f.DefValue,
+			NoOptDefVal: f.NoOptDefVal,
+			Hidden:      true,
+			Deprecated:  "use --" + name,
+		})
+	}
+	for _, sub := range cmd.Commands() {
+		addFlagAliases(sub)
+	}
+}
+
+// suggestFlag adds the closest flag of cmd to an unknown flag error.
+func suggestFlag(cmd *cobra.Command, err error) error {
+	unknown, ok := strings.CutPrefix(err.Error(), "unknown flag: --")
+	if !ok {
+		return err
+	}
+	best, bestDistance := "", maxFlagSuggestionDistance+1
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if f.Hidden {
+			return
+		}
+		if d := editDistance(unknown, f.Name); d < bestDistance || (d == bestDistance && f.Name < best) {
+			best, bestDistance = f.Name, d
+		}
+	})
+	if best == "" {
+		return err
+	}
+	return fmt.Errorf("%w\n\nDid you mean this?\n\t--%s", err, best)
+}
+
+// suggestCommand adds cobra's suggestions for a mistyped subcommand of cmd
+// to err. cobra.NoArgs reports it without them.
+func suggestCommand(cmd *cobra.Command, args []string, err error) error {
+	if !cmd.HasSubCommands() || len(args) == 0 || cmd.DisableSuggestions {
+		return err
+	}
+	suggestions := cmd.SuggestionsFor(args[0])
+	if len(suggestions) == 0 {
+		return err
+	}
+	return fmt.Errorf("%w\n\nDid you mean this?\n\t%s", err, strings.Join(suggestions, "\n\t"))
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
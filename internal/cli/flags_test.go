@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestFlagAliases(t *testing.T) {
+	opts := newGenerateOptions()
+	cmd := &cobra.Command{Use: "generate", RunE: func(*cobra.Command, []string) error { return nil }}
+	bindGenerateFlags(cmd.Flags(), opts)
+	addFlagAliases(cmd)
+	cmd.Flags().SetOutput(io.Discard)
+
+	if err := cmd.ParseFlags([]string{"--stagged", "--apikey", "sk-1"}); err != nil {
+		t.Fatalf("ParseFlags failed: %v", err)
+	}
+	if !opts.staged || opts.apiKey != "sk-1" {
+		t.Fatalf("expected the aliases to set --staged and --api-key, got %+v", *opts)
+	}
+	if f := cmd.Flags().Lookup("stagged"); f == nil || !f.Hidden || f.Deprecated == "" {
+		t.Fatalf("expected --stagged to be a hidden, deprecated alias, got %+v", f)
+	}
+}
+
+func TestSuggestFlag(t *testing.T) {
+	cmd := &cobra.Command{Use: "generate"}
+	bindGenerateFlags(cmd.Flags(), newGenerateOptions())
+	addFlagAliases(cmd)
+	cmd.Flags().SetOutput(io.Discard)
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"--stagd"}, want: "--staged"},
+		{args: []string{"--modle", "x"}, want: "--model"},
+		{args: []string{"--two-pas"}, want: "--two-pass"},
+		{args: []string{"--frobnicate"}, want: ""},
+	}
+	for _, tt := range tests {
+		err := cmd.ParseFlags(tt.args)
+		if err == nil {
+			t.Fatalf("%v: expected an unknown flag error", tt.args)
+		}
+		got := suggestFlag(cmd, err).Error()
+		if tt.want == "" {
+			if strings.Contains(got, "Did you mean") {
+				t.Errorf("%v: unexpected suggestion in %q", tt.args, got)
+			}
+			continue
+		}
+		if !strings.HasSuffix(got, "Did you mean this?\n\t"+tt.want) {
+			t.Errorf("%v: %q does not suggest %s", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestSuggestCommand(t *testing.T) {
+	root := &cobra.Command{Use: "goco", Args: cobra.NoArgs}
+	run := func(*cobra.Command, []string) {}
+	root.AddCommand(&cobra.Command{Use: "generate", Run: run}, &cobra.Command{Use: "models", Run: run})
+
+	err := cobra.NoArgs(root, []string{"generat"})
+	if got := suggestCommand(root, []string{"generat"}, err).Error(); !strings.HasSuffix(got, "Did you mean this?\n\tgenerate") {
+		t.Fatalf("suggestCommand() = %q, want a suggestion of generate", got)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"staged", "staged", 0},
+		{"stagged", "staged", 1},
+		{"stagd", "staged", 1},
+		{"modle", "model", 2},
+		{"", "yes", 3},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
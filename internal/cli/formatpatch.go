@@ -0,0 +1,270 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/razobeckett/goco/internal/ai"
+	"github.com/razobeckett/goco/internal/git"
+	"github.com/razobeckett/goco/internal/state"
+	"github.com/spf13/cobra"
+)
+
+// defaultSeriesRange is the patch series when no range is given: everything
+// not yet on the upstream branch.
+const defaultSeriesRange = "@{upstream}..HEAD"
+
+const (
+	coverLetterSubjectPlaceholder = "*** SUBJECT HERE ***"
+	coverLetterBlurbPlaceholder   = "*** BLURB HERE ***"
+)
+
+type formatPatchOptions struct {
+	generate    *generateOptions
+	outputDir   string
+	coverLetter bool
+	lintOnly    bool
+	regenerate  bool
+}
+
+// seriesLint is a commit of the series with the result of linting its subject.
+type seriesLint struct {
+	entry git.LogEntry
+	err   error
+}
+
+func newFormatPatchCmd(deps dependencies) *cobra.Command {
+	opts := &formatPatchOptions{generate: newGenerateOptions()}
+
+	cmd := &cobra.Command{
+		Use:     "format-patch [revision-range]",
+		Short:   "Lint a patch series and run git format-patch with a generated cover letter",
+		Long:    "Lint the subject of every commit in the series (default " + defaultSeriesRange + "; a bare revision means <revision>..HEAD), optionally suggest regenerated messages for the ones that fail, then run git format-patch. With --cover-letter, the cover letter's subject and blurb are generated from the series.",
+		GroupID: "main",
+		Args:    cobra.MaximumNArgs(1),
+		Example: "  goco format-patch --lint-only\n  goco format-patch origin/main..HEAD --regenerate\n  goco format-patch origin/main..HEAD --cover-letter -o outgoing/",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			revRange := defaultSeriesRange
+			if len(args) == 1 {
+				revRange = args[0]
+			}
+			return runFormatPatch(cmd.Context(), deps, opts, revRange)
+		},
+	}
+
+	fs := cmd.Flags()
+	fs.StringVarP(&opts.outputDir, "output-directory", "o", "", "Directory to write patches to (passed to git format-patch)")
+	fs.BoolVar(&opts.coverLetter, "cover-letter", false, "Generate a cover letter summarizing the series")
+	fs.BoolVar(&opts.lintOnly, "lint-only", false, "Only lint commit subjects; do not write patches")
+	fs.BoolVar(&opts.regenerate, "regenerate", false, "Suggest regenerated messages for commits whose subject fails lint")
+	fs.StringVarP(&opts.generate.provider, "provider", "p", "", providerUsage("AI provider to use"))
+	fs.StringVarP(&opts.generate.apiKey, "api-key", "k", "", "API key for the selected provider")
+	fs.StringVarP(&opts.generate.model, "model", "m", "", "Model to use (defaults to the provider's recommended model)")
+	fs.StringVarP(&opts.generate.customInstructions, "custom-instructions", "c", "", "Additional instructions to add to the AI prompt")
+	return cmd
+}
+
+func runFormatPatch(ctx context.Context, deps dependencies, opts *formatPatchOptions, revRange string) error {
+	if !opts.lintOnly {
+		if err := deps.requireWritable("writing patch files"); err != nil {
+			return fmt.Errorf("%w; add --lint-only to only check the series", err)
+		}
+	}
+	p := NewPipeline(deps, opts.generate)
+	var lints []seriesLint
+
+	var stages []pipelineStage
+	// Only resolve a provider when something will actually be generated.
+	if opts.regenerate || (opts.coverLetter && !opts.lintOnly) {
+		stages = append(stages, pipelineStage{"resolve", p.resolve})
+	}
+	stages = append(stages, pipelineStage{"lint", func(ctx context.Context) error {
+		var err error
+		lints, err = lintSeries(ctx, deps.repo, revRange)
+		return err
+	}})
+	stages = append(stages, pipelineStage{"review", func(ctx context.Context) error {
+		return reviewSeries(ctx, p, opts, lints, revRange)
+	}})
+	if !opts.lintOnly {
+		stages = append(stages, pipelineStage{"format", func(ctx context.Context) error {
+			return formatSeries(ctx, p, opts, lints, revRange)
+		}})
+	}
+
+	return p.run(ctx, "goco.format_patch", stages)
+}
+
+func lintSeries(ctx context.Context, repo *git.Repository, revRange string) ([]seriesLint, error) {
+	entries, err := repo.Series(ctx, revRange)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no commits in %s", revRange)
+	}
+
+	lints := make([]seriesLint, 0, len(entries))
+	for _, e := range entries {
+		lints = append(lints, seriesLint{entry: e, err: lintCommitMessage(e.Subject)})
+	}
+
+	fmt.Println(modelProviderStyle.Render(fmt.Sprintf("Patch Series %s (%d commits)", revRange, len(entries))))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "COMMIT\tSUBJECT\tLINT")
+	for _, l := range lints {
+		result := "ok"
+		if l.err != nil {
+			result = l.err.Error()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", l.entry.Short(), l.entry.Subject, result)
+	}
+	w.Flush()
+
+	return lints, nil
+}
+
+// reviewSeries stops before any patch is written when a subject fails lint,
+// optionally suggesting a regenerated message for each failure.
+func reviewSeries(ctx context.Context, p *Pipeline, opts *formatPatchOptions, lints []seriesLint, revRange string) error {
+	var failed []git.LogEntry
+	for _, l := range lints {
+		if l.err != nil {
+			failed = append(failed, l.entry)
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+
+	if !opts.regenerate {
+		return fmt.Errorf("%d commit subject(s) fail lint; reword them before sending, or pass --regenerate for suggestions", len(failed))
+	}
+
+	for _, e := range failed {
+		msg, err := p.regenerateCommitMessage(ctx, e)
+		if err != nil {
+			return fmt.Errorf("regenerate %s: %w", e.Short(), err)
+		}
+		fmt.Print(p.display.block(messageBlock, "Suggested message for "+e.Short(), msg))
+	}
+
+	base, _, _ := strings.Cut(revRange, "..")
+	return fmt.Errorf("%d commit subject(s) fail lint; apply the suggestions with `git rebase -i %s` (reword), then run goco format-patch again", len(failed), base)
+}
+
+// regenerateCommitMessage writes a new message for an existing commit from its patch.
+func (p *Pipeline) regenerateCommitMessage(ctx context.Context, e git.LogEntry) (string, error) {
+	diff, err := p.deps.repo.CommitDiff(ctx, e.Hash)
+	if err != nil {
+		return "", err
+	}
+	diff, _ = git.SummarizeLFSPointers(diff)
+
+	original := e.Subject
+	if e.Body != "" {
+		original += "\n\n" + e.Body
+	}
+	input := ai.PromptInput{
+		Status:             "commit " + e.Short() + "\noriginal message:\n" + original,
+		Diff:               diff,
+		CustomInstructions: p.opts.customInstructions,
+		Spec:               p.spec,
+		Template:           p.template,
+		Vars:               p.vars,
+	}
+	prompt, err := p.preparePrompt(ctx, input)
+	if err != nil {
+		return "", err
+	}
+	msg, err := p.send(ctx, input, prompt, "Regenerating "+e.Short()+"...")
+	if err != nil {
+		return "", err
+	}
+	if msg, err = ai.CleanOutput(msg); err != nil {
+		return "", err
+	}
+	// Rewording must not break Gerrit's tracking of the change, nor drop
+	// what commit hooks added to the original message.
+	msg = git.WithChangeID(p.repairMessage(msg), git.ChangeID(e.Body))
+	keys, err := state.LoadHookTrailers(p.root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+	return git.CarryTrailers(msg, e.Body, keys), nil
+}
+
+func formatSeries(ctx context.Context, p *Pipeline, opts *formatPatchOptions, lints []seriesLint, revRange string) error {
+	files, err := p.deps.repo.FormatPatch(ctx, revRange, opts.outputDir, opts.coverLetter)
+	if err != nil {
+		return err
+	}
+
+	if opts.coverLetter {
+		for _, f := range files {
+			if strings.HasSuffix(f, "0000-cover-letter.patch") {
+				if err := p.writeCoverLetter(ctx, f, lints, revRange); err != nil {
+					return err
+				}
+				break
+			}
+		}
+	}
+
+	for _, f := range files {
+		fmt.Println(f)
+	}
+	return nil
+}
+
+// writeCoverLetter replaces the subject and blurb placeholders git leaves in
+// the cover letter with a generated summary of the series.
+func (p *Pipeline) writeCoverLetter(ctx context.Context, path string, lints []seriesLint, revRange string) error {
+	stat, err := p.deps.repo.RangeDiff(ctx, revRange, true)
+	if err != nil {
+		return err
+	}
+	diff, err := p.deps.repo.RangeDiff(ctx, revRange, false)
+	if err != nil {
+		return err
+	}
+	diff, _ = git.SummarizeLFSPointers(diff)
+
+	var shortlog strings.Builder
+	for _, l := range lints {
+		shortlog.WriteString(l.entry.Subject + "\n")
+	}
+
+	input := ai.PromptInput{
+		Status:             shortlog.String() + "\n" + stat,
+		Diff:               diff,
+		CustomInstructions: p.opts.customInstructions,
+		Template:           ai.CoverLetterTemplate,
+	}
+	prompt, err := p.preparePrompt(ctx, input)
+	if err != nil {
+		return err
+	}
+	letter, err := p.send(ctx, input, prompt, "Writing cover letter...")
+	if err != nil {
+		return fmt.Errorf("generate cover letter: %w", err)
+	}
+	if letter, err = ai.CleanOutput(letter); err != nil {
+		return err
+	}
+
+	subject, blurb, _ := strings.Cut(strings.TrimSpace(letter), "\n")
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read cover letter: %w", err)
+	}
+	text := strings.Replace(string(content), coverLetterSubjectPlaceholder, strings.TrimSpace(subject), 1)
+	text = strings.Replace(text, coverLetterBlurbPlaceholder, strings.TrimSpace(blurb), 1)
+	if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+		return fmt.Errorf("write cover letter: %w", err)
+	}
+	return nil
+}
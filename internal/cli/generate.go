@@ -1,10 +1,20 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/razobeckett/goco/internal/ai"
+	"github.com/razobeckett/goco/internal/config"
+	"github.com/razobeckett/goco/internal/forge"
+	"github.com/razobeckett/goco/internal/git"
+	"github.com/razobeckett/goco/internal/policy"
+	"github.com/razobeckett/goco/internal/telemetry"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
@@ -13,12 +23,34 @@ type generateOptions struct {
 	provider           string
 	apiKey             string
 	model              string
+	geminiBackend      string
 	customInstructions string
 	newBranch          string
 	staged             bool
 	verbose            bool
 	edit               bool
 	noConfirm          bool
+	perPackage         bool
+	changeID           bool
+	blameContext       bool
+	minimizeDiff       bool
+	fastPath           bool
+	twoPass            bool
+	refine             bool
+	debug              bool
+	issue              string
+	explainActions     bool
+	maxFiles           int
+	ask                bool
+	scope              string
+	author             string
+	date               string
+	allowEmpty         bool
+	describe           string
+
+	// Set by `goco resolve-msg` to describe a conflict resolution instead.
+	resolveConflicts bool
+	printOnly        bool
 }
 
 func newGenerateOptions() *generateOptions {
@@ -40,26 +72,241 @@ func newGenerateCmd(deps dependencies) *cobra.Command {
 	}
 
 	bindGenerateFlags(cmd.Flags(), opts)
+	_ = cmd.RegisterFlagCompletionFunc("scope", completeScopes(deps))
 	return cmd
 }
 
 func bindGenerateFlags(fs *pflag.FlagSet, opts *generateOptions) {
-	fs.StringVarP(&opts.provider, "provider", "p", "", "AI provider to use (gemini or groq)")
+	fs.StringVarP(&opts.provider, "provider", "p", "", providerUsage("AI provider to use"))
 	fs.StringVarP(&opts.apiKey, "api-key", "k", "", "API key for the selected provider")
 	fs.StringVarP(&opts.model, "model", "m", "", "Model to use (defaults to the provider's recommended model)")
+	fs.StringVar(&opts.geminiBackend, "gemini-backend", "", "Reach Gemini through "+ai.GeminiBackendAPI+" or "+ai.GeminiBackendVertex+" for this run (defaults to [Gemini] backend)")
 	fs.BoolVarP(&opts.staged, "staged", "s", false, "Use staged changes instead of the working tree diff")
 	fs.BoolVarP(&opts.verbose, "verbose", "V", false, "Show git status and diff before generating the commit")
 	fs.BoolVarP(&opts.noConfirm, "yes", "y", false, "Skip confirmation and commit immediately")
 	fs.StringVarP(&opts.customInstructions, "custom-instructions", "c", "", "Additional instructions to add to the AI prompt")
 	fs.BoolVarP(&opts.edit, "edit", "e", false, "Open the generated commit message in your editor before committing")
 	fs.StringVarP(&opts.newBranch, "branch", "B", "", "Create a new branch from the current branch before committing")
+	fs.BoolVar(&opts.blameContext, "blame-context", false, "Include the commits that last touched the changed lines in the prompt")
+	fs.BoolVar(&opts.minimizeDiff, "minimize-diff", false, "Send a trimmed diff (less context, collapsed additions, no vendored files) to save tokens")
+	fs.BoolVar(&opts.fastPath, "fast-path", false, "Write messages for trivial changes (renames, version bumps, dependency updates, docs or tests only) locally, without the provider")
+	fs.BoolVar(&opts.twoPass, "two-pass", false, "Outline the change file by file first, then write the message from the outline (one extra request)")
+	fs.BoolVar(&opts.refine, "refine", false, "Have the model check the message against the diff and correct it (one extra request)")
+	fs.BoolVar(&opts.debug, "debug", false, "Print intermediate results, such as the two-pass outline, the refinement critique, and chunk summaries, to stderr")
+	fs.BoolVar(&opts.changeID, "change-id", false, "Append a Gerrit Change-Id trailer (kept across retries and edits)")
+	fs.StringVar(&opts.issue, "issue", "", "Fetch this ticket (e.g. PROJ-123 or 42) and describe the change against it")
+	fs.BoolVar(&opts.explainActions, "explain-actions", false, "Print the exact git commands goco will run, and the hooks and signing git will apply, before committing")
+	fs.BoolVar(&opts.printOnly, "print", false, "Print the message to stdout instead of committing (used by the prepare-commit-msg hook)")
+	fs.BoolVar(&opts.ask, "ask", false, "Pick the commit type and scope, seeded from the changes, before the model writes the message")
+	fs.StringVar(&opts.scope, "scope", "", "Use this commit scope, e.g. api; completes from the configured scopes and the repository's history")
+	fs.StringVar(&opts.author, "author", "", `Record this author, as "Name <email>", instead of the configured one`)
+	fs.StringVar(&opts.date, "date", "", "Record this author date (ISO 8601, RFC 2822, or @<unix-timestamp>) instead of now")
+	fs.BoolVar(&opts.allowEmpty, "allow-empty", false, "Record a commit without changes, e.g. to trigger CI, with a message written from --describe")
+	fs.StringVar(&opts.describe, "describe", "", "What the --allow-empty commit is for (asked for when not given)")
+	fs.IntVar(&opts.maxFiles, "max-files", 0, "Ask which files to commit when more than this many changed (defaults to [Commit] max_files)")
+	fs.BoolVar(&opts.perPackage, "per-package", false, "Create one commit per workspace package touched by the staged changes (implies --staged)")
 }
 
 func runGenerate(cmd *cobra.Command, deps dependencies, opts *generateOptions) error {
+	if opts.explainActions && opts.printOnly {
+		return fmt.Errorf("--explain-actions cannot be combined with --print, which never commits")
+	}
+	if (opts.author != "" || opts.date != "") && opts.printOnly {
+		return fmt.Errorf("--author and --date cannot be combined with --print, which never commits")
+	}
+	if opts.describe != "" && !opts.allowEmpty {
+		return fmt.Errorf("--describe only applies with --allow-empty; other commits are described from their diff")
+	}
+	if opts.allowEmpty && (opts.perPackage || opts.twoPass || opts.refine) {
+		return fmt.Errorf("--allow-empty cannot be combined with --per-package, --two-pass, or --refine, which need a diff")
+	}
+	if opts.perPackage {
+		if opts.scope != "" {
+			return fmt.Errorf("--scope cannot be combined with --per-package, which scopes each commit by its package")
+		}
+		if opts.printOnly {
+			return fmt.Errorf("--print cannot be combined with --per-package")
+		}
+		opts.staged = true
+	}
 	pipeline := NewPipeline(deps, opts)
 	return pipeline.Run(cmd.Context())
 }
 
+// commitOverrides reads --author, --date, and --allow-empty, rejecting
+// values git would misread rather than refuse.
+func commitOverrides(opts *generateOptions) (git.CommitOverrides, error) {
+	o := git.CommitOverrides{AllowEmpty: opts.allowEmpty}
+	if opts.author != "" {
+		o.Author = strings.TrimSpace(opts.author)
+		if err := git.CheckAuthor(o.Author); err != nil {
+			return o, fmt.Errorf("--author: %w", err)
+		}
+	}
+	if opts.date != "" {
+		date, err := git.ParseCommitDate(opts.date)
+		if err != nil {
+			return o, fmt.Errorf("--date: %w", err)
+		}
+		o.Date = date
+	}
+	return o, nil
+}
+
+// checkProviderName rejects provider names goco has no implementation for.
+// checkGeminiBackend rejects a --gemini-backend goco does not know.
+func checkGeminiBackend(backend string) error {
+	if backend != "" && backend != ai.GeminiBackendAPI && backend != ai.GeminiBackendVertex {
+		return usageError{fmt.Errorf("--gemini-backend %q: want %s or %s", backend, ai.GeminiBackendAPI, ai.GeminiBackendVertex)}
+	}
+	return nil
+}
+
+func checkProviderName(name string) error {
+	if !slices.Contains(ai.Providers, name) {
+		return fmt.Errorf("invalid provider %q; supported providers: %s", name, strings.Join(ai.Providers, ", "))
+	}
+	return nil
+}
+
+// checkModelProvider rejects a model that another provider serves before a
+// request to the chosen one fails on it.
+func checkModelProvider(providerName, model string) error {
+	if owner, ok := ai.ModelProvider(model); ok && owner != providerName {
+		return fmt.Errorf("model %q is served by %s, not %s; pass --provider %s, or pick a %s model from `goco models --provider %s`", model, owner, providerName, owner, providerName, providerName)
+	}
+	return nil
+}
+
+// loadConfig loads the config and applies the first [[Route]] matching the
+// current repository, so every command picks the same provider for it.
+func loadConfig(ctx context.Context, deps dependencies) (*config.Config, error) {
+	cfg, err := deps.configLoader.Load()
+	if err != nil {
+		return nil, fmt.Errorf("load config %q: %w", deps.configLoader.Path(), err)
+	}
+	if err := routeConfig(ctx, deps, cfg); err != nil {
+		return nil, err
+	}
+	cfg.ApplyCommand(deps.commandName())
+	return cfg, nil
+}
+
+// providerOptions returns the provider settings cfg configures: the [HTTP]
+// transport retries and the [Gemini], [Azure], and [Bedrock] backends.
+func providerOptions(cfg *config.Config) ai.Options {
+	return ai.Options{
+		Retry:  ai.RetryPolicy{Connect: cfg.HTTP.Connect(), Idempotent: cfg.HTTP.List()},
+		Gemini: ai.GeminiSettings{Backend: cfg.Gemini.Backend, Project: cfg.Gemini.Project, Location: cfg.Gemini.Location},
+		Azure: ai.AzureSettings{
+			Endpoint:    cfg.Azure.Endpoint,
+			APIVersion:  cfg.Azure.APIVersion,
+			Deployment:  cfg.Azure.Deployment,
+			Deployments: cfg.Azure.Deployments,
+		},
+		Bedrock: ai.BedrockSettings{Region: cfg.Bedrock.Region, Profile: cfg.Bedrock.Profile},
+	}
+}
+
+// routeConfig applies the first [[Route]] matching the current repository.
+// The remote is only looked up when a route needs it.
+func routeConfig(ctx context.Context, deps dependencies, cfg *config.Config) error {
+	if len(cfg.Routes) == 0 {
+		return nil
+	}
+	root, _ := deps.repo.Root(ctx)
+	var remote string
+	if root != "" && cfg.NeedsRemote() {
+		if url, err := deps.repo.RemoteURL(ctx, deps.repo.BranchRemote(ctx)); err == nil {
+			remote, _ = forge.RemotePath(url)
+		}
+	}
+	_, err := cfg.ApplyRoute(root, remote)
+	return err
+}
+
+// resolveAPIKey prefers the flag value, then the configured env var, and only
+// prompts interactively when neither is set. Providers that need no key get
+// an empty one.
+func resolveAPIKey(cfg *config.Config, providerName, flagValue string) (string, error) {
+	if !ai.NeedsAPIKey(providerName, providerOptions(cfg)) {
+		return "", nil
+	}
+	if flagValue != "" {
+		return flagValue, nil
+	}
+	if apiKey := providerAPIKey(cfg, providerName); apiKey != "" {
+		return apiKey, nil
+	}
+	return promptForAPIKey(cfg.APIKeyEnv(providerName), providerDisplayName(providerName))
+}
+
+// providerAPIKey returns the key in the provider's configured environment
+// variable. GitHub Models falls back to the GitHub token goco uses for pull
+// requests, then to the gh CLI's, so gh users need no separate key.
+func providerAPIKey(cfg *config.Config, providerName string) string {
+	if key := cfg.APIKey(providerName); key != "" || providerName != ai.ProviderGitHub {
+		return key
+	}
+	if token := forge.Token(forge.KindGitHub); token != "" {
+		return token
+	}
+	return ghToken()
+}
+
+// ghToken asks the gh CLI for its token, once per run.
+var ghToken = sync.OnceValue(func() string {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return forge.GitHubCLIToken(ctx)
+})
+
+// applyPolicy checks the requested provider and model against the
+// organization policy. When the request is denied and the policy names an
+// allowed fallback, the fallback is returned instead.
+func applyPolicy(pol *policy.Policy, opts ai.Options, providerName, model string) (string, string, error) {
+	err := pol.Check(policyRequest(opts, providerName, model))
+	if err == nil {
+		return providerName, model, nil
+	}
+	err = fmt.Errorf("%w (policy file: %s)", err, pol.Path())
+
+	fallback, fallbackModel := pol.FallbackProvider, pol.FallbackModel
+	if fallback == "" || checkProviderName(fallback) != nil {
+		return "", "", err
+	}
+	if pol.Check(policyRequest(opts, fallback, fallbackModel)) != nil {
+		return "", "", err
+	}
+
+	fmt.Fprintln(os.Stderr, noteStyle.Render(fmt.Sprintf(
+		"Policy does not allow %s; using fallback provider %s instead.",
+		providerName, providerDisplayName(fallback),
+	)))
+	return fallback, fallbackModel, nil
+}
+
+func policyRequest(opts ai.Options, providerName, model string) policy.Request {
+	if model == "" {
+		model = ai.DefaultModelFor(providerName, opts)
+	}
+	return policy.Request{Provider: providerName, Model: model, Endpoint: ai.Endpoint(providerName, opts)}
+}
+
+// newMetrics builds the configured telemetry sink; it is a no-op unless the
+// user opted in under [Telemetry], and always with --offline.
+func newMetrics(deps dependencies, cfg *config.Config) (telemetry.Sink, error) {
+	// Both sinks send over the network, which --offline disables.
+	if deps.isOffline() {
+		return telemetry.Nop{}, nil
+	}
+	sink, err := telemetry.New(cfg.Telemetry.Sink, cfg.Telemetry.Endpoint, cfg.Telemetry.Prefix)
+	if err != nil {
+		return nil, fmt.Errorf("configure telemetry: %w", err)
+	}
+	return sink, nil
+}
+
 func promptForAPIKey(envVar, providerName string) (string, error) {
 	fmt.Println(titleStyle.Render(fmt.Sprintf("%s API Key Required", providerName)))
 	apiKey, err := runAPIKeyPrompt(providerName, envVar)
@@ -81,14 +328,35 @@ func promptForAPIKey(envVar, providerName string) (string, error) {
 	return apiKey, nil
 }
 
-func confirmCommit() (bool, error) {
-	return runConfirmPrompt("Proceed with this commit?")
+// providerUsage is the help for a --provider flag: what names, followed by
+// the supported providers, e.g. "AI provider to use (gemini, groq, or grok)".
+func providerUsage(what string) string {
+	names := ai.Providers
+	return fmt.Sprintf("%s (%s, or %s)", what, strings.Join(names[:len(names)-1], ", "), names[len(names)-1])
 }
 
 func providerDisplayName(provider string) string {
 	switch provider {
 	case ai.ProviderGroq:
 		return "Groq"
+	case ai.ProviderOpenRouter:
+		return "OpenRouter"
+	case ai.ProviderMistral:
+		return "Mistral"
+	case ai.ProviderAzure:
+		return "Azure OpenAI"
+	case ai.ProviderBedrock:
+		return "AWS Bedrock"
+	case ai.ProviderGrok:
+		return "Grok"
+	case ai.ProviderDeepSeek:
+		return "DeepSeek"
+	case ai.ProviderCohere:
+		return "Cohere"
+	case ai.ProviderGitHub:
+		return "GitHub Models"
+	case ai.ProviderTogether:
+		return "Together AI"
 	default:
 		return "Gemini"
 	}
@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+// homeItem is one entry of the home screen and the command it runs.
+type homeItem struct {
+	title       string
+	description string
+	args        []string
+}
+
+var homeItems = []homeItem{
+	{title: "Generate commit", description: "Write a message for your changes and commit them", args: []string{"generate"}},
+	{title: "Split changes", description: "Commit the staged changes one package at a time", args: []string{"generate", "--per-package"}},
+	{title: "Lint history", description: "Check the branch's commit subjects before sending them", args: []string{"format-patch", "--lint-only"}},
+	{title: "Models", description: "List the models your provider offers", args: []string{"models"}},
+	{title: "Config", description: "Show goco's config file, directories, and environment", args: []string{"env"}},
+	{title: "Doctor", description: "Check goco's git hooks in this repository", args: []string{"verify-install", "--check"}},
+}
+
+// homeModel is the menu a bare `goco` shows in a terminal.
+type homeModel struct {
+	help   help.Model
+	keys   homeKeyMap
+	items  []homeItem
+	cursor int
+	// chosen is the index of the picked item, or -1.
+	chosen int
+}
+
+type homeKeyMap struct {
+	Up     key.Binding
+	Down   key.Binding
+	Choose key.Binding
+	Quit   key.Binding
+}
+
+func (k homeKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Choose, k.Quit}
+}
+
+func (k homeKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+func newHomeModel(items []homeItem) homeModel {
+	h := help.New()
+	h.Styles.ShortKey = promptDescriptionStyle
+	h.Styles.ShortDesc = promptDescriptionStyle
+	h.Styles.ShortSeparator = promptDescriptionStyle
+
+	return homeModel{
+		help:   h,
+		items:  items,
+		chosen: -1,
+		keys: homeKeyMap{
+			Up:     key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
+			Down:   key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
+			Choose: key.NewBinding(key.WithKeys("enter"), key.WithHelp("↵", "run")),
+			Quit:   key.NewBinding(key.WithKeys("q", "esc", "ctrl+c"), key.WithHelp("q", "quit")),
+		},
+	}
+}
+
+func (m homeModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m homeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.keys.Up):
+			m.cursor = (m.cursor + len(m.items) - 1) % len(m.items)
+		case key.Matches(msg, m.keys.Down):
+			m.cursor = (m.cursor + 1) % len(m.items)
+		case key.Matches(msg, m.keys.Choose):
+			m.chosen = m.cursor
+			return m, tea.Quit
+		case key.Matches(msg, m.keys.Quit):
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+func (m homeModel) View() string {
+	selectedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(electricOrange)).Bold(true)
+	itemStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(creamGleam))
+
+	lines := []string{promptTitleStyle.Render("What would you like to do?"), ""}
+	for i, item := range m.items {
+		marker, style := "  ", itemStyle
+		if i == m.cursor {
+			marker, style = "> ", selectedStyle
+		}
+		lines = append(lines,
+			style.Render(marker+item.title),
+			"    "+promptDescriptionStyle.Render(item.description+" (goco "+strings.Join(item.args, " ")+")"),
+		)
+	}
+	return strings.Join(append(lines, "", m.help.ShortHelpView(m.keys.ShortHelp())), "\n")
+}
+
+// runHome shows the home screen and runs the picked command. Outside a
+// terminal, or in one that cannot draw it, it prints help, as there is
+// nobody to pick.
+func runHome(root *cobra.Command) error {
+	if !stdinIsTerminal() {
+		return root.Help()
+	}
+	model, err := runProgram(newHomeModel(homeItems))
+	if errors.Is(err, errNoTUI) {
+		return root.Help()
+	}
+	if err != nil {
+		return err
+	}
+	home, ok := model.(homeModel)
+	if !ok || home.chosen < 0 {
+		return nil
+	}
+	return runSubcommand(root, home.items[home.chosen].args)
+}
+
+// runSubcommand runs the subcommand of root named by args as if it had been
+// typed, so its flags are parsed and root's hooks run for it. It runs the
+// subcommand directly rather than executing root again, as the home screen
+// is itself running inside root. Persistent flags given with the bare goco
+// keep their values.
+func runSubcommand(root *cobra.Command, args []string) error {
+	sub, rest, err := root.Find(args)
+	if err != nil {
+		return usageError{err}
+	}
+	if sub == root || sub.RunE == nil {
+		return usageError{fmt.Errorf("%q does not name a command", strings.Join(args, " "))}
+	}
+	sub.SetContext(root.Context())
+	if err := sub.ParseFlags(rest); err != nil {
+		return sub.FlagErrorFunc()(sub, err)
+	}
+	rest = sub.Flags().Args()
+	if err := sub.ValidateArgs(rest); err != nil {
+		return err
+	}
+	if hook := root.PersistentPreRunE; hook != nil {
+		if err := hook(sub, rest); err != nil {
+			return err
+		}
+	}
+	return sub.RunE(sub, rest)
+}
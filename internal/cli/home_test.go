@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestHomeModel(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []tea.KeyMsg
+		want int
+	}{
+		{name: "first item", keys: []tea.KeyMsg{{Type: tea.KeyEnter}}, want: 0},
+		{name: "down twice", keys: []tea.KeyMsg{{Type: tea.KeyDown}, {Type: tea.KeyRunes, Runes: []rune("j")}, {Type: tea.KeyEnter}}, want: 2},
+		{name: "up wraps", keys: []tea.KeyMsg{{Type: tea.KeyUp}, {Type: tea.KeyEnter}}, want: len(homeItems) - 1},
+		{name: "quit", keys: []tea.KeyMsg{{Type: tea.KeyDown}, {Type: tea.KeyRunes, Runes: []rune("q")}}, want: -1},
+		{name: "escape", keys: []tea.KeyMsg{{Type: tea.KeyEsc}}, want: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m tea.Model = newHomeModel(homeItems)
+			for _, k := range tt.keys {
+				m, _ = m.Update(k)
+			}
+			if got := m.(homeModel).chosen; got != tt.want {
+				t.Fatalf("chosen = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHomeItemsRunCommands(t *testing.T) {
+	root := NewRootCmd()
+	for _, item := range homeItems {
+		sub, rest, err := root.Find(item.args)
+		if err != nil || sub == root {
+			t.Fatalf("%s: %v does not name a command: %v", item.title, item.args, err)
+		}
+		if err := sub.ParseFlags(rest); err != nil {
+			t.Fatalf("%s: %v", item.title, err)
+		}
+	}
+}
+
+func TestRunSubcommand(t *testing.T) {
+	cacheHome := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cacheHome)
+	root := NewRootCmd()
+	root.SetContext(context.Background())
+
+	out, err := captureStdout(t, func() error {
+		return runSubcommand(root, []string{"env", "GOCO_CACHE_DIR"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(out) != filepath.Join(cacheHome, "goco") {
+		t.Fatalf("unexpected output: %q", out)
+	}
+
+	err = runSubcommand(root, []string{"env", "--bogus"})
+	if !IsUsageError(err) {
+		t.Fatalf("expected a usage error, got %v", err)
+	}
+
+	err = runSubcommand(root, []string{"env", "--repo", filepath.Join(cacheHome, "missing")})
+	if err == nil || !strings.Contains(err.Error(), "not a directory") {
+		t.Fatalf("expected root's --repo check to run, got %v", err)
+	}
+
+	// A name that is no command must not fall back to running root, which
+	// would show the home screen again.
+	err = runSubcommand(root, []string{"no-such-command"})
+	if !IsUsageError(err) {
+		t.Fatalf("expected a usage error for an unknown command, got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/razobeckett/goco/internal/config"
+	"github.com/razobeckett/goco/internal/forge"
+	"github.com/razobeckett/goco/internal/git"
+)
+
+// loadIssue fetches the ticket given with --issue, or the one named by the
+// branch when [Issues] fetch is on. A ticket named explicitly must load; a
+// branch-derived one is best-effort.
+func (p *Pipeline) loadIssue(ctx context.Context) error {
+	key := p.opts.issue
+	if key == "" && !p.issues.Fetch {
+		return nil
+	}
+	if err := p.deps.requireNetwork("fetching issue " + key); err != nil {
+		if key != "" {
+			return err
+		}
+		return nil // branch-derived context is optional
+	}
+
+	tracker, kind, err := openIssueTracker(ctx, p.deps.repo, p.issues, p.forgeHosts)
+	if err == nil && key == "" {
+		branch, _ := p.deps.repo.CurrentBranch(ctx)
+		if key = forge.IssueKey(branch, kind, p.issues.JiraProjects); key == "" {
+			return nil
+		}
+	}
+
+	var issue *forge.Issue
+	if err == nil {
+		issue, err = tracker.Issue(ctx, key)
+	}
+	if err != nil {
+		if p.opts.issue != "" {
+			return fmt.Errorf("fetch issue %s: %w", key, err)
+		}
+		fmt.Fprintln(os.Stderr, noteStyle.Render(fmt.Sprintf("Warning: could not fetch the branch's issue: %v", err)))
+		return nil
+	}
+
+	p.issue = issue.Summary()
+	if p.opts.verbose {
+		fmt.Println(noteStyle.Render(fmt.Sprintf("Using issue %s: %s", issue.Key, issue.URL)))
+	}
+	return nil
+}
+
+// openIssueTracker picks the tracker configured in cfg, falling back to the
+// forge hosting the current branch's remote, and returns it with its kind.
+func openIssueTracker(ctx context.Context, repo *git.Repository, cfg config.Issues, hosts map[string]string) (forge.IssueTracker, string, error) {
+	kind := cfg.Tracker
+	if kind == "" && cfg.JiraURL != "" {
+		kind = forge.KindJira
+	}
+	if kind == forge.KindJira {
+		tracker, err := forge.NewJira(cfg.JiraURL, cfg.JiraEmail, forge.Token(forge.KindJira))
+		return tracker, kind, err
+	}
+
+	url, err := repo.RemoteURL(ctx, repo.BranchRemote(ctx))
+	if err != nil {
+		return nil, kind, err
+	}
+	remote, err := forge.ParseRemote(url, hosts)
+	if err != nil {
+		return nil, kind, err
+	}
+	if kind != "" && kind != remote.Kind {
+		return nil, kind, fmt.Errorf("tracker %q does not match the %s remote %s", kind, remote.Kind, url)
+	}
+	tracker, err := forge.NewIssueTracker(remote, forge.Token(remote.Kind))
+	return tracker, remote.Kind, err
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/razobeckett/goco/internal/state"
+	"github.com/spf13/cobra"
+)
+
+func newLastPromptCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "last-prompt",
+		Short:   "Inspect the last prompt sent to the AI provider",
+		GroupID: "inspect",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "show",
+		Short: "Print the last saved prompt and raw response",
+		Long:  "Print the last prompt (with likely secrets redacted) and the provider's raw response. Saving is opt-in: set save_last_prompt = true under [Prompt] in your config.",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runLastPromptShow()
+		},
+	})
+
+	return cmd
+}
+
+func runLastPromptShow() error {
+	lp, err := state.LoadLastPrompt()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(titleStyle.Render(fmt.Sprintf(
+		"%s · %s · %s",
+		providerDisplayName(lp.Provider), lp.Model, lp.Time.Format("2006-01-02 15:04:05"),
+	)))
+
+	fmt.Println(statusHeaderStyle.Render("Prompt"))
+	fmt.Println(lp.Prompt)
+	fmt.Println()
+
+	fmt.Println(commitMessageHeaderStyle.Render("Response"))
+	if lp.Error != "" {
+		fmt.Println(promptErrorStyle.Render(lp.Error))
+	}
+	fmt.Println(lp.Response)
+
+	return nil
+}
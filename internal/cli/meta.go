@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// metaSchema is bumped when the output of `goco meta` changes in a way that
+// breaks readers; new fields alone do not bump it.
+const metaSchema = 1
+
+// metaOutput is the document `goco meta` prints.
+type metaOutput struct {
+	Schema  int         `json:"schema"`
+	Version string      `json:"version,omitempty"`
+	Command metaCommand `json:"command"`
+}
+
+type metaCommand struct {
+	Name      string        `json:"name"`
+	Path      string        `json:"path"`
+	Use       string        `json:"use"`
+	Short     string        `json:"short,omitempty"`
+	Long      string        `json:"long,omitempty"`
+	Example   string        `json:"example,omitempty"`
+	Aliases   []string      `json:"aliases,omitempty"`
+	Group     string        `json:"group,omitempty"`
+	Runnable  bool          `json:"runnable"`
+	ValidArgs []string      `json:"valid_args,omitempty"`
+	Flags     []metaFlag    `json:"flags,omitempty"`
+	Commands  []metaCommand `json:"commands,omitempty"`
+}
+
+type metaFlag struct {
+	Name      string `json:"name"`
+	Shorthand string `json:"shorthand,omitempty"`
+	Type      string `json:"type"`
+	Default   string `json:"default,omitempty"`
+	Usage     string `json:"usage"`
+	// Persistent flags are accepted by every subcommand too.
+	Persistent bool `json:"persistent,omitempty"`
+	Required   bool `json:"required,omitempty"`
+}
+
+func newMetaCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "meta",
+		Aliases: []string{"completion-data"},
+		Short:   "Print goco's commands and flags as JSON",
+		Long:    "Print the full command and flag tree as JSON, for IDE plugins, alternative interfaces, and docs generators. Hidden and deprecated commands and flags are left out. The schema field changes only when the format breaks compatibility.",
+		GroupID: "tools",
+		Args:    cobra.NoArgs,
+		Example: "  goco meta\n  goco meta | jq '.command.commands[].path'",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(collectMeta(cmd.Root()))
+		},
+	}
+}
+
+func collectMeta(root *cobra.Command) metaOutput {
+	return metaOutput{Schema: metaSchema, Version: root.Version, Command: describeCommand(root)}
+}
+
+func describeCommand(cmd *cobra.Command) metaCommand {
+	m := metaCommand{
+		Name:      cmd.Name(),
+		Path:      cmd.CommandPath(),
+		Use:       cmd.Use,
+		Short:     cmd.Short,
+		Long:      cmd.Long,
+		Example:   cmd.Example,
+		Aliases:   cmd.Aliases,
+		Group:     cmd.GroupID,
+		Runnable:  cmd.Runnable(),
+		ValidArgs: cmd.ValidArgs,
+	}
+
+	persistent := cmd.PersistentFlags()
+	cmd.NonInheritedFlags().VisitAll(func(f *pflag.Flag) {
+		if f.Hidden || f.Deprecated != "" || f.Name == "help" {
+			return
+		}
+		_, required := f.Annotations[cobra.BashCompOneRequiredFlag]
+		m.Flags = append(m.Flags, metaFlag{
+			Name:       f.Name,
+			Shorthand:  f.Shorthand,
+			Type:       f.Value.Type(),
+			Default:    f.DefValue,
+			Usage:      f.Usage,
+			Persistent: persistent.Lookup(f.Name) != nil,
+			Required:   required,
+		})
+	})
+
+	for _, sub := range cmd.Commands() {
+		if sub.IsAvailableCommand() {
+			m.Commands = append(m.Commands, describeCommand(sub))
+		}
+	}
+	return m
+}
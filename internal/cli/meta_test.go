@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestMetaDescribesCommandTree(t *testing.T) {
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"completion-data"})
+	out, err := captureStdout(t, cmd.Execute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var meta metaOutput
+	if err := json.Unmarshal([]byte(out), &meta); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out)
+	}
+	if meta.Schema != metaSchema || meta.Command.Name != "goco" {
+		t.Fatalf("unexpected header: %+v", meta)
+	}
+
+	find := func(cmds []metaCommand, name string) metaCommand {
+		t.Helper()
+		i := slices.IndexFunc(cmds, func(c metaCommand) bool { return c.Name == name })
+		if i < 0 {
+			t.Fatalf("command %q missing", name)
+		}
+		return cmds[i]
+	}
+	flag := func(c metaCommand, name string) metaFlag {
+		t.Helper()
+		i := slices.IndexFunc(c.Flags, func(f metaFlag) bool { return f.Name == name })
+		if i < 0 {
+			t.Fatalf("%s: flag %q missing", c.Path, name)
+		}
+		return c.Flags[i]
+	}
+
+	if repo := flag(meta.Command, "repo"); !repo.Persistent || repo.Shorthand != "C" {
+		t.Errorf("unexpected --repo: %+v", repo)
+	}
+	generate := find(meta.Command.Commands, "generate")
+	if generate.Path != "goco generate" || generate.Group != "main" || !generate.Runnable {
+		t.Errorf("unexpected generate: %+v", generate)
+	}
+	if provider := flag(generate, "provider"); provider.Shorthand != "p" || provider.Type != "string" || provider.Persistent {
+		t.Errorf("unexpected --provider: %+v", provider)
+	}
+	if slices.ContainsFunc(generate.Flags, func(f metaFlag) bool { return f.Name == "repo" || f.Name == "help" }) {
+		t.Errorf("generate lists inherited or help flags: %+v", generate.Flags)
+	}
+	gc := find(find(meta.Command.Commands, "cache").Commands, "gc")
+	if olderThan := flag(gc, "older-than"); olderThan.Default != "720h0m0s" || olderThan.Type != "duration" {
+		t.Errorf("unexpected --older-than: %+v", olderThan)
+	}
+}
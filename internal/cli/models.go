@@ -3,16 +3,24 @@ package cli
 import (
 	"context"
 	"fmt"
+	"math"
+	"slices"
 	"sort"
+	"strconv"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/razobeckett/goco/internal/ai"
+	"github.com/razobeckett/goco/internal/config"
+	"github.com/razobeckett/goco/internal/telemetry"
 	"github.com/spf13/cobra"
 )
 
 type modelsOptions struct {
 	provider string
 	apiKey   string
+	filter   string
+	family   string
 }
 
 func newModelsCmd(deps dependencies) *cobra.Command {
@@ -24,71 +32,137 @@ func newModelsCmd(deps dependencies) *cobra.Command {
 		Long:    "List all available AI models for the selected provider. Uses the models.dev community registry by default — no API key required.",
 		GroupID: "inspect",
 		Args:    cobra.NoArgs,
+		Example: "  goco models\n  goco models --provider gemini --family flash\n  goco models --filter 70b",
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			return runModels(cmd, deps, opts)
 		},
 	}
 
-	cmd.Flags().StringVarP(&opts.provider, "provider", "p", "", "AI provider to list models for (gemini or groq)")
-	cmd.Flags().StringVarP(&opts.apiKey, "api-key", "k", "", "API key for the selected provider (only needed if models.dev is unreachable)")
+	fs := cmd.PersistentFlags()
+	fs.StringVarP(&opts.provider, "provider", "p", "", providerUsage("AI provider to list models for"))
+	fs.StringVarP(&opts.apiKey, "api-key", "k", "", "API key for the selected provider (only needed if models.dev is unreachable)")
+	fs.StringVar(&opts.filter, "filter", "", "Only list models whose name contains this text")
+	fs.StringVar(&opts.family, "family", "", "Only list models of this family, a part of the name between dashes, e.g. flash, pro, or llama")
+
+	cmd.AddCommand(newModelsSelectCmd(deps, opts))
 	return cmd
 }
 
 func runModels(cmd *cobra.Command, deps dependencies, opts *modelsOptions) error {
-	ctx := cmd.Context()
+	list, err := listModels(cmd.Context(), deps, opts)
+	if err != nil {
+		return err
+	}
+	if len(list.models) == 0 {
+		fmt.Println(noteStyle.Render(fmt.Sprintf("No %s models match; %d are listed without --filter and --family.", providerDisplayName(list.provider), list.total)))
+		return nil
+	}
+	displayModels(cmd.Context(), list.models, list.provider, list.source, cmd.Root().Name())
+	return nil
+}
 
-	cfg, err := deps.configLoader.Load()
+// modelList is the provider's models that match the filters.
+type modelList struct {
+	cfg      *config.Config
+	provider string
+	models   []string
+	// total counts the models before filtering.
+	total  int
+	source string
+}
+
+// listModels lists the selected provider's models, from the models.dev
+// registry when it knows them and the provider's API otherwise, and
+// applies --filter and --family.
+func listModels(ctx context.Context, deps dependencies, opts *modelsOptions) (modelList, error) {
+	if err := deps.requireNetwork("listing provider models"); err != nil {
+		return modelList{}, err
+	}
+
+	cfg, err := loadConfig(ctx, deps)
 	if err != nil {
-		return fmt.Errorf("load config %q: %w", deps.configLoader.Path(), err)
+		return modelList{}, err
 	}
 
 	providerName := opts.provider
 	if providerName == "" {
 		providerName = cfg.DefaultProviderName()
 	}
-	if providerName != ai.ProviderGemini && providerName != ai.ProviderGroq {
-		return fmt.Errorf("invalid provider %q; supported providers: gemini, groq", providerName)
+	if err := checkProviderName(providerName); err != nil {
+		return modelList{}, err
 	}
 
-	displayName := providerDisplayName(providerName)
-
-	// Stage 1: Try models.dev — fast, cached, no API key needed.
-	models, source := tryModelsDev(ctx, providerName)
-	if len(models) > 0 {
-		displayModels(ctx, models, displayName, source, cmd.Root().Name())
-		return nil
+	metrics, err := newMetrics(deps, cfg)
+	if err != nil {
+		return modelList{}, err
 	}
+	defer func() { _ = metrics.Close() }()
+	tags := telemetry.Tags{"provider": providerName}
 
-	// Stage 2: models.dev unreachable — fall back to live API with spinner.
-	apiKey := opts.apiKey
-	if apiKey == "" {
-		apiKey = cfg.APIKey(providerName)
+	// Stage 1: Try models.dev — fast, cached, no API key needed. OpenRouter's
+	// own models API needs no key either and has its current prices.
+	var models []string
+	var source string
+	if providerName == ai.ProviderOpenRouter {
+		models, source = tryOpenRouterModels(ctx, providerOptions(cfg).Retry)
 	}
-	if apiKey == "" {
-		apiKey, err = promptForAPIKey(cfg.APIKeyEnv(providerName), displayName)
+	if len(models) == 0 {
+		models, source = tryModelsDev(ctx, providerName, providerOptions(cfg).Retry)
+	}
+	if len(models) > 0 {
+		metrics.Count("models.registry_hits", 1, tags)
+	} else {
+		metrics.Count("models.registry_misses", 1, tags)
+
+		// Stage 2: models.dev unreachable — fall back to live API with spinner.
+		apiKey, err := resolveAPIKey(cfg, providerName, opts.apiKey)
 		if err != nil {
-			return err
+			return modelList{}, err
 		}
-	}
 
-	provider, err := ai.NewProvider(ctx, providerName, apiKey, "")
-	if err != nil {
-		return err
-	}
+		provider, err := newProvider(ctx, providerOptions(cfg), providerName, apiKey, "")
+		if err != nil {
+			return modelList{}, err
+		}
 
-	models, err = fetchModelsWithSpinner(ctx, provider)
-	if err != nil {
-		return err
+		if models, err = fetchModelsWithSpinner(ctx, provider); err != nil {
+			return modelList{}, err
+		}
+		source = "live API"
 	}
 
-	displayModels(ctx, models, displayName, "live API", cmd.Root().Name())
-	return nil
+	return modelList{
+		cfg:      cfg,
+		provider: providerName,
+		models:   filterModels(models, opts.filter, opts.family),
+		total:    len(models),
+		source:   source,
+	}, nil
+}
+
+// filterModels keeps the models whose name contains filter and has family
+// as one of its parts between dashes or slashes, both ignoring case.
+func filterModels(models []string, filter, family string) []string {
+	filter, family = strings.ToLower(filter), strings.ToLower(family)
+	var kept []string
+	for _, model := range models {
+		name := strings.ToLower(model)
+		if !strings.Contains(name, filter) {
+			continue
+		}
+		parts := strings.FieldsFunc(name, func(r rune) bool { return r == '-' || r == '/' })
+		if family != "" && !slices.Contains(parts, family) {
+			continue
+		}
+		kept = append(kept, model)
+	}
+	return kept
 }
 
 // tryModelsDev attempts to get models from the models.dev registry cache.
 // Returns (models, source_description). On failure, returns empty slice.
-func tryModelsDev(ctx context.Context, providerName string) ([]string, string) {
-	models, err := ai.ListModelsFromDev(providerName)
+func tryModelsDev(ctx context.Context, providerName string, retry ai.RetryPolicy) ([]string, string) {
+	models, err := ai.ListModelsFromDev(providerName, retry)
 	if err != nil || len(models) == 0 {
 		return nil, ""
 	}
@@ -99,15 +173,38 @@ func tryModelsDev(ctx context.Context, providerName string) ([]string, string) {
 	return models, "models.dev registry"
 }
 
+// tryOpenRouterModels lists OpenRouter's models from its public models API,
+// which also caches their prices. On failure, returns empty slice.
+func tryOpenRouterModels(ctx context.Context, retry ai.RetryPolicy) ([]string, string) {
+	provider, err := ai.NewOpenRouterProvider(ctx, "", "", retry)
+	if err != nil {
+		return nil, ""
+	}
+	models, err := fetchModelsWithSpinner(ctx, provider)
+	if err != nil || len(models) == 0 {
+		return nil, ""
+	}
+	sort.Strings(models)
+	return models, "OpenRouter models API"
+}
+
 // displayModels prints the model list with appropriate header and source note.
 func displayModels(ctx context.Context, models []string, providerName, source, commandName string) {
 	fmt.Println(modelProviderStyle.Render(
-		fmt.Sprintf("Available %s Models (%d found)", providerName, len(models)),
+		fmt.Sprintf("Available %s Models (%d found)", providerDisplayName(providerName), len(models)),
 	))
 	fmt.Println()
 
+	width := 0
 	for _, model := range models {
-		fmt.Println(modelItemStyle.Render("• " + model))
+		width = max(width, len(model))
+	}
+	for _, model := range models {
+		item := "• " + model
+		if price, ok := ai.ModelPrice(providerName, model); ok {
+			item = fmt.Sprintf("• %-*s  %s", width, model, formatPrice(price))
+		}
+		fmt.Println(modelItemStyle.Render(item))
 	}
 
 	fmt.Println()
@@ -117,13 +214,28 @@ func displayModels(ctx context.Context, models []string, providerName, source, c
 }
 
 func fetchModelsWithSpinner(ctx context.Context, provider ai.Provider) ([]string, error) {
-	program := tea.NewProgram(newSpinnerModel(fmt.Sprintf("Fetching %s models...", providerDisplayName(provider.Name()))))
+	message := fetchingModelsMessage(provider.Name())
+	if tuiUnavailable() != "" {
+		var models []string
+		_, err := spin(ctx, message, func(ctx context.Context) (string, error) {
+			var err error
+			models, err = provider.ListModels(ctx)
+			return "", err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list models: %w", err)
+		}
+		return models, nil
+	}
+
+	program := tea.NewProgram(newSpinnerModel(message), programOptions()...)
 	resultCh := make(chan struct {
 		models []string
 		err    error
 	}, 1)
 
 	go func() {
+		defer restoreOnPanic()
 		models, err := provider.ListModels(ctx)
 		resultCh <- struct {
 			models []string
@@ -137,7 +249,7 @@ func fetchModelsWithSpinner(ctx context.Context, provider ai.Provider) ([]string
 		program.Send(spinnerStringListMsg{items: models})
 	}()
 
-	if _, err := program.Run(); err != nil {
+	if _, err := runTeaProgram(program); err != nil {
 		return nil, fmt.Errorf("run spinner: %w", err)
 	}
 
@@ -148,3 +260,17 @@ func fetchModelsWithSpinner(ctx context.Context, provider ai.Provider) ([]string
 
 	return result.models, nil
 }
+
+// formatPrice renders a model's price per million tokens, e.g. "$0.15 in,
+// $0.6 out per 1M tokens".
+func formatPrice(p ai.Price) string {
+	if p.Input == 0 && p.Output == 0 {
+		return "free"
+	}
+	return fmt.Sprintf("$%s in, $%s out per 1M tokens", formatDollars(p.Input), formatDollars(p.Output))
+}
+
+// formatDollars drops the float noise of converted per-token prices.
+func formatDollars(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1e4)/1e4, 'f', -1, 64)
+}
@@ -0,0 +1,231 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+// modelPickerRows is how many matches the picker shows at once.
+const modelPickerRows = 10
+
+func newModelsSelectCmd(deps dependencies, opts *modelsOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:     "select [model]",
+		Short:   "Pick a model and save it as the default",
+		Long:    "Search the provider's models as you type and save the one you pick to the config file as default_model, setting default_provider when it is another provider. Name the model to save it without the picker.",
+		Args:    cobra.MaximumNArgs(1),
+		Example: "  goco models select\n  goco models select --provider groq --family llama\n  goco models select gemini-2.5-pro",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runModelsSelect(cmd, deps, opts, args)
+		},
+	}
+}
+
+func runModelsSelect(cmd *cobra.Command, deps dependencies, opts *modelsOptions, args []string) error {
+	if reason := tuiUnavailable(); len(args) == 0 && reason != "" {
+		return fmt.Errorf("goco models select needs a terminal to pick from (%s); name the model instead: goco models select <model>", reason)
+	}
+	list, err := listModels(cmd.Context(), deps, opts)
+	if err != nil {
+		return err
+	}
+	if len(list.models) == 0 {
+		return fmt.Errorf("no %s models match --filter and --family", providerDisplayName(list.provider))
+	}
+
+	var model string
+	if len(args) == 1 {
+		model = args[0]
+		if !slices.Contains(list.models, model) {
+			return fmt.Errorf("model %q is not available for %s; run goco models to list them", model, providerDisplayName(list.provider))
+		}
+	} else {
+		picked, err := runProgram(newModelPickerModel(providerDisplayName(list.provider), list.models))
+		if err != nil {
+			return err
+		}
+		if m, ok := picked.(modelPickerModel); ok {
+			model = m.chosen
+		}
+		if model == "" {
+			return nil
+		}
+	}
+
+	values := map[string]string{"default_model": model}
+	if list.provider != list.cfg.DefaultProviderName() {
+		values["default_provider"] = list.provider
+	}
+	if err := deps.configLoader.SetGeneral(values); err != nil {
+		return err
+	}
+	fmt.Println(noteStyle.Render(fmt.Sprintf("Saved %s %s as the default in %s.", providerDisplayName(list.provider), model, deps.configLoader.Path())))
+	return nil
+}
+
+// modelPickerModel narrows a model list as the user types.
+type modelPickerModel struct {
+	input    textinput.Model
+	help     help.Model
+	keys     modelPickerKeyMap
+	provider string
+	models   []string
+	matches  []string
+	cursor   int
+	chosen   string
+}
+
+type modelPickerKeyMap struct {
+	Up     key.Binding
+	Down   key.Binding
+	Choose key.Binding
+	Quit   key.Binding
+}
+
+func (k modelPickerKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Choose, k.Quit}
+}
+
+func (k modelPickerKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+func newModelPickerModel(provider string, models []string) modelPickerModel {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = "Type to search"
+	input.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(electricOrange))
+	input.TextStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(creamGleam))
+	input.PlaceholderStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(mangoVolt))
+	input.Cursor.Style = lipgloss.NewStyle().Foreground(lipgloss.Color(electricOrange))
+	input.Focus()
+
+	h := help.New()
+	h.Styles.ShortKey = promptDescriptionStyle
+	h.Styles.ShortDesc = promptDescriptionStyle
+	h.Styles.ShortSeparator = promptDescriptionStyle
+
+	return modelPickerModel{
+		input:    input,
+		help:     h,
+		provider: provider,
+		models:   models,
+		matches:  models,
+		keys: modelPickerKeyMap{
+			Up:     key.NewBinding(key.WithKeys("up", "ctrl+p"), key.WithHelp("↑", "up")),
+			Down:   key.NewBinding(key.WithKeys("down", "ctrl+n"), key.WithHelp("↓", "down")),
+			Choose: key.NewBinding(key.WithKeys("enter"), key.WithHelp("↵", "save")),
+			Quit:   key.NewBinding(key.WithKeys("esc", "ctrl+c"), key.WithHelp("esc", "cancel")),
+		},
+	}
+}
+
+func (m modelPickerModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m modelPickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.keys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, m.keys.Up):
+			m.cursor = max(m.cursor-1, 0)
+			return m, nil
+		case key.Matches(msg, m.keys.Down):
+			m.cursor = min(m.cursor+1, max(len(m.matches)-1, 0))
+			return m, nil
+		case key.Matches(msg, m.keys.Choose):
+			if len(m.matches) == 0 {
+				return m, nil
+			}
+			m.chosen = m.matches[m.cursor]
+			return m, tea.Quit
+		}
+	}
+
+	var cmd tea.Cmd
+	query := m.input.Value()
+	m.input, cmd = m.input.Update(msg)
+	if m.input.Value() != query {
+		m.matches = fuzzyRank(m.models, m.input.Value())
+		m.cursor = 0
+	}
+	return m, cmd
+}
+
+func (m modelPickerModel) View() string {
+	selectedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(electricOrange)).Bold(true)
+	itemStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(creamGleam))
+
+	lines := []string{
+		promptTitleStyle.Render("Pick the default " + m.provider + " model"),
+		m.input.View(),
+		"",
+	}
+	// Scroll so the cursor stays in view.
+	first := max(m.cursor-modelPickerRows+1, 0)
+	for i := first; i < min(first+modelPickerRows, len(m.matches)); i++ {
+		if i == m.cursor {
+			lines = append(lines, selectedStyle.Render("> "+m.matches[i]))
+		} else {
+			lines = append(lines, itemStyle.Render("  "+m.matches[i]))
+		}
+	}
+	lines = append(lines, promptDescriptionStyle.Render(fmt.Sprintf("%d of %d models", len(m.matches), len(m.models))))
+	return strings.Join(append(lines, "", m.help.ShortHelpView(m.keys.ShortHelp())), "\n")
+}
+
+// fuzzyRank returns the models whose names hold the letters of query in
+// order, ignoring case: names containing query whole come first, then
+// those whose matching letters lie closest together.
+func fuzzyRank(models []string, query string) []string {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return models
+	}
+	type ranked struct {
+		model string
+		score int
+	}
+	var hits []ranked
+	for _, model := range models {
+		if score, ok := fuzzyScore(strings.ToLower(model), query); ok {
+			hits = append(hits, ranked{model, score})
+		}
+	}
+	slices.SortStableFunc(hits, func(a, b ranked) int { return a.score - b.score })
+	matches := make([]string, len(hits))
+	for i, hit := range hits {
+		matches[i] = hit.model
+	}
+	return matches
+}
+
+// fuzzyScore rates how well name matches query; lower is better.
+func fuzzyScore(name, query string) (int, bool) {
+	if i := strings.Index(name, query); i >= 0 {
+		return i, true
+	}
+	start, pos := -1, 0
+	for _, r := range query {
+		i := strings.IndexRune(name[pos:], r)
+		if i < 0 {
+			return 0, false
+		}
+		if start < 0 {
+			start = pos + i
+		}
+		pos += i + len(string(r))
+	}
+	// Whole-word matches rank ahead of every scattered one.
+	return len(name) + pos - start, true
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var testModels = []string{
+	"gemini-2.0-flash",
+	"gemini-2.0-flash-lite",
+	"gemini-2.5-flash",
+	"gemini-2.5-pro",
+	"meta-llama/llama-4-scout-17b-16e-instruct",
+	"llama-3.3-70b-versatile",
+}
+
+func TestFilterModels(t *testing.T) {
+	tests := []struct {
+		filter, family string
+		want           []string
+	}{
+		{family: "flash", want: []string{"gemini-2.0-flash", "gemini-2.0-flash-lite", "gemini-2.5-flash"}},
+		{family: "PRO", want: []string{"gemini-2.5-pro"}},
+		{family: "llama", want: []string{"meta-llama/llama-4-scout-17b-16e-instruct", "llama-3.3-70b-versatile"}},
+		{filter: "2.5", family: "flash", want: []string{"gemini-2.5-flash"}},
+		{filter: "70B", want: []string{"llama-3.3-70b-versatile"}},
+		{family: "fla"},
+	}
+	for _, tt := range tests {
+		if got := filterModels(testModels, tt.filter, tt.family); !slices.Equal(got, tt.want) {
+			t.Errorf("filterModels(%q, %q) = %v, want %v", tt.filter, tt.family, got, tt.want)
+		}
+	}
+}
+
+func TestFuzzyRank(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", testModels},
+		{"25pro", []string{"gemini-2.5-pro"}},
+		{"flash", []string{"gemini-2.0-flash", "gemini-2.0-flash-lite", "gemini-2.5-flash"}},
+		{"lite", []string{"gemini-2.0-flash-lite"}},
+		{"l70", []string{"llama-3.3-70b-versatile"}},
+		{"l17", []string{"meta-llama/llama-4-scout-17b-16e-instruct"}},
+		{"zzz", nil},
+	}
+	for _, tt := range tests {
+		if got := fuzzyRank(testModels, tt.query); !slices.Equal(got, tt.want) {
+			t.Errorf("fuzzyRank(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestModelPicker(t *testing.T) {
+	var m tea.Model = newModelPickerModel("Gemini", testModels)
+	for _, r := range "flash" {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := m.(modelPickerModel).chosen; got != "gemini-2.5-flash" || cmd == nil {
+		t.Fatalf("chosen = %q, want the last flash model", got)
+	}
+
+	m = newModelPickerModel("Gemini", testModels)
+	for _, r := range "zzz" {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter}); m.(modelPickerModel).chosen != "" {
+		t.Fatal("picked a model with no matches")
+	}
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/razobeckett/goco/internal/git"
+)
+
+// narrowFiles asks which files to commit when the commit would hold more
+// than maxFiles, so a stray `git add -A` does not become one vague commit,
+// and narrows the commit to the choice. With --yes it only warns.
+func (p *Pipeline) narrowFiles(status *git.Status, state git.State) error {
+	paths := status.Paths(p.opts.staged)
+	// --print leaves committing to git, and a sequenced commit cannot be
+	// partial.
+	if p.maxFiles <= 0 || len(paths) <= p.maxFiles || p.opts.perPackage || p.opts.printOnly || state.Sequenced() {
+		return nil
+	}
+	if p.opts.noConfirm {
+		fmt.Fprintln(os.Stderr, noteStyle.Render(fmt.Sprintf("Warning: committing %d files, more than the %d [Commit] max_files allows; drop --yes to choose fewer.", len(paths), p.maxFiles)))
+		return nil
+	}
+
+	chosen, err := p.prompter.choose(fmt.Sprintf("%d files changed, more than the %d one commit should hold. Which should this commit include?", len(paths), p.maxFiles), paths)
+	if errors.Is(err, tea.ErrProgramKilled) || errors.Is(err, tea.ErrInterrupted) {
+		return ErrCancelled
+	}
+	if err != nil {
+		return fmt.Errorf("choose files to commit: %w", err)
+	}
+	if len(chosen) == 0 {
+		fmt.Println(noteStyle.Render("No files chosen; nothing was committed."))
+		return ErrCancelled
+	}
+	if len(chosen) < len(paths) {
+		p.onlyFiles = chosen
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/razobeckett/goco/internal/git"
+)
+
+func TestNarrowFiles(t *testing.T) {
+	status := &git.Status{Entries: []git.StatusEntry{
+		{Index: 'M', Worktree: '.', Path: "a.go"},
+		{Index: 'M', Worktree: '.', Path: "b.go"},
+		{Index: 'A', Worktree: '.', Path: "c.go"},
+		{Untracked: true, Path: "notes.txt"},
+	}}
+
+	tests := []struct {
+		name      string
+		maxFiles  int
+		opts      generateOptions
+		state     git.State
+		chosen    []string
+		wantOnly  []string
+		wantAsked bool
+		wantErr   error
+	}{
+		{name: "off", maxFiles: 0},
+		{name: "within the limit", maxFiles: 3},
+		{name: "narrowed", maxFiles: 2, chosen: []string{"a.go", "c.go"}, wantOnly: []string{"a.go", "c.go"}, wantAsked: true},
+		{name: "kept all", maxFiles: 2, wantAsked: true},
+		{name: "none chosen", maxFiles: 2, chosen: []string{}, wantAsked: true, wantErr: ErrCancelled},
+		{name: "yes only warns", maxFiles: 2, opts: generateOptions{noConfirm: true}},
+		{name: "per-package splits anyway", maxFiles: 2, opts: generateOptions{perPackage: true}},
+		{name: "merge commits cannot be partial", maxFiles: 2, state: git.StateMerging},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPipeline(dependencies{}, &tt.opts)
+			p.maxFiles = tt.maxFiles
+			prompter := &scriptedPrompter{chosen: tt.chosen}
+			p.prompter = prompter
+
+			err := p.narrowFiles(status, tt.state)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("narrowFiles() = %v, want %v", err, tt.wantErr)
+			}
+			if !slices.Equal(p.onlyFiles, tt.wantOnly) {
+				t.Errorf("onlyFiles = %v, want %v", p.onlyFiles, tt.wantOnly)
+			}
+			if asked := len(prompter.asked) > 0; asked != tt.wantAsked {
+				t.Errorf("asked = %v, want %v", asked, tt.wantAsked)
+			}
+		})
+	}
+}
+
+func TestParseSelection(t *testing.T) {
+	tests := []struct {
+		answer string
+		want   []int
+		wantOK bool
+	}{
+		{answer: "1", want: []int{0}, wantOK: true},
+		{answer: "3, 1-2", want: []int{0, 1, 2}, wantOK: true},
+		{answer: "2-3,3", want: []int{1, 2}, wantOK: true},
+		{answer: "0"},
+		{answer: "4"},
+		{answer: "3-1"},
+		{answer: "a"},
+	}
+	for _, tt := range tests {
+		got, ok := parseSelection(tt.answer, 3)
+		if ok != tt.wantOK || !slices.Equal(got, tt.want) {
+			t.Errorf("parseSelection(%q) = %v, %v; want %v, %v", tt.answer, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/razobeckett/goco/internal/ai"
+)
+
+// outlinedDiff returns diff unchanged unless two-pass generation is on;
+// then it asks the model for a file-by-file outline of the change's intent
+// and returns the outline followed by diff, so the message is written from
+// the outline and checked against the code. Diffuse changes come out more
+// accurate when the model first decides what each file is for.
+func (p *Pipeline) outlinedDiff(ctx context.Context, diff string) (string, error) {
+	if !p.twoPass {
+		return diff, nil
+	}
+	// Regenerating the message reuses the outline of an unchanged diff.
+	if p.outlinedFor == diff {
+		return p.outlined, nil
+	}
+
+	input := ai.PromptInput{
+		Status:   p.statusContext(),
+		Diff:     diff,
+		Template: ai.OutlineTemplate,
+	}
+	prompt, err := p.preparePrompt(ctx, input)
+	if err != nil {
+		return "", err
+	}
+	outline, err := p.call(ctx, input, prompt, "Outlining the change...")
+	if err == nil {
+		outline, err = ai.CleanOutput(outline)
+	}
+	if err != nil {
+		return "", fmt.Errorf("outline change: %w", err)
+	}
+	outline = strings.TrimSpace(outline)
+	if outline == "" {
+		return "", fmt.Errorf("AI provider returned an empty outline")
+	}
+	p.debug("Change Outline", outline)
+
+	p.outlinedFor = diff
+	p.outlined = "The change was outlined file by file before writing this message; write the message from the outline, and check its details against the diff after it.\n\nOutline:\n" + outline + "\n\nDiff:\n" + diff
+	return p.outlined, nil
+}
+
+// debug shows an intermediate result on stderr with --debug.
+func (p *Pipeline) debug(title, text string) {
+	if p.opts.debug {
+		fmt.Fprint(os.Stderr, p.display.block(diffBlock, title, text))
+	}
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/razobeckett/goco/internal/git"
+)
+
+func TestOutlinedDiff(t *testing.T) {
+	diff := "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-a\n+b\n"
+
+	p := NewPipeline(dependencies{}, &generateOptions{})
+	p.status = &git.Status{}
+	provider := &scriptedProvider{replies: []scriptedReply{{msg: "- a.go: rename the flag\nOverall: rename the flag\n"}}}
+	p.provider = provider
+
+	if got, err := p.outlinedDiff(context.Background(), diff); err != nil || got != diff {
+		t.Fatalf("two-pass off: outlinedDiff() = %q, %v; want the diff unchanged", got, err)
+	}
+
+	p.twoPass = true
+	got, err := p.outlinedDiff(context.Background(), diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "write the message from the outline") || !strings.Contains(got, "Overall: rename the flag") || !strings.HasSuffix(got, diff) {
+		t.Errorf("outline = %q", got)
+	}
+	if again, err := p.outlinedDiff(context.Background(), diff); err != nil || again != got || provider.calls != 1 {
+		t.Errorf("regenerating re-outlined the diff: %d calls", provider.calls)
+	}
+
+	p.outlinedFor = ""
+	p.provider = &scriptedProvider{replies: []scriptedReply{{err: errors.New("quota exceeded")}}}
+	if _, err := p.outlinedDiff(context.Background(), diff); err == nil || !strings.Contains(err.Error(), "outline change: quota exceeded") {
+		t.Errorf("expected the outline failure, got %v", err)
+	}
+}
+
+func TestOutlinedDiffDebug(t *testing.T) {
+	p := NewPipeline(dependencies{}, &generateOptions{debug: true})
+	p.status = &git.Status{}
+	p.twoPass = true
+	p.display = renderer{plain: true}
+	p.provider = &scriptedProvider{replies: []scriptedReply{{msg: "- a.go: rename the flag"}}}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr := os.Stderr
+	os.Stderr = w
+	_, err = p.outlinedDiff(context.Background(), "diff --git a/a.go b/a.go\n")
+	os.Stderr = stderr
+	w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, _ := io.ReadAll(r)
+	if !strings.Contains(string(out), "Change Outline:\n- a.go: rename the flag") {
+		t.Errorf("stderr = %q, want the outline", out)
+	}
+}
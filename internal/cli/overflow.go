@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/razobeckett/goco/internal/ai"
+	"github.com/razobeckett/goco/internal/config"
+	"github.com/razobeckett/goco/internal/git"
+)
+
+// statOnlyIntro opens the file list the stat-only overflow strategy sends in
+// place of the diff.
+const statOnlyIntro = "The diff was too large to send; these are the changed files and their line counts.\n"
+
+// messageDiff is the diff the message is written from: the prompt diff, or
+// its chunk summaries, made to fit the model's context window.
+func (p *Pipeline) messageDiff(ctx context.Context) (string, error) {
+	diff, err := p.chunkedDiff(ctx, p.promptDiff())
+	if err != nil {
+		return "", err
+	}
+	return p.fitDiff(ctx, diff)
+}
+
+// fitDiff returns diff unchanged when the prompt fits the model's context
+// window; otherwise it applies the [Prompt] overflow strategy, saying what
+// it left out, so an oversized diff is never cut or rejected silently.
+func (p *Pipeline) fitDiff(ctx context.Context, diff string) (string, error) {
+	// Regenerating the message reuses the fit of an unchanged diff.
+	if p.fittedFor == diff && p.fitted != "" {
+		return p.fitted, nil
+	}
+	budget, tooLarge, err := p.overflowBudget(diff)
+	if err != nil || budget == 0 {
+		return diff, err
+	}
+
+	var fitted string
+	switch p.overflow {
+	case config.OverflowError:
+		return "", fmt.Errorf("%s; set [Prompt] overflow to \"truncate\", \"chunk\", or \"stat-only\", pass --minimize-diff, or commit fewer files", tooLarge)
+	case config.OverflowChunk:
+		if fitted, err = p.summarizeParts(ctx, diff, budget); err != nil {
+			return "", err
+		}
+	case config.OverflowStatOnly:
+		fitted = statOnlyIntro + git.DiffStat(diff)
+		if len(fitted) > budget {
+			return "", fmt.Errorf("%s, and even its file list does not fit; commit fewer files", tooLarge)
+		}
+		fmt.Fprintln(os.Stderr, noteStyle.Render(fmt.Sprintf("Warning: %s; sent only the changed files and their line counts.", tooLarge)))
+	default:
+		kept, dropped := git.TruncateDiff(diff, budget)
+		fitted = kept
+		note := fmt.Sprintf("Warning: %s; sent the diff cut short", tooLarge)
+		if len(dropped) > 0 {
+			fitted += fmt.Sprintf("\n[The diff was cut short; %d more file(s) changed: %s]\n", len(dropped), strings.Join(dropped, ", "))
+			note += fmt.Sprintf(", without %d file(s): %s", len(dropped), strings.Join(dropped, ", "))
+		}
+		fmt.Fprintln(os.Stderr, noteStyle.Render(note+". Set [Prompt] overflow to \"chunk\" to summarize it in parts instead."))
+	}
+	p.fittedFor, p.fitted = diff, fitted
+	return fitted, nil
+}
+
+// overflowBudget returns how many bytes of diff fit the model's context
+// window with the rest of the prompt, and why the diff needs fitting, or
+// zero when the whole prompt fits.
+func (p *Pipeline) overflowBudget(diff string) (int, string, error) {
+	base, err := ai.BuildPrompt(p.promptInput(""))
+	if err != nil {
+		return 0, "", err
+	}
+	overhead := estimateTokens(base) + ai.ReplyTokens
+	tokens := overhead + estimateTokens(diff)
+	if p.contextTokens == 0 && tokens <= ai.MinContextWindow {
+		return 0, "", nil
+	}
+	window, model := p.contextWindow()
+	if tokens <= window {
+		return 0, "", nil
+	}
+
+	tooLarge := fmt.Sprintf("the prompt is about %d tokens, more than the %d that fit %s", tokens, window, model)
+	budget := (window - overhead) * 4
+	if budget <= 0 {
+		return 0, "", fmt.Errorf("%s even without the diff; shorten the custom instructions or template, or raise [Prompt] context_tokens", tooLarge)
+	}
+	return budget, tooLarge, nil
+}
+
+// contextWindow returns how many tokens the selected model accepts and the
+// model's name.
+func (p *Pipeline) contextWindow() (int, string) {
+	model := p.modelName
+	if model == "" {
+		model = ai.DefaultModelFor(p.providerName, p.providerOpts)
+	}
+	if p.contextTokens > 0 {
+		return p.contextTokens, model
+	}
+	return ai.ContextWindow(p.providerName, model), model
+}
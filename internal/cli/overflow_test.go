@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/razobeckett/goco/internal/config"
+	"github.com/razobeckett/goco/internal/git"
+)
+
+func TestFitDiff(t *testing.T) {
+	small := "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-a\n+b\n"
+	large := "diff --git a/b.go b/b.go\n--- a/b.go\n+++ b/b.go\n@@ -0,0 +1,1000 @@\n" + strings.Repeat("+var generated = 1\n", 1000)
+	diff := small + large
+
+	tests := []struct {
+		overflow string
+		want     string
+		wantErr  string
+	}{
+		{overflow: config.OverflowTruncate, want: small + "\n[The diff was cut short; 1 more file(s) changed: b.go]\n"},
+		{overflow: config.OverflowStatOnly, want: "a.go | +1 -1\nb.go | +1000 -0\n"},
+		{overflow: config.OverflowChunk, want: "these are summaries of its 2 parts"},
+		{overflow: config.OverflowError, wantErr: "set [Prompt] overflow"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.overflow, func(t *testing.T) {
+			p := NewPipeline(dependencies{}, &generateOptions{})
+			p.status = &git.Status{}
+			p.provider = &scriptedProvider{replies: []scriptedReply{{msg: "adds generated variables"}}}
+			p.overflow = tt.overflow
+			p.contextTokens = 3000
+
+			got, err := p.fitDiff(context.Background(), diff)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("fitDiff() = %q, want it to contain %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFitDiffWithinWindow(t *testing.T) {
+	p := NewPipeline(dependencies{}, &generateOptions{})
+	p.status = &git.Status{}
+	p.overflow = config.OverflowError
+	diff := "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-a\n+b\n"
+
+	if got, err := p.fitDiff(context.Background(), diff); err != nil || got != diff {
+		t.Fatalf("fitDiff() = %q, %v; want the diff unchanged", got, err)
+	}
+}
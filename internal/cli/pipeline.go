@@ -1,16 +1,29 @@
 package cli
 
 import (
+	"cmp"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"regexp"
+	"slices"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/razobeckett/goco/internal/ai"
+	"github.com/razobeckett/goco/internal/audit"
+	"github.com/razobeckett/goco/internal/config"
+	"github.com/razobeckett/goco/internal/forge"
 	"github.com/razobeckett/goco/internal/git"
+	"github.com/razobeckett/goco/internal/notify"
+	"github.com/razobeckett/goco/internal/policy"
+	"github.com/razobeckett/goco/internal/state"
+	"github.com/razobeckett/goco/internal/style"
+	"github.com/razobeckett/goco/internal/telemetry"
 )
 
 // ErrCancelled is a sentinel returned when the user declines the confirmation prompt.
@@ -28,53 +41,308 @@ type Pipeline struct {
 	opts *generateOptions
 
 	// State accumulated across stages
-	provider  ai.Provider
-	modelName string
-	status    string
-	diff      string
-	recentLog string
-	commitMsg string
+	cfg *config.Config
+	// provider is nil until connect; providerName and apiKeyFlag are what
+	// connect builds it from.
+	provider     ai.Provider
+	providerName string
+	apiKeyFlag   string
+	modelName    string
+	// providerOpts are the provider settings cfg configures.
+	providerOpts ai.Options
+	spec         string
+	template     *template.Template
+	root         string
+	saveLast     bool
+	typeHints    map[string]string
+	// presetInstructions come from installed presets; styleInstructions from
+	// the learned style profile.
+	presetInstructions string
+	styleInstructions  string
+	// messageRules are required patterns; ruleFeedback tells the model what
+	// its previous message missed or repeated.
+	messageRules *policy.MessageRules
+	ruleFeedback string
+	// footer is the [Message] footer template; vars are the values it and
+	// a custom prompt template can use.
+	footer *template.Template
+	vars   ai.TemplateVars
+	// qualityThreshold is the score below which a provider's message is
+	// regenerated once; zero disables it. localDraft is set when the fast
+	// path wrote the message, which is never regenerated.
+	qualityThreshold int
+	localDraft       bool
+	// series holds the messages already committed in this run, so split
+	// commits do not repeat each other.
+	series []string
+	// imperative asks for, and repairs, imperative-mood subjects.
+	imperative bool
+	bodyFormat ai.BodyFormat
+	// blameContext enables history; history is recomputed for every diff.
+	blameContext bool
+	// minimizeDiff sends git.MinimizeDiff(diff) instead of the full diff;
+	// diff itself stays complete for blame.
+	minimizeDiff bool
+	minimize     git.Minimize
+	// chunking summarizes oversized diffs in parts; chunked holds the
+	// summaries of chunkedFor.
+	chunking   chunking
+	chunkedFor string
+	chunked    string
+	// overflow is the [Prompt] overflow strategy for a prompt larger than
+	// the context window, which contextTokens overrides when set; fitted
+	// holds what fitting fittedFor produced.
+	overflow      string
+	contextTokens int
+	fittedFor     string
+	fitted        string
+	// annotatedFor is the message diff the verbose file table was last
+	// printed for, so regenerating does not repeat it.
+	annotatedFor string
+	// twoPass writes the message from an outline of the diff; outlined
+	// holds the outline of outlinedFor.
+	twoPass     bool
+	outlinedFor string
+	outlined    string
+	// refining has the model critique and correct its first message.
+	refining bool
+	// fastPath lets draft classify trivial diffs locally.
+	fastPath bool
+	history  string
+	issues   config.Issues
+	// forgeHosts lists self-hosted forges from the [Forges] config.
+	forgeHosts map[string]string
+	issue      string
+	status     *git.Status
+	state      git.State
+	clone      git.CloneShape
+	diff       string
+	recentLog  string
+	commitMsg  string
+
+	// Gerrit Change-Id handling; changeID is reused for the current commit.
+	changeIDs bool
+	changeID  string
+	// changelogTrailer adds GitLab Changelog trailers for the branch's remote.
+	changelogTrailer bool
+
+	// Set in conflict-resolution mode by inspectResolution.
+	resolution *git.Resolution
+
+	// Per-package mode narrows each commit to one workspace package, and
+	// a commit of more than maxFiles files to the ones the user chooses.
+	scope         string
+	onlyFiles     []string
+	maxFiles      int
+	branchCreated bool
+	// askedType and askedScope are the user's --ask answers; an empty
+	// askedType means they were not asked.
+	askedType  string
+	askedScope string
+	// overrides are the author and date --author and --date record, and
+	// --allow-empty.
+	overrides git.CommitOverrides
+	// purpose is what an --allow-empty commit is for; its message is
+	// written from it instead of a diff.
+	purpose string
+
+	metrics telemetry.Sink
+	tracer  *telemetry.Tracer
+	audit   *audit.Log
+	// usage totals the tokens of every provider request in the run.
+	usage ai.UsageMeter
+
+	// prompter and committer are the interactive and the irreversible
+	// steps, injected so the whole flow runs in tests.
+	prompter  prompter
+	committer committer
+	display   renderer
+	// notifier announces a generation that took notifyAfter or longer;
+	// zero disables it.
+	notifier    notify.Notifier
+	notifyAfter time.Duration
 
 	// Retry policy for transient AI failures
 	maxRetries int
 	retryDelay time.Duration
 }
 
+// providerTimeout bounds a single provider request.
+const providerTimeout = 120 * time.Second
+
+// committer records a commit; *git.Repository is the real one.
+type committer interface {
+	Commit(ctx context.Context, message string, onlyFiles []string, o git.CommitOverrides) error
+}
+
 // NewPipeline creates a pipeline from the given dependencies and options.
 func NewPipeline(deps dependencies, opts *generateOptions) *Pipeline {
 	return &Pipeline{
 		deps:       deps,
 		opts:       opts,
+		metrics:    telemetry.Nop{},
+		prompter:   terminalPrompter{},
+		committer:  deps.repo,
+		display:    newRenderer(),
+		notifier:   notify.Desktop{},
+		tracer:     telemetry.NewTracer(telemetry.DefaultPrefix),
 		maxRetries: 2,
 		retryDelay: 2 * time.Second,
 	}
 }
 
 // Run advances through all pipeline stages in sequence.
-// The outer context carries user cancellation (Ctrl+C); the pipeline
-// wraps it with a hard timeout to prevent indefinite hangs.
+// The outer context carries user cancellation (Ctrl+C); each provider call
+// gets its own providerTimeout, so time spent reviewing or editing a message
+// never counts against it.
 func (p *Pipeline) Run(ctx context.Context) error {
-	ctx, cancel := context.WithTimeout(ctx, 120*time.Second)
-	defer cancel()
-
-	stages := []struct {
-		name string
-		fn   func(context.Context) error
-	}{
-		{"resolve", p.resolve},
-		{"inspect", p.inspect},
-		{"generate", p.generate},
+	if !p.opts.printOnly {
+		if err := p.deps.requireWritable("committing"); err != nil {
+			return fmt.Errorf("%w; add --print to only write the message", err)
+		}
+	}
+	// The issue is fetched after inspect so a clean tree costs no request.
+	stages := []pipelineStage{{"resolve", p.resolve}}
+	switch {
+	case p.opts.resolveConflicts:
+		stages = append(stages, pipelineStage{"inspect", p.inspectResolution}, pipelineStage{"issue", p.loadIssue})
+		stages = append(stages, p.messageStages()...)
+	case p.opts.perPackage:
+		stages = append(stages, pipelineStage{"inspect", p.inspect}, pipelineStage{"issue", p.loadIssue}, pipelineStage{"packages", p.commitPackages})
+	case p.opts.allowEmpty:
+		stages = append(stages, pipelineStage{"inspect", p.inspectEmpty}, pipelineStage{"issue", p.loadIssue}, pipelineStage{"ask", p.askKind})
+		stages = append(stages, p.messageStages()...)
+	default:
+		stages = append(stages, pipelineStage{"inspect", p.inspect}, pipelineStage{"issue", p.loadIssue}, pipelineStage{"ask", p.askKind})
+		stages = append(stages, p.messageStages()...)
+	}
+
+	return p.run(ctx, "goco.generate", stages)
+}
+
+// run executes stages under a root span named name, and flushes telemetry
+// on the way out. Declining a commit is not an error.
+func (p *Pipeline) run(ctx context.Context, name string, stages []pipelineStage) (err error) {
+	// Flush telemetry last; export failures must never fail the commit.
+	defer func() { _ = p.metrics.Close() }()
+	defer func() { _ = p.tracer.Close() }()
+
+	ctx, endRun := telemetry.StartSpan(telemetry.WithTracer(ctx, p.tracer), name, nil)
+	defer func() { endRun(err) }()
+
+	if err := p.runStages(ctx, stages); err != nil && !errors.Is(err, ErrCancelled) {
+		return err
+	}
+	return nil
+}
+
+type pipelineStage struct {
+	name string
+	fn   func(context.Context) error
+}
+
+// messageStages produce a single message from the current diff and either
+// commit it or, with --print, write it to stdout.
+func (p *Pipeline) messageStages() []pipelineStage {
+	if !p.opts.printOnly {
+		return p.commitStages()
+	}
+	return []pipelineStage{
+		{"generate", p.notifyWhenSlow(p.draft)},
+		{"score", p.scoreQuality},
+		{"enforce", p.enforceRules},
+		{"validate", p.validate},
+		{"print", p.print},
+	}
+}
+
+// commitStages produce and apply a single commit from the current diff.
+func (p *Pipeline) commitStages() []pipelineStage {
+	return []pipelineStage{
+		{"generate", p.notifyWhenSlow(p.draft)},
+		{"score", p.scoreQuality},
+		{"differentiate", p.differentiate},
+		{"enforce", p.enforceRules},
 		{"validate", p.validate},
 		{"review", p.review},
 		{"apply", p.apply},
 	}
+}
+
+// notifyWhenSlow runs stage and, when it took notifyAfter or longer, sends a
+// desktop notification so a user who switched away knows goco is done.
+// Failing to notify is ignored: the terminal shows the same result.
+func (p *Pipeline) notifyWhenSlow(stage func(context.Context) error) func(context.Context) error {
+	return func(ctx context.Context) error {
+		start := time.Now()
+		err := stage(ctx)
+		if p.notifyAfter <= 0 || time.Since(start) < p.notifyAfter || ctx.Err() != nil {
+			return err
+		}
+
+		subject, _, _ := strings.Cut(p.commitMsg, "\n")
+		message := "Commit message ready for review: " + subject
+		switch {
+		case err != nil:
+			message = "Generating the commit message failed"
+		case p.opts.printOnly || p.opts.noConfirm:
+			message = "Commit message ready: " + subject
+		}
+		_ = p.notifier.Notify("goco", message)
+		return err
+	}
+}
 
+func (p *Pipeline) runStages(ctx context.Context, stages []pipelineStage) error {
 	for _, s := range stages {
-		if err := s.fn(ctx); err != nil {
+		stageCtx, endStage := telemetry.StartSpan(ctx, "goco."+s.name, nil)
+		err := s.fn(stageCtx)
+		endStage(err)
+		if err != nil {
 			if errors.Is(err, ErrCancelled) {
-				return nil
+				return err
 			}
-			return fmt.Errorf("%s: %w", s.name, err)
+			return stageError{s.name, err}
+		}
+	}
+	return nil
+}
+
+// commitPackages runs the commit stages once per workspace package touched by
+// the staged changes. Declining one package's commit moves on to the next.
+func (p *Pipeline) commitPackages(ctx context.Context) error {
+	if p.state.Sequenced() {
+		return fmt.Errorf("--per-package cannot split a %s commit; run goco generate without --per-package", p.state)
+	}
+
+	packages := git.GroupByPackage(p.root, p.status.Paths(true))
+	if len(packages) == 0 {
+		return fmt.Errorf("no staged changes to commit")
+	}
+
+	for i, pkg := range packages {
+		diff, err := p.deps.repo.Diff(ctx, true, pkg.Paths...)
+		if err != nil {
+			return fmt.Errorf("read git diff for %s: %w", pkg.Dir, err)
+		}
+		if strings.TrimSpace(diff) == "" {
+			return fmt.Errorf("%s: staged diff is empty for %s", pkg.Dir, strings.Join(pkg.Paths, ", "))
+		}
+
+		p.diff, _ = git.SummarizeLFSPointers(diff)
+		p.loadHistory(ctx)
+		p.changeID = ""
+		p.scope = pkg.Name()
+		p.onlyFiles = pkg.Paths
+
+		fmt.Println(titleStyle.Render(fmt.Sprintf("Package %s (%d/%d, %d files)", pkg.Dir, i+1, len(packages), len(pkg.Paths))))
+
+		err = p.runStages(ctx, p.commitStages())
+		if err != nil && !errors.Is(err, ErrCancelled) {
+			return fmt.Errorf("%s: %w", pkg.Dir, err)
+		}
+		if err == nil {
+			p.series = append(p.series, p.commitMsg)
 		}
 	}
 	return nil
@@ -82,43 +350,198 @@ func (p *Pipeline) Run(ctx context.Context) error {
 
 // --- Stage 1: Resolve config + provider + model ---
 
+// resolve loads config and policy. It makes no request: the provider is
+// connected on first use, so a fast-path message needs no network or API key.
 func (p *Pipeline) resolve(ctx context.Context) error {
-	cfg, err := p.deps.configLoader.Load()
+	overrides, err := commitOverrides(p.opts)
 	if err != nil {
-		return fmt.Errorf("load config %q: %w", p.deps.configLoader.Path(), err)
+		return err
+	}
+	p.overrides = overrides
+
+	cfg, err := loadConfig(ctx, p.deps)
+	if err != nil {
+		return err
+	}
+	if err := checkGeminiBackend(p.opts.geminiBackend); err != nil {
+		return err
+	}
+	if p.opts.geminiBackend != "" {
+		cfg.Gemini.Backend = p.opts.geminiBackend
 	}
 
-	providerName := p.opts.provider
+	providerName := strings.ToLower(strings.TrimSpace(p.opts.provider))
 	if providerName == "" {
 		providerName = cfg.DefaultProviderName()
 	}
-	if providerName != ai.ProviderGemini && providerName != ai.ProviderGroq {
-		return fmt.Errorf("invalid provider %q; supported providers: gemini, groq", providerName)
+	// A [Commands] section's instructions stand in for --custom-instructions,
+	// as its model does for --model.
+	if p.opts.customInstructions == "" {
+		p.opts.customInstructions = cfg.Commands[p.deps.commandName()].PromptInstructions()
+	}
+	if err := checkProviderName(providerName); err != nil {
+		return err
+	}
+
+	pol, err := policy.Load()
+	if err != nil {
+		return err
+	}
+	model := p.opts.model
+	if model == "" {
+		model = cfg.ModelFor(providerName)
+	}
+	requestedProvider := providerName
+	providerName, model, err = applyPolicy(pol, providerOptions(cfg), providerName, model)
+	if err != nil {
+		return err
+	}
+	if err := checkModelProvider(providerName, model); err != nil {
+		return err
+	}
+	// The --api-key flag belongs to the requested provider, not a policy fallback.
+	apiKeyFlag := p.opts.apiKey
+	if providerName != requestedProvider {
+		apiKeyFlag = ""
+	}
+
+	metrics, err := newMetrics(p.deps, cfg)
+	if err != nil {
+		return err
+	}
+	p.metrics = metrics
+	if !p.deps.isOffline() {
+		p.tracer.SetEndpoint(cfg.Telemetry.TracesEndpoint)
+	}
+
+	spec, err := cfg.Spec()
+	if err != nil {
+		return err
+	}
+
+	// A missing root just means we're outside a repository; inspect reports that.
+	root, _ := p.deps.repo.Root(ctx)
+	templateText, err := p.deps.configLoader.PromptTemplate(cfg, root)
+	if err != nil {
+		return err
+	}
+
+	// Presets fill in whatever the config and template files leave unset.
+	pre, err := loadPresets(p.deps.configLoader.Path(), root)
+	if err != nil {
+		return err
+	}
+	typeHints := cfg.TypeHints
+	if pre != nil {
+		if spec == "" {
+			spec = pre.Spec
+		}
+		if templateText == "" {
+			templateText = pre.Template
+		}
+		typeHints = maps.Clone(pre.TypeHints)
+		if typeHints == nil {
+			typeHints = make(map[string]string, len(cfg.TypeHints))
+		}
+		maps.Copy(typeHints, cfg.TypeHints)
+		p.presetInstructions = pre.PromptInstructions()
+	}
+	var tmpl *template.Template
+	if templateText != "" {
+		if tmpl, err = ai.ParsePromptTemplate(templateText); err != nil {
+			return err
+		}
 	}
 
-	apiKey := p.opts.apiKey
-	if apiKey == "" {
-		apiKey = cfg.APIKey(providerName)
+	p.cfg = cfg
+	p.providerName = providerName
+	p.providerOpts = providerOptions(cfg)
+	p.apiKeyFlag = apiKeyFlag
+	p.modelName = model
+	p.spec = spec
+	p.template = tmpl
+	p.root = root
+	p.saveLast = cfg.Prompt.SaveLastPrompt
+	p.typeHints = typeHints
+	p.blameContext = p.opts.blameContext || cfg.Prompt.BlameContext
+	p.minimizeDiff = p.opts.minimizeDiff || cfg.Prompt.MinimizeDiff
+	p.minimize = minimizeConfig(cfg.Prompt)
+	p.chunking = newChunking(cfg.Prompt, providerName)
+	if p.overflow, err = cfg.Prompt.OverflowStrategy(); err != nil {
+		return fmt.Errorf("[Prompt]: %w", err)
+	}
+	p.contextTokens = cfg.Prompt.ContextTokens
+	p.fastPath = p.opts.fastPath || cfg.Prompt.FastPath
+	p.twoPass = p.opts.twoPass || cfg.Prompt.TwoPass
+	p.refining = p.opts.refine || cfg.Prompt.Refine
+	p.changeIDs = p.opts.changeID || cfg.Gerrit.ChangeID
+	p.issues = cfg.Issues
+	p.forgeHosts = cfg.Forges
+	if p.messageRules, err = policy.CompileMessageRules(cfg.Message.SubjectPatterns, cfg.Message.FooterPatterns); err != nil {
+		return fmt.Errorf("[Message]: %w", err)
+	}
+	if p.imperative, err = cfg.Message.Imperative(); err != nil {
+		return fmt.Errorf("[Message]: %w", err)
+	}
+	if p.footer, err = parseFooter(cfg.Message.Footer); err != nil {
+		return err
 	}
-	if apiKey == "" {
-		key, err := promptForAPIKey(cfg.APIKeyEnv(providerName), providerDisplayName(providerName))
+	// Reading the variables runs git, so only templates that may use them do.
+	if p.template != nil || p.footer != nil {
+		p.vars = p.templateVars(ctx)
+	}
+	if p.bodyFormat, err = ai.NewBodyFormat(cfg.Body.Style, cfg.Body.Bullet, cfg.Body.BulletSpacing); err != nil {
+		return fmt.Errorf("[Body]: %w", err)
+	}
+	if root != "" {
+		profile, err := style.Load(style.Path(root))
 		if err != nil {
 			return err
 		}
-		apiKey = key
+		p.styleInstructions = profile.Instructions()
+		// A learned tense replaces the imperative default, not an explicit mood.
+		if profile != nil && cfg.Message.Mood == "" && profile.Tense != "" && profile.Tense != "mixed" {
+			p.imperative = profile.Tense == "imperative"
+		}
 	}
+	p.changelogTrailer = cfg.Remotes[p.deps.repo.BranchRemote(ctx)].ChangelogTrailer
+	p.audit = audit.Open(cfg.AuditPath(audit.DefaultPath()))
+	p.notifyAfter = cfg.Notify.After()
+	p.qualityThreshold = cfg.Quality.MinScore()
+	p.maxFiles = cmp.Or(p.opts.maxFiles, cfg.Commit.MaxFiles)
+	return nil
+}
 
-	provider, err := ai.NewProvider(ctx, providerName, apiKey, p.opts.model)
+// connect creates the provider and validates the model the first time a
+// request is about to be sent.
+func (p *Pipeline) connect(ctx context.Context) error {
+	if p.provider != nil {
+		return nil
+	}
+	// Every supported provider is a hosted API.
+	if err := p.deps.requireNetwork("generating with an AI provider"); err != nil {
+		return err
+	}
+	apiKey, err := resolveAPIKey(p.cfg, p.providerName, p.apiKeyFlag)
+	if err != nil {
+		return err
+	}
+	provider, err := newProvider(ctx, p.providerOpts, p.providerName, apiKey, p.modelName)
 	if err != nil {
 		return err
 	}
 
-	modelName := p.opts.model
+	modelName := p.modelName
 	if modelName == "" {
 		modelName = provider.DefaultModel()
 	} else if modelName != provider.DefaultModel() {
 		// Only validate non-default models to save an API round-trip.
-		if err := provider.ValidateModel(ctx, modelName); err != nil {
+		spanCtx, endSpan := telemetry.StartSpan(ctx, "provider.validate_model", telemetry.Tags{"provider": p.providerName, "model": modelName})
+		callCtx, cancel := context.WithTimeout(spanCtx, providerTimeout)
+		err := provider.ValidateModel(callCtx, modelName)
+		cancel()
+		endSpan(err)
+		if err != nil {
 			return fmt.Errorf("validate model %q: %w", modelName, err)
 		}
 	}
@@ -139,20 +562,55 @@ func (p *Pipeline) inspect(ctx context.Context) error {
 		return err
 	}
 
-	diff, err := p.deps.repo.Diff(ctx, p.opts.staged)
+	state, err := p.deps.repo.State(ctx)
 	if err != nil {
-		return fmt.Errorf("read git diff: %w", err)
+		return err
+	}
+	if err := checkRepoState(state, status); err != nil {
+		return err
+	}
+	if err := p.narrowFiles(status, state); err != nil {
+		return err
+	}
+
+	diff, lfsPaths, err := p.readDiff(ctx, status)
+	if err != nil {
+		return err
 	}
 
 	if strings.TrimSpace(diff) == "" {
+		if status.Initial {
+			return fmt.Errorf("nothing to commit yet; add files with `git add` to make the first commit")
+		}
 		if p.opts.staged {
 			return fmt.Errorf("no staged changes to generate a commit from; stage files with `git add` first, or run without --staged to include working-tree changes")
 		}
 		return fmt.Errorf("no changes detected in the working tree; edit files before running goco")
 	}
 
+	if len(lfsPaths) > 0 && p.opts.verbose {
+		fmt.Println(noteStyle.Render(fmt.Sprintf("Summarized %d Git LFS pointer file(s) instead of sending their diffs.", len(lfsPaths))))
+	}
+	if cut := git.TruncatedFiles(diff); len(cut) > 0 {
+		fmt.Fprintln(os.Stderr, noteStyle.Render(fmt.Sprintf(
+			"Warning: the diffs of %d file(s) were too large to read whole and were cut short: %s.",
+			len(cut), strings.Join(cut, ", "),
+		)))
+	}
+	p.warnLargeBinaries(ctx, status)
+
+	if findings := ai.ScanInjection(diff); len(findings) > 0 {
+		fmt.Fprintln(os.Stderr, noteStyle.Render(fmt.Sprintf(
+			"Warning: the diff contains text that looks like instructions to an AI (%q); it is sent as data only, but review the generated message carefully.",
+			findings[0],
+		)))
+	}
+
 	p.status = status
+	p.state = state
+	p.clone = p.deps.repo.CloneShape(ctx)
 	p.diff = diff
+	p.loadHistory(ctx)
 
 	// Fetch recent commit history for contextual message generation.
 	if log, err := p.deps.repo.RecentLog(ctx, 3); err == nil {
@@ -160,18 +618,265 @@ func (p *Pipeline) inspect(ctx context.Context) error {
 	}
 
 	if p.opts.verbose {
-		fmt.Println(statusHeaderStyle.Render("Git Status"))
-		fmt.Println(statusBoxStyle.Render(status))
-		fmt.Println(diffHeaderStyle.Render("Git Diff"))
-		fmt.Println(diffBoxStyle.Render(diff))
+		fmt.Print(p.display.block(statusBlock, theme.Messages.StatusTitle, p.statusContext()))
+		fmt.Print(p.display.block(diffBlock, theme.Messages.DiffTitle, diff))
+		if p.minimizeDiff {
+			fmt.Println(noteStyle.Render(fmt.Sprintf("Minimized diff: %d of %d bytes are sent.", len(p.promptDiff()), len(diff))))
+		}
 	}
 
 	return nil
 }
 
+// readDiff reads the diff a commit would record, with Git LFS pointers
+// summarized, and returns the paths of the summarized pointers.
+func (p *Pipeline) readDiff(ctx context.Context, status *git.Status) (string, []string, error) {
+	var diff string
+	var err error
+	if status.Initial && !p.opts.staged {
+		// There is no HEAD yet; everything tracked goes into the first commit.
+		diff, err = p.deps.repo.EmptyTreeDiff(ctx, p.onlyFiles...)
+	} else {
+		diff, err = p.deps.repo.Diff(ctx, p.opts.staged, p.onlyFiles...)
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("read git diff: %w", err)
+	}
+	diff, lfsPaths := git.SummarizeLFSPointers(diff)
+	return diff, lfsPaths, nil
+}
+
+// promptDiff is the diff as sent to the model.
+func (p *Pipeline) promptDiff() string {
+	if !p.minimizeDiff {
+		return p.diff
+	}
+	return git.MinimizeDiff(p.diff, p.minimize)
+}
+
+// minimizeConfig applies the [Prompt] minimize settings over the defaults.
+func minimizeConfig(cfg config.Prompt) git.Minimize {
+	m := git.DefaultMinimize
+	if cfg.MinimizeContext > 0 {
+		m.Context = cfg.MinimizeContext
+	}
+	if cfg.MinimizeAddedLines > 0 {
+		m.AddedRun = cfg.MinimizeAddedLines
+	}
+	return m
+}
+
+// loadHistory blames the lines the current diff changes when blame context
+// is enabled. It is best-effort: the prompt is still useful without it.
+func (p *Pipeline) loadHistory(ctx context.Context) {
+	p.history = p.blameHistory(ctx, p.status, p.diff)
+}
+
+// blameHistory returns the blame context for diff, or "" when it is off or
+// unreadable. It does not write the pipeline.
+func (p *Pipeline) blameHistory(ctx context.Context, status *git.Status, diff string) string {
+	// Nothing to blame before the first commit.
+	if !p.blameContext || status.Initial {
+		return ""
+	}
+	// Blame reads old file versions, which a partial clone downloads one by one.
+	if p.clone.Partial() {
+		if p.opts.verbose {
+			fmt.Fprintln(os.Stderr, noteStyle.Render("Skipping blame context: this is a partial clone, and blame would download old file contents."))
+		}
+		return ""
+	}
+	history, err := p.deps.repo.BlameContext(ctx, diff, "HEAD")
+	if err != nil {
+		if p.opts.verbose {
+			fmt.Fprintf(os.Stderr, "warning: could not read blame context: %v\n", err)
+		}
+		return ""
+	}
+	return history
+}
+
+// warnLargeBinaries flags big binaries that are about to enter history
+// without Git LFS. Failures are ignored: the check is advisory only.
+func (p *Pipeline) warnLargeBinaries(ctx context.Context, status *git.Status) {
+	large, err := p.deps.repo.LargeBinaries(ctx, p.root, status.Paths(p.opts.staged))
+	if err != nil || len(large) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(large))
+	for _, f := range large {
+		names = append(names, fmt.Sprintf("%s (%s)", f.Path, git.FormatSize(f.Size)))
+	}
+	fmt.Fprintln(os.Stderr, noteStyle.Render(fmt.Sprintf(
+		"Warning: large binary files are not tracked by Git LFS: %s. Consider `git lfs track` before committing.",
+		strings.Join(names, ", "),
+	)))
+}
+
+// inspectResolution gathers a staged conflict resolution for `goco resolve-msg`.
+func (p *Pipeline) inspectResolution(ctx context.Context) error {
+	status, err := p.deps.repo.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("read git status: %w", err)
+	}
+	for _, entry := range status.Entries {
+		if entry.Conflicted {
+			return fmt.Errorf("%s still has unresolved conflicts; resolve it and stage with `git add` first", entry.Path)
+		}
+	}
+
+	res, err := p.deps.repo.Resolution(ctx)
+	if err != nil {
+		return err
+	}
+
+	diff := "Resolution compared with our side (HEAD):\n" + res.OursDiff +
+		"\nResolution compared with the incoming side (" + res.Theirs + "):\n" + res.TheirsDiff
+	diff, _ = git.SummarizeLFSPointers(diff)
+
+	p.status = status
+	p.state = res.State
+	p.resolution = res
+	p.diff = diff
+
+	if p.opts.verbose {
+		fmt.Print(p.display.block(statusBlock, "Conflict Resolution", p.statusContext()))
+		fmt.Print(p.display.block(diffBlock, theme.Messages.DiffTitle, diff))
+	}
+	return nil
+}
+
+// checkRepoState refuses states goco cannot commit through and warns about risky ones.
+func checkRepoState(state git.State, status *git.Status) error {
+	switch state {
+	case git.StateRebasing:
+		return fmt.Errorf("a rebase is in progress; finish it with `git rebase --continue` or abort with `git rebase --abort` before running goco")
+	case git.StateBisecting:
+		fmt.Fprintln(os.Stderr, noteStyle.Render("Warning: a bisect is in progress; the commit will be made on the bisect checkout."))
+	}
+
+	var conflicted int
+	for _, entry := range status.Entries {
+		if entry.Conflicted {
+			conflicted++
+		}
+	}
+	if conflicted > 0 {
+		return fmt.Errorf("%d file(s) still have unresolved conflicts; resolve them and stage with `git add` before running goco", conflicted)
+	}
+
+	if status.Detached {
+		fmt.Fprintln(os.Stderr, noteStyle.Render("Warning: HEAD is detached; create a branch with --branch to keep this commit reachable."))
+	}
+
+	return nil
+}
+
+// statusContext returns the status summary, prefixed with a hint when an
+// in-progress operation changes what kind of commit is being written.
+func (p *Pipeline) statusContext() string {
+	summary := p.status.String()
+	if sparse := p.clone.String(); sparse != "" {
+		summary = sparse + "\n" + summary
+	}
+
+	var hint string
+	switch p.state {
+	case git.StateMerging:
+		hint = "A merge is in progress: this commit concludes the merge, so describe the merge and any conflict resolution."
+	case git.StateCherryPicking:
+		hint = "A cherry-pick is in progress: this commit applies an existing change, so describe that change and any conflict resolution."
+	case git.StateReverting:
+		hint = "A revert is in progress: this commit reverts an earlier change, so describe what is being reverted and why."
+	}
+	if hint == "" && p.status.Initial {
+		return "This is the repository's initial commit: describe what the project starts out with.\n" + summary
+	}
+	if p.resolution != nil {
+		summary = p.resolution.String() + "\n" + summary
+	}
+	if hint == "" {
+		return summary
+	}
+
+	return "state: " + p.state.String() + "\n" + hint + "\n" + summary
+}
+
 // --- Stage 3: Generate commit message via AI (with retry) ---
 
+// draft writes the first message for the current diff. With the fast path,
+// trivial changes are described locally; anything the classifier does not
+// recognize or cannot fit in one subject, and every regeneration, goes to the
+// provider. With --refine, the provider's first message is then refined.
+func (p *Pipeline) draft(ctx context.Context) error {
+	msg, kind, ok := p.localMessage()
+	p.localDraft = ok
+	if !ok {
+		if err := p.generate(ctx); err != nil {
+			return err
+		}
+		return p.refine(ctx)
+	}
+	if p.opts.verbose {
+		fmt.Println(noteStyle.Render(fmt.Sprintf("Recognized a %s change; wrote the message without the provider.", kind)))
+	}
+	p.metrics.Count("messages.local", 1, telemetry.Tags{"kind": kind})
+	p.commitMsg = p.withTrailers(p.repairMessage(msg))
+	return nil
+}
+
+// localMessage returns the fast-path message for the current diff and the
+// kind of change it recognized, or false when the provider must write it.
+func (p *Pipeline) localMessage() (string, string, bool) {
+	if !p.fastPath || !p.defaultPrompt() {
+		return "", "", false
+	}
+	c, ok := ai.Classify(p.diff, p.typeHints)
+	if !ok {
+		return "", "", false
+	}
+	if p.opts.scope != "" {
+		c.Scope = p.opts.scope
+	}
+	if p.askedType != "" {
+		c.Type, c.Scope = p.askedType, p.askedScope
+	}
+	if p.scope != "" {
+		c.Scope = p.scope
+	}
+	msg := c.Message()
+	// A long rename or file list makes an overlong subject; the model can
+	// summarize it instead.
+	if subject, _, _ := strings.Cut(msg, "\n"); ai.SubjectLength(subject) > ai.MaxSubjectLength {
+		return "", "", false
+	}
+	return msg, c.Kind, true
+}
+
+// defaultPrompt reports whether the model would get goco's stock
+// Conventional Commits prompt. A custom spec, template, preset, learned
+// style, custom instructions, or issue asks for more than the classifier
+// writes, so the fast path steps aside. Body format and [Message] rules
+// still apply: repairMessage and enforceRules run on local messages too.
+func (p *Pipeline) defaultPrompt() bool {
+	return p.spec == "" && p.template == nil && p.presetInstructions == "" && p.styleInstructions == "" &&
+		p.opts.customInstructions == "" && p.issue == "" && p.resolution == nil
+}
+
 func (p *Pipeline) generate(ctx context.Context) error {
+	diff, err := p.messageDiff(ctx)
+	if err != nil {
+		return err
+	}
+	if p.opts.verbose && diff != p.annotatedFor {
+		p.annotatedFor = diff
+		fmt.Print(p.display.block(statusBlock, "Files", formatFileTable(p.annotateFiles(diff))))
+	}
+	if diff, err = p.outlinedDiff(ctx, diff); err != nil {
+		return err
+	}
+
 	var lastErr error
 
 	for attempt := 0; attempt <= p.maxRetries; attempt++ {
@@ -185,14 +890,20 @@ func (p *Pipeline) generate(ctx context.Context) error {
 			}
 		}
 
-		msg, err := p.spin(ctx, "Generating commit message...", func(ctx context.Context) (string, error) {
-			return p.provider.GenerateCommitMessage(ctx, p.status, p.diff, p.opts.customInstructions, p.recentLog)
-		})
+		input := p.promptInput(diff)
+		prompt, err := p.preparePrompt(ctx, input)
+		if err != nil {
+			return err
+		}
+		msg, err := p.send(ctx, input, prompt, theme.Messages.Generating)
 		if err == nil {
 			if strings.TrimSpace(msg) == "" {
 				return fmt.Errorf("AI provider returned an empty commit message")
 			}
-			p.commitMsg = strings.TrimSpace(msg)
+			if msg, err = ai.CleanOutput(msg); err != nil {
+				return err
+			}
+			p.commitMsg = p.withTrailers(p.repairMessage(msg))
 			return nil
 		}
 
@@ -209,19 +920,201 @@ func (p *Pipeline) generate(ctx context.Context) error {
 	return fmt.Errorf("generate commit message after %d retries: %w", p.maxRetries+1, lastErr)
 }
 
+// promptInput is what the model is asked about diff, which is the prompt
+// diff or its chunk summaries.
+func (p *Pipeline) promptInput(diff string) ai.PromptInput {
+	if p.purpose != "" {
+		return p.emptyPromptInput()
+	}
+	return ai.PromptInput{
+		Status:             p.statusContext(),
+		Diff:               diff,
+		CustomInstructions: p.instructions(),
+		RecentLog:          p.recentLog,
+		Spec:               p.spec,
+		TypeHints:          ai.FormatTypeHints(ai.TypeHints(p.commitPaths(), p.typeHints)),
+		History:            p.history,
+		Issue:              p.issue,
+		Template:           p.template,
+		Vars:               p.vars,
+	}
+}
+
+// preparePrompt connects the provider, renders input, and records it in the audit log. Nothing may
+// be sent to the provider when auditing fails.
+func (p *Pipeline) preparePrompt(ctx context.Context, input ai.PromptInput) (string, error) {
+	if err := p.connect(ctx); err != nil {
+		return "", err
+	}
+	prompt, err := ai.BuildPrompt(input)
+	if err != nil {
+		return "", err
+	}
+	if err := p.audit.Append(p.auditEntry("request", prompt, "", nil)); err != nil {
+		return "", fmt.Errorf("audit log: %w", err)
+	}
+	return prompt, nil
+}
+
+// send calls the provider with tracing, metrics, response auditing, and the
+// optional last-prompt snapshot.
+func (p *Pipeline) send(ctx context.Context, input ai.PromptInput, prompt, message string) (string, error) {
+	msg, err := p.call(ctx, input, prompt, message)
+	p.saveLastPrompt(prompt, msg, err)
+	return msg, err
+}
+
+// call makes one provider request with tracing, metrics, and response
+// auditing, behind a spinner showing message unless message is empty.
+// Without a spinner it is safe for concurrent use.
+func (p *Pipeline) call(ctx context.Context, input ai.PromptInput, prompt, message string) (string, error) {
+	start := time.Now()
+	spanCtx, endSpan := telemetry.StartSpan(ctx, "provider.generate", p.metricTags())
+	var used ai.UsageMeter
+	request := func(ctx context.Context) (string, error) {
+		ctx, cancel := context.WithTimeout(ctx, providerTimeout)
+		defer cancel()
+		return p.provider.GenerateCommitMessage(ai.WithUsageMeter(ctx, &used), input)
+	}
+	var msg string
+	var err error
+	if message != "" {
+		msg, err = spin(spanCtx, message, request)
+	} else {
+		msg, err = request(spanCtx)
+	}
+	endSpan(err)
+	usage, reported := used.Usage()
+	p.recordProviderCall(start, err)
+	entry := p.auditEntry("response", prompt, msg, err)
+	if reported {
+		p.recordUsage(usage)
+		entry.PromptTokens, entry.CompletionTokens = usage.PromptTokens, usage.CompletionTokens
+	}
+	if auditErr := p.audit.Append(entry); auditErr != nil {
+		fmt.Fprintf(os.Stderr, "warning: could not write audit log: %v\n", auditErr)
+	}
+	return msg, err
+}
+
+// withTrailers adds the [Message] footer and the trailers enabled by config
+// and flags.
+func (p *Pipeline) withTrailers(msg string) string {
+	msg = p.withFooter(msg)
+	if p.changelogTrailer && !git.HasTrailer(msg, "Changelog") {
+		if m := conventionalCommitRegex.FindStringSubmatch(msg); m != nil {
+			if category := forge.GitLabChangelogCategory(m[1]); category != "" {
+				msg = git.WithTrailer(msg, "Changelog", category)
+			}
+		}
+	}
+	return p.withChangeID(msg)
+}
+
+// withChangeID adds the Gerrit Change-Id trailer when enabled, keeping one
+// ID per commit across retries and edits. An ID already in msg wins.
+func (p *Pipeline) withChangeID(msg string) string {
+	if !p.changeIDs {
+		return msg
+	}
+	if id := git.ChangeID(msg); id != "" {
+		p.changeID = id
+		return msg
+	}
+	if p.changeID == "" {
+		p.changeID = git.NewChangeID(msg)
+	}
+	return git.WithChangeID(msg, p.changeID)
+}
+
+// commitPaths lists the paths the next commit will include.
+func (p *Pipeline) commitPaths() []string {
+	if p.opts.allowEmpty {
+		return nil
+	}
+	if p.onlyFiles != nil {
+		return p.onlyFiles
+	}
+	return p.status.Paths(p.opts.staged)
+}
+
+// instructions combines the user's custom instructions with per-package
+// scope and conflict-resolution guidance.
+func (p *Pipeline) instructions() string {
+	var parts []string
+	if p.presetInstructions != "" {
+		parts = append(parts, p.presetInstructions)
+	}
+	if p.styleInstructions != "" {
+		parts = append(parts, p.styleInstructions)
+	}
+	if p.opts.customInstructions != "" {
+		parts = append(parts, p.opts.customInstructions)
+	}
+	if p.scope != "" {
+		parts = append(parts, fmt.Sprintf("This commit only covers the %q package; use %q as the commit scope.", p.scope, p.scope))
+	}
+	if p.opts.scope != "" && p.askedType == "" {
+		parts = append(parts, fmt.Sprintf("The user chose the scope of this commit: use %q as the commit scope.", p.opts.scope))
+	}
+	if p.askedType != "" {
+		prefix := p.askedType
+		if p.askedScope != "" {
+			prefix += "(" + p.askedScope + ")"
+		}
+		parts = append(parts, fmt.Sprintf("The user chose the type and scope of this commit: start the subject with %q.", prefix+": "))
+	}
+	if p.imperative {
+		parts = append(parts, `Write the subject in the imperative mood: "add", not "added" or "adds".`)
+	}
+	if body := p.bodyFormat.Instructions(); body != "" {
+		parts = append(parts, body)
+	}
+	if rules := p.messageRules.Instructions(); rules != "" {
+		parts = append(parts, rules)
+	}
+	if p.ruleFeedback != "" {
+		parts = append(parts, p.ruleFeedback)
+	}
+	if p.resolution != nil {
+		parts = append(parts, "This commit concludes a conflict resolution. The diff shows the resolution against our side and against the incoming side. "+
+			"Write a message that names what was integrated and, in the body, summarizes for each conflicted file what was kept from each side.")
+	}
+	return strings.Join(parts, "\n")
+}
+
+// repairMessage fixes the mistakes models commonly make: a run-on first
+// line and, unless the team opted out, a verb not in the imperative. It then
+// lays the body out as configured and puts the trailers in canonical order.
+func (p *Pipeline) repairMessage(msg string) string {
+	msg = ai.SplitLongSubject(strings.TrimSpace(msg))
+	if p.imperative {
+		msg, _ = ai.ImperativeSubject(msg)
+	}
+	return ai.NormalizeTrailers(p.bodyFormat.Apply(msg))
+}
+
 // --- Stage 4: Validate the commit message ---
 
 func (p *Pipeline) validate(_ context.Context) error {
-	lines := strings.Split(p.commitMsg, "\n")
+	if err := lintCommitMessage(p.commitMsg); err != nil {
+		return err
+	}
+	return p.messageRules.Err(p.commitMsg)
+}
+
+// lintCommitMessage checks a message against the subject rules goco enforces.
+func lintCommitMessage(msg string) error {
+	lines := strings.Split(msg, "\n")
 	if len(lines) == 0 {
 		return fmt.Errorf("commit message is empty")
 	}
 
 	subject := lines[0]
-	if len(subject) > 72 {
+	if n := ai.SubjectLength(subject); n > ai.MaxSubjectLength {
 		return fmt.Errorf(
-			"commit subject is %d characters (max 72); use --edit to shorten it",
-			len(subject),
+			"commit subject is %d characters (max %d); use --edit to shorten it",
+			n, ai.MaxSubjectLength,
 		)
 	}
 
@@ -235,23 +1128,34 @@ func (p *Pipeline) validate(_ context.Context) error {
 	return nil
 }
 
+// print writes the bare message to stdout so it can be piped into git, or
+// with --output json the message with its score and usage.
+func (p *Pipeline) print(_ context.Context) error {
+	if p.deps.jsonOutput() {
+		return json.NewEncoder(os.Stdout).Encode(p.result())
+	}
+	if p.opts.verbose {
+		fmt.Fprintln(os.Stderr, noteStyle.Render(p.resultBadge()))
+	}
+	fmt.Println(p.commitMsg)
+	return nil
+}
+
 // --- Stage 5: Review — display, optional edit, confirm ---
 
 func (p *Pipeline) review(ctx context.Context) error {
-	fmt.Println(commitMessageHeaderStyle.Render("Generated Commit Message"))
-	fmt.Println(commitMessageBoxStyle.Render(p.commitMsg))
+	fmt.Print(p.display.block(messageBlock, theme.Messages.MessageTitle+" · "+p.resultBadge(), p.commitMsg))
 
 	if p.opts.edit {
 		fmt.Println(titleStyle.Render("Edit Commit Message"))
 
-		edited, err := editCommitMessage(p.commitMsg)
+		edited, err := p.prompter.edit(p.commitMsg)
 		if err != nil {
 			return err
 		}
-		p.commitMsg = edited
+		p.commitMsg = p.withTrailers(edited)
 
-		fmt.Println(commitMessageHeaderStyle.Render("Final Commit Message"))
-		fmt.Println(commitMessageBoxStyle.Render(p.commitMsg))
+		fmt.Print(p.display.block(messageBlock, theme.Messages.FinalTitle+" · "+p.resultBadge(), p.commitMsg))
 
 		// Re-validate after editing.
 		if err := p.validate(ctx); err != nil {
@@ -259,71 +1163,266 @@ func (p *Pipeline) review(ctx context.Context) error {
 		}
 	}
 
+	if p.opts.explainActions {
+		if err := p.explainActions(ctx); err != nil {
+			return err
+		}
+	}
+
 	if p.opts.noConfirm {
+		p.metrics.Count("commits.accepted", 1, p.metricTags())
 		return nil
 	}
 
-	confirmed, err := confirmCommit()
+	confirmed, err := p.prompter.confirm("Proceed with this commit?")
 	if err != nil {
 		return err
 	}
 	if !confirmed {
+		p.metrics.Count("commits.cancelled", 1, p.metricTags())
 		fmt.Println(noteStyle.Render("Commit cancelled."))
 		return ErrCancelled
 	}
 
+	p.metrics.Count("commits.accepted", 1, p.metricTags())
 	return nil
 }
 
+// providerLabel names the provider, including before connect when a
+// fast-path message never needed it.
+func (p *Pipeline) providerLabel() string {
+	if p.provider != nil {
+		return p.provider.Name()
+	}
+	return p.providerName
+}
+
+func (p *Pipeline) metricTags() telemetry.Tags {
+	return telemetry.Tags{"provider": p.providerLabel(), "model": p.modelName}
+}
+
+func (p *Pipeline) auditEntry(event, prompt, msg string, err error) audit.Entry {
+	return newAuditEntry(event, p.root, p.providerLabel(), p.modelName, prompt, msg, err)
+}
+
+// saveLastPrompt stores the redacted prompt and raw response when the user
+// opted in; failures only warn because this is a debugging aid.
+func (p *Pipeline) saveLastPrompt(prompt, msg string, err error) {
+	if !p.saveLast {
+		return
+	}
+
+	lp := state.LastPrompt{
+		Time:     time.Now(),
+		Provider: p.providerLabel(),
+		Model:    p.modelName,
+		Prompt:   ai.RedactSecrets(prompt),
+		Response: msg,
+	}
+	if err != nil {
+		lp.Error = err.Error()
+	}
+	if saveErr := state.SaveLastPrompt(lp); saveErr != nil {
+		fmt.Fprintf(os.Stderr, "warning: could not save last prompt: %v\n", saveErr)
+	}
+}
+
+func (p *Pipeline) recordProviderCall(start time.Time, err error) {
+	tags := p.metricTags()
+	tags["outcome"] = "ok"
+	if err != nil {
+		tags["outcome"] = "error"
+	}
+	p.metrics.Count("provider.calls", 1, tags)
+	p.metrics.Timing("provider.latency", time.Since(start), tags)
+}
+
+// recordUsage adds one request's tokens to the run's total and to the
+// provider.tokens metric.
+func (p *Pipeline) recordUsage(u ai.Usage) {
+	p.usage.Record(u)
+	for kind, n := range map[string]int{"prompt": u.PromptTokens, "completion": u.CompletionTokens} {
+		tags := p.metricTags()
+		tags["kind"] = kind
+		p.metrics.Count("provider.tokens", int64(n), tags)
+	}
+}
+
 // --- Stage 6: Apply — branch, stage, commit ---
 
+// commitPlan is what apply will do: create branch, then stage tracked
+// changes when stageTracked is set, then commit (only files, when set).
+type commitPlan struct {
+	branch       string
+	stageTracked bool
+	files        []string
+}
+
+func (p *Pipeline) planCommit(ctx context.Context) (commitPlan, error) {
+	var plan commitPlan
+	if p.opts.newBranch != "" && !p.branchCreated {
+		plan.branch = p.opts.newBranch
+	}
+
+	switch {
+	case p.opts.allowEmpty:
+		// An empty commit records nothing; inspectEmpty refused staged files.
+	case p.onlyFiles != nil:
+		plan.files = p.onlyFiles
+	case p.opts.staged:
+		files, err := p.deps.repo.StagedFiles(ctx)
+		if err != nil {
+			if err == git.ErrNoChanges {
+				return plan, fmt.Errorf("no staged changes to commit")
+			}
+			return plan, err
+		}
+		plan.files = files
+	default:
+		plan.stageTracked = true
+	}
+
+	// Git refuses partial commits while concluding a merge, cherry-pick, or revert.
+	if p.state.Sequenced() {
+		plan.files = nil
+	}
+	return plan, nil
+}
+
+// gitCommands lists the git invocations plan will run, in order.
+func (p *Pipeline) gitCommands(plan commitPlan) [][]string {
+	var cmds [][]string
+	if plan.branch != "" {
+		cmds = append(cmds, git.CreateBranchArgs(plan.branch))
+	}
+	if plan.stageTracked {
+		cmds = append(cmds, git.StageTrackedArgs())
+	}
+	return append(cmds, git.CommitArgs(p.commitMsg, plan.files, p.overrides))
+}
+
+// explainActions prints exactly what committing will run, including what
+// git itself will do on goco's behalf.
+func (p *Pipeline) explainActions(ctx context.Context) error {
+	plan, err := p.planCommit(ctx)
+	if err != nil {
+		return err
+	}
+	settings, err := p.deps.repo.CommitSettings(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(titleStyle.Render("Planned Git Commands"))
+	for _, args := range p.gitCommands(plan) {
+		quoted := make([]string, len(args))
+		for i, arg := range args {
+			quoted[i] = shellArg(arg)
+		}
+		fmt.Println("  git " + strings.Join(quoted, " "))
+	}
+	if len(settings.Hooks) > 0 {
+		fmt.Println(noteStyle.Render("git will run these hooks: " + strings.Join(settings.Hooks, ", ") + " (goco never passes --no-verify)."))
+	} else {
+		fmt.Println(noteStyle.Render("No commit hooks are installed."))
+	}
+	if settings.Sign {
+		format := settings.SignFormat
+		if settings.SigningKey != "" {
+			format += ", key " + settings.SigningKey
+		}
+		fmt.Println(noteStyle.Render(fmt.Sprintf("git will sign the commit (%s), as commit.gpgsign is set.", format)))
+		if err := git.CheckSSHSigning(ctx, settings); err != nil {
+			fmt.Println(noteStyle.Render(fmt.Sprintf("Warning: signing will fail: %v.", err)))
+		}
+	} else {
+		fmt.Println(noteStyle.Render("The commit will not be signed; commit.gpgsign is not set."))
+	}
+	fmt.Println()
+	return nil
+}
+
 func (p *Pipeline) apply(ctx context.Context) error {
-	if p.opts.newBranch != "" {
+	plan, err := p.planCommit(ctx)
+	if err != nil {
+		return err
+	}
+	// git only finds out it cannot sign once it writes the commit, after
+	// goco has created the branch and staged the files.
+	settings, err := p.deps.repo.CommitSettings(ctx)
+	if err != nil {
+		return err
+	}
+	if err := git.CheckSSHSigning(ctx, settings); err != nil {
+		return fmt.Errorf("cannot sign the commit: %w", err)
+	}
+
+	if plan.branch != "" {
 		currentBranch, err := p.deps.repo.CurrentBranch(ctx)
 		if err != nil {
 			return err
 		}
 
-		if err := p.deps.repo.CreateBranch(ctx, p.opts.newBranch); err != nil {
+		if err := p.deps.repo.CreateBranch(ctx, plan.branch); err != nil {
 			return err
 		}
 
+		p.branchCreated = true
+
 		if p.opts.verbose {
-			fmt.Printf("\nCreated and switched to %q from %q.\n\n", p.opts.newBranch, currentBranch)
+			fmt.Printf("\nCreated and switched to %q from %q.\n\n", plan.branch, currentBranch)
 		}
 	}
 
-	var stagedFiles []string
-	var err error
-
-	if p.opts.staged {
-		stagedFiles, err = p.deps.repo.StagedFiles(ctx)
-		if err != nil {
-			if err == git.ErrNoChanges {
-				return fmt.Errorf("no staged changes to commit")
-			}
-			return err
-		}
-	} else {
+	if plan.stageTracked {
 		if err := p.deps.repo.StageTracked(ctx); err != nil {
 			return err
 		}
 	}
 
-	if err := p.deps.repo.Commit(ctx, p.commitMsg, stagedFiles); err != nil {
+	if err := p.committer.Commit(ctx, p.commitMsg, plan.files, p.overrides); err != nil {
 		return err
 	}
+	p.learnHookTrailers(ctx, settings)
+	// The draft `goco watch` saved described what was just committed.
+	if err := state.ClearDraft(p.root); err != nil && p.opts.verbose {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
 
+	if p.resolution != nil && p.state == git.StateRebasing {
+		fmt.Println(noteStyle.Render("Run `git rebase --continue` to apply the remaining commits."))
+	}
 	return nil
 }
 
+// learnHookTrailers records the trailers commit hooks added to the commit
+// just made, such as a DCO sign-off, so messages regenerated for commits in
+// this repository keep them.
+func (p *Pipeline) learnHookTrailers(ctx context.Context, settings git.CommitSettings) {
+	if !slices.Contains(settings.Hooks, "commit-msg") && !slices.Contains(settings.Hooks, "prepare-commit-msg") {
+		return
+	}
+	head, err := p.deps.repo.History(ctx, 1)
+	if err != nil || len(head) == 0 {
+		return
+	}
+	keys := git.AddedTrailerKeys(p.commitMsg, head[0].Subject+"\n\n"+head[0].Body)
+	if len(keys) == 0 {
+		return
+	}
+	if err := state.AddHookTrailers(p.root, keys); err != nil && p.opts.verbose {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+}
+
 // --- Spinner ---
 // spin shows an animated spinner on stderr while fn executes.
-// It respects ctx cancellation and cleans up on return.
+// It respects ctx cancellation and cleans up on return. When stderr is not
+// a terminal, or one that cannot redraw a line, it prints message once.
 
 var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
 
-func (p *Pipeline) spin(ctx context.Context, message string, fn func(context.Context) (string, error)) (string, error) {
+func spin(ctx context.Context, message string, fn func(context.Context) (string, error)) (string, error) {
 	type result struct {
 		msg string
 		err error
@@ -332,23 +1431,34 @@ func (p *Pipeline) spin(ctx context.Context, message string, fn func(context.Con
 	done := make(chan result, 1)
 
 	go func() {
+		defer restoreOnPanic()
 		msg, err := fn(ctx)
 		done <- result{msg, err}
 	}()
 
-	ticker := time.NewTicker(80 * time.Millisecond)
+	if !stderrIsTerminal() || dumbTerminal() != "" {
+		fmt.Fprintln(os.Stderr, message)
+		select {
+		case res := <-done:
+			return res.msg, res.err
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+
+	ticker := time.NewTicker(display.spinnerInterval)
 	defer ticker.Stop()
+	defer clearSpinner()
 
 	i := 0
 	for {
 		select {
 		case res := <-done:
-			fmt.Fprint(os.Stderr, "\r\033[K") // clear spinner line
 			return res.msg, res.err
 		case <-ctx.Done():
-			fmt.Fprint(os.Stderr, "\r\033[K")
 			return "", ctx.Err()
 		case <-ticker.C:
+			terminal.spinner.Store(true)
 			fmt.Fprintf(os.Stderr, "\r%s %s", spinnerFrames[i%len(spinnerFrames)], message)
 			i++
 		}
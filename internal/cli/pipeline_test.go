@@ -0,0 +1,463 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/razobeckett/goco/internal/ai"
+	"github.com/razobeckett/goco/internal/config"
+	"github.com/razobeckett/goco/internal/git"
+	"github.com/razobeckett/goco/internal/policy"
+)
+
+func TestMinimizeConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.Prompt
+		want git.Minimize
+	}{
+		{name: "defaults", want: git.DefaultMinimize},
+		{
+			name: "both set",
+			cfg:  config.Prompt{MinimizeContext: 3, MinimizeAddedLines: 100},
+			want: git.Minimize{Context: 3, AddedRun: 100},
+		},
+		{
+			name: "only added lines",
+			cfg:  config.Prompt{MinimizeAddedLines: 10},
+			want: git.Minimize{Context: git.DefaultMinimize.Context, AddedRun: 10},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := minimizeConfig(tt.cfg); got != tt.want {
+				t.Fatalf("minimizeConfig() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDraftFastPathOffline(t *testing.T) {
+	rename := "diff --git a/old.go b/new.go\nsimilarity index 100%\nrename from old.go\nrename to new.go\n"
+	edit := "diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -1 +1 @@\n-a\n+b\n"
+	offline := true
+
+	tests := []struct {
+		name    string
+		diff    string
+		want    string
+		wantErr string
+	}{
+		{name: "trivial change", diff: rename, want: "refactor: rename old.go to new.go"},
+		{name: "needs the provider", diff: edit, wantErr: "--offline"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPipeline(dependencies{offline: &offline}, &generateOptions{})
+			p.fastPath = true
+			p.diff = tt.diff
+			p.status = &git.Status{}
+
+			err := p.draft(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.commitMsg != tt.want {
+				t.Fatalf("commitMsg = %q, want %q", p.commitMsg, tt.want)
+			}
+			if p.provider != nil {
+				t.Fatal("expected no provider for a local message")
+			}
+		})
+	}
+}
+
+// scriptedProvider returns replies in order, repeating the last one.
+type scriptedProvider struct {
+	stubProvider
+	replies []scriptedReply
+	calls   int
+}
+
+type scriptedReply struct {
+	msg   string
+	err   error
+	usage *ai.Usage
+}
+
+func (s *scriptedProvider) GenerateCommitMessage(ctx context.Context, _ ai.PromptInput) (string, error) {
+	r := s.replies[min(s.calls, len(s.replies)-1)]
+	s.calls++
+	if r.usage != nil {
+		ai.RecordUsage(ctx, *r.usage)
+	}
+	return r.msg, r.err
+}
+
+// scriptedPrompter answers prompts without a terminal.
+type scriptedPrompter struct {
+	confirmed  bool
+	confirmErr error
+	edited     string
+	texts      []string
+	// chosen answers choose; nil keeps every option.
+	chosen []string
+	// picked answers pick in turn; once it runs out, pick keeps the value.
+	picked []string
+	asked  []string
+}
+
+func (s *scriptedPrompter) confirm(title string) (bool, error) {
+	s.asked = append(s.asked, title)
+	return s.confirmed, s.confirmErr
+}
+
+func (s *scriptedPrompter) edit(string) (string, error) {
+	s.asked = append(s.asked, "edit")
+	return s.edited, nil
+}
+
+func (s *scriptedPrompter) choose(title string, options []string) ([]string, error) {
+	s.asked = append(s.asked, title)
+	if s.chosen == nil {
+		return options, nil
+	}
+	return s.chosen, nil
+}
+
+func (s *scriptedPrompter) pick(title string, _ []string, value string) (string, error) {
+	s.asked = append(s.asked, title)
+	if len(s.picked) == 0 {
+		return value, nil
+	}
+	answer := s.picked[0]
+	s.picked = s.picked[1:]
+	return answer, nil
+}
+
+func (s *scriptedPrompter) text(title, _, _, _ string) (string, error) {
+	s.asked = append(s.asked, title)
+	if len(s.texts) == 0 {
+		return "", errors.New("no scripted answer")
+	}
+	answer := s.texts[0]
+	s.texts = s.texts[1:]
+	return answer, nil
+}
+
+type recordingCommitter struct {
+	messages  []string
+	overrides git.CommitOverrides
+	err       error
+}
+
+func (c *recordingCommitter) Commit(_ context.Context, message string, _ []string, o git.CommitOverrides) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.messages = append(c.messages, message)
+	c.overrides = o
+	return nil
+}
+
+type recordingNotifier struct {
+	messages []string
+}
+
+func (n *recordingNotifier) Notify(_, message string) error {
+	n.messages = append(n.messages, message)
+	return nil
+}
+
+func TestNotifyWhenSlow(t *testing.T) {
+	tests := []struct {
+		name  string
+		after time.Duration
+		opts  generateOptions
+		err   error
+		want  string
+	}{
+		{name: "slow", after: time.Nanosecond, want: "Commit message ready for review: feat: add two"},
+		{name: "slow without review", after: time.Nanosecond, opts: generateOptions{noConfirm: true}, want: "Commit message ready: feat: add two"},
+		{name: "slow failure", after: time.Nanosecond, err: errors.New("boom"), want: "Generating the commit message failed"},
+		{name: "fast", after: time.Hour},
+		{name: "disabled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPipeline(dependencies{}, &tt.opts)
+			notifier := &recordingNotifier{}
+			p.notifier = notifier
+			p.notifyAfter = tt.after
+
+			stage := p.notifyWhenSlow(func(context.Context) error {
+				time.Sleep(time.Millisecond)
+				p.commitMsg = "feat: add two\n\nBody."
+				return tt.err
+			})
+			if err := stage(context.Background()); err != tt.err {
+				t.Fatalf("stage returned %v, want %v", err, tt.err)
+			}
+
+			var got string
+			if len(notifier.messages) > 0 {
+				got = notifier.messages[0]
+			}
+			if got != tt.want || len(notifier.messages) > 1 {
+				t.Errorf("notified %q, want %q", notifier.messages, tt.want)
+			}
+		})
+	}
+}
+
+func TestPipelineRun(t *testing.T) {
+	scopeRule := "[Message]\nsubject_patterns = ['^\\w+\\(core\\): ']\n"
+	qualityRule := "[Quality]\nthreshold = 40\n"
+
+	tests := []struct {
+		name      string
+		config    string
+		opts      generateOptions
+		replies   []scriptedReply
+		prompter  scriptedPrompter
+		commitErr error
+
+		wantCommit string
+		wantAuthor string
+		wantCalls  int
+		wantErr    string
+	}{
+		{
+			name:       "accept",
+			replies:    []scriptedReply{{msg: "feat: add two"}},
+			prompter:   scriptedPrompter{confirmed: true},
+			wantCommit: "feat: add two",
+			wantCalls:  1,
+		},
+		{
+			name:      "abort",
+			replies:   []scriptedReply{{msg: "feat: add two"}},
+			wantCalls: 1,
+		},
+		{
+			name:      "confirmation fails",
+			replies:   []scriptedReply{{msg: "feat: add two"}},
+			prompter:  scriptedPrompter{confirmErr: errors.New("no terminal")},
+			wantCalls: 1,
+			wantErr:   "review: no terminal",
+		},
+		{
+			name:       "no confirmation",
+			opts:       generateOptions{noConfirm: true},
+			replies:    []scriptedReply{{msg: "feat: add two"}},
+			prompter:   scriptedPrompter{confirmErr: errors.New("should not ask")},
+			wantCommit: "feat: add two",
+			wantCalls:  1,
+		},
+		{
+			name:       "edit",
+			opts:       generateOptions{edit: true},
+			replies:    []scriptedReply{{msg: "feat: add two"}},
+			prompter:   scriptedPrompter{confirmed: true, edited: "fix: correct the second line"},
+			wantCommit: "fix: correct the second line",
+			wantCalls:  1,
+		},
+		{
+			name:      "edit to an invalid message",
+			opts:      generateOptions{edit: true},
+			replies:   []scriptedReply{{msg: "feat: add two"}},
+			prompter:  scriptedPrompter{confirmed: true, edited: "corrected the second line"},
+			wantCalls: 1,
+			wantErr:   "edited message is invalid",
+		},
+		{
+			name:       "regenerate for a missing scope",
+			config:     scopeRule,
+			replies:    []scriptedReply{{msg: "feat: add two"}, {msg: "feat(core): add two"}},
+			prompter:   scriptedPrompter{confirmed: true},
+			wantCommit: "feat(core): add two",
+			wantCalls:  2,
+		},
+		{
+			name:       "ask once regenerating fails",
+			config:     scopeRule,
+			replies:    []scriptedReply{{msg: "feat: add two"}},
+			prompter:   scriptedPrompter{confirmed: true, texts: []string{"feat(core): add two"}},
+			wantCommit: "feat(core): add two",
+			wantCalls:  1 + ruleRegenerations,
+		},
+		{
+			name:       "regenerate a vague message",
+			config:     qualityRule,
+			replies:    []scriptedReply{{msg: "feat: stuff"}, {msg: "feat: add a second line to a.txt"}},
+			prompter:   scriptedPrompter{confirmed: true},
+			wantCommit: "feat: add a second line to a.txt",
+			wantCalls:  2,
+		},
+		{
+			name:       "keep the better message",
+			config:     qualityRule,
+			replies:    []scriptedReply{{msg: "feat: stuff"}, {msg: "wip"}},
+			prompter:   scriptedPrompter{confirmed: true},
+			wantCommit: "feat: stuff",
+			wantCalls:  2,
+		},
+		{
+			name:       "quality threshold off by default",
+			replies:    []scriptedReply{{msg: "feat: stuff"}},
+			prompter:   scriptedPrompter{confirmed: true},
+			wantCommit: "feat: stuff",
+			wantCalls:  1,
+		},
+		{
+			name:       "two-pass",
+			opts:       generateOptions{twoPass: true},
+			replies:    []scriptedReply{{msg: "- a.txt: add a second line"}, {msg: "feat: add a second line to a.txt"}},
+			prompter:   scriptedPrompter{confirmed: true},
+			wantCommit: "feat: add a second line to a.txt",
+			wantCalls:  2,
+		},
+		{
+			name:       "refine",
+			opts:       generateOptions{refine: true},
+			replies:    []scriptedReply{{msg: "feat: add two"}, {msg: "Critique:\n- the diff adds a line to a.txt\n\nMessage:\nfeat: add a second line to a.txt"}},
+			prompter:   scriptedPrompter{confirmed: true},
+			wantCommit: "feat: add a second line to a.txt",
+			wantCalls:  2,
+		},
+		{
+			name:       "unreadable refinement",
+			opts:       generateOptions{refine: true},
+			replies:    []scriptedReply{{msg: "feat: add two"}, {msg: "Looks good."}},
+			prompter:   scriptedPrompter{confirmed: true},
+			wantCommit: "feat: add two",
+			wantCalls:  2,
+		},
+		{
+			name:       "retry a transient error",
+			replies:    []scriptedReply{{err: errors.New("503 service unavailable")}, {msg: "feat: add two"}},
+			prompter:   scriptedPrompter{confirmed: true},
+			wantCommit: "feat: add two",
+			wantCalls:  2,
+		},
+		{
+			name:      "provider error",
+			replies:   []scriptedReply{{err: errors.New("invalid API key")}},
+			wantCalls: 1,
+			wantErr:   "invalid API key",
+		},
+		{
+			name:      "empty message",
+			replies:   []scriptedReply{{msg: "  \n"}},
+			wantCalls: 1,
+			wantErr:   "empty commit message",
+		},
+		{
+			name:       "author and date",
+			opts:       generateOptions{author: "Imported <imported@example.com>", date: "2019-06-01 09:30"},
+			replies:    []scriptedReply{{msg: "feat: add two"}},
+			prompter:   scriptedPrompter{confirmed: true},
+			wantCommit: "feat: add two",
+			wantAuthor: "Imported <imported@example.com>",
+			wantCalls:  1,
+		},
+		{
+			name:    "unreadable date",
+			opts:    generateOptions{date: "last tuesday"},
+			wantErr: `--date: date "last tuesday" is not ISO 8601`,
+		},
+		{
+			name:      "commit fails",
+			replies:   []scriptedReply{{msg: "feat: add two"}},
+			prompter:  scriptedPrompter{confirmed: true},
+			commitErr: errors.New("hook rejected the commit"),
+			wantCalls: 1,
+			wantErr:   "apply: hook rejected the commit",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "config"))
+			t.Setenv("XDG_STATE_HOME", filepath.Join(home, "state"))
+			t.Setenv("XDG_CACHE_HOME", filepath.Join(home, "cache"))
+			t.Setenv(policy.EnvVar, "")
+			if tt.config != "" {
+				path := filepath.Join(home, "config", "goco", "config.toml")
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(tt.config), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			dir := initTestRepo(t)
+			modify(t, dir)
+
+			p := NewPipeline(dependencies{configLoader: config.NewLoader(), repo: git.NewRepository(dir)}, &tt.opts)
+			provider := &scriptedProvider{replies: tt.replies}
+			committer := &recordingCommitter{err: tt.commitErr}
+			p.provider = provider
+			p.prompter = &tt.prompter
+			p.committer = committer
+			p.retryDelay = 0
+
+			err := p.Run(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if provider.calls != tt.wantCalls {
+				t.Errorf("provider called %d times, want %d", provider.calls, tt.wantCalls)
+			}
+			var got string
+			if len(committer.messages) > 0 {
+				got = committer.messages[0]
+			}
+			if got != tt.wantCommit {
+				t.Errorf("committed %q, want %q", got, tt.wantCommit)
+			}
+			if committer.overrides.Author != tt.wantAuthor {
+				t.Errorf("committed as %q, want %q", committer.overrides.Author, tt.wantAuthor)
+			}
+		})
+	}
+}
+
+func FuzzRepairMessage(f *testing.F) {
+	f.Add("feat: added retries. The client now retries transient failures with backoff so flaky networks stop failing\n\n* one\n* two\n\nrefs: ABC-1")
+	f.Add("```\nfix(ui)!: adds dark mode\n```")
+	f.Add("Here is the commit message: chore: bump deps")
+	f.Add("")
+	p := &Pipeline{imperative: true, bodyFormat: ai.BodyFormat{Bullet: "-"}}
+	f.Fuzz(func(t *testing.T, msg string) {
+		repaired := p.repairMessage(msg)
+		if again := p.repairMessage(repaired); again != repaired {
+			t.Fatalf("repairMessage is not idempotent for %q:\n%q\n%q", msg, repaired, again)
+		}
+		if lintCommitMessage(repaired) != nil {
+			return
+		}
+		subject, _, _ := strings.Cut(repaired, "\n")
+		if len(subject) > ai.MaxSubjectLength || !conventionalCommitRegex.MatchString(subject) {
+			t.Fatalf("lintCommitMessage accepted subject %q", subject)
+		}
+	})
+}
@@ -0,0 +1,251 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/razobeckett/goco/internal/ai"
+	"github.com/razobeckett/goco/internal/forge"
+	"github.com/razobeckett/goco/internal/git"
+	"github.com/spf13/cobra"
+)
+
+type prOptions struct {
+	generate  *generateOptions
+	remote    string
+	base      string
+	printOnly bool
+}
+
+// prDraft carries the pull request through the pr stages.
+type prDraft struct {
+	forge forge.Forge
+	head  string
+	base  string
+	title string
+	body  string
+}
+
+func newPRCmd(deps dependencies) *cobra.Command {
+	opts := &prOptions{generate: newGenerateOptions()}
+
+	cmd := &cobra.Command{
+		Use:     "pr",
+		Short:   "Draft a pull request description and create or update it on the forge",
+		Long:    "Generate a pull request title and description from the commits on the current branch, then create the pull request, or update the open one, on GitHub, GitLab, Bitbucket, or Azure DevOps. The forge is picked from the remote URL and authenticated with an API token from the environment.",
+		GroupID: "main",
+		Args:    cobra.NoArgs,
+		Example: "  goco pr\n  goco pr --base develop --remote upstream\n  goco pr --print",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runPR(cmd.Context(), deps, opts)
+		},
+	}
+
+	fs := cmd.Flags()
+	fs.StringVar(&opts.remote, "remote", "origin", "Remote that hosts the pull request")
+	fs.StringVar(&opts.base, "base", "", "Target branch (defaults to the remote's default branch)")
+	fs.BoolVar(&opts.printOnly, "print", false, "Print the draft instead of publishing it")
+	fs.StringVarP(&opts.generate.provider, "provider", "p", "", providerUsage("AI provider to use"))
+	fs.StringVarP(&opts.generate.apiKey, "api-key", "k", "", "API key for the selected provider")
+	fs.StringVarP(&opts.generate.model, "model", "m", "", "Model to use (defaults to the provider's recommended model)")
+	fs.StringVarP(&opts.generate.customInstructions, "custom-instructions", "c", "", "Additional instructions to add to the AI prompt")
+	fs.BoolVarP(&opts.generate.edit, "edit", "e", false, "Open the draft in your editor before publishing")
+	fs.BoolVarP(&opts.generate.noConfirm, "yes", "y", false, "Skip confirmation and publish immediately")
+	return cmd
+}
+
+func runPR(ctx context.Context, deps dependencies, opts *prOptions) error {
+	p := NewPipeline(deps, opts.generate)
+	draft := &prDraft{}
+
+	stages := []pipelineStage{{"resolve", p.resolve}}
+	if !opts.printOnly {
+		// Fail on a missing token before spending a provider call.
+		stages = append(stages, pipelineStage{"forge", func(ctx context.Context) error {
+			f, err := openForge(ctx, deps.repo, opts.remote, p.forgeHosts)
+			draft.forge = f
+			return err
+		}})
+	}
+	stages = append(stages,
+		pipelineStage{"draft", func(ctx context.Context) error { return p.draftPullRequest(ctx, opts, draft) }},
+		pipelineStage{"review", func(ctx context.Context) error { return p.reviewPullRequest(opts, draft) }},
+	)
+	if !opts.printOnly {
+		stages = append(stages, pipelineStage{"publish", func(ctx context.Context) error { return publishPullRequest(ctx, draft) }})
+	}
+
+	return p.run(ctx, "goco.pr", stages)
+}
+
+// openForge picks the forge client from the remote URL; hosts lists the
+// self-hosted forges from config.
+func openForge(ctx context.Context, repo *git.Repository, remoteName string, hosts map[string]string) (forge.Forge, error) {
+	url, err := repo.RemoteURL(ctx, remoteName)
+	if err != nil {
+		return nil, err
+	}
+	remote, err := forge.ParseRemote(url, hosts)
+	if err != nil {
+		return nil, err
+	}
+	return forge.New(remote, forge.Token(remote.Kind))
+}
+
+func (p *Pipeline) draftPullRequest(ctx context.Context, opts *prOptions, draft *prDraft) error {
+	head, err := p.deps.repo.CurrentBranch(ctx)
+	if err != nil {
+		return err
+	}
+	if head == "" {
+		return fmt.Errorf("HEAD is detached; check out the branch to open a pull request from")
+	}
+
+	base := opts.base
+	if base == "" {
+		base = p.deps.repo.DefaultBranch(ctx, opts.remote)
+	}
+	if base == head {
+		return fmt.Errorf("branch %q is the target branch; create a feature branch first", head)
+	}
+	upstream := opts.remote + "/" + base
+
+	commits, err := p.deps.repo.Series(ctx, upstream+"..HEAD")
+	if err != nil {
+		return err
+	}
+	if len(commits) == 0 {
+		return fmt.Errorf("no commits on %s that are not on %s", head, upstream)
+	}
+	diff, err := p.deps.repo.RangeDiff(ctx, upstream+"...HEAD", false)
+	if err != nil {
+		return err
+	}
+	diff, _ = git.SummarizeLFSPointers(diff)
+
+	var log strings.Builder
+	for _, c := range commits {
+		log.WriteString(c.Subject + "\n")
+		if c.Body != "" {
+			log.WriteString(c.Body + "\n")
+		}
+		log.WriteString("\n")
+	}
+
+	input := ai.PromptInput{
+		Status:             log.String(),
+		Diff:               diff,
+		CustomInstructions: p.prInstructions(ctx, opts.remote),
+		Template:           ai.PullRequestTemplate,
+	}
+	prompt, err := p.preparePrompt(ctx, input)
+	if err != nil {
+		return err
+	}
+	text, err := p.send(ctx, input, prompt, "Drafting pull request...")
+	if err != nil {
+		return fmt.Errorf("generate pull request: %w", err)
+	}
+	if text, err = ai.CleanOutput(text); err != nil {
+		return err
+	}
+
+	draft.head, draft.base = head, base
+	draft.title, draft.body = splitTitle(text)
+	return nil
+}
+
+// prInstructions adds the repository's description template (e.g. GitLab's
+// .gitlab/merge_request_templates/Default.md) to the custom instructions.
+func (p *Pipeline) prInstructions(ctx context.Context, remoteName string) string {
+	instructions := p.opts.customInstructions
+
+	url, err := p.deps.repo.RemoteURL(ctx, remoteName)
+	if err != nil {
+		return instructions
+	}
+	remote, err := forge.ParseRemote(url, p.forgeHosts)
+	if err != nil {
+		return instructions
+	}
+	tmpl := forge.DescriptionTemplate(p.root, remote.Kind)
+	if tmpl == "" {
+		return instructions
+	}
+
+	structure := "Structure the description after this template from the repository, filling in or removing each section:\n" + tmpl
+	if instructions == "" {
+		return structure
+	}
+	return instructions + "\n" + structure
+}
+
+func (p *Pipeline) reviewPullRequest(opts *prOptions, draft *prDraft) error {
+	text := draft.title + "\n\n" + draft.body
+	if opts.printOnly && !p.opts.edit {
+		fmt.Println(text)
+		return nil
+	}
+
+	fmt.Print(p.display.block(messageBlock, fmt.Sprintf("Pull Request (%s → %s)", draft.head, draft.base), text))
+
+	if p.opts.edit {
+		edited, err := p.prompter.edit(text)
+		if err != nil {
+			return err
+		}
+		draft.title, draft.body = splitTitle(edited)
+		if opts.printOnly {
+			fmt.Println(edited)
+			return nil
+		}
+	}
+
+	if p.opts.noConfirm {
+		return nil
+	}
+	confirmed, err := p.prompter.confirm("Publish this pull request?")
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		fmt.Println(noteStyle.Render("Pull request not published."))
+		return ErrCancelled
+	}
+	return nil
+}
+
+// publishPullRequest updates the open pull request for the branch, or creates one.
+func publishPullRequest(ctx context.Context, draft *prDraft) error {
+	pr := forge.PullRequest{Title: draft.title, Body: draft.body, Head: draft.head, Base: draft.base}
+
+	existing, err := draft.forge.FindPullRequest(ctx, draft.head)
+	if err != nil {
+		return fmt.Errorf("find %s pull request: %w", draft.forge.Name(), err)
+	}
+
+	var published *forge.PullRequest
+	if existing != nil {
+		pr.Number = existing.Number
+		published, err = draft.forge.UpdatePullRequest(ctx, pr)
+	} else {
+		published, err = draft.forge.CreatePullRequest(ctx, pr)
+	}
+	if err != nil {
+		return fmt.Errorf("publish %s pull request (is %q pushed?): %w", draft.forge.Name(), draft.head, err)
+	}
+
+	verb := "Created"
+	if existing != nil {
+		verb = "Updated"
+	}
+	fmt.Println(noteStyle.Render(fmt.Sprintf("%s %s pull request #%d: %s", verb, draft.forge.Name(), published.Number, published.URL)))
+	return nil
+}
+
+// splitTitle separates the first line from the rest of a generated text.
+func splitTitle(text string) (string, string) {
+	title, body, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	return strings.TrimSpace(title), strings.TrimSpace(body)
+}
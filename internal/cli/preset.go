@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/razobeckett/goco/internal/ai"
+	"github.com/razobeckett/goco/internal/preset"
+	"github.com/spf13/cobra"
+)
+
+type presetOptions struct {
+	user bool
+}
+
+func newPresetCmd(deps dependencies) *cobra.Command {
+	opts := &presetOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "preset",
+		Short:   "Install shared commit conventions from a URL",
+		Long:    "A preset bundles a team's prompt instructions, scopes, type hints, Conventional Commits rules, and prompt template in one TOML file served over HTTPS. Installing it into a repository writes .goco/preset.toml, which can be committed so everyone shares it; --user installs it next to your config instead.",
+		GroupID: "tools",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.PersistentFlags().BoolVar(&opts.user, "user", false, "Use the preset in your config directory instead of the repository's")
+
+	cmd.AddCommand(&cobra.Command{
+		Use:     "install <url>",
+		Short:   "Fetch a preset and install it",
+		Args:    cobra.ExactArgs(1),
+		Example: "  goco preset install https://example.com/conventions/goco.toml\n  goco preset install --user https://example.com/conventions/goco.toml",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPresetInstall(cmd.Context(), deps, opts, args[0])
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "update",
+		Short: "Reinstall the preset from the URL it was installed from",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runPresetUpdate(cmd.Context(), deps, opts)
+		},
+	})
+
+	return cmd
+}
+
+func runPresetInstall(ctx context.Context, deps dependencies, opts *presetOptions, url string) error {
+	if err := deps.requireNetwork("fetching a preset"); err != nil {
+		return err
+	}
+	path, err := presetPath(ctx, deps, opts)
+	if err != nil {
+		return err
+	}
+
+	p, err := preset.Fetch(ctx, url)
+	if err != nil {
+		return err
+	}
+	if p.Template != "" {
+		if _, err := ai.ParsePromptTemplate(p.Template); err != nil {
+			return fmt.Errorf("preset %q: %w", p.Name, err)
+		}
+	}
+	if err := preset.Write(path, p); err != nil {
+		return err
+	}
+
+	fmt.Println(noteStyle.Render(fmt.Sprintf("Installed preset %q to %s", p.Name, path)))
+	return nil
+}
+
+func runPresetUpdate(ctx context.Context, deps dependencies, opts *presetOptions) error {
+	path, err := presetPath(ctx, deps, opts)
+	if err != nil {
+		return err
+	}
+	current, err := preset.Load(path)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return fmt.Errorf("no preset installed at %s; run goco preset install <url>", path)
+	}
+	if current.Source == "" {
+		return fmt.Errorf("preset %q at %s records no source URL; reinstall it with goco preset install <url>", current.Name, path)
+	}
+	return runPresetInstall(ctx, deps, opts, current.Source)
+}
+
+// presetPath returns where the preset goes: the user's config directory with
+// --user, otherwise the repository, which --read-only protects.
+func presetPath(ctx context.Context, deps dependencies, opts *presetOptions) (string, error) {
+	if opts.user {
+		return preset.UserPath(deps.configLoader.Path()), nil
+	}
+	if err := deps.requireWritable("installing a repository preset"); err != nil {
+		return "", fmt.Errorf("%w; add --user to install it for yourself", err)
+	}
+	root, err := deps.repo.Root(ctx)
+	if err != nil {
+		return "", fmt.Errorf("not in a git repository; use --user to install the preset for all repositories: %w", err)
+	}
+	return preset.RepoPath(root), nil
+}
+
+// loadPresets returns the user preset with the repository's layered on top;
+// nil when neither is installed.
+func loadPresets(configPath, root string) (*preset.Preset, error) {
+	var user, repo *preset.Preset
+	var err error
+	if configPath != "" {
+		if user, err = preset.Load(preset.UserPath(configPath)); err != nil {
+			return nil, err
+		}
+	}
+	if root != "" {
+		if repo, err = preset.Load(preset.RepoPath(root)); err != nil {
+			return nil, err
+		}
+	}
+	return preset.Merge(user, repo), nil
+}
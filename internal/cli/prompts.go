@@ -1,7 +1,13 @@
 package cli
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/help"
@@ -9,54 +15,52 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-)
-
-var (
-	promptTitleStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color(electricOrange)).
-				Bold(true)
-
-	promptDescriptionStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color(mangoVolt)).
-				Italic(true)
-
-	promptErrorStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color(lipstickRed))
+	"github.com/charmbracelet/x/term"
 )
 
 type apiKeyPromptDoneMsg struct{}
 
-type apiKeyPromptModel struct {
+// textPromptModel asks for a single non-empty line of input.
+type textPromptModel struct {
 	input       textinput.Model
 	title       string
 	description string
-	err         error
-	submitted   bool
+	// emptyErr is shown when the input is submitted blank.
+	emptyErr  string
+	err       error
+	submitted bool
 }
 
-func newAPIKeyPromptModel(providerName, envVar string) apiKeyPromptModel {
+func newTextPromptModel(title, description, value, emptyErr string) textPromptModel {
 	input := textinput.New()
 	input.Prompt = "> "
-	input.Placeholder = "Paste API key"
-	input.EchoMode = textinput.EchoPassword
-	input.EchoCharacter = '•'
+	input.SetValue(value)
 	input.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(electricOrange))
 	input.TextStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(creamGleam))
 	input.PlaceholderStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(mangoVolt))
 	input.Cursor.Style = lipgloss.NewStyle().Foreground(lipgloss.Color(electricOrange))
 
-	return apiKeyPromptModel{
-		input:       input,
-		title:       fmt.Sprintf("Enter your %s API key", providerName),
-		description: fmt.Sprintf("This sets %s for the current session only.", envVar),
-	}
+	return textPromptModel{input: input, title: title, description: description, emptyErr: emptyErr}
 }
 
-func (m apiKeyPromptModel) Init() tea.Cmd {
+func newAPIKeyPromptModel(providerName, envVar string) textPromptModel {
+	m := newTextPromptModel(
+		fmt.Sprintf("Enter your %s API key", providerName),
+		fmt.Sprintf("This sets %s for the current session only.", envVar),
+		"",
+		"API key cannot be empty",
+	)
+	m.input.Placeholder = "Paste API key"
+	m.input.EchoMode = textinput.EchoPassword
+	m.input.EchoCharacter = '•'
+	return m
+}
+
+func (m textPromptModel) Init() tea.Cmd {
 	return m.input.Focus()
 }
 
-func (m apiKeyPromptModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+func (m textPromptModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
@@ -65,7 +69,7 @@ func (m apiKeyPromptModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "enter":
 			value := strings.TrimSpace(m.input.Value())
 			if value == "" {
-				m.err = fmt.Errorf("API key cannot be empty")
+				m.err = errors.New(m.emptyErr)
 				return m, nil
 			}
 			m.submitted = true
@@ -81,7 +85,7 @@ func (m apiKeyPromptModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
-func (m apiKeyPromptModel) View() string {
+func (m textPromptModel) View() string {
 	var parts []string
 	parts = append(parts, promptTitleStyle.Render(m.title))
 	parts = append(parts, promptDescriptionStyle.Render(m.description))
@@ -93,13 +97,21 @@ func (m apiKeyPromptModel) View() string {
 }
 
 func runAPIKeyPrompt(providerName, envVar string) (string, error) {
-	program := tea.NewProgram(newAPIKeyPromptModel(providerName, envVar))
-	model, err := program.Run()
+	apiKey, err := runTextPrompt(newAPIKeyPromptModel(providerName, envVar))
+	if errors.Is(err, errNoTUI) {
+		noteLinePrompts(err)
+		return stdinPrompter.secret(fmt.Sprintf("Enter your %s API key (sets %s for this session only)", providerName, envVar), "API key cannot be empty")
+	}
+	return apiKey, err
+}
+
+func runTextPrompt(m textPromptModel) (string, error) {
+	model, err := runProgram(m)
 	if err != nil {
 		return "", err
 	}
 
-	prompt, ok := model.(apiKeyPromptModel)
+	prompt, ok := model.(textPromptModel)
 	if !ok || !prompt.submitted {
 		return "", tea.ErrProgramKilled
 	}
@@ -219,8 +231,7 @@ func (m confirmPromptModel) View() string {
 }
 
 func runConfirmPrompt(title string) (bool, error) {
-	program := tea.NewProgram(newConfirmPromptModel(title))
-	model, err := program.Run()
+	model, err := runProgram(newConfirmPromptModel(title))
 	if err != nil {
 		return false, err
 	}
@@ -232,3 +243,484 @@ func runConfirmPrompt(title string) (bool, error) {
 
 	return prompt.selected == 0, nil
 }
+
+// chooseVisible is how many options the choose prompt shows at once.
+const chooseVisible = 15
+
+// choosePromptModel asks which of a list of options to keep; all start
+// chosen.
+type choosePromptModel struct {
+	help      help.Model
+	keys      choosePromptKeyMap
+	title     string
+	options   []string
+	chosen    []bool
+	cursor    int
+	submitted bool
+}
+
+type choosePromptKeyMap struct {
+	Up     key.Binding
+	Down   key.Binding
+	Toggle key.Binding
+	All    key.Binding
+	Submit key.Binding
+}
+
+func (k choosePromptKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Toggle, k.All, k.Submit}
+}
+
+func (k choosePromptKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+func newChoosePromptModel(title string, options []string) choosePromptModel {
+	keys := choosePromptKeyMap{
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		Toggle: key.NewBinding(
+			key.WithKeys(" ", "x"),
+			key.WithHelp("space", "toggle"),
+		),
+		All: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "all/none"),
+		),
+		Submit: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("↵", "confirm"),
+		),
+	}
+
+	h := help.New()
+	h.Styles.ShortKey = promptDescriptionStyle
+	h.Styles.ShortDesc = promptDescriptionStyle
+	h.Styles.ShortSeparator = promptDescriptionStyle
+
+	chosen := make([]bool, len(options))
+	for i := range chosen {
+		chosen[i] = true
+	}
+	return choosePromptModel{title: title, options: options, chosen: chosen, keys: keys, help: h}
+}
+
+func (m choosePromptModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m choosePromptModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch {
+	case key.Matches(keyMsg, m.keys.Up):
+		m.cursor = max(m.cursor-1, 0)
+	case key.Matches(keyMsg, m.keys.Down):
+		m.cursor = min(m.cursor+1, len(m.options)-1)
+	case key.Matches(keyMsg, m.keys.Toggle):
+		m.chosen[m.cursor] = !m.chosen[m.cursor]
+	case key.Matches(keyMsg, m.keys.All):
+		// Choose all unless all are chosen, then none.
+		all := !slices.Contains(m.chosen, false)
+		for i := range m.chosen {
+			m.chosen[i] = !all
+		}
+	case key.Matches(keyMsg, m.keys.Submit):
+		m.submitted = true
+		return m, tea.Quit
+	case keyMsg.String() == "ctrl+c" || keyMsg.String() == "esc":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m choosePromptModel) View() string {
+	cursorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(electricOrange)).Bold(true)
+	optionStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(creamGleam))
+
+	count := 0
+	for _, c := range m.chosen {
+		if c {
+			count++
+		}
+	}
+	parts := []string{
+		promptTitleStyle.Render(m.title),
+		promptDescriptionStyle.Render(fmt.Sprintf("%d of %d chosen", count, len(m.options))),
+	}
+	start := max(0, min(m.cursor-chooseVisible/2, len(m.options)-chooseVisible))
+	for i := start; i < len(m.options) && i < start+chooseVisible; i++ {
+		box := "[ ]"
+		if m.chosen[i] {
+			box = "[x]"
+		}
+		line := "  " + box + " " + m.options[i]
+		if i == m.cursor {
+			line = cursorStyle.Render("> " + box + " " + m.options[i])
+		} else {
+			line = optionStyle.Render(line)
+		}
+		parts = append(parts, line)
+	}
+	parts = append(parts, m.help.ShortHelpView(m.keys.ShortHelp()))
+	return strings.Join(parts, "\n")
+}
+
+// runChoosePrompt returns the options the user kept, in order.
+func runChoosePrompt(title string, options []string) ([]string, error) {
+	model, err := runProgram(newChoosePromptModel(title, options))
+	if err != nil {
+		return nil, err
+	}
+
+	prompt, ok := model.(choosePromptModel)
+	if !ok || !prompt.submitted {
+		return nil, tea.ErrProgramKilled
+	}
+
+	var chosen []string
+	for i, option := range prompt.options {
+		if prompt.chosen[i] {
+			chosen = append(chosen, option)
+		}
+	}
+	return chosen, nil
+}
+
+// pickPromptModel asks for one of a list of options.
+type pickPromptModel struct {
+	help      help.Model
+	keys      pickPromptKeyMap
+	title     string
+	options   []string
+	cursor    int
+	submitted bool
+}
+
+type pickPromptKeyMap struct {
+	Up     key.Binding
+	Down   key.Binding
+	Submit key.Binding
+}
+
+func (k pickPromptKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Submit}
+}
+
+func (k pickPromptKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+// newPickPromptModel starts with the cursor on value when it is an option.
+func newPickPromptModel(title string, options []string, value string) pickPromptModel {
+	keys := pickPromptKeyMap{
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		Submit: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("↵", "pick"),
+		),
+	}
+
+	h := help.New()
+	h.Styles.ShortKey = promptDescriptionStyle
+	h.Styles.ShortDesc = promptDescriptionStyle
+	h.Styles.ShortSeparator = promptDescriptionStyle
+
+	return pickPromptModel{title: title, options: options, cursor: max(slices.Index(options, value), 0), keys: keys, help: h}
+}
+
+func (m pickPromptModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m pickPromptModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch {
+	case key.Matches(keyMsg, m.keys.Up):
+		m.cursor = max(m.cursor-1, 0)
+	case key.Matches(keyMsg, m.keys.Down):
+		m.cursor = min(m.cursor+1, len(m.options)-1)
+	case key.Matches(keyMsg, m.keys.Submit):
+		m.submitted = true
+		return m, tea.Quit
+	case keyMsg.String() == "ctrl+c" || keyMsg.String() == "esc":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m pickPromptModel) View() string {
+	cursorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(electricOrange)).Bold(true)
+	optionStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(creamGleam))
+
+	parts := []string{promptTitleStyle.Render(m.title)}
+	start := max(0, min(m.cursor-chooseVisible/2, len(m.options)-chooseVisible))
+	for i := start; i < len(m.options) && i < start+chooseVisible; i++ {
+		if i == m.cursor {
+			parts = append(parts, cursorStyle.Render("> "+m.options[i]))
+		} else {
+			parts = append(parts, optionStyle.Render("  "+m.options[i]))
+		}
+	}
+	parts = append(parts, m.help.ShortHelpView(m.keys.ShortHelp()))
+	return strings.Join(parts, "\n")
+}
+
+// runPickPrompt returns the option the user picked.
+func runPickPrompt(title string, options []string, value string) (string, error) {
+	model, err := runProgram(newPickPromptModel(title, options, value))
+	if err != nil {
+		return "", err
+	}
+
+	prompt, ok := model.(pickPromptModel)
+	if !ok || !prompt.submitted {
+		return "", tea.ErrProgramKilled
+	}
+	return prompt.options[prompt.cursor], nil
+}
+
+// prompter asks the user about generated text. Pipelines hold one so tests
+// can script the answers; terminalPrompter is the interactive one.
+type prompter interface {
+	confirm(title string) (bool, error)
+	edit(text string) (string, error)
+	text(title, description, value, emptyErr string) (string, error)
+	// choose returns the options the user keeps, all of them by default.
+	choose(title string, options []string) ([]string, error)
+	// pick returns the one option the user picks, value by default.
+	pick(title string, options []string, value string) (string, error)
+}
+
+// terminalPrompter asks with bubbletea prompts, falling back to line
+// prompts when the terminal cannot run them.
+type terminalPrompter struct{}
+
+func (terminalPrompter) confirm(title string) (bool, error) {
+	ok, err := runConfirmPrompt(title)
+	if errors.Is(err, errNoTUI) {
+		noteLinePrompts(err)
+		return stdinPrompter.confirm(title)
+	}
+	return ok, err
+}
+
+func (terminalPrompter) edit(text string) (string, error) { return editCommitMessage(text) }
+
+func (terminalPrompter) text(title, description, value, emptyErr string) (string, error) {
+	answer, err := runTextPrompt(newTextPromptModel(title, description, value, emptyErr))
+	if errors.Is(err, errNoTUI) {
+		noteLinePrompts(err)
+		return stdinPrompter.text(title, description, value, emptyErr)
+	}
+	return answer, err
+}
+
+func (terminalPrompter) choose(title string, options []string) ([]string, error) {
+	chosen, err := runChoosePrompt(title, options)
+	if errors.Is(err, errNoTUI) {
+		noteLinePrompts(err)
+		return stdinPrompter.choose(title, options)
+	}
+	return chosen, err
+}
+
+func (terminalPrompter) pick(title string, options []string, value string) (string, error) {
+	picked, err := runPickPrompt(title, options, value)
+	if errors.Is(err, errNoTUI) {
+		noteLinePrompts(err)
+		return stdinPrompter.pick(title, options, value)
+	}
+	return picked, err
+}
+
+// stdinPrompter reads answers from standard input.
+var stdinPrompter = &linePrompter{in: bufio.NewReader(os.Stdin), out: os.Stderr, terminal: stdinIsTerminal}
+
+// linePrompter asks on out and reads one answer per line from in, for
+// terminals that cannot run bubbletea and for piped answers.
+type linePrompter struct {
+	in  *bufio.Reader
+	out io.Writer
+	// terminal reports whether in is the terminal on stdin, whose echo
+	// secret turns off; nil means it is not.
+	terminal func() bool
+}
+
+func (l *linePrompter) confirm(title string) (bool, error) {
+	for {
+		fmt.Fprintf(l.out, "%s [Y/n] ", title)
+		answer, err := l.readLine()
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "", "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		fmt.Fprintln(l.out, "Answer y or n.")
+	}
+}
+
+func (l *linePrompter) edit(text string) (string, error) { return editCommitMessage(text) }
+
+// text asks for a non-empty line; an empty answer keeps value.
+func (l *linePrompter) text(title, description, value, emptyErr string) (string, error) {
+	fmt.Fprintln(l.out, title)
+	if description != "" {
+		fmt.Fprintln(l.out, description)
+	}
+	for {
+		if value != "" {
+			fmt.Fprintf(l.out, "[%s] ", value)
+		}
+		fmt.Fprint(l.out, "> ")
+		answer, err := l.readLine()
+		if err != nil {
+			return "", err
+		}
+		if answer == "" {
+			answer = value
+		}
+		if answer != "" {
+			return answer, nil
+		}
+		fmt.Fprintln(l.out, emptyErr)
+	}
+}
+
+// choose lists the numbered options and reads which to keep, e.g. "1-3,5";
+// an empty answer keeps them all.
+func (l *linePrompter) choose(title string, options []string) ([]string, error) {
+	fmt.Fprintln(l.out, title)
+	for i, option := range options {
+		fmt.Fprintf(l.out, "%4d. %s\n", i+1, option)
+	}
+	for {
+		fmt.Fprint(l.out, "Numbers to keep, e.g. 1-3,5 [all]: ")
+		answer, err := l.readLine()
+		if err != nil {
+			return nil, err
+		}
+		if answer == "" {
+			return options, nil
+		}
+		if indexes, ok := parseSelection(answer, len(options)); ok {
+			chosen := make([]string, len(indexes))
+			for i, index := range indexes {
+				chosen[i] = options[index]
+			}
+			return chosen, nil
+		}
+		fmt.Fprintf(l.out, "Answer with numbers from 1 to %d, e.g. 1-3,5.\n", len(options))
+	}
+}
+
+// pick lists the numbered options and reads the number of one; an empty
+// answer picks value.
+func (l *linePrompter) pick(title string, options []string, value string) (string, error) {
+	fmt.Fprintln(l.out, title)
+	for i, option := range options {
+		fmt.Fprintf(l.out, "%4d. %s\n", i+1, option)
+	}
+	for {
+		if value != "" {
+			fmt.Fprintf(l.out, "[%s] ", value)
+		}
+		fmt.Fprint(l.out, "> ")
+		answer, err := l.readLine()
+		if err != nil {
+			return "", err
+		}
+		if answer == "" && value != "" {
+			return value, nil
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(options) {
+			return options[n-1], nil
+		}
+		fmt.Fprintf(l.out, "Answer with a number from 1 to %d.\n", len(options))
+	}
+}
+
+// parseSelection reads a list of 1-based numbers and ranges, such as
+// "1-3, 5", into sorted 0-based indexes below n, and false when a number
+// is out of range or not a number.
+func parseSelection(answer string, n int) ([]int, bool) {
+	seen := make([]bool, n)
+	for _, field := range strings.FieldsFunc(answer, func(r rune) bool { return r == ',' || r == ' ' }) {
+		first, last, isRange := strings.Cut(field, "-")
+		if !isRange {
+			last = first
+		}
+		from, err1 := strconv.Atoi(first)
+		to, err2 := strconv.Atoi(last)
+		if err1 != nil || err2 != nil || from < 1 || to > n || from > to {
+			return nil, false
+		}
+		for i := from; i <= to; i++ {
+			seen[i-1] = true
+		}
+	}
+	var indexes []int
+	for i, ok := range seen {
+		if ok {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes, true
+}
+
+// secret asks for a non-empty line without echoing it.
+func (l *linePrompter) secret(title, emptyErr string) (string, error) {
+	for {
+		fmt.Fprint(l.out, title+": ")
+		var answer string
+		if l.terminal != nil && l.terminal() {
+			b, err := term.ReadPassword(os.Stdin.Fd())
+			fmt.Fprintln(l.out)
+			if err != nil {
+				return "", fmt.Errorf("read answer: %w", err)
+			}
+			answer = strings.TrimSpace(string(b))
+		} else {
+			var err error
+			if answer, err = l.readLine(); err != nil {
+				return "", err
+			}
+		}
+		if answer != "" {
+			return answer, nil
+		}
+		fmt.Fprintln(l.out, emptyErr)
+	}
+}
+
+// readLine reads one trimmed line, failing at the end of input.
+func (l *linePrompter) readLine() (string, error) {
+	line, err := l.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		fmt.Fprintln(l.out)
+		return "", fmt.Errorf("read answer: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
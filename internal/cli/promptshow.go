@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/razobeckett/goco/internal/ai"
+	"github.com/razobeckett/goco/internal/config"
+	"github.com/razobeckett/goco/internal/git"
+	"github.com/spf13/cobra"
+)
+
+func newPromptCmd(deps dependencies) *cobra.Command {
+	opts := newGenerateOptions()
+
+	cmd := &cobra.Command{
+		Use:     "prompt",
+		Short:   "Inspect the prompt goco sends to the AI provider",
+		GroupID: "inspect",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+
+	show := &cobra.Command{
+		Use:     "show",
+		Short:   "Print the prompt goco would send for the current changes, without sending it",
+		Long:    "Build the prompt goco generate would send for the current changes, with the config, template, presets, diff minimizing, and context-window fitting applied, and print it to stdout without calling any provider. Notes on what else would be sent go to stderr. When the diff is summarized in parts or outlined first, the prompts of those first requests are printed, since the message prompt is built from their replies.",
+		Args:    cobra.NoArgs,
+		Example: "  goco prompt show --staged\n  goco prompt show --minimize-diff | wc -c\n  goco prompt show --provider groq --custom-instructions \"mention the ticket\"",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runPromptShow(cmd.Context(), deps, opts)
+		},
+	}
+	fs := show.Flags()
+	fs.StringVarP(&opts.provider, "provider", "p", "", providerUsage("AI provider whose context window the prompt is fitted to"))
+	fs.StringVarP(&opts.model, "model", "m", "", "Model whose context window the prompt is fitted to")
+	fs.BoolVarP(&opts.staged, "staged", "s", false, "Use staged changes instead of the working tree diff")
+	fs.StringVarP(&opts.customInstructions, "custom-instructions", "c", "", "Additional instructions to add to the AI prompt")
+	fs.StringVar(&opts.issue, "issue", "", "Fetch this ticket (e.g. PROJ-123 or 42) and include it, as generate would")
+	fs.BoolVar(&opts.blameContext, "blame-context", false, "Include the commits that last touched the changed lines")
+	fs.BoolVar(&opts.minimizeDiff, "minimize-diff", false, "Show the trimmed diff --minimize-diff would send")
+	fs.BoolVar(&opts.fastPath, "fast-path", false, "Report when the change is trivial enough to need no prompt at all")
+	fs.BoolVar(&opts.twoPass, "two-pass", false, "Show the outline request --two-pass sends first")
+	cmd.AddCommand(show)
+
+	return cmd
+}
+
+func runPromptShow(ctx context.Context, deps dependencies, opts *generateOptions) error {
+	p := NewPipeline(deps, opts)
+	return p.run(ctx, "goco.prompt_show", []pipelineStage{
+		{"resolve", p.resolve},
+		{"inspect", p.inspect},
+		{"issue", p.loadIssue},
+		{"show", p.showPrompt},
+	})
+}
+
+// showPrompt prints the prompts generating a message would send for the
+// current diff, in order, without calling the provider. A request whose
+// prompt is built from an earlier reply is described instead.
+func (p *Pipeline) showPrompt(ctx context.Context) error {
+	if _, kind, ok := p.localMessage(); ok {
+		fmt.Fprintln(os.Stderr, noteStyle.Render(fmt.Sprintf("Nothing would be sent: goco writes the message for this %s change without the provider.", kind)))
+		return nil
+	}
+
+	diff := p.promptDiff()
+	// Summarizing in parts is the one fitting that needs the provider.
+	size := 0
+	if p.chunking.enabled && len(diff) > p.chunking.size {
+		size = p.chunking.size
+	} else if p.overflow == config.OverflowChunk {
+		budget, _, err := p.overflowBudget(diff)
+		if err != nil {
+			return err
+		}
+		size = budget
+	}
+	if parts := git.GroupSections(diff, size); size > 0 && len(parts) > 1 {
+		for i, part := range parts {
+			prompt, err := ai.BuildPrompt(partInput(i, len(parts), part))
+			if err != nil {
+				return err
+			}
+			p.printPrompt(fmt.Sprintf("Request %d of %d: summarize part %d of the diff", i+1, len(parts)+1, i+1), prompt)
+		}
+		fmt.Fprintln(os.Stderr, noteStyle.Render("The last request writes the message from the summaries these return, so its prompt cannot be shown without sending them."))
+		return nil
+	}
+
+	fitted, err := p.fitDiff(ctx, diff)
+	if err != nil {
+		return err
+	}
+	if p.twoPass {
+		prompt, err := ai.BuildPrompt(ai.PromptInput{Status: p.statusContext(), Diff: fitted, Template: ai.OutlineTemplate})
+		if err != nil {
+			return err
+		}
+		p.printPrompt("Request 1 of 2: outline the change", prompt)
+		fmt.Fprintln(os.Stderr, noteStyle.Render("The second request writes the message from this outline and the diff, so its prompt cannot be shown without sending the first."))
+		return nil
+	}
+
+	prompt, err := ai.BuildPrompt(p.promptInput(fitted))
+	if err != nil {
+		return err
+	}
+	p.printPrompt("Commit message prompt", prompt)
+	if p.refining {
+		fmt.Fprintln(os.Stderr, noteStyle.Render("With refine on, a second request sends the generated message back with the diff to check."))
+	}
+	return nil
+}
+
+// printPrompt writes prompt to stdout under a title on stderr, so the prompt
+// alone can be piped.
+func (p *Pipeline) printPrompt(title, prompt string) {
+	window, model := p.contextWindow()
+	fmt.Fprintln(os.Stderr, titleStyle.Render(title))
+	fmt.Fprintln(os.Stderr, noteStyle.Render(fmt.Sprintf("About %d tokens, for %s %s (context window %d).", estimateTokens(prompt), providerDisplayName(p.providerName), model, window)))
+	if ai.RedactSecrets(prompt) != prompt {
+		fmt.Fprintln(os.Stderr, noteStyle.Render("Warning: this prompt contains text that looks like a secret, which would be sent as it is; unstage or clean up the file first."))
+	}
+	fmt.Println(prompt)
+}
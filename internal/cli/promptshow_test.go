@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/razobeckett/goco/internal/config"
+	"github.com/razobeckett/goco/internal/git"
+)
+
+func TestShowPrompt(t *testing.T) {
+	small := "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-a\n+b\n"
+	large := "diff --git a/b.go b/b.go\n--- a/b.go\n+++ b/b.go\n@@ -0,0 +1,1000 @@\n" + strings.Repeat("+var generated = 1\n", 1000)
+
+	tests := []struct {
+		name     string
+		diff     string
+		setup    func(p *Pipeline)
+		want     []string
+		wantNone string
+	}{
+		{
+			name: "message prompt",
+			diff: small,
+			want: []string{"+b", "Generate a Conventional Commit"},
+		},
+		{
+			name: "truncated to the window",
+			diff: small + large,
+			setup: func(p *Pipeline) {
+				p.contextTokens = 3000
+			},
+			want:     []string{"[The diff was cut short; 1 more file(s) changed: b.go]"},
+			wantNone: "+var generated",
+		},
+		{
+			name: "summarized in parts",
+			diff: small + large,
+			setup: func(p *Pipeline) {
+				p.overflow = config.OverflowChunk
+				p.contextTokens = 3000
+			},
+			want: []string{"Part 1 of 2: a.go", "Part 2 of 2: b.go"},
+		},
+		{
+			name: "outlined first",
+			diff: small,
+			setup: func(p *Pipeline) {
+				p.twoPass = true
+			},
+			want: []string{"outline"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPipeline(dependencies{}, &generateOptions{})
+			p.status = &git.Status{}
+			p.overflow = config.OverflowTruncate
+			p.diff = tt.diff
+			if tt.setup != nil {
+				tt.setup(p)
+			}
+
+			out, err := captureStdout(t, func() error { return p.showPrompt(context.Background()) })
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("prompt does not contain %q:\n%s", want, out)
+				}
+			}
+			if tt.wantNone != "" && strings.Contains(out, tt.wantNone) {
+				t.Errorf("prompt contains %q", tt.wantNone)
+			}
+			if p.provider != nil {
+				t.Error("showPrompt connected to the provider")
+			}
+		})
+	}
+}
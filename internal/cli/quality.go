@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/razobeckett/goco/internal/ai"
+)
+
+// scoreQuality regenerates a provider's message once, with feedback on what
+// lowered its score, when it scores below the [Quality] threshold. The
+// better-scoring of the two messages is kept, and a failed regeneration
+// keeps the first.
+func (p *Pipeline) scoreQuality(ctx context.Context) error {
+	if p.localDraft || p.qualityThreshold <= 0 {
+		return nil
+	}
+	first := p.commitMsg
+	q := p.quality(first)
+	if q.Score() >= p.qualityThreshold {
+		return nil
+	}
+
+	fmt.Fprintln(os.Stderr, noteStyle.Render(fmt.Sprintf("Generated message scored %d/100 (below %d); regenerating with stricter instructions.", q.Score(), p.qualityThreshold)))
+	p.ruleFeedback = q.Feedback(first)
+	err := p.generate(ctx)
+	p.ruleFeedback = ""
+	switch {
+	case ctx.Err() != nil:
+		return ctx.Err()
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "warning: could not regenerate the message: %v\n", err)
+		p.commitMsg = first
+	case p.quality(p.commitMsg).Score() < q.Score():
+		p.commitMsg = first
+	}
+	return nil
+}
+
+// quality scores msg against the files the next commit includes.
+func (p *Pipeline) quality(msg string) ai.Quality {
+	return ai.ScoreMessage(msg, p.commitPaths(), p.imperative)
+}
+
+// qualityBadge labels the current message with its score.
+func (p *Pipeline) qualityBadge() string {
+	return fmt.Sprintf("quality %d/100", p.quality(p.commitMsg).Score())
+}
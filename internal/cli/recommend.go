@@ -0,0 +1,269 @@
+package cli
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/razobeckett/goco/internal/ai"
+	"github.com/razobeckett/goco/internal/audit"
+	"github.com/razobeckett/goco/internal/config"
+	"github.com/razobeckett/goco/internal/policy"
+	"github.com/spf13/cobra"
+)
+
+// recommendHistory is how many recent commits goco recommend measures.
+const recommendHistory = 50
+
+// largeDiffTokens is the typical diff size above which goco recommend
+// passes over small models, which tend to miss details in big changes.
+const largeDiffTokens = 4000
+
+type recommendOptions struct {
+	runs      int
+	skipBench bool
+	save      bool
+}
+
+// recommendCandidate is a model goco recommend considers.
+type recommendCandidate struct {
+	provider string
+	model    string
+	// small models answer fastest but describe large diffs less well.
+	small bool
+}
+
+var recommendCandidates = []recommendCandidate{
+	{provider: ai.ProviderGemini, model: "gemini-2.5-flash-lite", small: true},
+	{provider: ai.ProviderGemini, model: ai.DefaultGeminiModel},
+	{provider: ai.ProviderGemini, model: "gemini-2.5-pro"},
+	{provider: ai.ProviderGroq, model: "llama-3.1-8b-instant", small: true},
+	{provider: ai.ProviderGroq, model: ai.DefaultGroqModel},
+	{provider: ai.ProviderOpenRouter, model: ai.DefaultOpenRouterModel},
+	{provider: ai.ProviderMistral, model: ai.DefaultMistralModel},
+	{provider: ai.ProviderGrok, model: ai.DefaultGrokModel},
+	{provider: ai.ProviderDeepSeek, model: ai.DefaultDeepSeekModel},
+	{provider: ai.ProviderCohere, model: ai.DefaultCohereModel},
+	{provider: ai.ProviderGitHub, model: ai.DefaultGitHubModel},
+	{provider: ai.ProviderTogether, model: ai.DefaultTogetherModel},
+}
+
+// recommendChoice is a candidate with what goco learned about it.
+type recommendChoice struct {
+	recommendCandidate
+	window int
+	// bench is nil when the model was not benchmarked.
+	bench *benchResult
+	// skip says why the model cannot be recommended, or is "".
+	skip string
+}
+
+// diffProfile summarizes the repository's recent diffs in estimated prompt
+// tokens.
+type diffProfile struct {
+	commits int
+	median  int
+	p90     int
+}
+
+func newRecommendCmd(deps dependencies) *cobra.Command {
+	opts := &recommendOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "recommend",
+		Short:   "Suggest a provider and model for this repository",
+		Long:    "Measure the repository's recent diffs, check which providers have API keys, and benchmark the models that fit to suggest a provider and model. With --save, write the suggestion to the config file as a [[Route]] for this repository.",
+		GroupID: "tools",
+		Args:    cobra.NoArgs,
+		Example: "  goco recommend\n  goco recommend --runs 3 --save\n  goco recommend --skip-bench",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runRecommend(cmd, deps, opts)
+		},
+	}
+
+	cmd.Flags().IntVarP(&opts.runs, "runs", "n", 1, "Number of benchmark generations per model")
+	cmd.Flags().BoolVar(&opts.skipBench, "skip-bench", false, "Recommend from diff sizes and keys alone, without calling any provider")
+	cmd.Flags().BoolVar(&opts.save, "save", false, "Save the recommendation as a [[Route]] for this repository")
+	return cmd
+}
+
+func runRecommend(cmd *cobra.Command, deps dependencies, opts *recommendOptions) error {
+	ctx := cmd.Context()
+	if opts.runs < 1 {
+		return fmt.Errorf("--runs must be at least 1")
+	}
+
+	cfg, err := loadConfig(ctx, deps)
+	if err != nil {
+		return err
+	}
+	root, rootErr := deps.repo.Root(ctx)
+	if opts.save && rootErr != nil {
+		return fmt.Errorf("--save routes this repository to the recommendation: %w", rootErr)
+	}
+
+	var sizes []int
+	if rootErr == nil {
+		if sizes, err = deps.repo.DiffSizes(ctx, recommendHistory); err != nil {
+			return err
+		}
+	}
+	profile := profileDiffs(sizes)
+
+	pol, err := policy.Load()
+	if err != nil {
+		return err
+	}
+	choices := recommendChoices(cfg, pol, profile)
+
+	bench := !opts.skipBench
+	if bench && deps.isOffline() {
+		fmt.Fprintln(os.Stderr, noteStyle.Render("Skipping benchmarks: --offline disables network access."))
+		bench = false
+	}
+	if bench {
+		auditLog := audit.Open(cfg.AuditPath(audit.DefaultPath()))
+		input := ai.PromptInput{Status: syntheticStatus, Diff: syntheticDiff}
+		for i := range choices {
+			c := &choices[i]
+			if c.skip != "" {
+				continue
+			}
+			result, err := benchModel(ctx, providerOptions(cfg), c.provider, providerAPIKey(cfg, c.provider), c.model, input, opts.runs, auditLog, root)
+			if err != nil {
+				return err
+			}
+			c.bench = &result
+			if result.runs == 0 {
+				c.skip = "every benchmark run failed"
+			}
+		}
+	}
+
+	printRecommendChoices(profile, choices)
+	best, reason, ok := pickRecommendation(profile, choices)
+	if !ok {
+		return fmt.Errorf("no model can be recommended; see the notes above")
+	}
+	fmt.Println()
+	fmt.Println(modelProviderStyle.Render(fmt.Sprintf("Recommended: %s %s", providerDisplayName(best.provider), best.model)))
+	fmt.Println(noteStyle.Render(reason + "."))
+
+	if !opts.save {
+		if rootErr == nil {
+			fmt.Println(noteStyle.Render("Run goco recommend --save to use it in this repository."))
+		}
+		return nil
+	}
+	route := config.Route{Path: root, Provider: best.provider, Model: best.model}
+	if err := deps.configLoader.SetRoute(route); err != nil {
+		return err
+	}
+	fmt.Println(noteStyle.Render(fmt.Sprintf("Saved a [[Route]] for %s to %s.", root, deps.configLoader.Path())))
+	return nil
+}
+
+// profileDiffs estimates prompt tokens from patch sizes in bytes, at four
+// characters per token.
+func profileDiffs(sizes []int) diffProfile {
+	if len(sizes) == 0 {
+		return diffProfile{}
+	}
+	tokens := make([]int, len(sizes))
+	for i, size := range sizes {
+		tokens[i] = (size + 3) / 4
+	}
+	slices.Sort(tokens)
+	return diffProfile{commits: len(tokens), median: tokens[len(tokens)/2], p90: tokens[len(tokens)*9/10]}
+}
+
+// recommendChoices lists the candidates, the configured provider's first,
+// noting why any cannot be used.
+func recommendChoices(cfg *config.Config, pol *policy.Policy, profile diffProfile) []recommendChoice {
+	choices := make([]recommendChoice, 0, len(recommendCandidates))
+	for _, c := range recommendCandidates {
+		choice := recommendChoice{recommendCandidate: c, window: ai.ContextWindow(c.provider, c.model)}
+		switch {
+		case ai.NeedsAPIKey(c.provider, providerOptions(cfg)) && providerAPIKey(cfg, c.provider) == "":
+			choice.skip = fmt.Sprintf("no API key; set %s", cfg.APIKeyEnv(c.provider))
+		case pol.Check(policyRequest(providerOptions(cfg), c.provider, c.model)) != nil:
+			choice.skip = "not allowed by policy"
+		case profile.p90 > choice.window-ai.ReplyTokens:
+			choice.skip = "too small for your larger diffs"
+		}
+		choices = append(choices, choice)
+	}
+	preferred := cfg.DefaultProviderName()
+	slices.SortStableFunc(choices, func(a, b recommendChoice) int {
+		return cmp.Compare(boolRank(a.provider != preferred), boolRank(b.provider != preferred))
+	})
+	return choices
+}
+
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// pickRecommendation returns the choice to recommend and why, or false when
+// none can be used. Large typical diffs rule out small models when another
+// fits. Benchmarked choices are ranked by latency; otherwise small diffs get
+// the first small model and large ones the first other.
+func pickRecommendation(profile diffProfile, choices []recommendChoice) (recommendChoice, string, bool) {
+	usable := slices.DeleteFunc(slices.Clone(choices), func(c recommendChoice) bool { return c.skip != "" })
+	if len(usable) == 0 {
+		return recommendChoice{}, "", false
+	}
+	large := profile.median > largeDiffTokens
+	if large && slices.ContainsFunc(usable, func(c recommendChoice) bool { return !c.small }) {
+		usable = slices.DeleteFunc(usable, func(c recommendChoice) bool { return c.small })
+	}
+
+	fits := "your diffs"
+	if large {
+		fits = "your large diffs"
+	}
+	if usable[0].bench != nil {
+		best := slices.MinFunc(usable, func(a, b recommendChoice) int { return cmp.Compare(a.bench.avg(), b.bench.avg()) })
+		return best, fmt.Sprintf("Fastest of the %d models with a key that suit %s", len(usable), fits), true
+	}
+	if i := slices.IndexFunc(usable, func(c recommendChoice) bool { return c.small != large }); i >= 0 {
+		if large {
+			return usable[i], "Suits " + fits + "; benchmark to compare latency", true
+		}
+		return usable[i], "Small and quick, which suits " + fits + "; benchmark to compare latency", true
+	}
+	return usable[0], "The closest usable match for " + fits + "; benchmark to compare latency", true
+}
+
+func printRecommendChoices(profile diffProfile, choices []recommendChoice) {
+	if profile.commits == 0 {
+		fmt.Println(noteStyle.Render("No commit history to measure; assuming small diffs."))
+	} else {
+		fmt.Println(noteStyle.Render(fmt.Sprintf(
+			"Your last %d commits: about %d prompt tokens of diff typically, %d at the 90th percentile.",
+			profile.commits, profile.median, profile.p90,
+		)))
+	}
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROVIDER\tMODEL\tWINDOW\tAVG\tNOTE")
+	for _, c := range choices {
+		avg := "-"
+		if c.bench != nil && c.bench.runs > 0 {
+			avg = c.bench.avg().Round(time.Millisecond).String()
+		}
+		note := c.skip
+		if note == "" && c.small {
+			note = "small"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%dk\t%s\t%s\n", c.provider, c.model, c.window>>10, avg, note)
+	}
+	w.Flush()
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProfileDiffs(t *testing.T) {
+	if got := profileDiffs(nil); got != (diffProfile{}) {
+		t.Fatalf("profileDiffs(nil) = %+v, want zero", got)
+	}
+	sizes := []int{4000, 40, 400, 0, 40000, 400, 400, 4, 400, 400}
+	want := diffProfile{commits: 10, median: 100, p90: 10000}
+	if got := profileDiffs(sizes); got != want {
+		t.Fatalf("profileDiffs() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPickRecommendation(t *testing.T) {
+	choice := func(provider, model string, small bool, avg time.Duration, skip string) recommendChoice {
+		c := recommendChoice{recommendCandidate: recommendCandidate{provider: provider, model: model, small: small}, skip: skip}
+		if avg > 0 {
+			c.bench = &benchResult{runs: 1, total: avg}
+		}
+		return c
+	}
+	small, large := diffProfile{commits: 5, median: 300, p90: 900}, diffProfile{commits: 5, median: 9000, p90: 30000}
+
+	tests := []struct {
+		name       string
+		profile    diffProfile
+		choices    []recommendChoice
+		wantModel  string
+		wantReason string
+	}{
+		{
+			name:    "nothing usable",
+			profile: small,
+			choices: []recommendChoice{choice("groq", "llama-3.1-8b-instant", true, 0, "no API key; set GROQ_API_KEY")},
+		},
+		{
+			name:    "fastest benchmarked",
+			profile: small,
+			choices: []recommendChoice{
+				choice("gemini", "gemini-2.5-flash", false, 2*time.Second, ""),
+				choice("groq", "llama-3.1-8b-instant", true, 300*time.Millisecond, ""),
+				choice("groq", "llama-3.3-70b-versatile", false, 0, "every benchmark run failed"),
+			},
+			wantModel:  "llama-3.1-8b-instant",
+			wantReason: "Fastest of the 2 models",
+		},
+		{
+			name:    "large diffs pass over small models",
+			profile: large,
+			choices: []recommendChoice{
+				choice("groq", "llama-3.1-8b-instant", true, 300*time.Millisecond, ""),
+				choice("gemini", "gemini-2.5-flash", false, 2*time.Second, ""),
+				choice("gemini", "gemini-2.5-pro", false, 6*time.Second, ""),
+			},
+			wantModel:  "gemini-2.5-flash",
+			wantReason: "your large diffs",
+		},
+		{
+			name:    "large diffs with only small models",
+			profile: large,
+			choices: []recommendChoice{
+				choice("groq", "llama-3.1-8b-instant", true, 0, ""),
+				choice("gemini", "gemini-2.5-flash", false, 0, "not allowed by policy"),
+			},
+			wantModel:  "llama-3.1-8b-instant",
+			wantReason: "closest usable match",
+		},
+		{
+			name:    "small diffs without benchmarks",
+			profile: small,
+			choices: []recommendChoice{
+				choice("gemini", "gemini-2.5-flash", false, 0, ""),
+				choice("gemini", "gemini-2.5-flash-lite", true, 0, ""),
+			},
+			wantModel:  "gemini-2.5-flash-lite",
+			wantReason: "Small and quick",
+		},
+		{
+			name:    "large diffs without benchmarks",
+			profile: large,
+			choices: []recommendChoice{
+				choice("gemini", "gemini-2.5-flash-lite", true, 0, ""),
+				choice("gemini", "gemini-2.5-flash", false, 0, ""),
+			},
+			wantModel:  "gemini-2.5-flash",
+			wantReason: "benchmark to compare latency",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason, ok := pickRecommendation(tt.profile, tt.choices)
+			if !ok {
+				if tt.wantModel != "" {
+					t.Fatalf("expected %s to be recommended", tt.wantModel)
+				}
+				return
+			}
+			if got.model != tt.wantModel || !strings.Contains(reason, tt.wantReason) {
+				t.Fatalf("recommended %s (%q), want %s (%q)", got.model, reason, tt.wantModel, tt.wantReason)
+			}
+		})
+	}
+}
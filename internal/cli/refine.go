@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/razobeckett/goco/internal/ai"
+)
+
+// refine sends the message back to the model with the diff it describes,
+// asking it to critique the message and correct what the diff does not
+// support. A refinement that fails or cannot be read keeps the message.
+func (p *Pipeline) refine(ctx context.Context) error {
+	if !p.refining {
+		return nil
+	}
+	diff, err := p.messageDiff(ctx)
+	if err != nil {
+		return err
+	}
+
+	input := ai.PromptInput{
+		Status:             p.commitMsg,
+		Diff:               diff,
+		CustomInstructions: p.instructions(),
+		Spec:               p.spec,
+		Template:           ai.RefineTemplate,
+	}
+	prompt, err := p.preparePrompt(ctx, input)
+	if err != nil {
+		return err
+	}
+	reply, err := p.send(ctx, input, prompt, "Checking the message against the diff...")
+	if err == nil {
+		reply, err = ai.CleanOutput(reply)
+	}
+	var critique, msg string
+	if err == nil {
+		critique, msg, err = ai.ParseRefinement(reply)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		fmt.Fprintf(os.Stderr, "warning: could not refine the message: %v\n", err)
+		return nil
+	}
+
+	p.debug("Critique", critique)
+	p.commitMsg = p.withTrailers(p.repairMessage(msg))
+	return nil
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// renderer formats the titled blocks goco shows: commit messages, git
+// status, and diffs. Styled output draws a header and a box sized to the
+// terminal. Plain output, used when stdout is not a terminal or NO_COLOR is
+// set or the terminal is dumb, prints the content verbatim under a one-line heading so scripts and
+// logs get the message exactly as written.
+type renderer struct {
+	plain bool
+	width int
+}
+
+func newRenderer() renderer {
+	return renderer{plain: !stdoutIsTerminal() || os.Getenv("NO_COLOR") != "" || dumbTerminal() != "", width: terminalWidth()}
+}
+
+func stdoutIsTerminal() bool {
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func stderrIsTerminal() bool {
+	info, err := os.Stderr.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// blockKind picks the header and box styles of a block.
+type blockKind int
+
+const (
+	messageBlock blockKind = iota
+	statusBlock
+	diffBlock
+)
+
+func (k blockKind) styles() (header, box lipgloss.Style) {
+	switch k {
+	case statusBlock:
+		return statusHeaderStyle, statusBoxStyle
+	case diffBlock:
+		return diffHeaderStyle, diffBoxStyle
+	default:
+		return commitMessageHeaderStyle, commitMessageBoxStyle
+	}
+}
+
+// block renders content under title, ending in a newline.
+func (r renderer) block(kind blockKind, title, content string) string {
+	if r.plain {
+		content = strings.TrimRight(content, "\n")
+		return title + ":\n" + content + "\n\n"
+	}
+	header, box := kind.styles()
+	if theme.Emoji != "" {
+		title = theme.Emoji + " " + title
+	}
+	return header.Render(title) + "\n" + box.Width(r.width).Render(content) + "\n"
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/razobeckett/goco/internal/config"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files under testdata")
+
+// golden compares got with testdata/<name>, rewriting it with -update.
+func golden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if got != string(want) {
+		t.Errorf("output differs from %s (run go test -update if the change is intended)\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+func TestRendererBlock(t *testing.T) {
+	message := "feat(api): add rate limiting to requests\n\n" +
+		"- Add a token bucket limiter in front of every handler so a single client can no longer exhaust the worker pool\n" +
+		"- Document the new limits\n\n" +
+		"Refs: #42\n"
+	status := "branch: main\nstaged: api/limit.go, README.md"
+	diff := "diff --git a/a.txt b/a.txt\n--- a/a.txt\n+++ b/a.txt\n@@ -1 +1,2 @@\n one\n+two\n"
+	// Wide characters take two cells; the box must wrap and pad them without
+	// splitting or escaping them.
+	i18n := "docs(文档): 更新安装说明 🎉\n\n- 为 Windows 用户补充 PowerShell 安装步骤，并说明如何配置 API 密钥\n- Ajoute la documentation en français\n"
+
+	tests := []struct {
+		name    string
+		kind    blockKind
+		title   string
+		content string
+	}{
+		{name: "message", kind: messageBlock, title: "Generated Commit Message", content: message},
+		{name: "status", kind: statusBlock, title: "Git Status", content: status},
+		{name: "diff", kind: diffBlock, title: "Git Diff", content: diff},
+		{name: "i18n", kind: messageBlock, title: "Generated Commit Message", content: i18n},
+	}
+	// termenv.TrueColor, so styled goldens hold the same escape codes
+	// whether or not the test's stdout is a terminal.
+	lipgloss.SetColorProfile(0)
+
+	for _, tt := range tests {
+		for _, plain := range []bool{true, false} {
+			mode := "styled"
+			if plain {
+				mode = "plain"
+			}
+			t.Run(tt.name+"/"+mode, func(t *testing.T) {
+				r := renderer{plain: plain, width: 60}
+				golden(t, filepath.Join("render", tt.name+"."+mode+".golden"), r.block(tt.kind, tt.title, tt.content))
+			})
+		}
+	}
+}
+
+func TestRendererPlainKeepsContent(t *testing.T) {
+	msg := "fix: handle\ttabs and  double spaces\n\n" + "A line far longer than any terminal is wide, which a box would wrap onto several lines and break for scripts.\n"
+	got := renderer{plain: true, width: 20}.block(messageBlock, "Message", msg)
+	if want := "Message:\n" + msg + "\n"; got != want {
+		t.Fatalf("plain block = %q, want %q", got, want)
+	}
+}
+
+func TestRendererTheme(t *testing.T) {
+	applyTheme(&config.Theme{
+		Emoji:    "🚀",
+		Colors:   config.ThemeColors{Primary: "#0055FF", Highlight: "33"},
+		Messages: config.ThemeMessages{FetchingModels: "Asking {provider} for models"},
+	})
+	t.Cleanup(func() { applyTheme(nil) })
+	lipgloss.SetColorProfile(0)
+
+	msg := "feat: rebrand the output\n"
+	golden(t, filepath.Join("render", "themed.styled.golden"), renderer{width: 40}.block(messageBlock, theme.Messages.MessageTitle, msg))
+	// Plain output is for scripts, which the emoji would only get in the way of.
+	if got, want := (renderer{plain: true}).block(messageBlock, theme.Messages.MessageTitle, msg), "Generated Commit Message:\n"+msg+"\n"; got != want {
+		t.Errorf("plain block = %q, want %q", got, want)
+	}
+	if got, want := fetchingModelsMessage("openrouter"), "Asking OpenRouter for models"; got != want {
+		t.Errorf("fetchingModelsMessage() = %q, want %q", got, want)
+	}
+	if theme.Colors.Secondary != defaultTheme.Colors.Secondary {
+		t.Errorf("unset secondary color = %q, want goco's own %q", theme.Colors.Secondary, defaultTheme.Colors.Secondary)
+	}
+}
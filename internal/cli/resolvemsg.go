@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newResolveMsgCmd(deps dependencies) *cobra.Command {
+	opts := newGenerateOptions()
+
+	cmd := &cobra.Command{
+		Use:     "resolve-msg",
+		Short:   "Generate a commit message describing a merge or rebase conflict resolution",
+		Long:    "After resolving and staging conflicts from a merge, rebase, cherry-pick, or revert, compare the resolution with both parents, summarize what was kept from each side, and generate a conflict-resolution commit message.",
+		GroupID: "main",
+		Args:    cobra.NoArgs,
+		Example: "  git merge feature/auth   # resolve conflicts, then git add\n  goco resolve-msg\n  goco resolve-msg --print > .git/MERGE_MSG",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			opts.resolveConflicts = true
+			opts.staged = true
+			return NewPipeline(deps, opts).Run(cmd.Context())
+		},
+	}
+
+	fs := cmd.Flags()
+	fs.StringVarP(&opts.provider, "provider", "p", "", providerUsage("AI provider to use"))
+	fs.StringVarP(&opts.apiKey, "api-key", "k", "", "API key for the selected provider")
+	fs.StringVarP(&opts.model, "model", "m", "", "Model to use (defaults to the provider's recommended model)")
+	fs.StringVarP(&opts.customInstructions, "custom-instructions", "c", "", "Additional instructions to add to the AI prompt")
+	fs.BoolVarP(&opts.verbose, "verbose", "V", false, "Show the resolution summary and diffs before generating")
+	fs.BoolVarP(&opts.noConfirm, "yes", "y", false, "Skip confirmation and commit immediately")
+	fs.BoolVarP(&opts.edit, "edit", "e", false, "Open the generated commit message in your editor before committing")
+	fs.BoolVar(&opts.changeID, "change-id", false, "Append a Gerrit Change-Id trailer")
+	fs.BoolVar(&opts.printOnly, "print", false, "Print the message to stdout instead of committing")
+	return cmd
+}
@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/razobeckett/goco/internal/forge"
+	"github.com/razobeckett/goco/internal/git"
+	"github.com/spf13/cobra"
+)
+
+type suggestReviewersOptions struct {
+	limit  int
+	remote string
+	base   string
+	add    bool
+}
+
+func newSuggestReviewersCmd(deps dependencies) *cobra.Command {
+	opts := &suggestReviewersOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "suggest-reviewers",
+		Short:   "Suggest reviewers from the history of the code you changed",
+		Long:    "Rank the past authors of the lines and files touched by the staged changes (or, with nothing staged, by the current branch) using git blame and git log, and optionally request their review on the branch's open pull request.",
+		GroupID: "inspect",
+		Args:    cobra.NoArgs,
+		Example: "  goco suggest-reviewers\n  goco suggest-reviewers --limit 5\n  goco suggest-reviewers --add",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runSuggestReviewers(cmd.Context(), deps, opts)
+		},
+	}
+
+	fs := cmd.Flags()
+	fs.IntVarP(&opts.limit, "limit", "n", 3, "Maximum number of reviewers to suggest")
+	fs.StringVar(&opts.remote, "remote", "origin", "Remote that hosts the pull request")
+	fs.StringVar(&opts.base, "base", "", "Target branch used when nothing is staged (defaults to the remote's default branch)")
+	fs.BoolVar(&opts.add, "add", false, "Request reviews from the suggestions on the branch's open pull request")
+	return cmd
+}
+
+func runSuggestReviewers(ctx context.Context, deps dependencies, opts *suggestReviewersOptions) error {
+	if opts.limit < 1 {
+		return fmt.Errorf("--limit must be at least 1")
+	}
+
+	diff, rev, source, err := reviewDiff(ctx, deps.repo, opts)
+	if err != nil {
+		return err
+	}
+
+	if clone := deps.repo.CloneShape(ctx); clone.Partial() {
+		fmt.Fprintln(os.Stderr, noteStyle.Render(fmt.Sprintf("Note: this is a partial clone; blame may download old file contents from %s.", clone.PartialRemote)))
+	}
+
+	reviewers, err := deps.repo.SuggestReviewers(ctx, diff, rev, deps.repo.UserEmail(ctx), opts.limit)
+	if err != nil {
+		return err
+	}
+	if len(reviewers) == 0 {
+		fmt.Println(noteStyle.Render("No past authors found for the changed code."))
+		return nil
+	}
+
+	fmt.Println(modelProviderStyle.Render(fmt.Sprintf("Suggested Reviewers (%s)", source)))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tEMAIL\tLINES\tCOMMITS")
+	for _, r := range reviewers {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\n", r.Name, r.Email, r.Lines, r.Commits)
+	}
+	w.Flush()
+
+	if !opts.add {
+		return nil
+	}
+	return addReviewers(ctx, deps, opts.remote, reviewers)
+}
+
+// reviewDiff picks the staged diff, falling back to the branch's changes
+// against the target branch. rev is the diff's old side, used for blame.
+func reviewDiff(ctx context.Context, repo *git.Repository, opts *suggestReviewersOptions) (diff, rev, source string, err error) {
+	diff, err = repo.Diff(ctx, true)
+	if err != nil {
+		return "", "", "", fmt.Errorf("read git diff: %w", err)
+	}
+	if strings.TrimSpace(diff) != "" {
+		return diff, "HEAD", "staged changes", nil
+	}
+
+	base := opts.base
+	if base == "" {
+		base = repo.DefaultBranch(ctx, opts.remote)
+	}
+	upstream := opts.remote + "/" + base
+	rev, err = repo.MergeBase(ctx, upstream, "HEAD")
+	if err != nil {
+		return "", "", "", err
+	}
+	diff, err = repo.RangeDiff(ctx, rev+"..HEAD", false)
+	if err != nil {
+		return "", "", "", err
+	}
+	if strings.TrimSpace(diff) == "" {
+		return "", "", "", fmt.Errorf("nothing staged and no commits ahead of %s", upstream)
+	}
+	return diff, rev, "branch changes since " + upstream, nil
+}
+
+func addReviewers(ctx context.Context, deps dependencies, remoteName string, reviewers []git.Reviewer) error {
+	repo := deps.repo
+	if err := deps.requireNetwork("requesting reviews"); err != nil {
+		return err
+	}
+	cfg, err := deps.configLoader.Load()
+	if err != nil {
+		return fmt.Errorf("load config %q: %w", deps.configLoader.Path(), err)
+	}
+	f, err := openForge(ctx, repo, remoteName, cfg.Forges)
+	if err != nil {
+		return err
+	}
+	head, err := repo.CurrentBranch(ctx)
+	if err != nil {
+		return err
+	}
+	pr, err := f.FindPullRequest(ctx, head)
+	if err != nil {
+		return fmt.Errorf("find %s pull request: %w", f.Name(), err)
+	}
+	if pr == nil {
+		return fmt.Errorf("no open %s pull request for %q; create one with `goco pr` first", f.Name(), head)
+	}
+
+	emails := make([]string, 0, len(reviewers))
+	for _, r := range reviewers {
+		emails = append(emails, r.Email)
+	}
+	added, err := f.AddReviewers(ctx, pr.Number, emails)
+	if errors.Is(err, forge.ErrReviewersUnsupported) {
+		return fmt.Errorf("%s: %w; add them in the web UI: %s", f.Name(), err, pr.URL)
+	}
+	if err != nil {
+		return fmt.Errorf("add reviewers: %w", err)
+	}
+	if len(added) == 0 {
+		fmt.Println(noteStyle.Render(fmt.Sprintf("None of the suggested emails match a %s account.", f.Name())))
+		return nil
+	}
+
+	fmt.Println(noteStyle.Render(fmt.Sprintf("Requested review from %s on #%d.", strings.Join(added, ", "), pr.Number)))
+	return nil
+}
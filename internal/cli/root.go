@@ -1,40 +1,206 @@
 package cli
 
 import (
+	"errors"
+	"fmt"
+	"os"
+
 	"github.com/razobeckett/goco/internal/config"
 	"github.com/razobeckett/goco/internal/git"
 	"github.com/spf13/cobra"
 )
 
+// offlineEnvVar enables --offline for every invocation, e.g. on air-gapped hosts.
+const offlineEnvVar = "GOCO_OFFLINE"
+
+// readOnlyEnvVar enables --read-only for every invocation, e.g. in a shell
+// opened on someone else's checkout.
+const readOnlyEnvVar = "GOCO_READ_ONLY"
+
 type dependencies struct {
 	configLoader *config.Loader
 	repo         *git.Repository
+	// offline is bound to the persistent --offline flag.
+	offline *bool
+	// readOnly is bound to the persistent --read-only flag.
+	readOnly *bool
+	// command is the subcommand being run, e.g. "generate", which picks
+	// its [Commands] section.
+	command *string
+	// output is bound to the persistent --output flag.
+	output *string
+}
+
+// commandName returns the subcommand being run, or "".
+func (d dependencies) commandName() string {
+	if d.command == nil {
+		return ""
+	}
+	return *d.command
+}
+
+// jsonOutput reports whether --output json is set.
+func (d dependencies) jsonOutput() bool {
+	return d.output != nil && *d.output == outputJSON
+}
+
+// isOffline reports whether --offline is set.
+func (d dependencies) isOffline() bool {
+	return d.offline != nil && *d.offline
+}
+
+// requireNetwork fails when --offline is set; what names the action that
+// would have used the network.
+func (d dependencies) requireNetwork(what string) error {
+	if d.isOffline() {
+		return fmt.Errorf("%s needs network access, which --offline disables", what)
+	}
+	return nil
+}
+
+// requireWritable fails when --read-only is set; what names the action that
+// would have changed the repository.
+func (d dependencies) requireWritable(what string) error {
+	if d.readOnly != nil && *d.readOnly {
+		return fmt.Errorf("%s changes the repository, which --read-only disables", what)
+	}
+	return nil
 }
 
 func NewRootCmd() *cobra.Command {
 	deps := dependencies{
 		configLoader: config.NewLoader(),
 		repo:         git.NewRepository(""),
+		offline:      new(bool),
+		readOnly:     new(bool),
+		command:      new(string),
+		output:       new(string),
 	}
+	var repoDir string
 
 	cmd := &cobra.Command{
 		Use:     "goco",
 		Short:   "Generate Conventional Commit messages with AI",
-		Long:    "GoCo generates Conventional Commit messages from your git changes using Gemini or Groq, with Fang-powered help, errors, completions, and manpages.",
+		Long:    "GoCo generates Conventional Commit messages from your git changes using Gemini, Groq, OpenRouter, Mistral, Azure OpenAI, AWS Bedrock, xAI Grok, DeepSeek, Cohere, GitHub Models, or Together AI, with Fang-powered help, errors, completions, and manpages.",
 		Example: "  goco\n  goco generate --provider groq --model llama-3.3-70b-versatile\n  goco generate --staged --verbose --custom-instructions \"focus on API changes\"\n  goco models --provider gemini",
 		Args:    cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			return cmd.Help()
+			return runHome(cmd)
+		},
+		PersistentPreRunE: func(c *cobra.Command, _ []string) error {
+			*deps.command = topCommand(c).Name()
+			if output := *deps.output; output != outputText && output != outputJSON {
+				return usageError{fmt.Errorf("--output %q: want %s or %s", output, outputText, outputJSON)}
+			}
+			if dir := repoDir; dir != "" {
+				dir = config.ExpandHome(dir)
+				if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+					return fmt.Errorf("--repo %s: not a directory", dir)
+				}
+				deps.repo.SetDir(dir)
+			}
+			deps.configLoader.SetOffline(*deps.offline)
+			// Only the local [Git] and [Terminal] tables are read here, so commands that never
+			// load the config do not fetch the remote one. A broken config is
+			// reported by the command that needs it.
+			if g, err := deps.configLoader.LocalGit(); err == nil {
+				args, err := g.Args()
+				if err != nil {
+					fmt.Fprintln(os.Stderr, noteStyle.Render(fmt.Sprintf("Warning: %v; leaving signing to git config.", err)))
+				}
+				deps.repo.SetGit(config.ExpandHome(g.Binary), args)
+			}
+			if t, err := deps.configLoader.LocalTerminal(); err == nil {
+				if err := configureDisplay(t); err != nil {
+					warnDisplay(err)
+					_ = configureDisplay(config.Terminal{})
+				}
+			}
+			return nil
 		},
 	}
+	cmd.PersistentFlags().StringVarP(&repoDir, "repo", "C", "", "Run as if goco was started in this repository directory, like git -C")
+	cmd.PersistentFlags().BoolVar(deps.readOnly, "read-only", os.Getenv(readOnlyEnvVar) != "", "Never stage, commit, create branches, or write files into the repository (or set "+readOnlyEnvVar+"=1)")
+	cmd.PersistentFlags().BoolVar(deps.offline, "offline", os.Getenv(offlineEnvVar) != "", "Disable all network access; remote config is read from cache only (or set "+offlineEnvVar+"=1)")
+	cmd.PersistentFlags().StringVar(deps.output, "output", outputText, "Format of errors on stderr and of the generate --print result: text, or json for tools")
 
 	cmd.AddGroup(
 		&cobra.Group{ID: "main", Title: "Main Commands"},
 		&cobra.Group{ID: "inspect", Title: "Inspect"},
+		&cobra.Group{ID: "tools", Title: "Tools"},
 	)
 
 	cmd.AddCommand(newGenerateCmd(deps))
+	cmd.AddCommand(newWatchCmd(deps))
+	cmd.AddCommand(newResolveMsgCmd(deps))
+	cmd.AddCommand(newFormatPatchCmd(deps))
+	cmd.AddCommand(newPRCmd(deps))
 	cmd.AddCommand(newModelsCmd(deps))
+	cmd.AddCommand(newStatusCmd(deps))
+	cmd.AddCommand(newSuggestReviewersCmd(deps))
+	cmd.AddCommand(newAuditCmd(deps))
+	cmd.AddCommand(newLastPromptCmd())
+	cmd.AddCommand(newPromptCmd(deps))
+	cmd.AddCommand(newStandupCmd(deps))
+	cmd.AddCommand(newPresetCmd(deps))
+	cmd.AddCommand(newStyleCmd(deps))
+	cmd.AddCommand(newVerifyInstallCmd(deps))
+	cmd.AddCommand(newEnvCmd(deps))
+	cmd.AddCommand(newExperimentCmd(deps))
+	cmd.AddCommand(newBenchCmd(deps))
+	cmd.AddCommand(newRecommendCmd(deps))
+	cmd.AddCommand(newDaemonCmd(deps))
+	cmd.AddCommand(newCacheCmd())
+	cmd.AddCommand(newMetaCmd())
+	cmd.AddCommand(newPromptSegmentCmd(deps))
 
+	addFlagAliases(cmd)
+	markUsageErrors(cmd)
 	return cmd
 }
+
+// topCommand returns the subcommand of goco that c is or belongs to, e.g.
+// "models" for `goco models select`.
+func topCommand(c *cobra.Command) *cobra.Command {
+	for c.HasParent() && c.Parent().HasParent() {
+		c = c.Parent()
+	}
+	return c
+}
+
+// usageError is a mistake in how goco was invoked, such as an unknown
+// command or flag, rather than a failure of the command itself.
+type usageError struct{ err error }
+
+func (e usageError) Error() string { return e.err.Error() }
+func (e usageError) Unwrap() error { return e.err }
+
+// IsUsageError reports whether err comes from a bad command line.
+func IsUsageError(err error) bool {
+	var u usageError
+	return errors.As(err, &u)
+}
+
+// markUsageErrors wraps the flag and argument errors of cmd and its
+// subcommands in usageError, with suggestions for mistyped flags and
+// commands.
+func markUsageErrors(cmd *cobra.Command) {
+	cmd.SetFlagErrorFunc(func(c *cobra.Command, err error) error {
+		return usageError{suggestFlag(c, err)}
+	})
+	var walk func(*cobra.Command)
+	walk = func(c *cobra.Command) {
+		if args := c.Args; args != nil {
+			c.Args = func(c *cobra.Command, a []string) error {
+				if err := args(c, a); err != nil {
+					return usageError{suggestCommand(c, a, err)}
+				}
+				return nil
+			}
+		}
+		for _, sub := range c.Commands() {
+			walk(sub)
+		}
+	}
+	walk(cmd)
+}
@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/razobeckett/goco/internal/ai"
+	"github.com/razobeckett/goco/internal/config"
+	"github.com/razobeckett/goco/internal/telemetry"
+	"github.com/spf13/cobra"
+)
+
+// captureStdout returns what fn prints to os.Stdout.
+func captureStdout(t *testing.T, fn func() error) (string, error) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan string)
+	go func() {
+		out, _ := io.ReadAll(r)
+		done <- string(out)
+	}()
+	runErr := fn()
+	w.Close()
+	return <-done, runErr
+}
+
+func TestRootRepoFlag(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "config"))
+	repo := initTestRepo(t)
+	linked := filepath.Join(home, "work")
+	if err := os.Symlink(repo, linked); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(repo, "a.txt")
+	wantRoot, err := filepath.EvalSymlinks(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "long flag", args: []string{"--repo", repo}},
+		{name: "short flag", args: []string{"-C", repo}},
+		{name: "home relative", args: []string{"--repo", "~/work"}},
+		{name: "file", args: []string{"--repo", file}, wantErr: "not a directory"},
+		{name: "missing", args: []string{"-C", filepath.Join(home, "missing")}, wantErr: "not a directory"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewRootCmd()
+			cmd.SetArgs(append(tt.args, "env", "GOCO_REPO_ROOT"))
+			cmd.SilenceUsage, cmd.SilenceErrors = true, true
+
+			out, err := captureStdout(t, cmd.Execute)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, _ := filepath.EvalSymlinks(strings.TrimSpace(out))
+			if got != wantRoot {
+				t.Fatalf("GOCO_REPO_ROOT = %q, want %q", got, wantRoot)
+			}
+		})
+	}
+}
+
+func TestRootReadOnly(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		env      string
+		readOnly bool
+	}{
+		{name: "generate", args: []string{"--read-only", "generate", "--yes"}, readOnly: true},
+		{name: "environment", args: []string{"generate"}, env: "1", readOnly: true},
+		{name: "resolve-msg", args: []string{"--read-only", "resolve-msg"}, readOnly: true},
+		{name: "format-patch", args: []string{"--read-only", "format-patch", "HEAD"}, readOnly: true},
+		{name: "verify-install", args: []string{"--read-only", "verify-install", "--install"}, readOnly: true},
+		{name: "style learn", args: []string{"--read-only", "style", "learn"}, readOnly: true},
+		{name: "preset install", args: []string{"--read-only", "--offline=false", "preset", "install", "https://example.invalid/p.toml"}, readOnly: true},
+		{name: "style learn --print", args: []string{"--read-only", "style", "learn", "--print"}},
+		{name: "verify-install --check", args: []string{"--read-only", "verify-install", "--check"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "config"))
+			t.Setenv("XDG_STATE_HOME", filepath.Join(home, "state"))
+			t.Setenv(readOnlyEnvVar, tt.env)
+			repo := initTestRepo(t)
+			modify(t, repo)
+
+			cmd := NewRootCmd()
+			cmd.SetArgs(append([]string{"-C", repo}, tt.args...))
+			cmd.SilenceUsage, cmd.SilenceErrors = true, true
+			_, err := captureStdout(t, cmd.Execute)
+
+			refused := err != nil && strings.Contains(err.Error(), "--read-only disables")
+			if refused != tt.readOnly {
+				t.Fatalf("refused = %v, want %v (err: %v)", refused, tt.readOnly, err)
+			}
+			if out, err := exec.Command("git", "-C", repo, "status", "--porcelain").Output(); err != nil || string(out) != " M a.txt\n" {
+				t.Fatalf("repository changed: %q, %v", out, err)
+			}
+		})
+	}
+}
+
+func TestNewMetricsOffline(t *testing.T) {
+	offline := true
+	cfg := &config.Config{Telemetry: config.Telemetry{Sink: "otlp", Endpoint: "http://127.0.0.1:1"}}
+
+	sink, err := newMetrics(dependencies{offline: &offline}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := sink.(telemetry.Nop); !ok {
+		t.Fatalf("newMetrics() with --offline = %T, want telemetry.Nop", sink)
+	}
+
+	offline = false
+	sink, err = newMetrics(dependencies{offline: &offline}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := sink.(telemetry.Nop); ok {
+		t.Fatal("newMetrics() online ignored the configured sink")
+	}
+	_ = sink.Close()
+}
+
+func TestProviderFlagsListProviders(t *testing.T) {
+	var walk func(cmd *cobra.Command)
+	checked := 0
+	walk = func(cmd *cobra.Command) {
+		if flag := cmd.Flags().Lookup("provider"); flag != nil {
+			checked++
+			for _, name := range ai.Providers {
+				if !strings.Contains(flag.Usage, name) {
+					t.Errorf("%s --provider help %q does not list %s", cmd.CommandPath(), flag.Usage, name)
+				}
+			}
+		}
+		for _, sub := range cmd.Commands() {
+			walk(sub)
+		}
+	}
+	walk(NewRootCmd())
+	if checked == 0 {
+		t.Fatal("found no --provider flags")
+	}
+}
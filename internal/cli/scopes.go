@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/razobeckett/goco/internal/cache"
+	"github.com/razobeckett/goco/internal/config"
+	"github.com/razobeckett/goco/internal/git"
+	"github.com/razobeckett/goco/internal/style"
+	"github.com/spf13/cobra"
+)
+
+// scopeHistoryCommits is how many recent commits --scope completion scans
+// for scopes.
+const scopeHistoryCommits = 1000
+
+// completeScopes completes --scope with the scopes of the installed presets
+// and learned style, then the ones the history uses, most used first.
+func completeScopes(deps dependencies) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		// Completion skips the root's hooks, which apply --repo.
+		repo := deps.repo
+		if dir, _ := cmd.Flags().GetString("repo"); dir != "" {
+			repo = repo.At(config.ExpandHome(dir))
+		}
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		root, err := repo.Root(ctx)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		seen := make(map[string]bool)
+		var completions []string
+		add := func(scope, description string) {
+			if seen[scope] || !strings.HasPrefix(scope, toComplete) {
+				return
+			}
+			seen[scope] = true
+			completions = append(completions, scope+"\t"+description)
+		}
+		for _, scope := range configuredScopes(deps.configLoader.Path(), root) {
+			add(scope, "configured")
+		}
+		for _, c := range historyScopes(ctx, repo, root) {
+			add(c.Name, fmt.Sprintf("%d in history", c.Count))
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// configuredScopes lists the scopes of the installed presets and the
+// repository's learned style. Broken files only cost their suggestions.
+func configuredScopes(configPath, root string) []string {
+	var scopes []string
+	if pre, err := loadPresets(configPath, root); err == nil && pre != nil {
+		scopes = append(scopes, pre.Scopes...)
+	}
+	if profile, err := style.Load(style.Path(root)); err == nil && profile != nil {
+		scopes = append(scopes, profile.Scopes...)
+	}
+	return scopes
+}
+
+// scopeCache is the cached scan of one repository's history.
+type scopeCache struct {
+	// Head is the commit the scan started from; a new commit invalidates it.
+	Head   string        `json:"head"`
+	Scopes []style.Count `json:"scopes"`
+}
+
+// historyScopes returns the scopes of the recent history, most used first,
+// scanning it again only when HEAD has moved since the last scan.
+func historyScopes(ctx context.Context, repo *git.Repository, root string) []style.Count {
+	head, err := repo.Head(ctx)
+	if err != nil {
+		return nil
+	}
+	sum := sha256.Sum256([]byte(root))
+	path := cache.Path("scopes-" + hex.EncodeToString(sum[:8]) + ".json")
+
+	if path != "" {
+		var cached scopeCache
+		if data, err := os.ReadFile(path); err == nil && json.Unmarshal(data, &cached) == nil && cached.Head == head {
+			cache.Record("scopes", true)
+			return cached.Scopes
+		}
+	}
+	cache.Record("scopes", false)
+
+	entries, err := repo.History(ctx, scopeHistoryCommits)
+	if err != nil {
+		return nil
+	}
+	scopes := style.Survey(entries).Scopes
+	if path != "" {
+		saveScopeCache(path, scopeCache{Head: head, Scopes: scopes})
+	}
+	return scopes
+}
+
+// saveScopeCache replaces the cached scan. Failing to write it only costs
+// the next completion a rescan.
+func saveScopeCache(path string, c scopeCache) {
+	data, err := json.Marshal(c)
+	if err != nil || os.MkdirAll(filepath.Dir(path), 0o755) != nil {
+		return
+	}
+	tmpPath := path + ".tmp"
+	if os.WriteFile(tmpPath, data, 0o644) != nil {
+		os.Remove(tmpPath)
+		return
+	}
+	os.Rename(tmpPath, path)
+}
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/razobeckett/goco/internal/cache"
+	"github.com/razobeckett/goco/internal/config"
+	"github.com/razobeckett/goco/internal/git"
+	"github.com/spf13/cobra"
+)
+
+func TestCompleteScopes(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := initTestRepo(t)
+	commit := func(subject string) {
+		t.Helper()
+		runGit(t, dir, "-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", subject)
+	}
+	commit("feat(api): add pagination")
+	commit("fix(api): check limits")
+	commit("docs(readme): explain install")
+	commit("fix(auth): refresh tokens")
+	if err := os.MkdirAll(filepath.Join(dir, ".goco"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".goco", "preset.toml"), []byte("name = \"acme\"\nscopes = [\"billing\", \"api\"]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	deps := dependencies{configLoader: config.NewLoader(), repo: git.NewRepository(dir)}
+	complete := func(toComplete string) []string {
+		t.Helper()
+		cmd := &cobra.Command{}
+		cmd.SetContext(context.Background())
+		got, directive := completeScopes(deps)(cmd, nil, toComplete)
+		if directive != cobra.ShellCompDirectiveNoFileComp {
+			t.Errorf("directive = %v, want no file completion", directive)
+		}
+		return got
+	}
+
+	// Configured scopes come first, and history only adds the rest.
+	want := []string{"billing\tconfigured", "api\tconfigured", "auth\t1 in history", "readme\t1 in history"}
+	if got := complete(""); !slices.Equal(got, want) {
+		t.Fatalf("completions = %q, want %q", got, want)
+	}
+	if got, want := complete("a"), []string{"api\tconfigured", "auth\t1 in history"}; !slices.Equal(got, want) {
+		t.Fatalf("completions for a = %q, want %q", got, want)
+	}
+
+	// The scan is cached until HEAD moves.
+	before := cache.Stats()["scopes"]
+	complete("")
+	if after := cache.Stats()["scopes"]; after.Hits != before.Hits+1 {
+		t.Fatalf("expected a cache hit, stats went from %+v to %+v", before, after)
+	}
+	commit("feat(cli): add --scope")
+	if got := complete("c"); !slices.Equal(got, []string{"cli\t1 in history"}) {
+		t.Fatalf("completions after a new commit = %q", got)
+	}
+}
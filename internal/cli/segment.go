@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/razobeckett/goco/internal/state"
+	"github.com/spf13/cobra"
+)
+
+const defaultSegmentFormat = "✏ {{with .Prefix}}{{.}} {{end}}draft ready"
+
+type promptSegmentOptions struct {
+	format string
+	maxAge time.Duration
+}
+
+// segmentData is what a --format template can use.
+type segmentData struct {
+	// Prefix is the Conventional Commits type and scope, such as "feat(api)";
+	// it and Type and Scope are empty for other subjects.
+	Prefix  string
+	Type    string
+	Scope   string
+	Subject string
+	Age     time.Duration
+}
+
+func newPromptSegmentCmd(deps dependencies) *cobra.Command {
+	opts := &promptSegmentOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "prompt-segment",
+		Short: "Print a one-line draft summary for shell prompts and tmux",
+		Long: "Print a short summary of the draft `goco watch` keeps for this repository, such as \"✏ feat(api) draft ready\", for shell prompts (starship, powerlevel10k) and tmux status lines. " +
+			"It reads the saved draft and never calls the provider. Nothing is printed outside a repository, without a draft, or when the draft is older than --max-age; committing with goco clears the draft.",
+		GroupID: "tools",
+		Args:    cobra.NoArgs,
+		Example: "  goco prompt-segment\n  goco prompt-segment --format '{{.Subject}}'\n  set -g status-right '#(cd #{pane_current_path} && goco prompt-segment)'",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runPromptSegment(cmd.Context(), deps, opts)
+		},
+	}
+
+	fs := cmd.Flags()
+	fs.StringVar(&opts.format, "format", defaultSegmentFormat, "Go template for the segment; fields: Prefix, Type, Scope, Subject, Age")
+	fs.DurationVar(&opts.maxAge, "max-age", time.Hour, "Hide drafts older than this (0 shows any age)")
+	return cmd
+}
+
+func runPromptSegment(ctx context.Context, deps dependencies, opts *promptSegmentOptions) error {
+	tmpl, err := template.New("segment").Parse(opts.format)
+	if err != nil {
+		return fmt.Errorf("--format: %w", err)
+	}
+
+	// A prompt is drawn in every directory, so no repository means no segment.
+	root, err := deps.repo.Root(ctx)
+	if err != nil {
+		return nil
+	}
+	draft, err := state.LoadDraft(root)
+	if errors.Is(err, state.ErrNoDraft) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	age := time.Since(draft.Time).Round(time.Second)
+	if opts.maxAge > 0 && age > opts.maxAge {
+		return nil
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, newSegmentData(draft.Message, age)); err != nil {
+		return fmt.Errorf("--format: %w", err)
+	}
+	fmt.Println(b.String())
+	return nil
+}
+
+func newSegmentData(message string, age time.Duration) segmentData {
+	subject, _, _ := strings.Cut(message, "\n")
+	d := segmentData{Subject: subject, Age: age}
+	m := conventionalCommitRegex.FindStringSubmatch(subject)
+	if m == nil {
+		return d
+	}
+	d.Prefix, d.Subject, _ = strings.Cut(subject, ": ")
+	d.Type = m[1]
+	d.Scope = strings.Trim(m[2], "()")
+	return d
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/razobeckett/goco/internal/config"
+	"github.com/razobeckett/goco/internal/git"
+	"github.com/razobeckett/goco/internal/state"
+)
+
+func TestNewSegmentData(t *testing.T) {
+	tests := []struct {
+		message string
+		want    segmentData
+	}{
+		{"feat(api): add retries\n\nBody.", segmentData{Prefix: "feat(api)", Type: "feat", Scope: "api", Subject: "add retries"}},
+		{"fix!: drop v1 endpoints", segmentData{Prefix: "fix!", Type: "fix", Subject: "drop v1 endpoints"}},
+		{"Update the readme", segmentData{Subject: "Update the readme"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			if got := newSegmentData(tt.message, 0); got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunPromptSegment(t *testing.T) {
+	tests := []struct {
+		name    string
+		draft   *state.Draft
+		opts    promptSegmentOptions
+		want    string
+		wantErr bool
+	}{
+		{name: "no draft", want: ""},
+		{
+			name:  "draft",
+			draft: &state.Draft{Time: time.Now(), Message: "feat(api): add retries"},
+			want:  "✏ feat(api) draft ready\n",
+		},
+		{
+			name:  "not conventional",
+			draft: &state.Draft{Time: time.Now(), Message: "Update the readme"},
+			want:  "✏ draft ready\n",
+		},
+		{
+			name:  "custom format",
+			draft: &state.Draft{Time: time.Now(), Message: "docs: explain watch mode"},
+			opts:  promptSegmentOptions{format: "{{.Type}}: {{.Subject}}"},
+			want:  "docs: explain watch mode\n",
+		},
+		{
+			name:  "too old",
+			draft: &state.Draft{Time: time.Now().Add(-2 * time.Hour), Message: "feat: add retries"},
+			want:  "",
+		},
+		{
+			name:    "bad format",
+			opts:    promptSegmentOptions{format: "{{.Type"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_STATE_HOME", t.TempDir())
+			dir := initTestRepo(t)
+			deps := dependencies{configLoader: config.NewLoader(), repo: git.NewRepository(dir)}
+			if tt.draft != nil {
+				root, err := deps.repo.Root(context.Background())
+				if err != nil {
+					t.Fatal(err)
+				}
+				tt.draft.Root = root
+				if err := state.SaveDraft(*tt.draft); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.opts.format == "" {
+				tt.opts.format = defaultSegmentFormat
+			}
+			tt.opts.maxAge = time.Hour
+
+			got, err := captureStdout(t, func() error {
+				return runPromptSegment(context.Background(), deps, &tt.opts)
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunPromptSegmentOutsideRepository(t *testing.T) {
+	deps := dependencies{configLoader: config.NewLoader(), repo: git.NewRepository(t.TempDir())}
+	got, err := captureStdout(t, func() error {
+		return runPromptSegment(context.Background(), deps, &promptSegmentOptions{format: defaultSegmentFormat})
+	})
+	if err != nil || got != "" {
+		t.Fatalf("got %q, %v; want no output", got, err)
+	}
+}
@@ -29,6 +29,9 @@ type spinnerModel struct {
 func newSpinnerModel(message string) spinnerModel {
 	s := spinner.New()
 	s.Spinner = spinner.Dot
+	if display.remote {
+		s.Spinner.FPS = remoteSpinnerInterval
+	}
 	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color(electricOrange))
 
 	return spinnerModel{
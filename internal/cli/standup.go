@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/razobeckett/goco/internal/ai"
+	"github.com/razobeckett/goco/internal/config"
+	"github.com/razobeckett/goco/internal/git"
+	"github.com/spf13/cobra"
+)
+
+// authorMe selects the user.email configured in each repository.
+const authorMe = "me"
+
+type standupOptions struct {
+	generate *generateOptions
+	since    string
+	author   string
+	repos    []string
+}
+
+func newStandupCmd(deps dependencies) *cobra.Command {
+	opts := &standupOptions{generate: newGenerateOptions()}
+
+	cmd := &cobra.Command{
+		Use:     "standup",
+		Short:   "Summarize your recent commits into a short status update",
+		Long:    "Collect your commits on every local branch of one or more repositories since a given date and have the provider summarize them into a short natural-language update for standups and status reports.",
+		GroupID: "tools",
+		Args:    cobra.NoArgs,
+		Example: "  goco standup\n  goco standup --since \"1 week ago\"\n  goco standup --repos ~/src/api,~/src/web --author alice@example.com",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runStandup(cmd.Context(), deps, opts)
+		},
+	}
+
+	fs := cmd.Flags()
+	fs.StringVar(&opts.since, "since", "yesterday", "Include commits since this date (any git date, e.g. \"monday\", \"2 weeks ago\")")
+	fs.StringVar(&opts.author, "author", authorMe, "Author to summarize (\"me\" uses each repository's user.email)")
+	fs.StringSliceVar(&opts.repos, "repos", []string{"."}, "Comma-separated repository paths")
+	fs.StringVarP(&opts.generate.provider, "provider", "p", "", providerUsage("AI provider to use"))
+	fs.StringVarP(&opts.generate.apiKey, "api-key", "k", "", "API key for the selected provider")
+	fs.StringVarP(&opts.generate.model, "model", "m", "", "Model to use (defaults to the provider's recommended model)")
+	fs.StringVarP(&opts.generate.customInstructions, "custom-instructions", "c", "", "Additional instructions to add to the AI prompt")
+	return cmd
+}
+
+func runStandup(ctx context.Context, deps dependencies, opts *standupOptions) error {
+	p := NewPipeline(deps, opts.generate)
+	var activity string
+
+	return p.run(ctx, "goco.standup", []pipelineStage{
+		{"collect", func(ctx context.Context) error {
+			var err error
+			activity, err = collectActivity(ctx, deps.repo, opts)
+			return err
+		}},
+		{"resolve", p.resolve},
+		{"summarize", func(ctx context.Context) error {
+			input := ai.PromptInput{
+				Status:             activity,
+				CustomInstructions: p.opts.customInstructions,
+				Template:           ai.StandupTemplate,
+			}
+			prompt, err := p.preparePrompt(ctx, input)
+			if err != nil {
+				return err
+			}
+			summary, err := p.send(ctx, input, prompt, "Summarizing your work...")
+			if err != nil {
+				return fmt.Errorf("generate summary: %w", err)
+			}
+			if summary, err = ai.CleanOutput(summary); err != nil {
+				return err
+			}
+			fmt.Println(strings.TrimSpace(summary))
+			return nil
+		}},
+	})
+}
+
+// collectActivity lists the author's commits per repository, failing only
+// when no repository has any. "." is base itself; other repositories run
+// git the way base does.
+func collectActivity(ctx context.Context, base *git.Repository, opts *standupOptions) (string, error) {
+	var b strings.Builder
+	for _, dir := range opts.repos {
+		repo := base
+		if dir != "." {
+			repo = base.At(config.ExpandHome(dir))
+		}
+		name, err := repo.Name(ctx)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", dir, err)
+		}
+
+		author := opts.author
+		if author == authorMe {
+			if author = repo.UserEmail(ctx); author == "" {
+				return "", fmt.Errorf("%s: user.email is not set; pass --author", dir)
+			}
+		}
+
+		commits, err := repo.Activity(ctx, opts.since, author)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", dir, err)
+		}
+		if len(commits) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&b, "repository: %s\n", name)
+		for _, c := range commits {
+			fmt.Fprintf(&b, "- %s\n", c.Subject)
+			if c.Body != "" {
+				fmt.Fprintf(&b, "  %s\n", strings.ReplaceAll(c.Body, "\n", "\n  "))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	if b.Len() == 0 {
+		return "", fmt.Errorf("no commits by %s since %s", opts.author, opts.since)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,240 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/razobeckett/goco/internal/ai"
+	"github.com/razobeckett/goco/internal/git"
+	"github.com/razobeckett/goco/internal/state"
+	"github.com/spf13/cobra"
+)
+
+type statusOptions struct {
+	generate generateOptions
+	json     bool
+}
+
+// statusSummary is what `goco status` reports; it is also its JSON output.
+type statusSummary struct {
+	Branch     string   `json:"branch,omitempty"`
+	Staged     int      `json:"staged"`
+	Unstaged   int      `json:"unstaged"`
+	Untracked  int      `json:"untracked"`
+	Conflicted int      `json:"conflicted"`
+	Scopes     []string `json:"scopes,omitempty"`
+	// PromptTokens estimates the prompt for the diff goco would send;
+	// PromptParts is above 1 when the diff would be summarized in parts.
+	PromptTokens int    `json:"prompt_tokens"`
+	PromptParts  int    `json:"prompt_parts"`
+	Provider     string `json:"provider"`
+	Model        string `json:"model"`
+	APIKeyEnv    string `json:"api_key_env"`
+	APIKeySet    bool   `json:"api_key_set"`
+	// LocalDraft is the message the fast path would write without the
+	// provider; SavedReply is when the saved last prompt, identical to the
+	// current one, was answered.
+	LocalDraft string     `json:"local_draft,omitempty"`
+	SavedReply *time.Time `json:"saved_reply,omitempty"`
+}
+
+func newStatusCmd(deps dependencies) *cobra.Command {
+	opts := &statusOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "status",
+		Short:   "Summarize what goco generate would send, without sending it",
+		Long:    "Show staged and unstaged file counts, the scopes the changes touch, an estimate of the prompt's size in tokens, the provider and model goco would use, and whether a message is already available without a request: from the fast path, or as the saved reply to an identical prompt. Nothing is sent to the provider.",
+		GroupID: "inspect",
+		Args:    cobra.NoArgs,
+		Example: "  goco status\n  goco status --staged\n  goco status --provider groq --json",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runStatus(cmd.Context(), deps, opts)
+		},
+	}
+
+	fs := cmd.Flags()
+	fs.StringVarP(&opts.generate.provider, "provider", "p", "", providerUsage("AI provider to report"))
+	fs.StringVarP(&opts.generate.model, "model", "m", "", "Model to report (defaults to the provider's recommended model)")
+	fs.BoolVarP(&opts.generate.staged, "staged", "s", false, "Estimate the prompt for staged changes only")
+	fs.BoolVar(&opts.generate.fastPath, "fast-path", false, "Check whether the fast path would write the message locally")
+	fs.BoolVar(&opts.json, "json", false, "Print the summary as JSON")
+	return cmd
+}
+
+func runStatus(ctx context.Context, deps dependencies, opts *statusOptions) error {
+	p := NewPipeline(deps, &opts.generate)
+	var summary *statusSummary
+	err := p.run(ctx, "goco.status", []pipelineStage{
+		{"resolve", p.resolve},
+		{"summarize", func(ctx context.Context) (err error) {
+			summary, err = p.summarize(ctx)
+			return err
+		}},
+	})
+	if err != nil {
+		return err
+	}
+
+	if opts.json {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(summary)
+	}
+	printStatusSummary(summary, opts.generate.staged)
+	return nil
+}
+
+// summarize gathers the status summary. Unlike inspect, a clean tree or an
+// in-progress rebase is reported rather than refused.
+func (p *Pipeline) summarize(ctx context.Context) (*statusSummary, error) {
+	status, err := p.deps.repo.Status(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("read git status: %w", err)
+	}
+
+	s := &statusSummary{
+		Branch:   status.Branch,
+		Provider: p.providerName,
+		Model:    p.modelName,
+	}
+	if ai.NeedsAPIKey(p.providerName, p.providerOpts) {
+		s.APIKeyEnv = p.cfg.APIKeyEnv(p.providerName)
+		s.APIKeySet = providerAPIKey(p.cfg, p.providerName) != ""
+	}
+	if s.Model == "" {
+		s.Model = ai.DefaultModelFor(p.providerName, p.providerOpts)
+	}
+	s.countEntries(status)
+	for _, pkg := range git.GroupByPackage(p.root, status.Paths(p.opts.staged)) {
+		if name := pkg.Name(); name != "" {
+			s.Scopes = append(s.Scopes, name)
+		}
+	}
+
+	diff, _, err := p.readDiff(ctx, status)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(diff) == "" {
+		return s, nil
+	}
+
+	p.status = status
+	p.state, _ = p.deps.repo.State(ctx)
+	p.clone = p.deps.repo.CloneShape(ctx)
+	p.diff = diff
+	if log, err := p.deps.repo.RecentLog(ctx, 3); err == nil {
+		p.recentLog = log
+	}
+
+	// Chunk summaries are not known without asking the provider, so a
+	// chunked diff is estimated at its full size.
+	promptDiff := p.promptDiff()
+	s.PromptParts = 1
+	if p.chunking.enabled && len(promptDiff) > p.chunking.size {
+		s.PromptParts = max(1, len(git.GroupSections(promptDiff, p.chunking.size)))
+	}
+	prompt, err := ai.BuildPrompt(p.promptInput(promptDiff))
+	if err != nil {
+		return nil, err
+	}
+	s.PromptTokens = estimateTokens(prompt)
+
+	if msg, _, ok := p.localMessage(); ok {
+		s.LocalDraft, _, _ = strings.Cut(msg, "\n")
+	}
+	lp, err := state.LoadLastPrompt()
+	switch {
+	case err == nil:
+		if lp.Error == "" && lp.Response != "" && lp.Prompt == ai.RedactSecrets(prompt) {
+			s.SavedReply = &lp.Time
+		}
+	case !errors.Is(err, state.ErrNoLastPrompt):
+		return nil, err
+	}
+	return s, nil
+}
+
+// countEntries counts status's entries by kind. A file with both staged
+// and unstaged changes counts as both.
+func (s *statusSummary) countEntries(status *git.Status) {
+	for _, e := range status.Entries {
+		switch {
+		case e.Conflicted:
+			s.Conflicted++
+		case e.Untracked:
+			s.Untracked++
+		default:
+			if e.Staged() {
+				s.Staged++
+			}
+			if e.Worktree != '.' {
+				s.Unstaged++
+			}
+		}
+	}
+}
+
+// changes describes the counts in one line.
+func (s *statusSummary) changes() string {
+	changes := fmt.Sprintf("%d staged, %d unstaged, %d untracked", s.Staged, s.Unstaged, s.Untracked)
+	if s.Conflicted > 0 {
+		changes += fmt.Sprintf(", %d conflicted", s.Conflicted)
+	}
+	return changes
+}
+
+func printStatusSummary(s *statusSummary, staged bool) {
+	branch := s.Branch
+	if branch == "" {
+		branch = "(detached)"
+	}
+	fmt.Println(modelProviderStyle.Render("Status on " + branch))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Changes\t%s\n", s.changes())
+	if len(s.Scopes) > 0 {
+		fmt.Fprintf(w, "Scopes\t%s\n", strings.Join(s.Scopes, ", "))
+	}
+
+	source := "working tree"
+	if staged {
+		source = "staged changes"
+	}
+	switch {
+	case s.PromptTokens == 0:
+		fmt.Fprintf(w, "Prompt\tnothing to send from the %s\n", source)
+	case s.PromptParts > 1:
+		fmt.Fprintf(w, "Prompt\t~%d tokens for the %s, summarized in %d parts\n", s.PromptTokens, source, s.PromptParts)
+	default:
+		fmt.Fprintf(w, "Prompt\t~%d tokens for the %s\n", s.PromptTokens, source)
+	}
+
+	key := s.APIKeyEnv + " is set"
+	switch {
+	case s.APIKeyEnv == "":
+		key = "no API key needed"
+	case !s.APIKeySet:
+		key = s.APIKeyEnv + " is not set"
+	}
+	fmt.Fprintf(w, "Provider\t%s, %s (%s)\n", providerDisplayName(s.Provider), s.Model, key)
+
+	switch {
+	case s.PromptTokens == 0:
+		// Nothing to write a message for.
+	case s.LocalDraft != "":
+		fmt.Fprintf(w, "Draft\tthe fast path would write %q locally\n", s.LocalDraft)
+	case s.SavedReply != nil:
+		fmt.Fprintf(w, "Draft\ta reply to this exact prompt was saved %s ago; see goco last-prompt show\n", time.Since(*s.SavedReply).Round(time.Second))
+	default:
+		fmt.Fprintf(w, "Draft\tnone; goco generate will ask the provider\n")
+	}
+	w.Flush()
+}
@@ -0,0 +1,188 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/razobeckett/goco/internal/ai"
+	"github.com/razobeckett/goco/internal/config"
+	"github.com/razobeckett/goco/internal/git"
+	"github.com/razobeckett/goco/internal/policy"
+	"github.com/razobeckett/goco/internal/state"
+)
+
+// statusEnv isolates goco's config and state and returns a test repository.
+func statusEnv(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "config"))
+	t.Setenv("XDG_STATE_HOME", filepath.Join(home, "state"))
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(home, "cache"))
+	t.Setenv(policy.EnvVar, "")
+	t.Setenv(config.DefaultGeminiAPIKeyEnv, "")
+	t.Setenv(config.DefaultGroqAPIKeyEnv, "")
+	return initTestRepo(t)
+}
+
+func summarizeRepo(t *testing.T, dir string, opts *generateOptions) (*Pipeline, *statusSummary) {
+	t.Helper()
+	p := NewPipeline(dependencies{configLoader: config.NewLoader(), repo: git.NewRepository(dir)}, opts)
+	ctx := context.Background()
+	if err := p.resolve(ctx); err != nil {
+		t.Fatal(err)
+	}
+	s, err := p.summarize(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p, s
+}
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  generateOptions
+		env   map[string]string
+		setup func(t *testing.T, dir string)
+		check func(t *testing.T, s *statusSummary)
+	}{
+		{
+			name: "clean tree",
+			check: func(t *testing.T, s *statusSummary) {
+				if s.Staged+s.Unstaged+s.Untracked != 0 || s.PromptTokens != 0 {
+					t.Errorf("clean tree summarized as %+v", s)
+				}
+			},
+		},
+		{
+			name:  "working tree change",
+			setup: modify,
+			check: func(t *testing.T, s *statusSummary) {
+				if s.Staged != 0 || s.Unstaged != 1 {
+					t.Errorf("got %d staged, %d unstaged; want 0, 1", s.Staged, s.Unstaged)
+				}
+				if s.PromptTokens == 0 || s.PromptParts != 1 {
+					t.Errorf("got %d tokens in %d parts, want an estimate in 1 part", s.PromptTokens, s.PromptParts)
+				}
+				if s.Provider != ai.ProviderGemini || s.Model != ai.DefaultModelFor(ai.ProviderGemini, ai.Options{}) || s.APIKeySet {
+					t.Errorf("got provider %s, model %s, key set %v", s.Provider, s.Model, s.APIKeySet)
+				}
+				if s.LocalDraft != "" || s.SavedReply != nil {
+					t.Errorf("unexpected draft: %+v", s)
+				}
+			},
+		},
+		{
+			name: "staged changes only",
+			opts: generateOptions{provider: ai.ProviderGroq, staged: true},
+			env:  map[string]string{config.DefaultGroqAPIKeyEnv: "key"},
+			setup: func(t *testing.T, dir string) {
+				if err := os.MkdirAll(filepath.Join(dir, "api"), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(dir, "api", "go.mod"), []byte("module api\n"), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				runGit(t, dir, "add", "api/go.mod")
+				modify(t, dir)
+				if err := os.WriteFile(filepath.Join(dir, "new.txt"), []byte("new\n"), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			},
+			check: func(t *testing.T, s *statusSummary) {
+				if s.Staged != 1 || s.Unstaged != 1 || s.Untracked != 1 {
+					t.Errorf("got %d staged, %d unstaged, %d untracked; want 1 each", s.Staged, s.Unstaged, s.Untracked)
+				}
+				if strings.Join(s.Scopes, ",") != "api" {
+					t.Errorf("scopes = %v, want [api]", s.Scopes)
+				}
+				if s.Provider != ai.ProviderGroq || s.APIKeyEnv != config.DefaultGroqAPIKeyEnv || !s.APIKeySet {
+					t.Errorf("got provider %s, key %s set %v", s.Provider, s.APIKeyEnv, s.APIKeySet)
+				}
+			},
+		},
+		{
+			name: "fast path draft",
+			opts: generateOptions{fastPath: true},
+			setup: func(t *testing.T, dir string) {
+				if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Project\n"), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				runGit(t, dir, "add", "README.md")
+				runGit(t, dir, "-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "-qm", "docs")
+				if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Project\n\nUsage.\n"), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			},
+			check: func(t *testing.T, s *statusSummary) {
+				if !strings.HasPrefix(s.LocalDraft, "docs") {
+					t.Errorf("local draft = %q, want a docs message", s.LocalDraft)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := statusEnv(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			if tt.setup != nil {
+				tt.setup(t, dir)
+			}
+			_, s := summarizeRepo(t, dir, &tt.opts)
+			tt.check(t, s)
+		})
+	}
+}
+
+func TestSummarizeSavedReply(t *testing.T) {
+	dir := statusEnv(t)
+	modify(t, dir)
+
+	p, _ := summarizeRepo(t, dir, &generateOptions{})
+	prompt, err := ai.BuildPrompt(p.promptInput(p.promptDiff()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := time.Now().Add(-time.Minute).Truncate(time.Second)
+	if err := state.SaveLastPrompt(state.LastPrompt{Time: saved, Prompt: ai.RedactSecrets(prompt), Response: "feat: add two"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, s := summarizeRepo(t, dir, &generateOptions{}); s.SavedReply == nil || !s.SavedReply.Equal(saved) {
+		t.Errorf("saved reply = %v, want %v", s.SavedReply, saved)
+	}
+
+	// A different diff makes the saved reply stale.
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("one\nthree\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, s := summarizeRepo(t, dir, &generateOptions{}); s.SavedReply != nil {
+		t.Errorf("saved reply for a different prompt = %v, want none", s.SavedReply)
+	}
+}
+
+func TestSummarizeGeminiBackend(t *testing.T) {
+	dir := statusEnv(t)
+	t.Setenv("GOOGLE_CLOUD_PROJECT", "acme")
+	t.Setenv("GOOGLE_CLOUD_LOCATION", "us-central1")
+
+	p, s := summarizeRepo(t, dir, &generateOptions{geminiBackend: ai.GeminiBackendVertex})
+	if p.providerOpts.Gemini.Backend != ai.GeminiBackendVertex {
+		t.Errorf("backend = %q, want --gemini-backend to override the config", p.providerOpts.Gemini.Backend)
+	}
+	if s.Provider != ai.ProviderGemini || s.APIKeySet {
+		t.Errorf("got provider %s, key set %v", s.Provider, s.APIKeySet)
+	}
+
+	p = NewPipeline(dependencies{configLoader: config.NewLoader(), repo: git.NewRepository(dir)}, &generateOptions{geminiBackend: "vertex"})
+	if err := p.resolve(context.Background()); !IsUsageError(err) {
+		t.Errorf("expected an unknown backend to be a usage error, got %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/razobeckett/goco/internal/style"
+	"github.com/spf13/cobra"
+)
+
+type styleOptions struct {
+	commits int
+	print   bool
+}
+
+func newStyleCmd(deps dependencies) *cobra.Command {
+	opts := &styleOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "style",
+		Short:   "Learn the repository's commit message style",
+		Long:    "A style profile records how a repository's commit messages are written: language, tense, emoji, capitalization, Conventional Commits usage, typical lengths, and scopes. goco adds it to every prompt so generated messages read like the existing history.",
+		GroupID: "tools",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+
+	learn := &cobra.Command{
+		Use:     "learn",
+		Short:   "Analyze recent commits and write .goco/style.toml",
+		Args:    cobra.NoArgs,
+		Example: "  goco style learn\n  goco style learn --commits 500\n  goco style learn --print",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runStyleLearn(cmd.Context(), deps, opts)
+		},
+	}
+	learn.Flags().IntVarP(&opts.commits, "commits", "n", style.DefaultCommits, "Number of recent commits to analyze")
+	learn.Flags().BoolVar(&opts.print, "print", false, "Print the prompt guidance instead of writing the profile")
+	cmd.AddCommand(learn)
+
+	return cmd
+}
+
+func runStyleLearn(ctx context.Context, deps dependencies, opts *styleOptions) error {
+	if opts.commits < 1 {
+		return fmt.Errorf("--commits must be at least 1")
+	}
+	if !opts.print {
+		if err := deps.requireWritable("saving the style profile"); err != nil {
+			return fmt.Errorf("%w; add --print to only show it", err)
+		}
+	}
+	root, err := deps.repo.Root(ctx)
+	if err != nil {
+		return fmt.Errorf("not in a git repository: %w", err)
+	}
+	entries, err := deps.repo.History(ctx, opts.commits)
+	if err != nil {
+		return err
+	}
+	profile := style.Learn(entries)
+	if profile == nil {
+		return fmt.Errorf("no commits to learn from")
+	}
+
+	if opts.print {
+		fmt.Println(profile.Instructions())
+		return nil
+	}
+	path := style.Path(root)
+	if err := style.Write(path, profile); err != nil {
+		return err
+	}
+	fmt.Println(noteStyle.Render(fmt.Sprintf("Learned the style of %d commits into %s", profile.Commits, path)))
+	return nil
+}
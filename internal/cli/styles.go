@@ -29,47 +29,80 @@ func boxStyle(borderColor, textColor string) lipgloss.Style {
 		Width(terminalWidth())
 }
 
+// The styles goco draws with, set from the palette by setStyles.
 var (
+	titleStyle               lipgloss.Style
+	noteStyle                lipgloss.Style
+	statusHeaderStyle        lipgloss.Style
+	diffHeaderStyle          lipgloss.Style
+	statusBoxStyle           lipgloss.Style
+	diffBoxStyle             lipgloss.Style
+	commitMessageHeaderStyle lipgloss.Style
+	commitMessageBoxStyle    lipgloss.Style
+	modelProviderStyle       lipgloss.Style
+	modelItemStyle           lipgloss.Style
+	promptTitleStyle         lipgloss.Style
+	promptDescriptionStyle   lipgloss.Style
+	promptErrorStyle         lipgloss.Style
+)
+
+func init() {
+	setStyles()
+}
+
+// setStyles builds the styles from the palette, which a theme can change.
+func setStyles() {
 	titleStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color(electricOrange)).
-			Bold(true).
-			MarginBottom(1)
+		Foreground(lipgloss.Color(electricOrange)).
+		Bold(true).
+		MarginBottom(1)
 
 	noteStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color(tangerineShock)).
-			Italic(true).
-			MarginTop(1)
+		Foreground(lipgloss.Color(tangerineShock)).
+		Italic(true).
+		MarginTop(1)
 
 	statusHeaderStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color(creamGleam)).
-				Bold(true).
-				Background(lipgloss.Color(electricOrange)).
-				Padding(0, 1)
+		Foreground(lipgloss.Color(creamGleam)).
+		Bold(true).
+		Background(lipgloss.Color(electricOrange)).
+		Padding(0, 1)
 
 	diffHeaderStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color(creamGleam)).
-			Bold(true).
-			Background(lipgloss.Color(tangerineShock)).
-			Padding(0, 1)
+		Foreground(lipgloss.Color(creamGleam)).
+		Bold(true).
+		Background(lipgloss.Color(tangerineShock)).
+		Padding(0, 1)
 
 	statusBoxStyle = boxStyle(electricOrange, tangerineShock)
-	diffBoxStyle   = boxStyle(tangerineShock, electricOrange)
+	diffBoxStyle = boxStyle(tangerineShock, electricOrange)
 
 	commitMessageHeaderStyle = lipgloss.NewStyle().
-					Foreground(lipgloss.Color(creamGleam)).
-					Bold(true).
-					Background(lipgloss.Color(electricOrange)).
-					Padding(0, 1)
+		Foreground(lipgloss.Color(creamGleam)).
+		Bold(true).
+		Background(lipgloss.Color(electricOrange)).
+		Padding(0, 1)
 
 	commitMessageBoxStyle = boxStyle(mangoVolt, electricOrange)
 
 	modelProviderStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color(electricOrange)).
-				Bold(true).
-				MarginTop(1).
-				MarginBottom(1)
+		Foreground(lipgloss.Color(electricOrange)).
+		Bold(true).
+		MarginTop(1).
+		MarginBottom(1)
 
 	modelItemStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color(tangerineShock)).
-			PaddingLeft(2)
-)
+		Foreground(lipgloss.Color(tangerineShock)).
+		PaddingLeft(2)
+
+	promptTitleStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color(electricOrange)).
+		Bold(true)
+
+	promptDescriptionStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color(mangoVolt)).
+		Italic(true)
+
+	promptErrorStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color(lipstickRed))
+}
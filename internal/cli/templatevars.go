@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/razobeckett/goco/internal/ai"
+	"github.com/razobeckett/goco/internal/git"
+)
+
+// templateEnvPrefix marks the environment variables templates can read.
+// Templates can come from the repository or a URL preset, so the rest of the
+// environment, API keys and cloud credentials included, stays out of reach.
+const templateEnvPrefix = "GOCO_VAR_"
+
+// templateVars reads the values prompt templates and the [Message] footer
+// can use. It is best-effort: a value git cannot read is empty.
+func (p *Pipeline) templateVars(ctx context.Context) ai.TemplateVars {
+	branch, _ := p.deps.repo.CurrentBranch(ctx)
+	env := make(map[string]string)
+	for _, kv := range os.Environ() {
+		key, value, _ := strings.Cut(kv, "=")
+		if name, ok := strings.CutPrefix(key, templateEnvPrefix); ok && name != "" {
+			env[name] = value
+		}
+	}
+	return ai.TemplateVars{
+		GitUserName:  p.deps.repo.UserName(ctx),
+		GitUserEmail: p.deps.repo.UserEmail(ctx),
+		Branch:       branch,
+		Env:          env,
+	}
+}
+
+// parseFooter parses the [Message] footer template, or returns nil when
+// there is none.
+func parseFooter(text string) (*template.Template, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+	tmpl, err := template.New("footer").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("[Message] footer: %w", err)
+	}
+	return tmpl, nil
+}
+
+// withFooter adds the rendered [Message] footer to msg unless it renders
+// empty or msg already holds it, as a regenerated or edited message may. A
+// footer of trailers joins msg's trailer block.
+func (p *Pipeline) withFooter(msg string) string {
+	if p.footer == nil {
+		return msg
+	}
+	var b strings.Builder
+	if err := p.footer.Execute(&b, p.vars); err != nil {
+		fmt.Fprintln(os.Stderr, noteStyle.Render(fmt.Sprintf("Warning: could not render the [Message] footer: %v.", err)))
+		return msg
+	}
+	footer := strings.TrimSpace(b.String())
+	if footer == "" || strings.Contains(msg, footer) {
+		return msg
+	}
+	msg = strings.TrimRight(msg, "\n")
+	if git.Trailers(msg) != nil && git.IsTrailerBlock(strings.Split(footer, "\n")) {
+		return msg + "\n" + footer
+	}
+	return msg + "\n\n" + footer
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/razobeckett/goco/internal/ai"
+	"github.com/razobeckett/goco/internal/git"
+)
+
+func TestWithFooter(t *testing.T) {
+	vars := ai.TemplateVars{Branch: "feature/login", Env: map[string]string{"TICKET": "PROJ-7"}}
+	tests := []struct {
+		name   string
+		footer string
+		msg    string
+		want   string
+	}{
+		{name: "none", msg: "feat: add login", want: "feat: add login"},
+		{name: "paragraph", footer: "Refs: {{.Env.TICKET}}", msg: "feat: add login", want: "feat: add login\n\nRefs: PROJ-7"},
+		{name: "joins the trailers", footer: "Refs: {{.Env.TICKET}}", msg: "feat: add login\n\nBody.\n\nSigned-off-by: Ada <ada@example.com>", want: "feat: add login\n\nBody.\n\nSigned-off-by: Ada <ada@example.com>\nRefs: PROJ-7"},
+		{name: "already there", footer: "Refs: {{.Env.TICKET}}", msg: "feat: add login\n\nRefs: PROJ-7", want: "feat: add login\n\nRefs: PROJ-7"},
+		{name: "renders empty", footer: "{{with .Env.UNSET}}Refs: {{.}}{{end}}", msg: "feat: add login", want: "feat: add login"},
+		{name: "branch", footer: "Branch: {{.Branch}}", msg: "feat: add login\n", want: "feat: add login\n\nBranch: feature/login"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			footer, err := parseFooter(tt.footer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := &Pipeline{footer: footer, vars: vars}
+			if got := p.withFooter(tt.msg); got != tt.want {
+				t.Errorf("withFooter() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := parseFooter("Refs: {{.Env.TICKET"); err == nil {
+		t.Error("expected an unparsable footer to be rejected")
+	}
+}
+
+func TestTemplateVars(t *testing.T) {
+	dir := initTestRepo(t)
+	runGit(t, dir, "config", "user.name", "Ada")
+	runGit(t, dir, "config", "user.email", "ada@example.com")
+	runGit(t, dir, "checkout", "-qb", "feature/login")
+	t.Setenv("GOCO_VAR_TICKET", "PROJ-7")
+
+	p := NewPipeline(dependencies{repo: git.NewRepository(dir)}, &generateOptions{})
+	vars := p.templateVars(context.Background())
+	if vars.GitUserName != "Ada" || vars.GitUserEmail != "ada@example.com" || vars.Branch != "feature/login" || vars.Env["TICKET"] != "PROJ-7" {
+		t.Errorf("templateVars() = %+v", vars)
+	}
+}
+
+func TestTemplateVarsHideSecrets(t *testing.T) {
+	dir := initTestRepo(t)
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "wJalrXUtnFEMI")
+	t.Setenv("GOCO_GROQ_KEY", "gsk_secret")
+	t.Setenv("GOCO_VAR_TICKET", "PROJ-7")
+
+	footer, err := parseFooter("{{.Env.AWS_SECRET_ACCESS_KEY}}{{.Env.GOCO_GROQ_KEY}}{{range .Env}}{{.}} {{end}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := NewPipeline(dependencies{repo: git.NewRepository(dir)}, &generateOptions{})
+	p.footer, p.vars = footer, p.templateVars(context.Background())
+	got := p.withFooter("feat: add login")
+	if strings.Contains(got, "wJalrXUtnFEMI") || strings.Contains(got, "gsk_secret") {
+		t.Fatalf("a template read a secret from the environment: %q", got)
+	}
+	if !strings.Contains(got, "PROJ-7") {
+		t.Errorf("footer = %q, want the GOCO_VAR_ value", got)
+	}
+}
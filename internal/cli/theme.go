@@ -1,21 +1,111 @@
 package cli
 
 import (
+	"cmp"
+	"fmt"
 	"image/color"
+	"os"
+	"strings"
 
 	"charm.land/fang/v2"
 	lipglossv2 "charm.land/lipgloss/v2"
+	"github.com/razobeckett/goco/internal/config"
 )
 
-const (
-	electricOrange = "#FF6A00"
-	tangerineShock = "#FF8C1A"
-	sunburstSurge  = "#FF9F40"
-	mangoVolt      = "#FFC266"
-	creamGleam     = "#FFF1E6"
-	lipstickRed    = "#FD0040"
+// themeEnvVar names a theme file, over [Terminal] theme.
+const themeEnvVar = "GOCO_THEME"
+
+// defaultTheme is goco's own look.
+var defaultTheme = config.Theme{
+	Colors: config.ThemeColors{
+		Primary:   "#FF6A00",
+		Secondary: "#FF8C1A",
+		Accent:    "#FF9F40",
+		Highlight: "#FFC266",
+		Text:      "#FFF1E6",
+		Error:     "#FD0040",
+	},
+	Messages: config.ThemeMessages{
+		Generating:     "Generating commit message...",
+		FetchingModels: "Fetching {provider} models...",
+		StatusTitle:    "Git Status",
+		DiffTitle:      "Git Diff",
+		MessageTitle:   "Generated Commit Message",
+		FinalTitle:     "Final Commit Message",
+	},
+}
+
+// theme is the look in use: defaultTheme under the user's theme file.
+var theme = defaultTheme
+
+// The palette, set from theme.Colors.
+var (
+	electricOrange = defaultTheme.Colors.Primary
+	tangerineShock = defaultTheme.Colors.Secondary
+	sunburstSurge  = defaultTheme.Colors.Accent
+	mangoVolt      = defaultTheme.Colors.Highlight
+	creamGleam     = defaultTheme.Colors.Text
+	lipstickRed    = defaultTheme.Colors.Error
 )
 
+// LoadTheme applies the theme file named by GOCO_THEME or [Terminal]
+// theme, or theme.toml next to the config file. It runs before the command
+// line is parsed so help and errors are themed too. A broken theme is
+// reported and goco's own look kept.
+func LoadTheme() {
+	loader := config.NewLoader()
+	t, err := loader.LocalTerminal()
+	if err != nil {
+		// The command that loads the config reports it.
+		return
+	}
+	custom, err := loader.Theme(cmp.Or(os.Getenv(themeEnvVar), t.Theme))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, noteStyle.Render(fmt.Sprintf("Warning: %v; using goco's own theme.", err)))
+		return
+	}
+	applyTheme(custom)
+}
+
+// applyTheme sets the look to custom over defaultTheme; nil restores
+// defaultTheme.
+func applyTheme(custom *config.Theme) {
+	theme = defaultTheme
+	if custom != nil {
+		theme.Emoji = custom.Emoji
+		c, m := custom.Colors, custom.Messages
+		theme.Colors = config.ThemeColors{
+			Primary:   cmp.Or(c.Primary, theme.Colors.Primary),
+			Secondary: cmp.Or(c.Secondary, theme.Colors.Secondary),
+			Accent:    cmp.Or(c.Accent, theme.Colors.Accent),
+			Highlight: cmp.Or(c.Highlight, theme.Colors.Highlight),
+			Text:      cmp.Or(c.Text, theme.Colors.Text),
+			Error:     cmp.Or(c.Error, theme.Colors.Error),
+		}
+		theme.Messages = config.ThemeMessages{
+			Generating:     cmp.Or(m.Generating, theme.Messages.Generating),
+			FetchingModels: cmp.Or(m.FetchingModels, theme.Messages.FetchingModels),
+			StatusTitle:    cmp.Or(m.StatusTitle, theme.Messages.StatusTitle),
+			DiffTitle:      cmp.Or(m.DiffTitle, theme.Messages.DiffTitle),
+			MessageTitle:   cmp.Or(m.MessageTitle, theme.Messages.MessageTitle),
+			FinalTitle:     cmp.Or(m.FinalTitle, theme.Messages.FinalTitle),
+		}
+	}
+	electricOrange = theme.Colors.Primary
+	tangerineShock = theme.Colors.Secondary
+	sunburstSurge = theme.Colors.Accent
+	mangoVolt = theme.Colors.Highlight
+	creamGleam = theme.Colors.Text
+	lipstickRed = theme.Colors.Error
+	setStyles()
+}
+
+// fetchingModelsMessage is the spinner message while providerName's models
+// are listed.
+func fetchingModelsMessage(providerName string) string {
+	return strings.ReplaceAll(theme.Messages.FetchingModels, "{provider}", providerDisplayName(providerName))
+}
+
 func FangColorScheme(ld lipglossv2.LightDarkFunc) fang.ColorScheme {
 	return fang.ColorScheme{
 		Base:           ld(lipglossv2.Color(electricOrange), lipglossv2.Color(creamGleam)),
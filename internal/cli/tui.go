@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/term"
+)
+
+// noTUIEnvVar makes goco use line prompts and plain output everywhere, e.g.
+// in an editor's shell buffer goco does not recognize.
+const noTUIEnvVar = "GOCO_NO_TUI"
+
+// errNoTUI reports that the terminal cannot run goco's interactive prompts.
+var errNoTUI = errors.New("this terminal cannot run interactive prompts")
+
+// dumbTerminal returns why the terminal cannot draw goco's spinners and
+// prompts although it is one, or "" when it can.
+func dumbTerminal() string {
+	switch {
+	case os.Getenv(noTUIEnvVar) != "":
+		return noTUIEnvVar + " is set"
+	case os.Getenv("INSIDE_EMACS") != "":
+		return "running inside Emacs"
+	case os.Getenv("TERM") == "dumb":
+		return "TERM is dumb"
+	}
+	return ""
+}
+
+// tuiUnavailable returns why goco cannot run its interactive prompts here,
+// or "" when it can. When stdin is not a terminal, bubbletea reads keys from
+// the controlling terminal, which an ssh session without a pty or a CI job
+// does not have.
+func tuiUnavailable() string {
+	if reason := dumbTerminal(); reason != "" {
+		return reason
+	}
+	if !stdoutIsTerminal() {
+		return "output is not a terminal"
+	}
+	if stdinIsTerminal() || runtime.GOOS == "windows" {
+		return ""
+	}
+	tty, err := os.Open("/dev/tty")
+	if err != nil {
+		return "no controlling terminal"
+	}
+	tty.Close()
+	return ""
+}
+
+// runProgram runs a bubbletea program, or fails with errNoTUI when the
+// terminal cannot run it, found before it starts or as it opens the
+// terminal.
+func runProgram(m tea.Model, opts ...tea.ProgramOption) (tea.Model, error) {
+	if reason := tuiUnavailable(); reason != "" {
+		return nil, fmt.Errorf("%w (%s)", errNoTUI, reason)
+	}
+	model, err := runTeaProgram(tea.NewProgram(m, programOptions(opts...)...))
+	if err != nil && isTTYError(err) {
+		return nil, fmt.Errorf("%w: %w", errNoTUI, err)
+	}
+	return model, err
+}
+
+// runTeaProgram runs program, noting that it owns the terminal so
+// RestoreTerminal can undo its drawing if goco dies under it.
+func runTeaProgram(program *tea.Program) (tea.Model, error) {
+	terminal.program.Store(true)
+	defer terminal.program.Store(false)
+	return program.Run()
+}
+
+// terminal tracks what goco has done to the terminal that a crash would
+// leave behind.
+var terminal struct {
+	// saved is the state of the terminal on stdin when goco started.
+	saved *term.State
+	// program is set while a bubbletea program runs, and spinner while
+	// spin has drawn a frame it has not cleared.
+	program atomic.Bool
+	spinner atomic.Bool
+}
+
+const (
+	clearLine  = "\r\033[K"
+	showCursor = "\033[?25h"
+)
+
+// SaveTerminal records the state of the terminal on stdin, if it is one,
+// for RestoreTerminal. Call it once at startup.
+func SaveTerminal() {
+	if !stdinIsTerminal() {
+		return
+	}
+	if state, err := term.GetState(os.Stdin.Fd()); err == nil {
+		terminal.saved = state
+	}
+}
+
+// RestoreTerminal puts the terminal back as goco found it: out of raw mode,
+// with the cursor bubbletea hides shown and no spinner line left behind.
+// main defers it, and goroutines that run while a prompt or spinner owns
+// the terminal call it before a panic crashes goco.
+func RestoreTerminal() {
+	if terminal.saved != nil {
+		_ = term.Restore(os.Stdin.Fd(), terminal.saved)
+	}
+	clearSpinner()
+	if terminal.program.Swap(false) && stdoutIsTerminal() && dumbTerminal() == "" {
+		fmt.Fprint(os.Stdout, "\n"+showCursor)
+	}
+}
+
+// clearSpinner erases the line spin draws on, if it has drawn a frame.
+func clearSpinner() {
+	if terminal.spinner.Swap(false) {
+		fmt.Fprint(os.Stderr, clearLine)
+	}
+}
+
+// restoreOnPanic restores the terminal before a panic crashes goco. A
+// panic on another goroutine skips main's deferred RestoreTerminal, so
+// goroutines started under a prompt or spinner defer this first.
+func restoreOnPanic() {
+	if r := recover(); r != nil {
+		RestoreTerminal()
+		panic(r)
+	}
+}
+
+// isTTYError reports whether bubbletea failed to take over the terminal.
+// It returns these as plain errors.
+func isTTYError(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "could not open a new TTY") || strings.Contains(msg, "raw mode")
+}
+
+var noTUINote sync.Once
+
+// noteLinePrompts says once per run why goco asks line by line.
+func noteLinePrompts(err error) {
+	noTUINote.Do(func() {
+		fmt.Fprintln(os.Stderr, noteStyle.Render(fmt.Sprintf("Using line prompts: %v.", err)))
+	})
+}
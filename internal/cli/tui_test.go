@@ -0,0 +1,172 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestDumbTerminal(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{name: "capable", env: map[string]string{"TERM": "xterm-256color"}},
+		{name: "dumb", env: map[string]string{"TERM": "dumb"}, want: "TERM is dumb"},
+		{name: "emacs shell-mode", env: map[string]string{"TERM": "xterm", "INSIDE_EMACS": "29.1,comint"}, want: "running inside Emacs"},
+		{name: "forced", env: map[string]string{"TERM": "xterm", noTUIEnvVar: "1"}, want: noTUIEnvVar + " is set"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{"TERM", "INSIDE_EMACS", noTUIEnvVar} {
+				t.Setenv(name, tt.env[name])
+			}
+			if got := dumbTerminal(); got != tt.want {
+				t.Fatalf("dumbTerminal() = %q, want %q", got, tt.want)
+			}
+			if tt.want != "" {
+				if _, err := runProgram(newConfirmPromptModel("Commit?")); !errors.Is(err, errNoTUI) {
+					t.Fatalf("runProgram() error = %v, want errNoTUI", err)
+				}
+			}
+		})
+	}
+}
+
+func TestIsTTYError(t *testing.T) {
+	if !isTTYError(fmt.Errorf("could not open a new TTY: %w", io.EOF)) || !isTTYError(errors.New("error entering raw mode: operation not supported")) {
+		t.Fatal("expected bubbletea's terminal errors to be recognized")
+	}
+	if isTTYError(errors.New("program was killed")) {
+		t.Fatal("expected other errors to be returned as they are")
+	}
+}
+
+func TestLinePrompter(t *testing.T) {
+	prompter := func(input string) (*linePrompter, *strings.Builder) {
+		var out strings.Builder
+		return &linePrompter{in: bufio.NewReader(strings.NewReader(input)), out: &out}, &out
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		ask     func(l *linePrompter) (string, error)
+		want    string
+		wantErr bool
+		wantOut string
+	}{
+		{
+			name:  "confirm defaults to yes",
+			input: "\n",
+			ask: func(l *linePrompter) (string, error) {
+				ok, err := l.confirm("Commit?")
+				return fmt.Sprint(ok), err
+			},
+			want:    "true",
+			wantOut: "Commit? [Y/n] ",
+		},
+		{
+			name:  "confirm asks again",
+			input: "maybe\nN\n",
+			ask: func(l *linePrompter) (string, error) {
+				ok, err := l.confirm("Commit?")
+				return fmt.Sprint(ok), err
+			},
+			want:    "false",
+			wantOut: "Answer y or n.",
+		},
+		{
+			name:  "confirm without input",
+			input: "",
+			ask: func(l *linePrompter) (string, error) {
+				ok, err := l.confirm("Commit?")
+				return fmt.Sprint(ok), err
+			},
+			wantErr: true,
+		},
+		{
+			name:  "text keeps the value",
+			input: "\n",
+			ask: func(l *linePrompter) (string, error) {
+				return l.text("Subject", "Edit the subject", "feat: add two", "Subject cannot be empty")
+			},
+			want:    "feat: add two",
+			wantOut: "[feat: add two] > ",
+		},
+		{
+			name:  "text without a final newline",
+			input: "\nfix: two",
+			ask: func(l *linePrompter) (string, error) {
+				return l.text("Subject", "", "", "Subject cannot be empty")
+			},
+			want:    "fix: two",
+			wantOut: "Subject cannot be empty",
+		},
+		{
+			name:  "choose asks again",
+			input: "4\n1,3\n",
+			ask: func(l *linePrompter) (string, error) {
+				chosen, err := l.choose("Which files?", []string{"a.go", "b.go", "c.go"})
+				return strings.Join(chosen, " "), err
+			},
+			want:    "a.go c.go",
+			wantOut: "Answer with numbers from 1 to 3",
+		},
+		{
+			name:  "choose keeps all",
+			input: "\n",
+			ask: func(l *linePrompter) (string, error) {
+				chosen, err := l.choose("Which files?", []string{"a.go", "b.go"})
+				return strings.Join(chosen, " "), err
+			},
+			want:    "a.go b.go",
+			wantOut: "   2. b.go",
+		},
+		{
+			name:  "secret from a pipe",
+			input: "  sk-123  \n",
+			ask: func(l *linePrompter) (string, error) {
+				return l.secret("Enter your Groq API key", "API key cannot be empty")
+			},
+			want:    "sk-123",
+			wantOut: "Enter your Groq API key: ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, out := prompter(tt.input)
+			got, err := tt.ask(l)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want && !tt.wantErr {
+				t.Fatalf("answer = %q, want %q", got, tt.want)
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Fatalf("output %q does not contain %q", out.String(), tt.wantOut)
+			}
+		})
+	}
+}
+
+func TestRestoreOnPanic(t *testing.T) {
+	terminal.spinner.Store(true)
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Fatalf("recovered %v, want the panic to continue", r)
+		}
+		if terminal.spinner.Load() {
+			t.Fatal("expected the spinner line to be cleared before the panic continued")
+		}
+	}()
+	func() {
+		defer restoreOnPanic()
+		panic("boom")
+	}()
+}
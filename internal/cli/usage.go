@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/razobeckett/goco/internal/ai"
+)
+
+// resultBadge labels the current message with its score and, when the
+// provider reported it, what the run's requests used.
+func (p *Pipeline) resultBadge() string {
+	badge := p.qualityBadge()
+	if usage := p.usageBadge(); usage != "" {
+		badge += " · " + usage
+	}
+	return badge
+}
+
+// runResult is the outcome of a --print run as --output json prints it.
+type runResult struct {
+	Message  string       `json:"message"`
+	Provider string       `json:"provider,omitempty"`
+	Model    string       `json:"model,omitempty"`
+	Quality  int          `json:"quality"`
+	Usage    *usageReport `json:"usage,omitempty"`
+}
+
+// usageReport is what the run's provider requests used.
+type usageReport struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+	// CostUSD is set when the cached models.dev registry lists the
+	// model's prices.
+	CostUSD *float64 `json:"cost_usd,omitempty"`
+}
+
+// result reports the current message with its score and usage.
+func (p *Pipeline) result() runResult {
+	return runResult{
+		Message:  p.commitMsg,
+		Provider: p.providerName,
+		Model:    p.resultModel(),
+		Quality:  p.quality(p.commitMsg).Score(),
+		Usage:    p.usageReport(),
+	}
+}
+
+// resultModel is the model the run asked, its provider's default when none
+// was named.
+func (p *Pipeline) resultModel() string {
+	if p.modelName != "" {
+		return p.modelName
+	}
+	return ai.DefaultModelFor(p.providerName, p.providerOpts)
+}
+
+// usageReport totals every provider request in the run. It is nil when no
+// provider reported usage, as for a local message.
+func (p *Pipeline) usageReport() *usageReport {
+	u, ok := p.usage.Usage()
+	if !ok {
+		return nil
+	}
+	report := &usageReport{PromptTokens: u.PromptTokens, CompletionTokens: u.CompletionTokens, TotalTokens: u.Total()}
+	if cost, ok := ai.Cost(p.providerLabel(), p.resultModel(), u); ok {
+		report.CostUSD = &cost
+	}
+	return report
+}
+
+// usageBadge reports the tokens of every provider request in the run, and
+// their cost when the cached models.dev registry lists the model's prices.
+// It is "" when no provider reported usage, as for a local message.
+func (p *Pipeline) usageBadge() string {
+	u := p.usageReport()
+	if u == nil {
+		return ""
+	}
+	badge := fmt.Sprintf("%d tokens (%d in, %d out)", u.TotalTokens, u.PromptTokens, u.CompletionTokens)
+	if u.CostUSD != nil {
+		badge += fmt.Sprintf(", about $%.4f", *u.CostUSD)
+	}
+	return badge
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/razobeckett/goco/internal/ai"
+	"github.com/razobeckett/goco/internal/git"
+)
+
+func TestUsageBadge(t *testing.T) {
+	p := NewPipeline(dependencies{}, &generateOptions{})
+	p.status = &git.Status{}
+	p.providerName, p.modelName = ai.ProviderGroq, "unlisted-model"
+	if got := p.usageBadge(); got != "" {
+		t.Fatalf("usageBadge() before any request = %q", got)
+	}
+
+	p.provider = &scriptedProvider{replies: []scriptedReply{
+		{msg: "- a.go: rename", usage: &ai.Usage{PromptTokens: 900, CompletionTokens: 20}},
+		{msg: "refactor: rename the flag", usage: &ai.Usage{PromptTokens: 1000, CompletionTokens: 12}},
+	}}
+	for range 2 {
+		if _, err := p.call(context.Background(), ai.PromptInput{}, "prompt", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := p.usageBadge(), "1932 tokens (1900 in, 32 out)"; got != want {
+		t.Errorf("usageBadge() = %q, want %q", got, want)
+	}
+}
+
+func TestPrintJSONResult(t *testing.T) {
+	output := outputJSON
+	p := NewPipeline(dependencies{output: &output}, &generateOptions{})
+	p.status = &git.Status{}
+	p.providerName, p.modelName = ai.ProviderGroq, "unlisted-model"
+	p.provider = &scriptedProvider{replies: []scriptedReply{
+		{msg: "refactor: rename the flag", usage: &ai.Usage{PromptTokens: 1000, CompletionTokens: 12}},
+	}}
+	msg, err := p.call(context.Background(), ai.PromptInput{}, "prompt", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.commitMsg = msg
+
+	out, err := captureStdout(t, func() error { return p.print(context.Background()) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got runResult
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("not JSON: %q: %v", out, err)
+	}
+	want := usageReport{PromptTokens: 1000, CompletionTokens: 12, TotalTokens: 1012}
+	if got.Message != "refactor: rename the flag" || got.Provider != ai.ProviderGroq || got.Model != "unlisted-model" || got.Usage == nil || *got.Usage != want {
+		t.Errorf("result = %+v with usage %+v", got, got.Usage)
+	}
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/razobeckett/goco/internal/config"
+	"github.com/razobeckett/goco/internal/git"
+	"github.com/spf13/cobra"
+)
+
+type verifyInstallOptions struct {
+	install bool
+	check   bool
+}
+
+func newVerifyInstallCmd(deps dependencies) *cobra.Command {
+	opts := &verifyInstallOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "verify-install [repository...]",
+		Short:   "Check and update goco's git hooks in one or more repositories",
+		Long:    "Check that the prepare-commit-msg and pre-push hooks are installed in each repository (default: the current one) and match this goco's version marker. Outdated goco hooks are reinstalled; missing ones are installed with --install. Hooks goco did not write are reported and never overwritten.",
+		GroupID: "tools",
+		Example: "  goco verify-install --install\n  goco verify-install ~/src/api ~/src/web\n  goco verify-install --check ~/src/*",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				args = []string{"."}
+			}
+			return runVerifyInstall(cmd.Context(), deps, opts, args)
+		},
+	}
+
+	fs := cmd.Flags()
+	fs.BoolVar(&opts.install, "install", false, "Also install hooks that are missing")
+	fs.BoolVar(&opts.check, "check", false, "Only report; exit with an error if any hook is missing or outdated")
+	return cmd
+}
+
+func runVerifyInstall(ctx context.Context, deps dependencies, opts *verifyInstallOptions, dirs []string) error {
+	if opts.check && opts.install {
+		return fmt.Errorf("--check and --install cannot be combined")
+	}
+	if !opts.check {
+		if err := deps.requireWritable("installing hooks"); err != nil {
+			return fmt.Errorf("%w; add --check to only report them", err)
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "REPOSITORY\tHOOK\tSTATUS")
+	var problems int
+	for _, dir := range dirs {
+		repo := deps.repo
+		if dir != "." {
+			repo = deps.repo.At(config.ExpandHome(dir))
+		}
+		root, err := repo.Root(ctx)
+		if err != nil {
+			fmt.Fprintf(w, "%s\t-\t%v\n", dir, err)
+			problems++
+			continue
+		}
+
+		hooks, err := repo.Hooks(ctx)
+		if err != nil {
+			fmt.Fprintf(w, "%s\t-\t%v\n", filepath.Base(root), err)
+			problems++
+			continue
+		}
+		for _, h := range hooks {
+			status, ok := verifyHook(h, opts)
+			if !ok {
+				problems++
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", filepath.Base(root), h.Name, status)
+		}
+	}
+	w.Flush()
+
+	if problems > 0 {
+		return fmt.Errorf("%d hook(s) need attention", problems)
+	}
+	return nil
+}
+
+// verifyHook brings h up to date as opts allow and describes the outcome.
+func verifyHook(h git.Hook, opts *verifyInstallOptions) (string, bool) {
+	switch {
+	case h.State == git.HookCurrent:
+		return h.State.String(), true
+	case h.State == git.HookForeign:
+		return h.State.String() + " (left untouched)", false
+	case opts.check, h.State == git.HookMissing && !opts.install:
+		return h.State.String(), false
+	}
+
+	if err := git.InstallHook(h); err != nil {
+		return err.Error(), false
+	}
+	if h.State == git.HookOutdated {
+		return fmt.Sprintf("updated v%d → v%d", h.Version, git.HookVersion), true
+	}
+	return "installed", true
+}
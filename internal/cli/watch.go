@@ -0,0 +1,354 @@
+package cli
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/razobeckett/goco/internal/ai"
+	"github.com/razobeckett/goco/internal/git"
+	"github.com/razobeckett/goco/internal/state"
+	"github.com/spf13/cobra"
+)
+
+type watchOptions struct {
+	generate generateOptions
+	debounce time.Duration
+}
+
+func newWatchCmd(deps dependencies) *cobra.Command {
+	opts := &watchOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "watch",
+		Short:   "Keep a draft commit message up to date while you work",
+		Long:    "Watch the working tree and redraft the commit message once your edits settle. Drafts come from the local classifier used by --fast-path, so watching costs no requests; press g to ask the provider for the current diff. The last draft is printed when you quit.",
+		GroupID: "main",
+		Args:    cobra.NoArgs,
+		Example: "  goco watch\n  goco watch --staged\n  goco watch --debounce 1s",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runWatch(cmd.Context(), deps, opts)
+		},
+	}
+
+	fs := cmd.Flags()
+	fs.StringVarP(&opts.generate.provider, "provider", "p", "", providerUsage("AI provider to ask on demand"))
+	fs.StringVarP(&opts.generate.apiKey, "api-key", "k", "", "API key for the selected provider")
+	fs.StringVarP(&opts.generate.model, "model", "m", "", "Model to use (defaults to the provider's recommended model)")
+	fs.BoolVarP(&opts.generate.staged, "staged", "s", false, "Draft from staged changes instead of the working tree diff")
+	fs.DurationVar(&opts.debounce, "debounce", 500*time.Millisecond, "How long the working tree must go without changes before it is redrafted")
+	return cmd
+}
+
+func runWatch(ctx context.Context, deps dependencies, opts *watchOptions) error {
+	if opts.debounce < 0 {
+		return fmt.Errorf("--debounce must not be negative")
+	}
+	if reason := tuiUnavailable(); !stdinIsTerminal() || reason != "" {
+		return fmt.Errorf("goco watch needs a terminal it can draw in (%s); use goco status or goco generate --print in scripts", cmp.Or(reason, "input is not a terminal"))
+	}
+	// Watching only ever drafts locally; the provider is asked on demand.
+	opts.generate.fastPath = true
+
+	p := NewPipeline(deps, &opts.generate)
+	return p.run(ctx, "goco.watch", []pipelineStage{
+		{"resolve", p.resolve},
+		{"watch", func(ctx context.Context) error {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			changes, err := watchTree(ctx, p.deps.repo, p.root, opts.debounce)
+			if err != nil {
+				return err
+			}
+			model, err := runProgram(newWatchModel(ctx, p, changes), tea.WithContext(ctx))
+			if err != nil {
+				return err
+			}
+			if m, ok := model.(watchModel); ok && m.draft != "" {
+				fmt.Println(m.draft)
+			}
+			return nil
+		}},
+	})
+}
+
+// watchSnapshot is the repository as of one poll.
+type watchSnapshot struct {
+	at        time.Time
+	status    *git.Status
+	diff      string
+	recentLog string
+	err       error
+}
+
+// watchChangeMsg reports that the tree changed and then went quiet.
+type watchChangeMsg struct{}
+
+// watchLocalMsg carries the blame context for a snapshot to draft locally.
+type watchLocalMsg struct {
+	snap    watchSnapshot
+	history string
+}
+
+// watchDraftMsg carries the provider's message for the drafted diff.
+type watchDraftMsg struct {
+	msg string
+	err error
+}
+
+// watchModel reads the repository whenever changes reports that the tree
+// has settled, and redrafts when the diff changed. Git runs only in
+// commands; the pipeline is written from Update, and not while a command
+// drafts from it.
+type watchModel struct {
+	ctx     context.Context
+	p       *Pipeline
+	changes <-chan struct{}
+	help    help.Model
+	keys    watchKeyMap
+
+	// polled is the latest diff; drafted is the diff the draft describes.
+	polled  watchSnapshot
+	drafted *string
+	summary statusSummary
+
+	draft      string
+	source     string
+	draftedAt  time.Time
+	drafting   bool
+	generating bool
+	// pollErr is from reading the repository; err from asking the provider.
+	pollErr error
+	err     error
+}
+
+type watchKeyMap struct {
+	Generate key.Binding
+	Quit     key.Binding
+}
+
+func (k watchKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Generate, k.Quit}
+}
+
+func (k watchKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+func newWatchModel(ctx context.Context, p *Pipeline, changes <-chan struct{}) watchModel {
+	h := help.New()
+	h.Styles.ShortKey = promptDescriptionStyle
+	h.Styles.ShortDesc = promptDescriptionStyle
+	h.Styles.ShortSeparator = promptDescriptionStyle
+
+	return watchModel{
+		ctx:     ctx,
+		p:       p,
+		changes: changes,
+		help:    h,
+		keys: watchKeyMap{
+			Generate: key.NewBinding(key.WithKeys("g"), key.WithHelp("g", "ask the provider")),
+			Quit:     key.NewBinding(key.WithKeys("q", "esc", "ctrl+c"), key.WithHelp("q", "quit")),
+		},
+	}
+}
+
+func (m watchModel) Init() tea.Cmd {
+	return tea.Batch(m.poll, m.waitForChange)
+}
+
+// waitForChange blocks until the tree has changed and settled.
+func (m watchModel) waitForChange() tea.Msg {
+	select {
+	case <-m.changes:
+		return watchChangeMsg{}
+	case <-m.ctx.Done():
+		return nil
+	}
+}
+
+// poll reads the repository. It runs off the event loop and leaves the
+// pipeline alone.
+func (m watchModel) poll() tea.Msg {
+	snap := watchSnapshot{at: time.Now()}
+	snap.status, snap.err = m.p.deps.repo.Status(m.ctx)
+	if snap.err != nil {
+		return snap
+	}
+	snap.diff, _, snap.err = m.p.readDiff(m.ctx, snap.status)
+	if snap.err == nil {
+		snap.recentLog, _ = m.p.deps.repo.RecentLog(m.ctx, 3)
+	}
+	return snap
+}
+
+func (m watchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, m.keys.Generate):
+			return m.generate()
+		}
+	case watchChangeMsg:
+		return m, tea.Batch(m.poll, m.waitForChange)
+	case watchSnapshot:
+		m.pollErr = msg.err
+		if msg.err != nil {
+			return m, nil
+		}
+		m.polled = msg
+		m.summary = statusSummary{}
+		m.summary.countEntries(msg.status)
+		return m, m.redraft()
+	case watchLocalMsg:
+		m.drafting = false
+		m.draftLocally(msg)
+		return m, m.redraft()
+	case watchDraftMsg:
+		m.generating = false
+		m.err = msg.err
+		if msg.err == nil {
+			m.draft = msg.msg
+			m.source = m.p.providerLabel() + " " + m.p.modelName
+			m.draftedAt = time.Now()
+			m.publish()
+		}
+		return m, m.redraft()
+	}
+	return m, nil
+}
+
+// redraft starts drafting the latest poll when its diff is not the drafted
+// one. A diff that changes while a draft is in flight is drafted after it.
+func (m *watchModel) redraft() tea.Cmd {
+	snap := m.polled
+	if snap.status == nil || m.drafting || m.generating || (m.drafted != nil && *m.drafted == snap.diff) {
+		return nil
+	}
+
+	m.drafted = &snap.diff
+	m.draft, m.source = "", ""
+	if strings.TrimSpace(snap.diff) == "" {
+		m.publish()
+		return nil
+	}
+	// Blame runs git for every changed file, so it runs off the event loop.
+	m.drafting = true
+	p, ctx := m.p, m.ctx
+	return func() tea.Msg {
+		return watchLocalMsg{snap: snap, history: p.blameHistory(ctx, snap.status, snap.diff)}
+	}
+}
+
+// draftLocally drafts a snapshot with the local classifier.
+func (m *watchModel) draftLocally(msg watchLocalMsg) {
+	defer m.publish()
+	p := m.p
+	p.status = msg.snap.status
+	p.diff = msg.snap.diff
+	p.recentLog = msg.snap.recentLog
+	p.history = msg.history
+	if text, kind, ok := p.localMessage(); ok {
+		m.draft = p.repairMessage(text)
+		m.source = "local, " + kind + " change"
+		m.draftedAt = msg.snap.at
+	}
+}
+
+// publish saves the draft for `goco prompt-segment`, or clears it when there
+// is none.
+func (m *watchModel) publish() {
+	var err error
+	if m.draft == "" {
+		err = state.ClearDraft(m.p.root)
+	} else {
+		err = state.SaveDraft(state.Draft{Time: m.draftedAt, Root: m.p.root, Message: m.draft})
+	}
+	if err != nil {
+		m.err = err
+	}
+}
+
+// generate asks the provider for a message for the drafted diff, without a
+// spinner or retries: the pane shows progress, and g asks again.
+func (m watchModel) generate() (tea.Model, tea.Cmd) {
+	if m.generating || m.drafting || m.drafted == nil || strings.TrimSpace(*m.drafted) == "" {
+		return m, nil
+	}
+	p := m.p
+	if p.provider == nil && ai.NeedsAPIKey(p.providerName, p.providerOpts) && p.apiKeyFlag == "" && providerAPIKey(p.cfg, p.providerName) == "" {
+		m.err = fmt.Errorf("set %s or pass --api-key to ask %s", p.cfg.APIKeyEnv(p.providerName), providerDisplayName(p.providerName))
+		return m, nil
+	}
+	diff := p.promptDiff()
+	if p.chunking.enabled && len(diff) > p.chunking.size {
+		m.err = fmt.Errorf("the diff is too large to draft in one request; run goco generate to summarize it in parts")
+		return m, nil
+	}
+
+	m.generating, m.err = true, nil
+	return m, func() tea.Msg {
+		input := p.promptInput(diff)
+		prompt, err := p.preparePrompt(m.ctx, input)
+		if err != nil {
+			return watchDraftMsg{err: err}
+		}
+		msg, err := p.send(m.ctx, input, prompt, "")
+		if err == nil {
+			msg, err = ai.CleanOutput(msg)
+		}
+		if err == nil && strings.TrimSpace(msg) == "" {
+			err = fmt.Errorf("AI provider returned an empty commit message")
+		}
+		if err != nil {
+			return watchDraftMsg{err: err}
+		}
+		return watchDraftMsg{msg: p.repairMessage(msg)}
+	}
+}
+
+func (m watchModel) View() string {
+	source := "working tree"
+	if m.p.opts.staged {
+		source = "staged changes"
+	}
+	lines := []string{promptTitleStyle.Render("Watching the " + source)}
+	if m.drafted != nil {
+		lines = append(lines, promptDescriptionStyle.Render(m.summary.changes()))
+	}
+	lines = append(lines, "")
+
+	switch {
+	case m.drafted == nil:
+		lines = append(lines, promptDescriptionStyle.Render("Reading the repository..."))
+	case strings.TrimSpace(*m.drafted) == "":
+		lines = append(lines, promptDescriptionStyle.Render("No changes to describe yet."))
+	case m.drafting:
+		lines = append(lines, promptDescriptionStyle.Render("Drafting..."))
+	case m.draft != "":
+		lines = append(lines,
+			promptDescriptionStyle.Render(fmt.Sprintf("Draft (%s, %s):", m.source, m.draftedAt.Format(time.TimeOnly))),
+			lipgloss.NewStyle().Foreground(lipgloss.Color(creamGleam)).Render(m.draft),
+		)
+	default:
+		lines = append(lines, promptDescriptionStyle.Render("The local classifier does not recognize this change; press g to ask the provider."))
+	}
+
+	if m.generating {
+		lines = append(lines, "", noteStyle.Render("Asking "+providerDisplayName(m.p.providerName)+"..."))
+	}
+	for _, err := range []error{m.pollErr, m.err} {
+		if err != nil {
+			lines = append(lines, "", noteStyle.Render("Error: "+err.Error()))
+		}
+	}
+	return strings.Join(append(lines, "", m.help.ShortHelpView(m.keys.ShortHelp())), "\n")
+}
@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/razobeckett/goco/internal/config"
+	"github.com/razobeckett/goco/internal/git"
+	"github.com/razobeckett/goco/internal/state"
+)
+
+func newTestWatchModel(t *testing.T, dir string) watchModel {
+	t.Helper()
+	p := NewPipeline(dependencies{configLoader: config.NewLoader(), repo: git.NewRepository(dir)}, &generateOptions{fastPath: true})
+	if err := p.resolve(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return newWatchModel(context.Background(), p, nil)
+}
+
+// pollNow reads the repository as after a change, and runs the local draft
+// it starts.
+func pollNow(t *testing.T, m watchModel) watchModel {
+	t.Helper()
+	snap, ok := m.poll().(watchSnapshot)
+	if !ok || snap.err != nil {
+		t.Fatalf("poll = %+v", snap)
+	}
+	next, cmd := m.Update(snap)
+	m = next.(watchModel)
+	if cmd == nil {
+		return m
+	}
+	local, ok := cmd().(watchLocalMsg)
+	if !ok || !m.drafting || !strings.Contains(m.View(), "Drafting") {
+		t.Fatal("the snapshot did not start a local draft")
+	}
+	next, _ = m.Update(local)
+	return next.(watchModel)
+}
+
+func writeReadme(t *testing.T, dir, text string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatchRedraft(t *testing.T) {
+	dir := statusEnv(t)
+	writeReadme(t, dir, "# Project\n")
+	runGit(t, dir, "add", "README.md")
+	runGit(t, dir, "-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "-qm", "docs")
+
+	m := newTestWatchModel(t, dir)
+	m = pollNow(t, m)
+	if m.drafted == nil || m.draft != "" || !strings.Contains(m.View(), "No changes") {
+		t.Fatalf("clean tree: drafted %v, draft %q", m.drafted, m.draft)
+	}
+
+	writeReadme(t, dir, "# Project\n\nUsage.\n")
+	m = pollNow(t, m)
+	if m.summary.Unstaged != 1 || !strings.HasPrefix(m.draft, "docs") || !strings.Contains(m.source, "local") {
+		t.Fatalf("draft = %q from %q, want a local docs message", m.draft, m.source)
+	}
+	if saved, err := state.LoadDraft(m.p.root); err != nil || saved.Message != m.draft {
+		t.Fatalf("saved draft = %+v, %v; want %q", saved, err, m.draft)
+	}
+
+	// A change the classifier does not recognize leaves the draft to the provider.
+	modify(t, dir)
+	m = pollNow(t, m)
+	if m.draft != "" || !strings.Contains(m.View(), "press g") {
+		t.Fatalf("mixed change drafted locally as %q", m.draft)
+	}
+	if _, err := state.LoadDraft(m.p.root); !errors.Is(err, state.ErrNoDraft) {
+		t.Fatalf("stale draft kept: %v", err)
+	}
+}
+
+func TestWatchGenerate(t *testing.T) {
+	tests := []struct {
+		name      string
+		provider  bool
+		wantDraft string
+		wantErr   string
+	}{
+		{name: "asks the provider", provider: true, wantDraft: "feat: add two"},
+		{name: "no API key", wantErr: "set GOCO_GEMINI_KEY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := statusEnv(t)
+			modify(t, dir)
+			m := newTestWatchModel(t, dir)
+			provider := &scriptedProvider{replies: []scriptedReply{{msg: "feat: add two"}}}
+			if tt.provider {
+				m.p.provider = provider
+			}
+			m = pollNow(t, m)
+
+			next, cmd := m.generate()
+			m = next.(watchModel)
+			if tt.wantErr != "" {
+				if m.err == nil || !strings.Contains(m.err.Error(), tt.wantErr) || cmd != nil {
+					t.Fatalf("err = %v, want %q and no request", m.err, tt.wantErr)
+				}
+				return
+			}
+			if !m.generating || cmd == nil {
+				t.Fatal("generate did not start a request")
+			}
+			var model tea.Model
+			model, _ = m.Update(cmd())
+			m = model.(watchModel)
+			if m.generating || m.err != nil || m.draft != tt.wantDraft || provider.calls != 1 {
+				t.Fatalf("draft %q, err %v, %d calls; want %q from one call", m.draft, m.err, provider.calls, tt.wantDraft)
+			}
+		})
+	}
+}
+
+func TestWatchDraftInFlight(t *testing.T) {
+	dir := statusEnv(t)
+	m := newTestWatchModel(t, dir)
+	m = pollNow(t, m)
+
+	// A change read while a draft is in flight is drafted once it finishes.
+	modify(t, dir)
+	next, cmd := m.Update(m.poll())
+	m = next.(watchModel)
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("one\ntwo\nthree\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	next, again := m.Update(m.poll())
+	m = next.(watchModel)
+	if again != nil {
+		t.Fatal("a second draft started while one was in flight")
+	}
+	next, cmd = m.Update(cmd())
+	m = next.(watchModel)
+	if cmd == nil || *m.drafted != m.polled.diff {
+		t.Fatal("the newer diff was not drafted after the first")
+	}
+}
+
+func TestWatchTree(t *testing.T) {
+	dir := statusEnv(t)
+	if err := os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("build/\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "build"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes, err := watchTree(ctx, git.NewRepository(dir), dir, 50*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A burst of writes is reported once it settles.
+	for i := range 5 {
+		writeReadme(t, dir, strings.Repeat("#", i+1)+" Project\n")
+	}
+	select {
+	case <-changes:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change reported")
+	}
+	select {
+	case <-changes:
+		t.Fatal("one burst was reported twice")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// Ignored directories are not watched.
+	if err := os.WriteFile(filepath.Join(dir, "build", "out.o"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-changes:
+		t.Fatal("a write to an ignored directory was reported")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
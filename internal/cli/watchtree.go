@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/razobeckett/goco/internal/git"
+)
+
+// treeWatcher signals on changes once the working tree and the git
+// directory have been quiet for the debounce, so a burst of saves, a
+// checkout, or a commit is read once.
+type treeWatcher struct {
+	w       *fsnotify.Watcher
+	repo    *git.Repository
+	gitDir  string
+	ignored map[string]bool
+	changes chan struct{}
+}
+
+// watchTree watches the directories under root that git does not ignore,
+// and the git directory itself for staging and commits. It stops when ctx
+// is done.
+func watchTree(ctx context.Context, repo *git.Repository, root string, debounce time.Duration) (<-chan struct{}, error) {
+	gitDir, err := repo.GitDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ignored, err := repo.IgnoredDirs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("watch the working tree: %w", err)
+	}
+	tw := &treeWatcher{w: w, repo: repo, gitDir: gitDir, ignored: map[string]bool{}, changes: make(chan struct{}, 1)}
+	for _, dir := range ignored {
+		tw.ignored[filepath.Join(root, filepath.FromSlash(dir))] = true
+	}
+	// Only the top of the git directory: index and HEAD are rewritten when
+	// files are staged or committed.
+	if err := tw.addTree(root); err == nil {
+		err = w.Add(gitDir)
+	}
+	if err != nil {
+		w.Close()
+		return nil, fmt.Errorf("watch the working tree: %w (on Linux, raise fs.inotify.max_user_watches)", err)
+	}
+	go tw.run(ctx, debounce)
+	return tw.changes, nil
+}
+
+func (tw *treeWatcher) addTree(dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// A directory removed during the walk has nothing to watch.
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if d.Name() == ".git" || tw.ignored[path] {
+			return filepath.SkipDir
+		}
+		return tw.w.Add(path)
+	})
+}
+
+func (tw *treeWatcher) run(ctx context.Context, debounce time.Duration) {
+	defer restoreOnPanic()
+	defer tw.w.Close()
+	var quiet <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-tw.w.Events:
+			if !ok {
+				return
+			}
+			// Lock files come and go with every git command, including the
+			// ones goco runs to read the tree.
+			if strings.HasSuffix(event.Name, ".lock") {
+				continue
+			}
+			if event.Has(fsnotify.Create) && filepath.Dir(event.Name) != tw.gitDir {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() && !tw.repo.Ignored(ctx, event.Name) {
+					_ = tw.addTree(event.Name)
+				}
+			}
+			quiet = time.After(debounce)
+		case _, ok := <-tw.w.Errors:
+			if !ok {
+				return
+			}
+			// Events were lost; read the tree again to be sure.
+			quiet = time.After(debounce)
+		case <-quiet:
+			quiet = nil
+			select {
+			case tw.changes <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
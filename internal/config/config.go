@@ -1,36 +1,501 @@
 package config
 
 import (
+	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
 )
 
 const (
-	DefaultGeminiAPIKeyEnv = "GOCO_GEMINI_KEY"
-	DefaultGroqAPIKeyEnv   = "GOCO_GROQ_KEY"
-	DefaultProvider        = "gemini"
+	DefaultGeminiAPIKeyEnv     = "GOCO_GEMINI_KEY"
+	DefaultGroqAPIKeyEnv       = "GOCO_GROQ_KEY"
+	DefaultOpenRouterAPIKeyEnv = "GOCO_OPENROUTER_KEY"
+	DefaultMistralAPIKeyEnv    = "GOCO_MISTRAL_KEY"
+	DefaultAzureAPIKeyEnv      = "GOCO_AZURE_OPENAI_KEY"
+	DefaultGrokAPIKeyEnv       = "GOCO_GROK_KEY"
+	DefaultDeepSeekAPIKeyEnv   = "GOCO_DEEPSEEK_KEY"
+	DefaultCohereAPIKeyEnv     = "GOCO_COHERE_KEY"
+	DefaultGitHubAPIKeyEnv     = "GOCO_GITHUB_MODELS_KEY"
+	DefaultTogetherAPIKeyEnv   = "GOCO_TOGETHER_KEY"
+	DefaultProvider            = "gemini"
 )
 
 type General struct {
-	GeminiAPIKeyEnv string `toml:"api_key_gemini_env_variable"`
-	GroqAPIKeyEnv   string `toml:"api_key_groq_env_variable"`
-	DefaultProvider string `toml:"default_provider"`
+	GeminiAPIKeyEnv     string `toml:"api_key_gemini_env_variable"`
+	GroqAPIKeyEnv       string `toml:"api_key_groq_env_variable"`
+	OpenRouterAPIKeyEnv string `toml:"api_key_openrouter_env_variable"`
+	MistralAPIKeyEnv    string `toml:"api_key_mistral_env_variable"`
+	AzureAPIKeyEnv      string `toml:"api_key_azure_env_variable"`
+	GrokAPIKeyEnv       string `toml:"api_key_grok_env_variable"`
+	DeepSeekAPIKeyEnv   string `toml:"api_key_deepseek_env_variable"`
+	CohereAPIKeyEnv     string `toml:"api_key_cohere_env_variable"`
+	GitHubAPIKeyEnv     string `toml:"api_key_github_env_variable"`
+	TogetherAPIKeyEnv   string `toml:"api_key_together_env_variable"`
+	DefaultProvider     string `toml:"default_provider"`
+	// DefaultModel is used with the default provider when no --model is
+	// given; empty means the provider's recommended model.
+	DefaultModel string `toml:"default_model"`
+	// ConfigRemoteURL points at an org-managed config merged beneath this
+	// file. It must be signed with the key in ConfigRemotePublicKey.
+	ConfigRemoteURL       string `toml:"config_remote_url"`
+	ConfigRemotePublicKey string `toml:"config_remote_public_key"`
+	// ConfigRemoteTTL is how long the fetched config is cached, e.g. "6h".
+	ConfigRemoteTTL string `toml:"config_remote_ttl"`
+	// DefaultCommand is what a bare `goco` runs, e.g. "generate"; empty
+	// prints help.
+	DefaultCommand string `toml:"default_command"`
+}
+
+// Prompt controls the text goco sends to providers.
+type Prompt struct {
+	// Spec replaces the bundled Conventional Commits rules with inline text.
+	Spec string `toml:"spec"`
+	// SpecFile points at a local file holding the rules; it wins over Spec.
+	SpecFile string `toml:"spec_file"`
+	// TemplateFile points at a Go text/template that replaces the whole prompt.
+	TemplateFile string `toml:"template_file"`
+	// SaveLastPrompt keeps the last prompt and response for `goco last-prompt show`.
+	SaveLastPrompt bool `toml:"save_last_prompt"`
+	// BlameContext adds the commits that last touched the changed lines.
+	BlameContext bool `toml:"blame_context"`
+	// MinimizeDiff trims context lines, long additions, and vendored files
+	// from the diff before it is sent.
+	MinimizeDiff bool `toml:"minimize_diff"`
+	// MinimizeContext is how many unchanged lines a minimized diff keeps
+	// around each change; zero keeps the default of 1.
+	MinimizeContext int `toml:"minimize_context"`
+	// MinimizeAddedLines is how many lines of each block of additions a
+	// minimized diff keeps; zero keeps the default of 40.
+	MinimizeAddedLines int `toml:"minimize_added_lines"`
+	// ChunkDiff summarizes a diff larger than ChunkSize in parts, one
+	// request per part, and writes the message from the summaries.
+	ChunkDiff bool `toml:"chunk_diff"`
+	// ChunkSize is the most bytes of diff in one part; zero means 64 KiB.
+	ChunkSize int `toml:"chunk_size"`
+	// ChunkConcurrency is how many parts are summarized at once; zero means 4.
+	ChunkConcurrency int `toml:"chunk_concurrency"`
+	// ChunkRateLimits caps part requests per minute by provider name, e.g.
+	// groq = 30.
+	ChunkRateLimits map[string]int `toml:"chunk_rate_limits"`
+	// FastPath writes messages for trivial changes (renames, version bumps,
+	// dependency updates, docs- or test-only changes) without the provider.
+	FastPath bool `toml:"fast_path"`
+	// TwoPass first asks for a file-by-file outline of the change, then
+	// writes the message from the outline.
+	TwoPass bool `toml:"two_pass"`
+	// Refine sends each generated message back to the model with the diff
+	// to critique and correct.
+	Refine bool `toml:"refine"`
+	// Overflow is what happens when the prompt does not fit the model's
+	// context window: "truncate" (the default) drops whole files from the
+	// end of the diff, "chunk" summarizes the diff in parts, "stat-only"
+	// sends only per-file line counts, and "error" stops.
+	Overflow string `toml:"overflow"`
+	// ContextTokens overrides the model's context window, e.g. to stay under
+	// a per-request token limit; zero looks it up.
+	ContextTokens int `toml:"context_tokens"`
+}
+
+// Overflow strategies for [Prompt] overflow.
+const (
+	OverflowTruncate = "truncate"
+	OverflowChunk    = "chunk"
+	OverflowStatOnly = "stat-only"
+	OverflowError    = "error"
+)
+
+// OverflowStrategy returns the configured overflow strategy.
+func (p Prompt) OverflowStrategy() (string, error) {
+	switch p.Overflow {
+	case "":
+		return OverflowTruncate, nil
+	case OverflowTruncate, OverflowChunk, OverflowStatOnly, OverflowError:
+		return p.Overflow, nil
+	default:
+		return "", fmt.Errorf("overflow must be \"truncate\", \"chunk\", \"stat-only\", or \"error\", got %q", p.Overflow)
+	}
+}
+
+// Telemetry configures opt-in metrics export. Leaving Sink empty disables it.
+type Telemetry struct {
+	// Sink is "statsd" or "otlp".
+	Sink     string `toml:"sink"`
+	Endpoint string `toml:"endpoint"`
+	Prefix   string `toml:"prefix"`
+	// TracesEndpoint enables OTLP/HTTP span export for git and provider calls.
+	TracesEndpoint string `toml:"traces_endpoint"`
+}
+
+// Audit configures the append-only log of data sent to AI providers.
+type Audit struct {
+	Enabled bool `toml:"enabled"`
+	// Path overrides the default $XDG_STATE_HOME/goco/audit.jsonl location;
+	// it is ignored unless Enabled is set.
+	Path string `toml:"path"`
+}
+
+// DefaultNotifyAfter is how long generation takes before goco notifies,
+// unless [Notify] says otherwise.
+const DefaultNotifyAfter = 10 * time.Second
+
+// Notify configures desktop notifications for slow generations.
+type Notify struct {
+	// AfterSeconds is how long generating a message must take before goco
+	// sends a desktop notification when it finishes; zero means 10, and a
+	// negative value turns notifications off.
+	AfterSeconds int `toml:"after_seconds"`
+}
+
+// After returns the notification threshold, or 0 when notifications are off.
+func (n Notify) After() time.Duration {
+	switch {
+	case n.AfterSeconds < 0:
+		return 0
+	case n.AfterSeconds == 0:
+		return DefaultNotifyAfter
+	default:
+		return time.Duration(n.AfterSeconds) * time.Second
+	}
+}
+
+// DefaultHTTPRetries is how often a failed request is retried at the
+// transport level, unless [HTTP] says otherwise.
+const DefaultHTTPRetries = 2
+
+// HTTP configures retries below the provider SDKs. They are separate from
+// the retries of a failed generation, and never resend a generation the
+// provider may have received.
+type HTTP struct {
+	// ConnectRetries is how often a request whose connection failed is sent
+	// again; zero means 2, and a negative value never retries.
+	ConnectRetries int `toml:"connect_retries"`
+	// ListRetries is how often a model list or models.dev fetch is sent
+	// again after a dropped connection or a 429 or 5xx response; zero means
+	// 2, and a negative value never retries.
+	ListRetries int `toml:"list_retries"`
+}
+
+// Connect returns the connect retries.
+func (h HTTP) Connect() int {
+	return retries(h.ConnectRetries)
+}
+
+// List returns the model list retries.
+func (h HTTP) List() int {
+	return retries(h.ListRetries)
+}
+
+func retries(n int) int {
+	switch {
+	case n < 0:
+		return 0
+	case n == 0:
+		return DefaultHTTPRetries
+	default:
+		return n
+	}
+}
+
+// Quality configures the local quality score shown with each message.
+type Quality struct {
+	// Threshold is the score, from 0 to 100, below which goco regenerates
+	// the message once with stricter instructions. Zero, the default, never
+	// regenerates, so no request is sent that the user did not ask for.
+	Threshold int `toml:"threshold"`
+}
+
+// MinScore returns the regeneration threshold, or 0 when it is off.
+func (q Quality) MinScore() int {
+	return max(q.Threshold, 0)
+}
+
+// Gerrit adapts commits for Gerrit code review.
+type Gerrit struct {
+	// ChangeID appends a Change-Id trailer to every commit goco creates.
+	ChangeID bool `toml:"change_id"`
+}
+
+// Message sets patterns every commit message must match.
+type Message struct {
+	// SubjectPatterns are regular expressions the subject line must each match.
+	SubjectPatterns []string `toml:"subject_patterns"`
+	// FooterPatterns are regular expressions that must each match a line of
+	// the message's closing paragraph.
+	FooterPatterns []string `toml:"footer_patterns"`
+	// Mood is "imperative" (the default) to ask for and repair imperative
+	// subjects, or "any" to leave the subject's mood alone.
+	Mood string `toml:"mood"`
+	// Footer is a Go text/template added as the last paragraph of every
+	// message, e.g. "Refs: {{.Env.TICKET}}"; it can use the prompt
+	// template variables. An empty rendering adds nothing.
+	Footer string `toml:"footer"`
+}
+
+// Imperative reports whether subjects must use the imperative mood.
+func (m Message) Imperative() (bool, error) {
+	switch m.Mood {
+	case "", "imperative":
+		return true, nil
+	case "any":
+		return false, nil
+	default:
+		return false, fmt.Errorf("mood must be \"imperative\" or \"any\", got %q", m.Mood)
+	}
+}
+
+// Commit guards what a single commit holds.
+type Commit struct {
+	// MaxFiles is how many files a commit may hold before goco asks which
+	// of them to commit; zero never asks.
+	MaxFiles int `toml:"max_files"`
+}
+
+// Azure locates the Azure OpenAI resource the azure provider sends
+// requests to. Azure serves models through named deployments rather than
+// model names.
+type Azure struct {
+	// Endpoint is the resource URL, e.g. https://my-resource.openai.azure.com.
+	Endpoint string `toml:"endpoint"`
+	// Deployment is used when no --model is given.
+	Deployment string `toml:"deployment"`
+	// APIVersion is the api-version requests carry; empty means goco's
+	// default.
+	APIVersion string `toml:"api_version"`
+	// Deployments maps the names --model accepts to deployment names, e.g.
+	// "gpt-4o" = "prod-gpt4o". Other names are taken as deployment names.
+	Deployments map[string]string `toml:"deployments"`
+}
+
+// Gemini chooses how the gemini provider reaches Gemini. Corporate Google
+// Cloud accounts can use Vertex AI, which authenticates with Application
+// Default Credentials instead of an API key.
+type Gemini struct {
+	// Backend is "gemini-api" (the default) or "vertex-ai".
+	Backend string `toml:"backend"`
+	// Project and Location are the Google Cloud project and region Vertex
+	// AI serves from; empty means GOOGLE_CLOUD_PROJECT and
+	// GOOGLE_CLOUD_LOCATION.
+	Project  string `toml:"project"`
+	Location string `toml:"location"`
+}
+
+// Bedrock chooses the AWS region and credentials profile the bedrock
+// provider uses. Credentials come from the AWS credential chain, never from
+// goco's config.
+type Bedrock struct {
+	// Region is e.g. us-west-2; empty means AWS_REGION or the profile's.
+	Region string `toml:"region"`
+	// Profile names a profile of ~/.aws/config; empty means AWS_PROFILE or
+	// the environment's credentials.
+	Profile string `toml:"profile"`
+}
+
+// Git controls how goco runs git. It describes this machine, so only the
+// local config file sets it; a remote config's [Git] is ignored.
+type Git struct {
+	// Binary is the git executable; "git" from PATH when empty.
+	Binary string `toml:"binary"`
+	// ExtraArgs go before every git subcommand, e.g. ["-c", "commit.gpgsign=false"].
+	ExtraArgs []string `toml:"extra_args"`
+	// Sign signs every commit goco makes in this format, "ssh", "openpgp",
+	// or "x509", whatever commit.gpgsign says. Empty leaves it to git config.
+	Sign string `toml:"sign"`
+	// SigningKey is the key to sign with in place of user.signingkey, e.g.
+	// "~/.ssh/id_ed25519.pub".
+	SigningKey string `toml:"signing_key"`
+}
+
+// Args returns the arguments to put before every git subcommand:
+// ExtraArgs, then the -c overrides Sign and SigningKey ask for, so git
+// config reports what git commit will do.
+func (g Git) Args() ([]string, error) {
+	args := slices.Clone(g.ExtraArgs)
+	switch g.Sign {
+	case "":
+	case "ssh", "openpgp", "x509":
+		args = append(args, "-c", "commit.gpgsign=true", "-c", "gpg.format="+g.Sign)
+	default:
+		return g.ExtraArgs, fmt.Errorf("[Git] sign must be \"ssh\", \"openpgp\", or \"x509\", got %q", g.Sign)
+	}
+	if g.SigningKey != "" {
+		args = append(args, "-c", "user.signingkey="+ExpandHome(g.SigningKey))
+	}
+	return args, nil
+}
+
+// Terminal adapts how goco draws to the terminal. Like [Git] it describes
+// this machine, so only the local config file sets it.
+type Terminal struct {
+	// Remote is "auto" (the default) to treat SSH sessions as remote, or
+	// "on" or "off" to decide for every terminal. Remote terminals get
+	// fewer animation frames and, unless they announce 24-bit color, at
+	// most 256 colors.
+	Remote string `toml:"remote"`
+	// Colors is "auto" (the default) to detect the terminal's colors, or
+	// "truecolor", "256", "16", or "none" to set them.
+	Colors string `toml:"colors"`
+	// Theme is a theme file rebranding goco's colors, heading emoji, and
+	// spinner and heading words; theme.toml next to the config file is
+	// used when it is empty. GOCO_THEME overrides it.
+	Theme string `toml:"theme"`
+}
+
+// Settings for [Terminal] remote and colors.
+const (
+	TerminalAuto    = "auto"
+	TerminalOn      = "on"
+	TerminalOff     = "off"
+	ColorsTrueColor = "truecolor"
+	Colors256       = "256"
+	Colors16        = "16"
+	ColorsNone      = "none"
+)
+
+// IsRemote reports whether to draw for a remote terminal, given whether
+// goco detected an SSH session.
+func (t Terminal) IsRemote(detected bool) (bool, error) {
+	switch t.Remote {
+	case "", TerminalAuto:
+		return detected, nil
+	case TerminalOn:
+		return true, nil
+	case TerminalOff:
+		return false, nil
+	default:
+		return false, fmt.Errorf("[Terminal] remote must be \"auto\", \"on\", or \"off\", got %q", t.Remote)
+	}
+}
+
+// ColorSetting returns the configured colors.
+func (t Terminal) ColorSetting() (string, error) {
+	switch t.Colors {
+	case "":
+		return TerminalAuto, nil
+	case TerminalAuto, ColorsTrueColor, Colors256, Colors16, ColorsNone:
+		return t.Colors, nil
+	default:
+		return "", fmt.Errorf("[Terminal] colors must be \"auto\", \"truecolor\", \"256\", \"16\", or \"none\", got %q", t.Colors)
+	}
+}
+
+// Body controls how message bodies are laid out after generation.
+type Body struct {
+	// Style is "bullets" or "paragraphs"; empty keeps the model's layout.
+	Style string `toml:"style"`
+	// Bullet is the list marker: "-" (the default), "*", "+", or "•".
+	Bullet string `toml:"bullet"`
+	// BulletSpacing is "tight" (the default) or "loose", which puts a blank
+	// line between list items.
+	BulletSpacing string `toml:"bullet_spacing"`
+}
+
+// Issues configures fetching the ticket a branch implements.
+type Issues struct {
+	// Fetch looks up the ticket named by the current branch for every commit.
+	Fetch bool `toml:"fetch"`
+	// Tracker is "jira", "github", or "gitlab". When empty, Jira is used if
+	// JiraURL is set, otherwise the forge hosting the branch's remote.
+	Tracker string `toml:"tracker"`
+	JiraURL string `toml:"jira_url"`
+	// JiraEmail pairs with a Jira Cloud API token; leave it empty for a Data
+	// Center personal access token.
+	JiraEmail string `toml:"jira_email"`
+	// JiraProjects limits the keys read from branch names to these
+	// projects, e.g. ["PROJ", "OPS"].
+	JiraProjects []string `toml:"jira_projects"`
+}
+
+// Remote holds settings for one git remote, keyed by remote name.
+type Remote struct {
+	// ChangelogTrailer adds GitLab `Changelog:` trailers derived from the commit type.
+	ChangelogTrailer bool `toml:"changelog_trailer"`
+}
+
+// Command holds the defaults for one subcommand, keyed by its name, e.g.
+// [Commands.pr] for a larger model than commits get. Flags still win.
+type Command struct {
+	Provider string `toml:"provider"`
+	Model    string `toml:"model"`
+	// Instructions are added to the prompt in place of --custom-instructions,
+	// e.g. how much detail to go into.
+	Instructions string `toml:"instructions"`
+	// Language is the language to write in, e.g. "German".
+	Language string `toml:"language"`
+}
+
+// PromptInstructions returns the instructions the section adds to the
+// prompt, or "".
+func (c Command) PromptInstructions() string {
+	var parts []string
+	if c.Instructions != "" {
+		parts = append(parts, c.Instructions)
+	}
+	if c.Language != "" {
+		parts = append(parts, fmt.Sprintf("Write in %s.", c.Language))
+	}
+	return strings.Join(parts, "\n")
 }
 
 type Config struct {
-	General General `toml:"General"`
+	General   General   `toml:"General"`
+	Prompt    Prompt    `toml:"Prompt"`
+	Telemetry Telemetry `toml:"Telemetry"`
+	Audit     Audit     `toml:"Audit"`
+	Notify    Notify    `toml:"Notify"`
+	Quality   Quality   `toml:"Quality"`
+	HTTP      HTTP      `toml:"HTTP"`
+	Gerrit    Gerrit    `toml:"Gerrit"`
+	Issues    Issues    `toml:"Issues"`
+	Message   Message   `toml:"Message"`
+	Body      Body      `toml:"Body"`
+	Commit    Commit    `toml:"Commit"`
+	Gemini    Gemini    `toml:"Gemini"`
+	Azure     Azure     `toml:"Azure"`
+	Bedrock   Bedrock   `toml:"Bedrock"`
+	Git       Git       `toml:"Git"`
+	Terminal  Terminal  `toml:"Terminal"`
+	// Remotes holds per-remote settings, e.g. [Remotes.origin].
+	Remotes map[string]Remote `toml:"Remotes"`
+	// Forges maps self-hosted forge hosts to their kind, "github" or
+	// "gitlab", e.g. "git.example.com" = "gitlab". Other hosts are never
+	// sent a token.
+	Forges map[string]string `toml:"Forges"`
+	// TypeHints maps file patterns to likely commit types, e.g. "*.md" = "docs".
+	TypeHints map[string]string `toml:"TypeHints"`
+	// Aliases maps user-defined subcommands to the arguments they stand
+	// for, e.g. quick = "generate --yes --model fast".
+	Aliases map[string]string `toml:"alias"`
+	// Routes pick the provider, key, and model by repository path or
+	// remote, e.g. [[Route]] path = "~/work"; the first match wins.
+	Routes []Route `toml:"Route"`
+	// Commands holds per-subcommand defaults, e.g. [Commands.generate].
+	Commands map[string]Command `toml:"Commands"`
 }
 
 type Loader struct {
 	path string
+	// offline restricts the remote config to its cached copy.
+	offline bool
+	// cacheDir and http override the remote config cache and transport in tests.
+	cacheDir string
+	http     *http.Client
 }
 
 func NewLoader() *Loader {
 	return &Loader{path: configPath()}
 }
 
+// SetOffline makes Load use only the cached remote config, however old.
+func (l *Loader) SetOffline(offline bool) {
+	l.offline = offline
+}
+
 func (l *Loader) Path() string {
 	return l.path
 }
@@ -38,23 +503,161 @@ func (l *Loader) Path() string {
 func (l *Loader) Load() (*Config, error) {
 	cfg := &Config{
 		General: General{
-			GeminiAPIKeyEnv: DefaultGeminiAPIKeyEnv,
-			GroqAPIKeyEnv:   DefaultGroqAPIKeyEnv,
-			DefaultProvider: DefaultProvider,
+			GeminiAPIKeyEnv:     DefaultGeminiAPIKeyEnv,
+			GroqAPIKeyEnv:       DefaultGroqAPIKeyEnv,
+			OpenRouterAPIKeyEnv: DefaultOpenRouterAPIKeyEnv,
+			MistralAPIKeyEnv:    DefaultMistralAPIKeyEnv,
+			AzureAPIKeyEnv:      DefaultAzureAPIKeyEnv,
+			GrokAPIKeyEnv:       DefaultGrokAPIKeyEnv,
+			DeepSeekAPIKeyEnv:   DefaultDeepSeekAPIKeyEnv,
+			CohereAPIKeyEnv:     DefaultCohereAPIKeyEnv,
+			GitHubAPIKeyEnv:     DefaultGitHubAPIKeyEnv,
+			TogetherAPIKeyEnv:   DefaultTogetherAPIKeyEnv,
+			DefaultProvider:     DefaultProvider,
 		},
 	}
 
-	if _, err := os.Stat(l.path); os.IsNotExist(err) {
+	data, err := os.ReadFile(l.path)
+	if os.IsNotExist(err) {
 		return cfg, nil
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The remote config sits beneath the local file, so decode it first.
+	var local struct {
+		General General `toml:"General"`
+	}
+	if _, err := toml.Decode(string(data), &local); err != nil {
+		return nil, err
+	}
+	if local.General.ConfigRemoteURL != "" {
+		remote, err := l.remoteConfig(local.General)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := toml.Decode(string(remote), cfg); err != nil {
+			return nil, fmt.Errorf("parse remote config %s: %w", local.General.ConfigRemoteURL, err)
+		}
+		cfg.Git = Git{}
+		cfg.Terminal = Terminal{}
+	}
 
-	if _, err := toml.DecodeFile(l.path, cfg); err != nil {
+	if _, err := toml.Decode(string(data), cfg); err != nil {
 		return nil, err
 	}
 
 	return cfg, nil
 }
 
+// LocalGit returns the [Git] settings from the local config file alone. It
+// never fetches the remote config, so it is cheap enough to run before
+// every command.
+func (l *Loader) LocalGit() (Git, error) {
+	var local struct {
+		Git Git `toml:"Git"`
+	}
+	err := l.decodeLocal(&local)
+	return local.Git, err
+}
+
+// LocalTerminal returns the [Terminal] settings from the local config file
+// alone, like LocalGit.
+func (l *Loader) LocalTerminal() (Terminal, error) {
+	var local struct {
+		Terminal Terminal `toml:"Terminal"`
+	}
+	err := l.decodeLocal(&local)
+	return local.Terminal, err
+}
+
+// CommandLine holds the settings that rewrite goco's arguments before they
+// are parsed.
+type CommandLine struct {
+	DefaultCommand string
+	Aliases        map[string]string
+}
+
+// LocalCommandLine returns the default command and the [alias] table from
+// the local config file alone, like LocalGit, since they apply before every
+// command.
+func (l *Loader) LocalCommandLine() (CommandLine, error) {
+	var local struct {
+		General General           `toml:"General"`
+		Aliases map[string]string `toml:"alias"`
+	}
+	if err := l.decodeLocal(&local); err != nil {
+		return CommandLine{}, err
+	}
+	return CommandLine{DefaultCommand: local.General.DefaultCommand, Aliases: local.Aliases}, nil
+}
+
+// decodeLocal decodes the local config file into v, leaving v alone when
+// there is none.
+func (l *Loader) decodeLocal(v any) error {
+	data, err := os.ReadFile(l.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_, err = toml.Decode(string(data), v)
+	return err
+}
+
+// PromptTemplate returns the prompt template override, if any. A repository's
+// .goco/prompt.tmpl wins over the configured template_file, which wins over
+// prompt.tmpl next to the config file. It returns "" when none exist.
+func (l *Loader) PromptTemplate(cfg *Config, repoRoot string) (string, error) {
+	type candidate struct {
+		path     string
+		required bool
+	}
+
+	var candidates []candidate
+	if repoRoot != "" {
+		candidates = append(candidates, candidate{path: filepath.Join(repoRoot, ".goco", "prompt.tmpl")})
+	}
+	if cfg.Prompt.TemplateFile != "" {
+		candidates = append(candidates, candidate{path: ExpandHome(cfg.Prompt.TemplateFile), required: true})
+	}
+	if l.path != "" {
+		candidates = append(candidates, candidate{path: filepath.Join(filepath.Dir(l.path), "prompt.tmpl")})
+	}
+
+	for _, c := range candidates {
+		data, err := os.ReadFile(c.path)
+		if os.IsNotExist(err) && !c.required {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("read prompt template: %w", err)
+		}
+		return string(data), nil
+	}
+
+	return "", nil
+}
+
+// ApplyCommand makes the [Commands] section for the subcommand name the
+// default provider and model, over [General] and any route.
+func (c *Config) ApplyCommand(name string) {
+	cmd, ok := c.Commands[name]
+	if !ok {
+		return
+	}
+	if cmd.Provider != "" && cmd.Provider != c.DefaultProviderName() {
+		// default_model belongs to the provider being replaced.
+		c.General.DefaultProvider = cmd.Provider
+		c.General.DefaultModel = ""
+	}
+	if cmd.Model != "" {
+		c.General.DefaultModel = cmd.Model
+	}
+}
+
 func (c *Config) DefaultProviderName() string {
 	if c.General.DefaultProvider == "" {
 		return DefaultProvider
@@ -62,18 +665,55 @@ func (c *Config) DefaultProviderName() string {
 	return c.General.DefaultProvider
 }
 
+// ModelFor returns the configured model for provider, or "" for its
+// recommended one. DefaultModel belongs to the default provider only.
+func (c *Config) ModelFor(provider string) string {
+	if provider != c.DefaultProviderName() {
+		return ""
+	}
+	return c.General.DefaultModel
+}
+
+// APIKeyEnv returns the environment variable that holds provider's API key,
+// or "" for a provider that does not use one.
 func (c *Config) APIKeyEnv(provider string) string {
+	field, fallback := c.apiKeyEnvField(provider)
+	if field == nil {
+		return ""
+	}
+	if *field != "" {
+		return *field
+	}
+	return fallback
+}
+
+// apiKeyEnvField returns the [General] setting naming provider's API key
+// variable and its default. It is nil for Bedrock, which signs requests
+// with AWS credentials rather than a key.
+func (c *Config) apiKeyEnvField(provider string) (*string, string) {
 	switch provider {
 	case "groq":
-		if c.General.GroqAPIKeyEnv != "" {
-			return c.General.GroqAPIKeyEnv
-		}
-		return DefaultGroqAPIKeyEnv
+		return &c.General.GroqAPIKeyEnv, DefaultGroqAPIKeyEnv
+	case "openrouter":
+		return &c.General.OpenRouterAPIKeyEnv, DefaultOpenRouterAPIKeyEnv
+	case "mistral":
+		return &c.General.MistralAPIKeyEnv, DefaultMistralAPIKeyEnv
+	case "azure":
+		return &c.General.AzureAPIKeyEnv, DefaultAzureAPIKeyEnv
+	case "bedrock":
+		return nil, ""
+	case "grok":
+		return &c.General.GrokAPIKeyEnv, DefaultGrokAPIKeyEnv
+	case "deepseek":
+		return &c.General.DeepSeekAPIKeyEnv, DefaultDeepSeekAPIKeyEnv
+	case "cohere":
+		return &c.General.CohereAPIKeyEnv, DefaultCohereAPIKeyEnv
+	case "github":
+		return &c.General.GitHubAPIKeyEnv, DefaultGitHubAPIKeyEnv
+	case "together":
+		return &c.General.TogetherAPIKeyEnv, DefaultTogetherAPIKeyEnv
 	default:
-		if c.General.GeminiAPIKeyEnv != "" {
-			return c.General.GeminiAPIKeyEnv
-		}
-		return DefaultGeminiAPIKeyEnv
+		return &c.General.GeminiAPIKeyEnv, DefaultGeminiAPIKeyEnv
 	}
 }
 
@@ -81,6 +721,42 @@ func (c *Config) APIKey(provider string) string {
 	return os.Getenv(c.APIKeyEnv(provider))
 }
 
+// Spec returns the configured Conventional Commits rules, or an empty string
+// when the bundled spec should be used.
+func (c *Config) Spec() (string, error) {
+	if c.Prompt.SpecFile != "" {
+		data, err := os.ReadFile(ExpandHome(c.Prompt.SpecFile))
+		if err != nil {
+			return "", fmt.Errorf("read spec file: %w", err)
+		}
+		return string(data), nil
+	}
+	return c.Prompt.Spec, nil
+}
+
+// AuditPath returns where audit entries are written, or "" when auditing is off.
+func (c *Config) AuditPath(defaultPath string) string {
+	if !c.Audit.Enabled {
+		return ""
+	}
+	if c.Audit.Path != "" {
+		return ExpandHome(c.Audit.Path)
+	}
+	return defaultPath
+}
+
+// ExpandHome replaces a leading "~" with the user's home directory.
+func ExpandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(homeDir, strings.TrimPrefix(path, "~"))
+}
+
 func configPath() string {
 	configDir := os.Getenv("XDG_CONFIG_HOME")
 	if configDir == "" {
@@ -0,0 +1,223 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestAuditPath(t *testing.T) {
+	tests := []struct {
+		name  string
+		audit Audit
+		want  string
+	}{
+		{name: "disabled", want: ""},
+		{name: "path without enabled", audit: Audit{Path: "/tmp/audit.jsonl"}, want: ""},
+		{name: "enabled default", audit: Audit{Enabled: true}, want: "/state/audit.jsonl"},
+		{name: "enabled with path", audit: Audit{Enabled: true, Path: "/tmp/audit.jsonl"}, want: "/tmp/audit.jsonl"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Audit: tt.audit}
+			if got := cfg.AuditPath("/state/audit.jsonl"); got != tt.want {
+				t.Fatalf("AuditPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotifyAfter(t *testing.T) {
+	tests := []struct {
+		seconds int
+		want    time.Duration
+	}{
+		{0, DefaultNotifyAfter},
+		{30, 30 * time.Second},
+		{-1, 0},
+	}
+	for _, tt := range tests {
+		if got := (Notify{AfterSeconds: tt.seconds}).After(); got != tt.want {
+			t.Errorf("After() with after_seconds = %d is %v, want %v", tt.seconds, got, tt.want)
+		}
+	}
+}
+
+func TestHTTPRetries(t *testing.T) {
+	tests := []struct {
+		retries int
+		want    int
+	}{
+		{0, DefaultHTTPRetries},
+		{5, 5},
+		{-1, 0},
+	}
+	for _, tt := range tests {
+		h := HTTP{ConnectRetries: tt.retries, ListRetries: tt.retries}
+		if h.Connect() != tt.want || h.List() != tt.want {
+			t.Errorf("retries = %d gives Connect() %d and List() %d, want %d", tt.retries, h.Connect(), h.List(), tt.want)
+		}
+	}
+}
+
+func TestTerminal(t *testing.T) {
+	tests := []struct {
+		terminal   Terminal
+		detected   bool
+		wantRemote bool
+		wantColors string
+		wantErr    bool
+	}{
+		{terminal: Terminal{}, detected: true, wantRemote: true, wantColors: TerminalAuto},
+		{terminal: Terminal{Remote: "auto", Colors: "auto"}, wantColors: TerminalAuto},
+		{terminal: Terminal{Remote: "on", Colors: "256"}, wantRemote: true, wantColors: Colors256},
+		{terminal: Terminal{Remote: "off", Colors: "none"}, detected: true, wantColors: ColorsNone},
+		{terminal: Terminal{Remote: "yes"}, wantErr: true},
+		{terminal: Terminal{Colors: "24bit"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		remote, err := tt.terminal.IsRemote(tt.detected)
+		colors, colorsErr := tt.terminal.ColorSetting()
+		if (err != nil || colorsErr != nil) != tt.wantErr {
+			t.Errorf("%+v: errors %v and %v, wantErr %v", tt.terminal, err, colorsErr, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && (remote != tt.wantRemote || colors != tt.wantColors) {
+			t.Errorf("%+v with detected = %v: remote %v and colors %q, want %v and %q", tt.terminal, tt.detected, remote, colors, tt.wantRemote, tt.wantColors)
+		}
+	}
+}
+
+func TestQualityMinScore(t *testing.T) {
+	tests := []struct {
+		threshold int
+		want      int
+	}{
+		{0, 0},
+		{70, 70},
+		{-1, 0},
+	}
+	for _, tt := range tests {
+		if got := (Quality{Threshold: tt.threshold}).MinScore(); got != tt.want {
+			t.Errorf("MinScore() with threshold = %d is %d, want %d", tt.threshold, got, tt.want)
+		}
+	}
+}
+
+func TestLocalGit(t *testing.T) {
+	dir := t.TempDir()
+	l := &Loader{path: filepath.Join(dir, "config.toml")}
+
+	if g, err := l.LocalGit(); err != nil || g.Binary != "" {
+		t.Fatalf("LocalGit() without a config = %+v, %v", g, err)
+	}
+
+	// The remote URL is unreachable: LocalGit must not try it.
+	config := "[General]\nconfig_remote_url = \"http://127.0.0.1:1/goco.toml\"\n\n[Git]\nbinary = \"/opt/git/bin/git\"\nextra_args = [\"-c\", \"core.quotepath=off\"]\n"
+	if err := os.WriteFile(l.path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	g, err := l.LocalGit()
+	if err != nil {
+		t.Fatalf("LocalGit() failed: %v", err)
+	}
+	if g.Binary != "/opt/git/bin/git" || len(g.ExtraArgs) != 2 {
+		t.Fatalf("unexpected git settings: %+v", g)
+	}
+}
+
+func TestGitArgs(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip(err)
+	}
+	tests := []struct {
+		git     Git
+		want    []string
+		wantErr bool
+	}{
+		{git: Git{}, want: nil},
+		{git: Git{ExtraArgs: []string{"-c", "core.quotepath=off"}}, want: []string{"-c", "core.quotepath=off"}},
+		{
+			git:  Git{ExtraArgs: []string{"-c", "core.quotepath=off"}, Sign: "ssh", SigningKey: "~/.ssh/id_ed25519.pub"},
+			want: []string{"-c", "core.quotepath=off", "-c", "commit.gpgsign=true", "-c", "gpg.format=ssh", "-c", "user.signingkey=" + filepath.Join(home, ".ssh/id_ed25519.pub")},
+		},
+		{git: Git{SigningKey: "key::ssh-ed25519 AAAA"}, want: []string{"-c", "user.signingkey=key::ssh-ed25519 AAAA"}},
+		{git: Git{Sign: "gpg"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := tt.git.Args()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%+v: error %v, wantErr %v", tt.git, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !slices.Equal(got, tt.want) {
+			t.Errorf("%+v: Args() = %q, want %q", tt.git, got, tt.want)
+		}
+	}
+}
+
+func TestApplyCommand(t *testing.T) {
+	l := &Loader{path: filepath.Join(t.TempDir(), "config.toml")}
+	config := `[General]
+default_provider = "groq"
+default_model = "llama-3.1-8b-instant"
+
+[Commands.pr]
+provider = "gemini"
+language = "German"
+
+[Commands.standup]
+model = "llama-3.3-70b-versatile"
+instructions = "One line per commit."
+`
+	if err := os.WriteFile(l.path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		command          string
+		wantProvider     string
+		wantModel        string
+		wantInstructions string
+	}{
+		{command: "generate", wantProvider: "groq", wantModel: "llama-3.1-8b-instant"},
+		{command: "pr", wantProvider: "gemini", wantInstructions: "Write in German."},
+		{command: "standup", wantProvider: "groq", wantModel: "llama-3.3-70b-versatile", wantInstructions: "One line per commit."},
+	}
+	for _, tt := range tests {
+		cfg, err := l.Load()
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg.ApplyCommand(tt.command)
+		provider := cfg.DefaultProviderName()
+		if provider != tt.wantProvider || cfg.ModelFor(provider) != tt.wantModel {
+			t.Errorf("%s: got %s, %q; want %s, %q", tt.command, provider, cfg.ModelFor(provider), tt.wantProvider, tt.wantModel)
+		}
+		if got := cfg.Commands[tt.command].PromptInstructions(); got != tt.wantInstructions {
+			t.Errorf("%s: PromptInstructions() = %q, want %q", tt.command, got, tt.wantInstructions)
+		}
+	}
+}
+
+func TestLocalCommandLine(t *testing.T) {
+	l := &Loader{path: filepath.Join(t.TempDir(), "config.toml")}
+	if c, err := l.LocalCommandLine(); err != nil || c.DefaultCommand != "" || c.Aliases != nil {
+		t.Fatalf("LocalCommandLine() without a config = %+v, %v", c, err)
+	}
+
+	config := "[General]\nconfig_remote_url = \"http://127.0.0.1:1/goco.toml\"\ndefault_command = \"quick\"\n\n[alias]\nquick = \"generate --yes\"\n"
+	if err := os.WriteFile(l.path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := l.LocalCommandLine()
+	if err != nil {
+		t.Fatalf("LocalCommandLine() failed: %v", err)
+	}
+	if c.DefaultCommand != "quick" || c.Aliases["quick"] != "generate --yes" || len(c.Aliases) != 1 {
+		t.Fatalf("unexpected command line settings: %+v", c)
+	}
+}
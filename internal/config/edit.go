@@ -0,0 +1,172 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+var (
+	tableHeader = regexp.MustCompile(`^\s*\[\[?\s*([^\[\]]+?)\s*\]\]?\s*(#.*)?$`)
+	keyLine     = regexp.MustCompile(`^\s*([A-Za-z0-9_-]+)\s*=`)
+)
+
+// SetGeneral sets string keys of the [General] table in the config file,
+// creating the file or table as needed. Other lines, comments included,
+// are kept as they are.
+func (l *Loader) SetGeneral(values map[string]string) error {
+	return l.edit(func(data string) string {
+		return setTableKeys(data, "General", values)
+	})
+}
+
+// SetRoute saves r, which must set Path, to the config file: it replaces
+// the provider and model of the [[Route]] for the same path that matches no
+// remote, or else adds r ahead of the other routes so it wins over broader
+// ones.
+func (l *Loader) SetRoute(r Route) error {
+	return l.edit(func(data string) string {
+		return setRoute(data, r)
+	})
+}
+
+// edit rewrites the config file with change, writing nothing unless the
+// result parses.
+func (l *Loader) edit(change func(data string) string) error {
+	if l.path == "" {
+		return fmt.Errorf("cannot find the config directory; set XDG_CONFIG_HOME or HOME")
+	}
+	data, err := os.ReadFile(l.path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	updated := change(string(data))
+	if _, err := toml.Decode(updated, &Config{}); err != nil {
+		return fmt.Errorf("update %s: the result would not parse: %w", l.path, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return err
+	}
+	tmp := l.path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(updated), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, l.path)
+}
+
+// setTableKeys sets string keys in table, replacing existing lines and
+// adding missing keys after the table's last line.
+func setTableKeys(data, table string, values map[string]string) string {
+	lines := splitLines(data)
+	for start, end := range tables(lines) {
+		if m := tableHeader.FindStringSubmatch(lines[start]); m[1] == table {
+			return joinLines(setKeys(lines, start, end, values))
+		}
+	}
+
+	if len(lines) > 0 {
+		lines = append(lines, "")
+	}
+	lines = append(lines, "["+table+"]")
+	return joinLines(setKeys(lines, len(lines)-1, len(lines), values))
+}
+
+// setRoute saves r as setRoute describes.
+func setRoute(data string, r Route) string {
+	values := map[string]string{"provider": r.Provider, "model": r.Model}
+	for key, value := range values {
+		if value == "" {
+			delete(values, key)
+		}
+	}
+
+	lines := splitLines(data)
+	first := -1
+	for start, end := range tables(lines) {
+		if !strings.HasPrefix(strings.TrimSpace(lines[start]), "[[") || tableHeader.FindStringSubmatch(lines[start])[1] != "Route" {
+			continue
+		}
+		if first < 0 {
+			first = start
+		}
+		var existing Route
+		if _, err := toml.Decode(strings.Join(lines[start+1:end], "\n"), &existing); err == nil && existing.Path == r.Path && existing.Remote == "" {
+			return joinLines(setKeys(lines, start, end, values))
+		}
+	}
+
+	block := []string{"[[Route]]", "path = " + strconv.Quote(r.Path)}
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		block = append(block, key+" = "+strconv.Quote(values[key]))
+	}
+	if first >= 0 {
+		return joinLines(slices.Insert(lines, first, append(block, "")...))
+	}
+	if len(lines) > 0 {
+		block = append([]string{""}, block...)
+	}
+	return joinLines(append(lines, block...))
+}
+
+// tables yields the header line and the end, exclusive, of each table.
+func tables(lines []string) func(yield func(start, end int) bool) {
+	return func(yield func(start, end int) bool) {
+		start := -1
+		for i, line := range lines {
+			if !tableHeader.MatchString(line) {
+				continue
+			}
+			if start >= 0 && !yield(start, i) {
+				return
+			}
+			start = i
+		}
+		if start >= 0 {
+			yield(start, len(lines))
+		}
+	}
+}
+
+// setKeys sets string keys in the table from the header at start to end,
+// replacing existing lines and adding missing keys after its last
+// non-blank line.
+func setKeys(lines []string, start, end int, values map[string]string) []string {
+	pending := maps.Clone(values)
+	last := start
+	for i := start + 1; i < end; i++ {
+		if strings.TrimSpace(lines[i]) != "" {
+			last = i
+		}
+		if m := keyLine.FindStringSubmatch(lines[i]); m != nil {
+			if value, ok := pending[m[1]]; ok {
+				lines[i] = m[1] + " = " + strconv.Quote(value)
+				delete(pending, m[1])
+			}
+		}
+	}
+
+	var added []string
+	for _, key := range slices.Sorted(maps.Keys(pending)) {
+		added = append(added, key+" = "+strconv.Quote(pending[key]))
+	}
+	return slices.Insert(lines, last+1, added...)
+}
+
+func splitLines(data string) []string {
+	if data == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(data, "\n"), "\n")
+}
+
+func joinLines(lines []string) string {
+	return strings.Join(lines, "\n") + "\n"
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetTableKeys(t *testing.T) {
+	values := map[string]string{"default_provider": "groq", "default_model": "llama-3.1-8b-instant"}
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{
+			name: "empty file",
+			want: "[General]\ndefault_model = \"llama-3.1-8b-instant\"\ndefault_provider = \"groq\"\n",
+		},
+		{
+			name: "replace and add, keeping comments",
+			data: "# my config\n[General]\ndefault_provider = \"gemini\" # work\n\n[Quality]\nthreshold = 40\n",
+			want: "# my config\n[General]\ndefault_provider = \"groq\"\ndefault_model = \"llama-3.1-8b-instant\"\n\n[Quality]\nthreshold = 40\n",
+		},
+		{
+			name: "no General table",
+			data: "[Quality]\nthreshold = 40\n",
+			want: "[Quality]\nthreshold = 40\n\n[General]\ndefault_model = \"llama-3.1-8b-instant\"\ndefault_provider = \"groq\"\n",
+		},
+		{
+			name: "same key in another table",
+			data: "[Remotes.origin]\ndefault_model = \"x\"\n[General]\n",
+			want: "[Remotes.origin]\ndefault_model = \"x\"\n[General]\ndefault_model = \"llama-3.1-8b-instant\"\ndefault_provider = \"groq\"\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := setTableKeys(tt.data, "General", values); got != tt.want {
+				t.Errorf("setTableKeys() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetRoute(t *testing.T) {
+	route := Route{Path: "/src/api", Provider: "groq", Model: "llama-3.1-8b-instant"}
+	added := "[[Route]]\npath = \"/src/api\"\nmodel = \"llama-3.1-8b-instant\"\nprovider = \"groq\"\n"
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{
+			name: "empty file",
+			want: added,
+		},
+		{
+			name: "after the other tables",
+			data: "[General]\ndefault_provider = \"gemini\"\n",
+			want: "[General]\ndefault_provider = \"gemini\"\n\n" + added,
+		},
+		{
+			name: "ahead of broader routes",
+			data: "[General]\n\n[[Route]]\npath = \"/src\"\nprovider = \"gemini\"\n",
+			want: "[General]\n\n" + added + "\n[[Route]]\npath = \"/src\"\nprovider = \"gemini\"\n",
+		},
+		{
+			name: "replace the route for the path",
+			data: "[[Route]]\npath = \"/src\"\n\n[[Route]] # api\npath = \"/src/api\"\nprovider = \"gemini\"\napi_key_env = \"WORK_KEY\"\n",
+			want: "[[Route]]\npath = \"/src\"\n\n[[Route]] # api\npath = \"/src/api\"\nprovider = \"groq\"\napi_key_env = \"WORK_KEY\"\nmodel = \"llama-3.1-8b-instant\"\n",
+		},
+		{
+			name: "keep a route that also matches a remote",
+			data: "[[Route]]\npath = \"/src/api\"\nremote = \"github.com/acme/*\"\n",
+			want: added + "\n[[Route]]\npath = \"/src/api\"\nremote = \"github.com/acme/*\"\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := setRoute(tt.data, route); got != tt.want {
+				t.Errorf("setRoute() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoaderSetGeneral(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "goco", "config.toml")
+	l := &Loader{path: path}
+	if err := l.SetGeneral(map[string]string{"default_model": "gemini-2.5-pro"}); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := l.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ModelFor(DefaultProvider) != "gemini-2.5-pro" {
+		t.Fatalf("default_model = %q after SetGeneral", cfg.General.DefaultModel)
+	}
+
+	if err := os.WriteFile(path, []byte("[General\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.SetGeneral(map[string]string{"default_model": "x"}); err == nil {
+		t.Fatal("expected an unparsable config to be left alone")
+	}
+}
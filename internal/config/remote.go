@@ -0,0 +1,204 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/razobeckett/goco/internal/cache"
+)
+
+// DefaultRemoteTTL is how long a fetched remote config is used before it is
+// fetched again.
+const DefaultRemoteTTL = 24 * time.Hour
+
+// maxRemoteSize caps a remote config download.
+const maxRemoteSize = 1 << 20
+
+// remoteConfig returns the verified org-managed config named by g, from the
+// cache while it is fresh. When a refresh fails, or the loader is offline, a
+// stale cached copy that still verifies and has not expired is used so a
+// network outage does not block commits.
+func (l *Loader) remoteConfig(g General) ([]byte, error) {
+	if g.ConfigRemotePublicKey == "" {
+		return nil, fmt.Errorf("config_remote_url requires config_remote_public_key to verify it")
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(g.ConfigRemotePublicKey))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("config_remote_public_key must be a base64 Ed25519 public key")
+	}
+	ttl := DefaultRemoteTTL
+	if g.ConfigRemoteTTL != "" {
+		if ttl, err = time.ParseDuration(g.ConfigRemoteTTL); err != nil {
+			return nil, fmt.Errorf("parse config_remote_ttl: %w", err)
+		}
+	}
+
+	cachePath := l.remoteCachePath(g.ConfigRemoteURL)
+	cached, cachedSig, cachedErr := readVerified(cachePath, g.ConfigRemoteURL, key)
+	if l.offline {
+		if cachedErr != nil {
+			return nil, fmt.Errorf("remote config %s is not cached and --offline forbids fetching it: %w", g.ConfigRemoteURL, cachedErr)
+		}
+		cache.Record("remote-config", true)
+		return cached, nil
+	}
+	if cachedErr == nil {
+		if info, err := os.Stat(cachePath); err == nil && time.Since(info.ModTime()) < ttl {
+			cache.Record("remote-config", true)
+			return cached, nil
+		}
+	}
+	cache.Record("remote-config", false)
+
+	data, sigText, err := l.fetchRemote(g.ConfigRemoteURL)
+	var sig remoteSignature
+	if err == nil {
+		sig, err = verifyRemote(key, g.ConfigRemoteURL, data, sigText)
+	}
+	if err == nil && cachedErr == nil && sig.expires.Before(cachedSig.expires) {
+		err = fmt.Errorf("config expires before the cached copy; refusing a rollback")
+	}
+	if err != nil {
+		if cachedErr == nil {
+			return cached, nil
+		}
+		return nil, fmt.Errorf("fetch remote config %s: %w", g.ConfigRemoteURL, err)
+	}
+
+	if cachePath != "" {
+		// Caching is an optimization; a read-only cache directory is fine.
+		if err := os.MkdirAll(filepath.Dir(cachePath), 0o755); err == nil {
+			_ = os.WriteFile(cachePath+".sig", sigText, 0o644)
+			_ = os.WriteFile(cachePath, data, 0o644)
+		}
+	}
+	return data, nil
+}
+
+// fetchRemote downloads the config and its detached signature, published
+// next to it with a .sig suffix.
+func (l *Loader) fetchRemote(url string) ([]byte, []byte, error) {
+	data, err := l.get(url)
+	if err != nil {
+		return nil, nil, err
+	}
+	sigText, err := l.get(url + ".sig")
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, sigText, nil
+}
+
+// remoteSignature is a parsed .sig file: an "expires <RFC 3339 time>" line
+// followed by the base64 Ed25519 signature of signedRemote.
+type remoteSignature struct {
+	// stamp is the expiry exactly as written, which is what was signed.
+	stamp   string
+	expires time.Time
+	sig     []byte
+}
+
+func parseRemoteSignature(text []byte) (remoteSignature, error) {
+	header, encoded, ok := strings.Cut(strings.TrimSpace(string(text)), "\n")
+	expiresText, found := strings.CutPrefix(header, "expires ")
+	if !ok || !found {
+		return remoteSignature{}, fmt.Errorf("signature file must start with an \"expires <time>\" line")
+	}
+	stamp := strings.TrimSpace(expiresText)
+	expires, err := time.Parse(time.RFC3339, stamp)
+	if err != nil {
+		return remoteSignature{}, fmt.Errorf("parse signature expiry: %w", err)
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return remoteSignature{}, fmt.Errorf("decode signature: %w", err)
+	}
+	return remoteSignature{stamp: stamp, expires: expires, sig: sig}, nil
+}
+
+// signedRemote is what the publisher signs. Binding the URL and expiry stops
+// a config published for one URL, or an old one, from being replayed.
+func signedRemote(url, stamp string, data []byte) []byte {
+	return append([]byte("goco-config\n"+url+"\n"+stamp+"\n"), data...)
+}
+
+// verifyRemote checks that sigText signs data for url and has not expired.
+func verifyRemote(key ed25519.PublicKey, url string, data, sigText []byte) (remoteSignature, error) {
+	sig, err := parseRemoteSignature(sigText)
+	if err != nil {
+		return sig, err
+	}
+	if !ed25519.Verify(key, signedRemote(url, sig.stamp, data), sig.sig) {
+		return sig, fmt.Errorf("signature does not match config_remote_public_key")
+	}
+	if time.Now().After(sig.expires) {
+		return sig, fmt.Errorf("signature expired at %s", sig.expires.Format(time.RFC3339))
+	}
+	return sig, nil
+}
+
+func (l *Loader) get(url string) ([]byte, error) {
+	client := l.http
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxRemoteSize {
+		return nil, fmt.Errorf("GET %s: larger than %d bytes", url, maxRemoteSize)
+	}
+	return data, nil
+}
+
+// remoteCachePath keys the cache by URL so switching URLs never serves the
+// old config.
+func (l *Loader) remoteCachePath(url string) string {
+	dir := l.cacheDir
+	if dir == "" {
+		if dir = cache.Dir(); dir == "" {
+			return ""
+		}
+	}
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(dir, "remote-config-"+hex.EncodeToString(sum[:8])+".toml")
+}
+
+// readVerified returns the cached config at path if its cached signature
+// verifies for url and has not expired.
+func readVerified(path, url string, key ed25519.PublicKey) ([]byte, remoteSignature, error) {
+	if path == "" {
+		return nil, remoteSignature{}, os.ErrNotExist
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, remoteSignature{}, err
+	}
+	sigText, err := os.ReadFile(path + ".sig")
+	if err != nil {
+		return nil, remoteSignature{}, err
+	}
+	sig, err := verifyRemote(key, url, data, sigText)
+	if err != nil {
+		return nil, sig, fmt.Errorf("cached remote config failed verification: %w", err)
+	}
+	return data, sig, nil
+}
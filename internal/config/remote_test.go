@@ -0,0 +1,140 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// signRemote returns a .sig file for body published at url.
+func signRemote(priv ed25519.PrivateKey, url string, expires time.Time, body []byte) string {
+	stamp := expires.UTC().Format(time.RFC3339)
+	return "expires " + stamp + "\n" + base64.StdEncoding.EncodeToString(ed25519.Sign(priv, signedRemote(url, stamp, body))) + "\n"
+}
+
+func TestRemoteConfig(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir()) // cache hit stats
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := []byte("[Telemetry]\nsink = \"statsd\"\n")
+	var sig string
+
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/goco.toml":
+			_, _ = w.Write(body)
+		case "/goco.toml.sig":
+			_, _ = w.Write([]byte(sig))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	l := &Loader{cacheDir: t.TempDir(), http: srv.Client()}
+	g := General{ConfigRemoteURL: srv.URL + "/goco.toml", ConfigRemotePublicKey: base64.StdEncoding.EncodeToString(pub)}
+	sig = signRemote(priv, g.ConfigRemoteURL, time.Now().Add(time.Hour), body)
+
+	got, err := l.remoteConfig(g)
+	if err != nil || string(got) != string(body) {
+		t.Fatalf("remoteConfig() = %q, %v", got, err)
+	}
+	if requests != 2 {
+		t.Fatalf("expected config and signature fetches, got %d requests", requests)
+	}
+
+	// A fresh cache is served without fetching.
+	if _, err := l.remoteConfig(g); err != nil || requests != 2 {
+		t.Fatalf("expected cached config, got %v after %d requests", err, requests)
+	}
+
+	// Offline, an unexpired cache is used however old, and nothing is fetched.
+	offline := &Loader{cacheDir: l.cacheDir, http: srv.Client(), offline: true}
+	if got, err := offline.remoteConfig(General{ConfigRemoteURL: g.ConfigRemoteURL, ConfigRemotePublicKey: g.ConfigRemotePublicKey, ConfigRemoteTTL: "0s"}); err != nil || string(got) != string(body) || requests != 2 {
+		t.Fatalf("expected offline cache hit, got %q, %v after %d requests", got, err, requests)
+	}
+	offline.cacheDir = t.TempDir()
+	if _, err := offline.remoteConfig(g); err == nil || requests != 2 {
+		t.Fatalf("expected offline error without a cache, got %v after %d requests", err, requests)
+	}
+
+	// A refresh that would roll back to an earlier expiry keeps the cache.
+	g.ConfigRemoteTTL = "0s"
+	body, oldBody := []byte("[Audit]\nenabled = false\n"), body
+	sig = signRemote(priv, g.ConfigRemoteURL, time.Now().Add(time.Minute), body)
+	if got, err := l.remoteConfig(g); err != nil || string(got) != string(oldBody) {
+		t.Fatalf("expected the rollback to be refused, got %q, %v", got, err)
+	}
+	body = oldBody
+
+	// A cache past its TTL is still used when the server is unreachable.
+	srv.Close()
+	if got, err := l.remoteConfig(g); err != nil || string(got) != string(body) {
+		t.Fatalf("expected stale cache fallback, got %q, %v", got, err)
+	}
+
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+	g.ConfigRemotePublicKey = base64.StdEncoding.EncodeToString(otherPub)
+	if _, err := l.remoteConfig(g); err == nil {
+		t.Fatal("expected verification failure with the wrong key")
+	}
+}
+
+func TestRemoteConfigRejectsReplay(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir()) // cache hit stats
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	body := []byte("[Audit]\nenabled = false\n")
+	var sig string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/goco.toml.sig" {
+			_, _ = w.Write([]byte(sig))
+			return
+		}
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	url := srv.URL + "/goco.toml"
+	g := General{ConfigRemoteURL: url, ConfigRemotePublicKey: base64.StdEncoding.EncodeToString(pub)}
+	for name, s := range map[string]string{
+		"expired":       signRemote(priv, url, time.Now().Add(-time.Minute), body),
+		"other URL":     signRemote(priv, srv.URL+"/other.toml", time.Now().Add(time.Hour), body),
+		"no expiry":     base64.StdEncoding.EncodeToString(ed25519.Sign(priv, body)),
+		"body only sig": "expires 2999-01-01T00:00:00Z\n" + base64.StdEncoding.EncodeToString(ed25519.Sign(priv, body)),
+	} {
+		sig = s
+		l := &Loader{cacheDir: t.TempDir(), http: srv.Client()}
+		if _, err := l.remoteConfig(g); err == nil {
+			t.Errorf("%s: expected the signature to be rejected", name)
+		}
+	}
+}
+
+func TestRemoteConfigRejectsBadSignature(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir()) // cache hit stats
+	pub, _, _ := ed25519.GenerateKey(nil)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/goco.toml.sig" {
+			_, _ = w.Write([]byte(base64.StdEncoding.EncodeToString(make([]byte, ed25519.SignatureSize))))
+			return
+		}
+		_, _ = w.Write([]byte("[Audit]\nenabled = false\n"))
+	}))
+	defer srv.Close()
+
+	l := &Loader{cacheDir: t.TempDir(), http: srv.Client()}
+	g := General{ConfigRemoteURL: srv.URL + "/goco.toml", ConfigRemotePublicKey: base64.StdEncoding.EncodeToString(pub)}
+	if _, err := l.remoteConfig(g); err == nil {
+		t.Fatal("expected a forged config to be rejected")
+	}
+	if _, err := l.remoteConfig(General{ConfigRemoteURL: g.ConfigRemoteURL}); err == nil {
+		t.Fatal("expected a missing public key to be rejected")
+	}
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Route sends the repositories it matches to a provider, key, and model of
+// their own, e.g. work repositories to a company account and personal ones to
+// another. A route matches when every condition it sets matches.
+type Route struct {
+	// Path matches the repository root: a directory and every repository
+	// below it, e.g. "~/work", or a pattern such as "~/src/*-client".
+	Path string `toml:"path"`
+	// Remote matches the host and path of the current branch's remote,
+	// without scheme, user, or ".git", e.g. "github.com/acme/*".
+	Remote string `toml:"remote"`
+
+	Provider string `toml:"provider"`
+	Model    string `toml:"model"`
+	// APIKeyEnv names the environment variable holding the key for Provider.
+	APIKeyEnv string `toml:"api_key_env"`
+}
+
+// NeedsRemote reports whether any route matches on the remote, so callers
+// can skip looking it up otherwise.
+func (c *Config) NeedsRemote() bool {
+	for _, r := range c.Routes {
+		if r.Remote != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyRoute applies the first route matching the repository at root with
+// the given remote ("host/path", or "" when unknown) and returns it, or nil
+// when none matches. Outside a repository root is "" and only routes without
+// conditions match.
+func (c *Config) ApplyRoute(root, remote string) (*Route, error) {
+	for i := range c.Routes {
+		r := &c.Routes[i]
+		ok, err := r.matches(root, remote)
+		if err != nil {
+			return nil, fmt.Errorf("[[Route]] %d: %w", i+1, err)
+		}
+		if !ok {
+			continue
+		}
+
+		if r.Provider != "" {
+			c.General.DefaultProvider = r.Provider
+		}
+		if r.Model != "" {
+			c.General.DefaultModel = r.Model
+		}
+		if r.APIKeyEnv != "" {
+			// Bedrock uses AWS credentials; there is no key to route.
+			if field, _ := c.apiKeyEnvField(c.DefaultProviderName()); field != nil {
+				*field = r.APIKeyEnv
+			}
+		}
+		return r, nil
+	}
+	return nil, nil
+}
+
+func (r *Route) matches(root, remote string) (bool, error) {
+	if r.Path != "" {
+		if root == "" {
+			return false, nil
+		}
+		ok, err := matchRoot(filepath.ToSlash(ExpandHome(r.Path)), filepath.ToSlash(root))
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	if r.Remote != "" {
+		if remote == "" {
+			return false, nil
+		}
+		ok, err := path.Match(r.Remote, remote)
+		if err != nil {
+			return false, fmt.Errorf("remote %q: %w", r.Remote, err)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// matchRoot reports whether root is pattern, lies below it, or matches it
+// as a path.Match pattern.
+func matchRoot(pattern, root string) (bool, error) {
+	pattern = strings.TrimSuffix(pattern, "/")
+	if root == pattern || strings.HasPrefix(root, pattern+"/") {
+		return true, nil
+	}
+	ok, err := path.Match(pattern, root)
+	if err != nil {
+		return false, fmt.Errorf("path %q: %w", pattern, err)
+	}
+	return ok, nil
+}
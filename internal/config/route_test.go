@@ -0,0 +1,68 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestApplyRoute(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	work := filepath.Join(home, "work")
+
+	routes := []Route{
+		{Remote: "github.com/acme/*", Provider: "groq", Model: "llama-3.1-8b-instant", APIKeyEnv: "ACME_GROQ_KEY"},
+		{Path: "~/work", Provider: "gemini", APIKeyEnv: "WORK_GEMINI_KEY"},
+		{Path: filepath.Join(home, "src", "*-client"), Model: "gemini-2.5-pro"},
+		{Path: "~/lab", Provider: "openrouter", Model: "anthropic/claude-3.5-haiku", APIKeyEnv: "LAB_OPENROUTER_KEY"},
+		{Path: "~/code", Provider: "mistral", Model: "codestral-latest", APIKeyEnv: "CODE_MISTRAL_KEY"},
+	}
+	tests := []struct {
+		name         string
+		root, remote string
+		wantRoute    int
+		wantProvider string
+		wantModel    string
+		wantKeyEnv   string
+	}{
+		{name: "remote", root: filepath.Join(home, "oss", "api"), remote: "github.com/acme/api", wantRoute: 0, wantProvider: "groq", wantModel: "llama-3.1-8b-instant", wantKeyEnv: "ACME_GROQ_KEY"},
+		{name: "first match wins", root: filepath.Join(work, "api"), remote: "github.com/acme/api", wantRoute: 0, wantProvider: "groq", wantModel: "llama-3.1-8b-instant", wantKeyEnv: "ACME_GROQ_KEY"},
+		{name: "below path", root: filepath.Join(work, "team", "api"), remote: "gitlab.com/acme/api", wantRoute: 1, wantProvider: "gemini", wantKeyEnv: "WORK_GEMINI_KEY"},
+		{name: "path itself", root: work, wantRoute: 1, wantProvider: "gemini", wantKeyEnv: "WORK_GEMINI_KEY"},
+		{name: "prefix is not a parent", root: work + "shop", wantRoute: -1, wantProvider: "groq", wantKeyEnv: DefaultGroqAPIKeyEnv},
+		{name: "path pattern", root: filepath.Join(home, "src", "web-client"), wantRoute: 2, wantProvider: "groq", wantModel: "gemini-2.5-pro", wantKeyEnv: DefaultGroqAPIKeyEnv},
+		{name: "openrouter", root: filepath.Join(home, "lab"), wantRoute: 3, wantProvider: "openrouter", wantModel: "anthropic/claude-3.5-haiku", wantKeyEnv: "LAB_OPENROUTER_KEY"},
+		{name: "mistral", root: filepath.Join(home, "code", "api"), wantRoute: 4, wantProvider: "mistral", wantModel: "codestral-latest", wantKeyEnv: "CODE_MISTRAL_KEY"},
+		{name: "outside a repository", wantRoute: -1, wantProvider: "groq", wantKeyEnv: DefaultGroqAPIKeyEnv},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				General: General{DefaultProvider: "groq", GroqAPIKeyEnv: DefaultGroqAPIKeyEnv, GeminiAPIKeyEnv: DefaultGeminiAPIKeyEnv},
+				Routes:  append([]Route(nil), routes...),
+			}
+			route, err := cfg.ApplyRoute(tt.root, tt.remote)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantRoute < 0 && route != nil {
+				t.Fatalf("matched %+v, want no route", *route)
+			}
+			if tt.wantRoute >= 0 && route != &cfg.Routes[tt.wantRoute] {
+				t.Fatalf("matched %+v, want route %d", route, tt.wantRoute+1)
+			}
+			provider := cfg.DefaultProviderName()
+			if provider != tt.wantProvider || cfg.ModelFor(provider) != tt.wantModel || cfg.APIKeyEnv(provider) != tt.wantKeyEnv {
+				t.Errorf("got %s, %q, %s; want %s, %q, %s", provider, cfg.ModelFor(provider), cfg.APIKeyEnv(provider), tt.wantProvider, tt.wantModel, tt.wantKeyEnv)
+			}
+		})
+	}
+}
+
+func TestApplyRouteBadPattern(t *testing.T) {
+	cfg := &Config{Routes: []Route{{Remote: "github.com/["}}}
+	if _, err := cfg.ApplyRoute("/src/api", "github.com/acme/api"); err == nil {
+		t.Fatal("expected an error for a malformed pattern")
+	}
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ThemeFileName is the theme file read from next to the config file when
+// no other is configured.
+const ThemeFileName = "theme.toml"
+
+// Theme rebrands goco's output, so a fork or wrapper can change its look
+// and words without code changes. Empty fields keep goco's own.
+type Theme struct {
+	// Emoji is put before the headings of styled blocks, e.g. "🚀".
+	Emoji    string        `toml:"emoji"`
+	Colors   ThemeColors   `toml:"colors"`
+	Messages ThemeMessages `toml:"messages"`
+}
+
+// ThemeColors replaces goco's palette. Each is a hex color such as
+// "#0055FF" or an ANSI color number from "0" to "255".
+type ThemeColors struct {
+	// Primary draws headings, the spinner, and the selected item.
+	Primary string `toml:"primary"`
+	// Secondary draws notes, flags, and the diff heading.
+	Secondary string `toml:"secondary"`
+	// Accent draws help text and flag defaults.
+	Accent string `toml:"accent"`
+	// Highlight draws the commit message box and descriptions.
+	Highlight string `toml:"highlight"`
+	// Text draws heading text and list items.
+	Text string `toml:"text"`
+	// Error draws errors.
+	Error string `toml:"error"`
+}
+
+// ThemeMessages replaces the words of goco's spinners and block headings.
+type ThemeMessages struct {
+	// Generating is shown while the commit message is written.
+	Generating string `toml:"generating"`
+	// FetchingModels is shown while models are listed; "{provider}" is
+	// replaced with the provider's name.
+	FetchingModels string `toml:"fetching_models"`
+	StatusTitle    string `toml:"status_title"`
+	DiffTitle      string `toml:"diff_title"`
+	// MessageTitle and FinalTitle head the generated and the edited
+	// commit message.
+	MessageTitle string `toml:"message_title"`
+	FinalTitle   string `toml:"final_title"`
+}
+
+// ParseTheme decodes a theme, rejecting unknown keys and colors so typos
+// are reported instead of ignored.
+func ParseTheme(data []byte) (*Theme, error) {
+	var t Theme
+	md, err := toml.NewDecoder(bytes.NewReader(data)).Decode(&t)
+	if err != nil {
+		return nil, fmt.Errorf("parse theme: %w", err)
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, len(undecoded))
+		for i, k := range undecoded {
+			keys[i] = k.String()
+		}
+		return nil, fmt.Errorf("parse theme: unsupported keys: %s", strings.Join(keys, ", "))
+	}
+	for _, c := range []struct{ name, value string }{
+		{"primary", t.Colors.Primary},
+		{"secondary", t.Colors.Secondary},
+		{"accent", t.Colors.Accent},
+		{"highlight", t.Colors.Highlight},
+		{"text", t.Colors.Text},
+		{"error", t.Colors.Error},
+	} {
+		if c.value != "" && !validColor(c.value) {
+			return nil, fmt.Errorf("parse theme: colors.%s must be a hex color like \"#0055FF\" or an ANSI color from 0 to 255, got %q", c.name, c.value)
+		}
+	}
+	return &t, nil
+}
+
+func validColor(c string) bool {
+	if hex, ok := strings.CutPrefix(c, "#"); ok {
+		if len(hex) != 3 && len(hex) != 6 {
+			return false
+		}
+		_, err := strconv.ParseUint(hex, 16, 32)
+		return err == nil
+	}
+	n, err := strconv.Atoi(c)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// Theme reads the theme file at path, or theme.toml next to the config
+// file when path is empty. It returns nil when there is no theme; a
+// missing file at a given path is an error.
+func (l *Loader) Theme(path string) (*Theme, error) {
+	required := path != ""
+	if !required {
+		if l.path == "" {
+			return nil, nil
+		}
+		path = filepath.Join(filepath.Dir(l.path), ThemeFileName)
+	}
+	data, err := os.ReadFile(ExpandHome(path))
+	if os.IsNotExist(err) && !required {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read theme: %w", err)
+	}
+	t, err := ParseTheme(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return t, nil
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseTheme(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{name: "empty", data: ""},
+		{name: "full", data: "emoji = \"🚀\"\n\n[colors]\nprimary = \"#0055FF\"\ntext = \"#fff\"\nerror = \"196\"\n\n[messages]\ngenerating = \"Asking Acme AI...\"\n"},
+		{name: "unknown key", data: "[colors]\nprimray = \"#0055FF\"\n", wantErr: "unsupported keys: colors.primray"},
+		{name: "bad hex", data: "[colors]\naccent = \"#12345\"\n", wantErr: "colors.accent must be"},
+		{name: "named color", data: "[colors]\nprimary = \"blue\"\n", wantErr: "colors.primary must be"},
+		{name: "ANSI out of range", data: "[colors]\nhighlight = \"256\"\n", wantErr: "colors.highlight must be"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTheme([]byte(tt.data))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("ParseTheme() failed: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ParseTheme() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoaderTheme(t *testing.T) {
+	dir := t.TempDir()
+	l := &Loader{path: filepath.Join(dir, "config.toml")}
+
+	if theme, err := l.Theme(""); err != nil || theme != nil {
+		t.Fatalf("Theme() without a theme file = %+v, %v", theme, err)
+	}
+	if _, err := l.Theme(filepath.Join(dir, "missing.toml")); err == nil {
+		t.Fatal("Theme() with a missing configured file succeeded")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ThemeFileName), []byte("emoji = \"🦊\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	acme := filepath.Join(dir, "acme.toml")
+	if err := os.WriteFile(acme, []byte("emoji = \"🚀\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if theme, err := l.Theme(""); err != nil || theme.Emoji != "🦊" {
+		t.Fatalf("Theme() = %+v, %v, want theme.toml next to the config", theme, err)
+	}
+	if theme, err := l.Theme(acme); err != nil || theme.Emoji != "🚀" {
+		t.Fatalf("Theme(%q) = %+v, %v", acme, theme, err)
+	}
+}
@@ -0,0 +1,143 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/razobeckett/goco/internal/ai"
+)
+
+// ErrNotRunning is returned when no server answers on the socket.
+var ErrNotRunning = errors.New("goco daemon is not running")
+
+// dialTimeout keeps the check for a running server from slowing down
+// invocations when there is none.
+const dialTimeout = 200 * time.Millisecond
+
+// Client talks to a server over its socket.
+type Client struct {
+	path string
+}
+
+// NewClient returns a client for the server listening on path.
+func NewClient(path string) *Client {
+	return &Client{path: path}
+}
+
+// Running reports whether a server answers on the socket. It is cheap when
+// the socket does not exist.
+func (c *Client) Running(ctx context.Context) bool {
+	if c.path == "" {
+		return false
+	}
+	if _, err := os.Stat(c.path); err != nil {
+		return false
+	}
+	_, err := c.Status(ctx)
+	return err == nil
+}
+
+// Status describes the running server.
+func (c *Client) Status(ctx context.Context) (*Status, error) {
+	resp, err := c.call(ctx, request{Op: opStatus})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Status, nil
+}
+
+// Stop asks the server to exit.
+func (c *Client) Stop(ctx context.Context) error {
+	_, err := c.call(ctx, request{Op: opStop})
+	return err
+}
+
+// Provider returns an ai.Provider whose calls go through the server, which
+// sets up its client with opts.
+func (c *Client) Provider(providerName, apiKey, model string, opts ai.Options) ai.Provider {
+	return &remoteProvider{client: c, name: providerName, apiKey: apiKey, model: model, opts: opts}
+}
+
+func (c *Client) call(ctx context.Context, req request) (*response, error) {
+	dialer := net.Dialer{Timeout: dialTimeout}
+	conn, err := dialer.DialContext(ctx, "unix", c.path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotRunning, err)
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, fmt.Errorf("send daemon request: %w", err)
+	}
+	var resp response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("read daemon response: %w", err)
+	}
+	if resp.Error != "" {
+		return nil, errors.New(resp.Error)
+	}
+	return &resp, nil
+}
+
+// remoteProvider forwards calls to the server, which renders the prompt and
+// calls the provider with a warm client.
+type remoteProvider struct {
+	client *Client
+	name   string
+	apiKey string
+	model  string
+	opts   ai.Options
+}
+
+func (p *remoteProvider) Name() string {
+	return p.name
+}
+
+func (p *remoteProvider) DefaultModel() string {
+	return ai.DefaultModelFor(p.name, p.opts)
+}
+
+func (p *remoteProvider) GenerateCommitMessage(ctx context.Context, input ai.PromptInput) (string, error) {
+	wire, err := toWire(input)
+	if err != nil {
+		return "", err
+	}
+	resp, err := p.client.call(ctx, request{Op: opGenerate, Provider: p.name, APIKey: p.apiKey, Model: p.model, Options: p.opts, Input: wire})
+	if err != nil {
+		return "", err
+	}
+	if resp.Usage != nil {
+		ai.RecordUsage(ctx, *resp.Usage)
+	}
+	return resp.Text, nil
+}
+
+func (p *remoteProvider) ListModels(ctx context.Context) ([]string, error) {
+	resp, err := p.client.call(ctx, request{Op: opModels, Provider: p.name, APIKey: p.apiKey, Options: p.opts})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Models, nil
+}
+
+func (p *remoteProvider) ValidateModel(ctx context.Context, model string) error {
+	models, err := p.ListModels(ctx)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(models, model) {
+		return fmt.Errorf("model %q is not available for %s", model, p.name)
+	}
+	return nil
+}
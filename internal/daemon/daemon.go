@@ -0,0 +1,128 @@
+// Package daemon keeps provider clients warm between goco invocations. The
+// server holds one client per provider, model, and API key, and caches model
+// lists; the CLI reaches it over a unix socket that only the user can open.
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/razobeckett/goco/internal/ai"
+	"github.com/razobeckett/goco/internal/state"
+)
+
+// DefaultIdleTimeout is how long the server waits without a request before
+// it exits.
+const DefaultIdleTimeout = 30 * time.Minute
+
+// modelsTTL is how long a cached model list is served before it is fetched
+// again.
+const modelsTTL = 10 * time.Minute
+
+// Operations a request can ask for.
+const (
+	opGenerate = "generate"
+	opModels   = "models"
+	opStatus   = "status"
+	opStop     = "stop"
+)
+
+// request is one call from the CLI. Each connection carries one request and
+// one response, both JSON.
+type request struct {
+	Op       string `json:"op"`
+	Provider string `json:"provider,omitempty"`
+	APIKey   string `json:"api_key,omitempty"`
+	Model    string `json:"model,omitempty"`
+	// Options are the provider settings of the CLI's config, so the
+	// client is set up as it would be without the daemon.
+	Options ai.Options   `json:"options"`
+	Input   *promptInput `json:"input,omitempty"`
+}
+
+// promptInput is ai.PromptInput on the wire. The server renders the prompt
+// with ai.BuildPrompt, so repository data is fenced exactly as it is without
+// the daemon; a custom template travels as its source text.
+type promptInput struct {
+	Status             string `json:"status,omitempty"`
+	Diff               string `json:"diff,omitempty"`
+	CustomInstructions string `json:"custom_instructions,omitempty"`
+	RecentLog          string `json:"recent_log,omitempty"`
+	Spec               string `json:"spec,omitempty"`
+	TypeHints          string `json:"type_hints,omitempty"`
+	History            string `json:"history,omitempty"`
+	Issue              string `json:"issue,omitempty"`
+	Template           string `json:"template,omitempty"`
+}
+
+func toWire(in ai.PromptInput) (*promptInput, error) {
+	w := &promptInput{
+		Status:             in.Status,
+		Diff:               in.Diff,
+		CustomInstructions: in.CustomInstructions,
+		RecentLog:          in.RecentLog,
+		Spec:               in.Spec,
+		TypeHints:          in.TypeHints,
+		History:            in.History,
+		Issue:              in.Issue,
+	}
+	if in.Template != nil {
+		text, ok := ai.TemplateSource(in.Template)
+		if !ok {
+			return nil, fmt.Errorf("prompt template %q has no source text to send to the daemon", in.Template.Name())
+		}
+		w.Template = text
+	}
+	return w, nil
+}
+
+func (w *promptInput) input() (ai.PromptInput, error) {
+	in := ai.PromptInput{
+		Status:             w.Status,
+		Diff:               w.Diff,
+		CustomInstructions: w.CustomInstructions,
+		RecentLog:          w.RecentLog,
+		Spec:               w.Spec,
+		TypeHints:          w.TypeHints,
+		History:            w.History,
+		Issue:              w.Issue,
+	}
+	if w.Template != "" {
+		tmpl, err := ai.ParsePromptTemplate(w.Template)
+		if err != nil {
+			return ai.PromptInput{}, err
+		}
+		in.Template = tmpl
+	}
+	return in, nil
+}
+
+type response struct {
+	Text string `json:"text,omitempty"`
+	// Usage is what the provider reported for a generate request.
+	Usage  *ai.Usage `json:"usage,omitempty"`
+	Models []string  `json:"models,omitempty"`
+	Status *Status   `json:"status,omitempty"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// Status describes a running server.
+type Status struct {
+	PID      int       `json:"pid"`
+	Started  time.Time `json:"started"`
+	LastUsed time.Time `json:"last_used"`
+	// Clients is how many provider clients are warm.
+	Clients int `json:"clients"`
+}
+
+// SocketPath returns where the server listens: under $XDG_RUNTIME_DIR when
+// set, since it is private to the user and cleared at logout, otherwise in
+// goco's state directory. It is "" when neither can be determined.
+func SocketPath() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "goco", "daemon.sock")
+	}
+	return state.Path("daemon.sock")
+}
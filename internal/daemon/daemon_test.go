@@ -0,0 +1,209 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/razobeckett/goco/internal/ai"
+	"github.com/razobeckett/goco/internal/policy"
+)
+
+type fakeProvider struct {
+	name        string
+	listedCount *atomic.Int32
+}
+
+func (f *fakeProvider) Name() string         { return f.name }
+func (f *fakeProvider) DefaultModel() string { return "fake-model" }
+
+func (f *fakeProvider) GenerateCommitMessage(ctx context.Context, input ai.PromptInput) (string, error) {
+	prompt, err := ai.BuildPrompt(input)
+	if err != nil {
+		return "", err
+	}
+	if !strings.Contains(prompt, "diff --git a/x b/x") {
+		return "", fmt.Errorf("prompt lost the diff")
+	}
+	ai.RecordUsage(ctx, ai.Usage{PromptTokens: 10, CompletionTokens: 3})
+	return "feat: echo", nil
+}
+
+func (f *fakeProvider) ListModels(context.Context) ([]string, error) {
+	f.listedCount.Add(1)
+	return []string{"fake-model", "other-model"}, nil
+}
+
+func (f *fakeProvider) ValidateModel(context.Context, string) error { return nil }
+
+func TestServerRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "d.sock")
+	var created, listed atomic.Int32
+	server := NewServer(path, 0, func(_ context.Context, name, _, _ string, _ ai.Options) (ai.Provider, error) {
+		created.Add(1)
+		return &fakeProvider{name: name, listedCount: &listed}, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- server.Serve(ctx) }()
+
+	client := NewClient(path)
+	deadline := time.Now().Add(5 * time.Second)
+	for !client.Running(ctx) {
+		if time.Now().After(deadline) {
+			t.Fatal("server did not start")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	provider := client.Provider("groq", "key", "fake-model", ai.Options{})
+	var meter ai.UsageMeter
+	for range 2 {
+		msg, err := provider.GenerateCommitMessage(ai.WithUsageMeter(ctx, &meter), ai.PromptInput{Diff: "diff --git a/x b/x"})
+		if err != nil {
+			t.Fatalf("generate: %v", err)
+		}
+		if msg == "" {
+			t.Fatal("expected a message")
+		}
+	}
+	if created.Load() != 1 {
+		t.Fatalf("expected one warm client, created %d", created.Load())
+	}
+	if usage, ok := meter.Usage(); !ok || usage.Total() != 26 {
+		t.Fatalf("usage over the socket = %+v, %v; want 26 tokens", usage, ok)
+	}
+
+	if err := provider.ValidateModel(ctx, "other-model"); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if err := provider.ValidateModel(ctx, "missing"); err == nil {
+		t.Fatal("expected an unknown model to fail validation")
+	}
+	if listed.Load() != 1 {
+		t.Fatalf("expected the model list to be cached, listed %d times", listed.Load())
+	}
+
+	if err := client.Stop(ctx); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+	if client.Running(ctx) {
+		t.Fatal("server still running after stop")
+	}
+}
+
+func TestPromptInputWire(t *testing.T) {
+	tmpl, err := ai.ParsePromptTemplate("{{define \"issue\"}}Ticket: {{.Issue}}{{end}}Write a message.\n{{.Diff}}\n{{if .Issue}}{{template \"issue\" .}}{{end}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := ai.PromptInput{Diff: "diff --git a/x b/x", Issue: "PROJ-1", Template: tmpl}
+	want, err := ai.BuildPrompt(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wire, err := toWire(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	back, err := wire.input()
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	got, err := ai.BuildPrompt(back)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Fatalf("prompt changed on the wire:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestServerPolicy(t *testing.T) {
+	policyPath := filepath.Join(t.TempDir(), "policy.toml")
+	if err := os.WriteFile(policyPath, []byte(`allowed_endpoints = ["https://llm.internal.example"]`+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(policy.EnvVar, policyPath)
+
+	path := filepath.Join(t.TempDir(), "d.sock")
+	var created, listed atomic.Int32
+	server := NewServer(path, 0, func(_ context.Context, name, _, _ string, _ ai.Options) (ai.Provider, error) {
+		created.Add(1)
+		return &fakeProvider{name: name, listedCount: &listed}, nil
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	go func() { _ = server.Serve(ctx) }()
+
+	client := NewClient(path)
+	for !client.Running(ctx) {
+		if ctx.Err() != nil {
+			t.Fatal("server did not start")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	_, err := client.Provider("gemini", "key", "", ai.Options{}).GenerateCommitMessage(ctx, ai.PromptInput{Diff: "diff --git a/x b/x"})
+	if err == nil || !strings.Contains(err.Error(), "denied by policy") {
+		t.Fatalf("expected the daemon to deny the endpoint, got %v", err)
+	}
+	if created.Load() != 0 {
+		t.Fatal("the daemon created a client for a denied endpoint")
+	}
+}
+
+func TestServerBackendsPerRepository(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "d.sock")
+	var listed atomic.Int32
+	var mu sync.Mutex
+	var backends []string
+	server := NewServer(path, 0, func(_ context.Context, name, _, _ string, opts ai.Options) (ai.Provider, error) {
+		mu.Lock()
+		backends = append(backends, opts.Gemini.Backend)
+		mu.Unlock()
+		return &fakeProvider{name: name, listedCount: &listed}, nil
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	go func() { _ = server.Serve(ctx) }()
+
+	client := NewClient(path)
+	for !client.Running(ctx) {
+		if ctx.Err() != nil {
+			t.Fatal("server did not start")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Two repositories use the same provider, model, and key, but one is
+	// configured for the Gemini API and the other for Vertex AI.
+	repos := []ai.Options{
+		{Gemini: ai.GeminiSettings{Backend: ai.GeminiBackendAPI}},
+		{Gemini: ai.GeminiSettings{Backend: ai.GeminiBackendVertex, Project: "acme", Location: "us-central1"}},
+	}
+	for range 2 {
+		for _, opts := range repos {
+			if _, err := client.Provider("gemini", "key", "fake-model", opts).GenerateCommitMessage(ctx, ai.PromptInput{Diff: "diff --git a/x b/x"}); err != nil {
+				t.Fatalf("generate: %v", err)
+			}
+		}
+	}
+	want := []string{ai.GeminiBackendAPI, ai.GeminiBackendVertex}
+	if !slices.Equal(backends, want) {
+		t.Fatalf("clients created for backends %q, want one each for %q", backends, want)
+	}
+}
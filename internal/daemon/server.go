@@ -0,0 +1,285 @@
+package daemon
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/razobeckett/goco/internal/ai"
+	"github.com/razobeckett/goco/internal/policy"
+)
+
+// requestTimeout bounds a single provider call made for a client.
+const requestTimeout = 2 * time.Minute
+
+// ProviderFactory creates provider clients; ai.NewProvider in production.
+type ProviderFactory func(ctx context.Context, providerName, apiKey, model string, opts ai.Options) (ai.Provider, error)
+
+// Server answers CLI requests with long-lived provider clients.
+type Server struct {
+	path        string
+	idleTimeout time.Duration
+	newProvider ProviderFactory
+
+	mu       sync.Mutex
+	clients  map[string]ai.Provider
+	models   map[string]cachedModels
+	started  time.Time
+	lastUsed time.Time
+	stop     context.CancelFunc
+}
+
+type cachedModels struct {
+	models  []string
+	fetched time.Time
+}
+
+// NewServer returns a server listening on path once Serve is called. It exits
+// after idleTimeout without requests; zero disables the timeout.
+func NewServer(path string, idleTimeout time.Duration, newProvider ProviderFactory) *Server {
+	return &Server{
+		path:        path,
+		idleTimeout: idleTimeout,
+		newProvider: newProvider,
+		clients:     make(map[string]ai.Provider),
+		models:      make(map[string]cachedModels),
+	}
+}
+
+// Serve listens until ctx is cancelled, a stop request arrives, or the server
+// has been idle for its timeout. The socket is removed on return.
+func (s *Server) Serve(ctx context.Context) error {
+	if s.path == "" {
+		return fmt.Errorf("cannot determine daemon socket path")
+	}
+	// The socket accepts API keys. Only the owner may enter its directory,
+	// so nobody else can connect even before the socket itself is
+	// restricted below.
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("create daemon directory: %w", err)
+	}
+	if err := os.Chmod(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("restrict daemon directory permissions: %w", err)
+	}
+	if _, err := os.Stat(s.path); err == nil {
+		if NewClient(s.path).Running(ctx) {
+			return fmt.Errorf("a daemon is already listening on %s", s.path)
+		}
+		// Left behind by a daemon that did not shut down cleanly.
+		if err := os.Remove(s.path); err != nil {
+			return fmt.Errorf("remove stale socket: %w", err)
+		}
+	}
+
+	listener, err := net.Listen("unix", s.path)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", s.path, err)
+	}
+	defer os.Remove(s.path)
+	if err := os.Chmod(s.path, 0o600); err != nil {
+		listener.Close()
+		return fmt.Errorf("restrict socket permissions: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	s.mu.Lock()
+	s.started = time.Now()
+	s.lastUsed = s.started
+	s.stop = cancel
+	s.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+	if s.idleTimeout > 0 {
+		go s.exitWhenIdle(ctx, cancel)
+	}
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("accept: %w", err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.handle(ctx, conn)
+		}()
+	}
+}
+
+func (s *Server) exitWhenIdle(ctx context.Context, cancel context.CancelFunc) {
+	ticker := time.NewTicker(min(s.idleTimeout, time.Minute))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			idle := time.Since(s.lastUsed)
+			s.mu.Unlock()
+			if idle >= s.idleTimeout {
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+func (s *Server) handle(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+
+	var req request
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		_ = json.NewEncoder(conn).Encode(response{Error: fmt.Sprintf("decode request: %v", err)})
+		return
+	}
+	s.mu.Lock()
+	s.lastUsed = time.Now()
+	s.mu.Unlock()
+
+	reqCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	resp := s.serve(reqCtx, req)
+	_ = json.NewEncoder(conn).Encode(resp)
+}
+
+func (s *Server) serve(ctx context.Context, req request) response {
+	switch req.Op {
+	case opStatus:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return response{Status: &Status{PID: os.Getpid(), Started: s.started, LastUsed: s.lastUsed, Clients: len(s.clients)}}
+	case opStop:
+		s.mu.Lock()
+		stop := s.stop
+		s.mu.Unlock()
+		if stop != nil {
+			stop()
+		}
+		return response{}
+	case opGenerate:
+		if req.Input == nil {
+			return response{Error: "generate request has no prompt input"}
+		}
+		input, err := req.Input.input()
+		if err != nil {
+			return response{Error: err.Error()}
+		}
+		if err := checkPolicy(req); err != nil {
+			return response{Error: err.Error()}
+		}
+		provider, err := s.provider(req)
+		if err != nil {
+			return response{Error: err.Error()}
+		}
+		var meter ai.UsageMeter
+		text, err := provider.GenerateCommitMessage(ai.WithUsageMeter(ctx, &meter), input)
+		if err != nil {
+			return response{Error: err.Error()}
+		}
+		resp := response{Text: text}
+		if usage, ok := meter.Usage(); ok {
+			resp.Usage = &usage
+		}
+		return resp
+	case opModels:
+		models, err := s.listModels(ctx, req)
+		if err != nil {
+			return response{Error: err.Error()}
+		}
+		return response{Models: models}
+	default:
+		return response{Error: fmt.Sprintf("unknown operation %q", req.Op)}
+	}
+}
+
+// checkPolicy applies the organization policy to a generate request. The
+// CLI checked the endpoint its own environment names; this checks the one
+// the daemon will actually dial.
+func checkPolicy(req request) error {
+	pol, err := policy.Load()
+	if err != nil {
+		return err
+	}
+	model := req.Model
+	if model == "" {
+		model = ai.DefaultModelFor(req.Provider, req.Options)
+	}
+	if err := pol.Check(policy.Request{Provider: req.Provider, Model: model, Endpoint: ai.Endpoint(req.Provider, req.Options)}); err != nil {
+		return fmt.Errorf("%w (policy file: %s)", err, pol.Path())
+	}
+	return nil
+}
+
+// provider returns the warm client for req, creating it on first use. The
+// API key and the provider settings are part of the cache key, hashed, so
+// rotating a key or running from a repository configured for another
+// endpoint, region, or backend starts a new client. Clients are created
+// outside the lock so one slow setup does not hold up other requests; if two
+// race, the first stored wins.
+func (s *Server) provider(req request) (ai.Provider, error) {
+	key := clientKey(req.Provider, req.Model, req.APIKey, req.Options)
+	s.mu.Lock()
+	p, ok := s.clients[key]
+	s.mu.Unlock()
+	if ok {
+		return p, nil
+	}
+	// Clients outlive this request, so they get a background context.
+	p, err := s.newProvider(context.Background(), req.Provider, req.APIKey, req.Model, req.Options)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.clients[key]; ok {
+		return existing, nil
+	}
+	s.clients[key] = p
+	return p, nil
+}
+
+func (s *Server) listModels(ctx context.Context, req request) ([]string, error) {
+	key := clientKey(req.Provider, "", req.APIKey, req.Options)
+	s.mu.Lock()
+	cached, ok := s.models[key]
+	s.mu.Unlock()
+	if ok && time.Since(cached.fetched) < modelsTTL {
+		return cached.models, nil
+	}
+
+	provider, err := s.provider(request{Provider: req.Provider, APIKey: req.APIKey, Options: req.Options})
+	if err != nil {
+		return nil, err
+	}
+	models, err := provider.ListModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.models[key] = cachedModels{models: models, fetched: time.Now()}
+	s.mu.Unlock()
+	return models, nil
+}
+
+func clientKey(provider, model, apiKey string, opts ai.Options) string {
+	settings, _ := json.Marshal(opts)
+	sum := sha256.Sum256(append([]byte(apiKey+"\x00"), settings...))
+	return provider + "\x00" + model + "\x00" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,108 @@
+package forge
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// azureAPIVersion pins the Azure DevOps REST API version.
+const azureAPIVersion = "7.1"
+
+type azureDevOps struct {
+	client  *client
+	baseURL string
+	webURL  string
+}
+
+type azurePullRequestInput struct {
+	Title         string `json:"title"`
+	Description   string `json:"description"`
+	SourceRefName string `json:"sourceRefName,omitempty"`
+	TargetRefName string `json:"targetRefName,omitempty"`
+}
+
+type azurePullRequest struct {
+	PullRequestID int    `json:"pullRequestId"`
+	Title         string `json:"title"`
+	Description   string `json:"description"`
+	SourceRefName string `json:"sourceRefName"`
+	TargetRefName string `json:"targetRefName"`
+}
+
+func newAzureDevOps(c *client, r *Remote) *azureDevOps {
+	// Personal access tokens use basic auth with an empty user name.
+	c.auth = func(req *http.Request, token string) {
+		req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(":"+token)))
+	}
+	repoPath := fmt.Sprintf("%s/%s/_git/%s", url.PathEscape(r.Owner), url.PathEscape(r.Project), url.PathEscape(r.Repo))
+	return &azureDevOps{
+		client: c,
+		baseURL: fmt.Sprintf("https://dev.azure.com/%s/%s/_apis/git/repositories/%s/pullrequests",
+			url.PathEscape(r.Owner), url.PathEscape(r.Project), url.PathEscape(r.Repo)),
+		webURL: "https://dev.azure.com/" + repoPath + "/pullrequest/",
+	}
+}
+
+func (a *azureDevOps) Name() string { return "Azure DevOps" }
+
+func (a *azureDevOps) FindPullRequest(ctx context.Context, head string) (*PullRequest, error) {
+	var page struct {
+		Value []azurePullRequest `json:"value"`
+	}
+	query := url.Values{
+		"searchCriteria.sourceRefName": {"refs/heads/" + head},
+		"searchCriteria.status":        {"active"},
+		"api-version":                  {azureAPIVersion},
+	}
+	if err := a.client.do(ctx, http.MethodGet, a.baseURL+"?"+query.Encode(), nil, &page); err != nil {
+		return nil, err
+	}
+	if len(page.Value) == 0 {
+		return nil, nil
+	}
+	return a.pullRequest(page.Value[0]), nil
+}
+
+func (a *azureDevOps) CreatePullRequest(ctx context.Context, pr PullRequest) (*PullRequest, error) {
+	in := azurePullRequestInput{
+		Title:         pr.Title,
+		Description:   pr.Body,
+		SourceRefName: "refs/heads/" + pr.Head,
+		TargetRefName: "refs/heads/" + pr.Base,
+	}
+	var out azurePullRequest
+	if err := a.client.do(ctx, http.MethodPost, a.baseURL+"?api-version="+azureAPIVersion, in, &out); err != nil {
+		return nil, err
+	}
+	return a.pullRequest(out), nil
+}
+
+func (a *azureDevOps) UpdatePullRequest(ctx context.Context, pr PullRequest) (*PullRequest, error) {
+	in := azurePullRequestInput{Title: pr.Title, Description: pr.Body}
+	var out azurePullRequest
+	endpoint := fmt.Sprintf("%s/%d?api-version=%s", a.baseURL, pr.Number, azureAPIVersion)
+	if err := a.client.do(ctx, http.MethodPatch, endpoint, in, &out); err != nil {
+		return nil, err
+	}
+	return a.pullRequest(out), nil
+}
+
+func (a *azureDevOps) pullRequest(p azurePullRequest) *PullRequest {
+	return &PullRequest{
+		Number: p.PullRequestID,
+		URL:    fmt.Sprintf("%s%d", a.webURL, p.PullRequestID),
+		Title:  p.Title,
+		Body:   p.Description,
+		Head:   strings.TrimPrefix(p.SourceRefName, "refs/heads/"),
+		Base:   strings.TrimPrefix(p.TargetRefName, "refs/heads/"),
+	}
+}
+
+// AddReviewers is unsupported: identity lookup needs the separate Graph API.
+func (a *azureDevOps) AddReviewers(context.Context, int, []string) ([]string, error) {
+	return nil, ErrReviewersUnsupported
+}
@@ -0,0 +1,102 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type bitbucket struct {
+	client    *client
+	baseURL   string
+	workspace string
+	repo      string
+}
+
+type bitbucketBranchRef struct {
+	Branch struct {
+		Name string `json:"name"`
+	} `json:"branch"`
+}
+
+type bitbucketPullRequest struct {
+	ID          int                 `json:"id,omitempty"`
+	Title       string              `json:"title"`
+	Description string              `json:"description"`
+	Source      *bitbucketBranchRef `json:"source,omitempty"`
+	Destination *bitbucketBranchRef `json:"destination,omitempty"`
+	Links       *struct {
+		HTML struct {
+			Href string `json:"href"`
+		} `json:"html"`
+	} `json:"links,omitempty"`
+}
+
+func newBitbucket(c *client, r *Remote) *bitbucket {
+	c.auth = bearerAuth
+	return &bitbucket{client: c, baseURL: "https://api.bitbucket.org/2.0", workspace: r.Owner, repo: r.Repo}
+}
+
+func (b *bitbucket) Name() string { return "Bitbucket" }
+
+func (b *bitbucket) pullRequestsURL() string {
+	return fmt.Sprintf("%s/repositories/%s/%s/pullrequests", b.baseURL, url.PathEscape(b.workspace), url.PathEscape(b.repo))
+}
+
+func (b *bitbucket) FindPullRequest(ctx context.Context, head string) (*PullRequest, error) {
+	var page struct {
+		Values []bitbucketPullRequest `json:"values"`
+	}
+	query := url.Values{"q": {fmt.Sprintf(`source.branch.name = %q AND state = "OPEN"`, head)}}
+	if err := b.client.do(ctx, http.MethodGet, b.pullRequestsURL()+"?"+query.Encode(), nil, &page); err != nil {
+		return nil, err
+	}
+	if len(page.Values) == 0 {
+		return nil, nil
+	}
+	return page.Values[0].pullRequest(), nil
+}
+
+func (b *bitbucket) CreatePullRequest(ctx context.Context, pr PullRequest) (*PullRequest, error) {
+	in := bitbucketPullRequest{Title: pr.Title, Description: pr.Body, Source: branchRef(pr.Head), Destination: branchRef(pr.Base)}
+	var out bitbucketPullRequest
+	if err := b.client.do(ctx, http.MethodPost, b.pullRequestsURL(), in, &out); err != nil {
+		return nil, err
+	}
+	return out.pullRequest(), nil
+}
+
+func (b *bitbucket) UpdatePullRequest(ctx context.Context, pr PullRequest) (*PullRequest, error) {
+	in := bitbucketPullRequest{Title: pr.Title, Description: pr.Body}
+	var out bitbucketPullRequest
+	if err := b.client.do(ctx, http.MethodPut, fmt.Sprintf("%s/%d", b.pullRequestsURL(), pr.Number), in, &out); err != nil {
+		return nil, err
+	}
+	return out.pullRequest(), nil
+}
+
+func branchRef(name string) *bitbucketBranchRef {
+	ref := &bitbucketBranchRef{}
+	ref.Branch.Name = name
+	return ref
+}
+
+func (p bitbucketPullRequest) pullRequest() *PullRequest {
+	pr := &PullRequest{Number: p.ID, Title: p.Title, Body: p.Description}
+	if p.Source != nil {
+		pr.Head = p.Source.Branch.Name
+	}
+	if p.Destination != nil {
+		pr.Base = p.Destination.Branch.Name
+	}
+	if p.Links != nil {
+		pr.URL = p.Links.HTML.Href
+	}
+	return pr
+}
+
+// AddReviewers is unsupported: Bitbucket Cloud does not expose account emails.
+func (b *bitbucket) AddReviewers(context.Context, int, []string) ([]string, error) {
+	return nil, ErrReviewersUnsupported
+}
@@ -0,0 +1,287 @@
+// Package forge creates and updates pull requests on code hosting services
+// and fetches tickets from issue trackers. The service is picked from the git
+// remote URL.
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Supported forge kinds.
+const (
+	KindGitHub      = "github"
+	KindGitLab      = "gitlab"
+	KindBitbucket   = "bitbucket"
+	KindAzureDevOps = "azure-devops"
+)
+
+// PullRequest is the forge-neutral view of a pull or merge request.
+type PullRequest struct {
+	Number int
+	URL    string
+	Title  string
+	Body   string
+	Head   string
+	Base   string
+}
+
+// Forge creates and updates pull requests for one repository.
+type Forge interface {
+	Name() string
+	// FindPullRequest returns the open pull request for head, or nil.
+	FindPullRequest(ctx context.Context, head string) (*PullRequest, error)
+	CreatePullRequest(ctx context.Context, pr PullRequest) (*PullRequest, error)
+	// UpdatePullRequest replaces the title and description of pr.Number.
+	UpdatePullRequest(ctx context.Context, pr PullRequest) (*PullRequest, error)
+	// AddReviewers requests reviews on pull request number from the accounts
+	// matching emails and returns the accounts added. Emails without a
+	// matching account are skipped.
+	AddReviewers(ctx context.Context, number int, emails []string) ([]string, error)
+}
+
+// ErrReviewersUnsupported is returned by forges whose API cannot map commit
+// emails to accounts.
+var ErrReviewersUnsupported = errors.New("adding reviewers by email is not supported on this forge")
+
+// Remote identifies a repository on a forge.
+type Remote struct {
+	Kind string
+	Host string
+	// Owner is the GitHub owner, GitLab namespace, Bitbucket workspace, or
+	// Azure DevOps organization.
+	Owner string
+	// Project is the Azure DevOps project; empty elsewhere.
+	Project string
+	Repo    string
+}
+
+// tokenEnvVars lists, per kind, the environment variables searched for an API token.
+var tokenEnvVars = map[string][]string{
+	KindGitHub:      {"GOCO_GITHUB_TOKEN", "GITHUB_TOKEN", "GH_TOKEN"},
+	KindGitLab:      {"GOCO_GITLAB_TOKEN", "GITLAB_TOKEN"},
+	KindBitbucket:   {"GOCO_BITBUCKET_TOKEN", "BITBUCKET_TOKEN"},
+	KindAzureDevOps: {"GOCO_AZURE_DEVOPS_TOKEN", "AZURE_DEVOPS_EXT_PAT"},
+	KindJira:        {"GOCO_JIRA_TOKEN", "JIRA_API_TOKEN"},
+}
+
+// TokenEnvVars returns the environment variables checked for kind's token, in order.
+func TokenEnvVars(kind string) []string {
+	return tokenEnvVars[kind]
+}
+
+// Token returns the first API token set for kind.
+func Token(kind string) string {
+	for _, env := range tokenEnvVars[kind] {
+		if token := os.Getenv(env); token != "" {
+			return token
+		}
+	}
+	return ""
+}
+
+// ParseRemote recognizes HTTPS and SSH remote URLs of the supported forges.
+// Self-hosted GitHub Enterprise and GitLab instances are only recognized when
+// hosts maps their host name to KindGitHub or KindGitLab, so a token is never
+// sent to a server the user did not name.
+func ParseRemote(raw string, hosts map[string]string) (*Remote, error) {
+	host, path, err := splitRemote(raw)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.Split(path, "/")
+
+	kind := hosts[host]
+	if kind != "" && kind != KindGitHub && kind != KindGitLab {
+		return nil, fmt.Errorf("forge host %q: unsupported kind %q; use %q or %q", host, kind, KindGitHub, KindGitLab)
+	}
+
+	switch {
+	case host == "dev.azure.com" || host == "ssh.dev.azure.com" || strings.HasSuffix(host, ".visualstudio.com"):
+		return parseAzureRemote(host, parts)
+	case host == "bitbucket.org":
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("unrecognized Bitbucket remote %q", raw)
+		}
+		return &Remote{Kind: KindBitbucket, Host: host, Owner: parts[0], Repo: parts[1]}, nil
+	case host == "github.com" || kind == KindGitHub:
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("unrecognized GitHub remote %q", raw)
+		}
+		return &Remote{Kind: KindGitHub, Host: host, Owner: parts[0], Repo: parts[1]}, nil
+	case host == "gitlab.com" || kind == KindGitLab:
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("unrecognized GitLab remote %q", raw)
+		}
+		// GitLab namespaces can be nested groups.
+		last := len(parts) - 1
+		return &Remote{Kind: KindGitLab, Host: host, Owner: strings.Join(parts[:last], "/"), Repo: parts[last]}, nil
+	default:
+		return nil, fmt.Errorf("cannot tell which forge hosts %q; supported: GitHub, GitLab, Bitbucket, Azure DevOps (list self-hosted GitHub and GitLab hosts under [Forges])", host)
+	}
+}
+
+// RemotePath returns the host and repository path of a remote URL, such as
+// "github.com/acme/api", whatever its scheme, user, or ".git" suffix.
+func RemotePath(raw string) (string, error) {
+	host, path, err := splitRemote(raw)
+	if err != nil {
+		return "", err
+	}
+	return host + "/" + path, nil
+}
+
+// splitRemote returns the host of raw and its path without slashes around
+// it or the ".git" suffix.
+func splitRemote(raw string) (string, string, error) {
+	host, path, err := splitRemoteURL(raw)
+	if err != nil {
+		return "", "", err
+	}
+	return host, strings.TrimSuffix(strings.Trim(path, "/"), ".git"), nil
+}
+
+func parseAzureRemote(host string, parts []string) (*Remote, error) {
+	r := &Remote{Kind: KindAzureDevOps, Host: "dev.azure.com"}
+	switch {
+	case host == "ssh.dev.azure.com" && len(parts) == 4 && parts[0] == "v3":
+		// git@ssh.dev.azure.com:v3/org/project/repo
+		r.Owner, r.Project, r.Repo = parts[1], parts[2], parts[3]
+	case host == "dev.azure.com" && len(parts) == 4 && parts[2] == "_git":
+		// https://dev.azure.com/org/project/_git/repo
+		r.Owner, r.Project, r.Repo = parts[0], parts[1], parts[3]
+	case strings.HasSuffix(host, ".visualstudio.com") && len(parts) == 3 && parts[1] == "_git":
+		// https://org.visualstudio.com/project/_git/repo
+		r.Owner, r.Project, r.Repo = strings.TrimSuffix(host, ".visualstudio.com"), parts[0], parts[2]
+	default:
+		return nil, fmt.Errorf("unrecognized Azure DevOps remote path %q", strings.Join(parts, "/"))
+	}
+	return r, nil
+}
+
+// splitRemoteURL handles scheme URLs and scp-like "user@host:path" remotes.
+func splitRemoteURL(raw string) (string, string, error) {
+	if strings.Contains(raw, "://") {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return "", "", fmt.Errorf("parse remote URL: %w", err)
+		}
+		return strings.ToLower(u.Hostname()), u.Path, nil
+	}
+
+	hostPart, path, ok := strings.Cut(raw, ":")
+	if !ok {
+		return "", "", fmt.Errorf("unrecognized remote URL %q", raw)
+	}
+	if _, host, found := strings.Cut(hostPart, "@"); found {
+		hostPart = host
+	}
+	return strings.ToLower(hostPart), path, nil
+}
+
+// New returns the forge client for remote, authenticated with token.
+func New(remote *Remote, token string) (Forge, error) {
+	if token == "" {
+		return nil, fmt.Errorf("no API token for %s; set %s", remote.Kind, strings.Join(tokenEnvVars[remote.Kind], " or "))
+	}
+
+	c := &client{http: &http.Client{Timeout: 30 * time.Second}, token: token}
+	switch remote.Kind {
+	case KindGitHub:
+		return newGitHub(c, remote), nil
+	case KindGitLab:
+		return newGitLab(c, remote), nil
+	case KindBitbucket:
+		return newBitbucket(c, remote), nil
+	case KindAzureDevOps:
+		return newAzureDevOps(c, remote), nil
+	default:
+		return nil, fmt.Errorf("unsupported forge %q", remote.Kind)
+	}
+}
+
+// client is the JSON-over-HTTP transport shared by all forges.
+type client struct {
+	http  *http.Client
+	token string
+	// auth sets the forge-specific authentication header.
+	auth func(req *http.Request, token string)
+}
+
+func (c *client) do(ctx context.Context, method, endpoint string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		payload, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	c.auth(req, c.token)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, redactQuery(endpoint), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s %s: %s: %s", method, redactQuery(endpoint), resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+func redactQuery(endpoint string) string {
+	path, _, _ := strings.Cut(endpoint, "?")
+	return path
+}
+
+func bearerAuth(req *http.Request, token string) {
+	req.Header.Set("Authorization", "Bearer "+token)
+}
+
+// descriptionTemplates lists, per kind, where a repository keeps its default
+// pull request description template, relative to the repository root.
+var descriptionTemplates = map[string][]string{
+	KindGitHub: {".github/pull_request_template.md", ".github/PULL_REQUEST_TEMPLATE.md", "docs/pull_request_template.md", "pull_request_template.md"},
+	KindGitLab: {".gitlab/merge_request_templates/Default.md"},
+	// Azure DevOps reads the same locations under .azuredevops first.
+	KindAzureDevOps: {".azuredevops/pull_request_template.md", ".vsts/pull_request_template.md", "docs/pull_request_template.md", "pull_request_template.md"},
+}
+
+// DescriptionTemplate returns the repository's default pull request
+// description template for kind, or "" when there is none.
+func DescriptionTemplate(root, kind string) string {
+	for _, rel := range descriptionTemplates[kind] {
+		if data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(rel))); err == nil {
+			return strings.TrimSpace(string(data))
+		}
+	}
+	return ""
+}
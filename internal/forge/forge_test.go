@@ -0,0 +1,270 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestParseRemote(t *testing.T) {
+	tests := []struct {
+		url  string
+		want Remote
+	}{
+		{"git@github.com:razobeckett/goco.git", Remote{Kind: KindGitHub, Host: "github.com", Owner: "razobeckett", Repo: "goco"}},
+		{"https://github.com/razobeckett/goco", Remote{Kind: KindGitHub, Host: "github.com", Owner: "razobeckett", Repo: "goco"}},
+		{"https://gitlab.com/group/sub/project.git", Remote{Kind: KindGitLab, Host: "gitlab.com", Owner: "group/sub", Repo: "project"}},
+		{"ssh://git@gitlab.example.com:2222/team/app.git", Remote{Kind: KindGitLab, Host: "gitlab.example.com", Owner: "team", Repo: "app"}},
+		{"https://git.corp.example/org/svc.git", Remote{Kind: KindGitHub, Host: "git.corp.example", Owner: "org", Repo: "svc"}},
+		{"git@bitbucket.org:workspace/repo.git", Remote{Kind: KindBitbucket, Host: "bitbucket.org", Owner: "workspace", Repo: "repo"}},
+		{"https://org@dev.azure.com/org/Project/_git/repo", Remote{Kind: KindAzureDevOps, Host: "dev.azure.com", Owner: "org", Project: "Project", Repo: "repo"}},
+		{"git@ssh.dev.azure.com:v3/org/Project/repo", Remote{Kind: KindAzureDevOps, Host: "dev.azure.com", Owner: "org", Project: "Project", Repo: "repo"}},
+		{"https://org.visualstudio.com/Project/_git/repo", Remote{Kind: KindAzureDevOps, Host: "dev.azure.com", Owner: "org", Project: "Project", Repo: "repo"}},
+	}
+
+	hosts := map[string]string{"gitlab.example.com": KindGitLab, "git.corp.example": KindGitHub}
+	for _, tt := range tests {
+		got, err := ParseRemote(tt.url, hosts)
+		if err != nil {
+			t.Fatalf("ParseRemote(%q) failed: %v", tt.url, err)
+		}
+		if *got != tt.want {
+			t.Fatalf("ParseRemote(%q) = %+v, want %+v", tt.url, *got, tt.want)
+		}
+	}
+
+	for _, url := range []string{
+		"https://git.example.com/a/b.git",
+		// Lookalike hosts must be configured before they receive a token.
+		"https://github.com.evil.example/a/b.git",
+		"git@gitlab.attacker.example:a/b.git",
+	} {
+		if _, err := ParseRemote(url, hosts); err == nil {
+			t.Fatalf("expected an error for unlisted host in %q", url)
+		}
+	}
+	if _, err := ParseRemote("https://git.example.com/a/b.git", map[string]string{"git.example.com": "gitea"}); err == nil {
+		t.Fatal("expected an error for an unsupported forge kind")
+	}
+}
+
+func TestRemotePath(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"git@github.com:acme/api.git", "github.com/acme/api"},
+		{"https://user@GitHub.com/acme/api.git", "github.com/acme/api"},
+		{"ssh://git@git.example.com:2222/group/sub/api", "git.example.com/group/sub/api"},
+	}
+	for _, tt := range tests {
+		got, err := RemotePath(tt.raw)
+		if err != nil || got != tt.want {
+			t.Errorf("RemotePath(%q) = %q, %v; want %q", tt.raw, got, err, tt.want)
+		}
+	}
+	if _, err := RemotePath("not a remote"); err == nil {
+		t.Error("expected an error for an unrecognized remote")
+	}
+}
+
+func TestGitHubCreateAndUpdate(t *testing.T) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("unexpected Authorization header %q", got)
+		}
+
+		var in gitHubPullRequest
+		_ = json.NewDecoder(r.Body).Decode(&in)
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`[]`))
+		case http.MethodPost:
+			if in.Head != "feature" || in.Base != "main" {
+				t.Errorf("unexpected create payload %+v", in)
+			}
+			_, _ = w.Write([]byte(`{"number": 7, "html_url": "https://github.com/o/r/pull/7"}`))
+		case http.MethodPatch:
+			_, _ = w.Write([]byte(`{"number": 7, "html_url": "https://github.com/o/r/pull/7"}`))
+		}
+	}))
+	defer srv.Close()
+
+	remote := &Remote{Kind: KindGitHub, Host: "github.com", Owner: "o", Repo: "r"}
+	f, err := New(remote, "secret")
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	f.(*gitHub).baseURL = srv.URL
+
+	ctx := context.Background()
+	existing, err := f.FindPullRequest(ctx, "feature")
+	if err != nil || existing != nil {
+		t.Fatalf("expected no open pull request, got %+v, %v", existing, err)
+	}
+
+	pr, err := f.CreatePullRequest(ctx, PullRequest{Title: "feat: x", Body: "Body", Head: "feature", Base: "main"})
+	if err != nil {
+		t.Fatalf("CreatePullRequest failed: %v", err)
+	}
+	if pr.Number != 7 || pr.URL != "https://github.com/o/r/pull/7" {
+		t.Fatalf("unexpected pull request %+v", pr)
+	}
+
+	if _, err := f.UpdatePullRequest(ctx, *pr); err != nil {
+		t.Fatalf("UpdatePullRequest failed: %v", err)
+	}
+
+	want := []string{"GET /repos/o/r/pulls", "POST /repos/o/r/pulls", "PATCH /repos/o/r/pulls/7"}
+	for i := range want {
+		if i >= len(requests) || requests[i] != want[i] {
+			t.Fatalf("unexpected requests %v, want %v", requests, want)
+		}
+	}
+}
+
+func TestNewRequiresToken(t *testing.T) {
+	_, err := New(&Remote{Kind: KindBitbucket}, "")
+	if err == nil {
+		t.Fatal("expected an error without a token")
+	}
+}
+
+func TestGitHubCLIToken(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake gh is a shell script")
+	}
+	dir := t.TempDir()
+	t.Setenv("PATH", dir)
+	if got := GitHubCLIToken(context.Background()); got != "" {
+		t.Fatalf("GitHubCLIToken() without gh = %q", got)
+	}
+
+	script := "#!/bin/sh\n[ \"$*\" = \"auth token --hostname github.com\" ] && echo gho_test || exit 1\n"
+	if err := os.WriteFile(filepath.Join(dir, "gh"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if got := GitHubCLIToken(context.Background()); got != "gho_test" {
+		t.Fatalf("GitHubCLIToken() = %q, want gho_test", got)
+	}
+}
+
+func TestGitLabChangelogCategory(t *testing.T) {
+	if got := GitLabChangelogCategory("feat"); got != "added" {
+		t.Fatalf("feat maps to %q, want added", got)
+	}
+	if got := GitLabChangelogCategory("docs"); got != "" {
+		t.Fatalf("docs maps to %q, want no trailer", got)
+	}
+}
+
+func TestGitHubAddReviewers(t *testing.T) {
+	var requested []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search/users":
+			if r.URL.Query().Get("q") == "bob@example.com in:email" {
+				_, _ = w.Write([]byte(`{"items": [{"login": "bob"}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"items": []}`))
+		case "/repos/o/r/pulls/7/requested_reviewers":
+			var in struct {
+				Reviewers []string `json:"reviewers"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&in)
+			requested = in.Reviewers
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	f, err := New(&Remote{Kind: KindGitHub, Host: "github.com", Owner: "o", Repo: "r"}, "secret")
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	f.(*gitHub).baseURL = srv.URL
+
+	added, err := f.AddReviewers(context.Background(), 7, []string{"123+alice@users.noreply.github.com", "bob@example.com", "nobody@example.com"})
+	if err != nil {
+		t.Fatalf("AddReviewers failed: %v", err)
+	}
+	if len(added) != 2 || added[0] != "alice" || added[1] != "bob" || len(requested) != 2 {
+		t.Fatalf("unexpected reviewers added=%v requested=%v", added, requested)
+	}
+}
+
+func TestIssueKey(t *testing.T) {
+	tests := []struct {
+		branch, kind string
+		projects     []string
+		want         string
+	}{
+		{"feature/PROJ-123-add-login", KindJira, nil, "PROJ-123"},
+		{"proj-42_fix", KindJira, nil, "PROJ-42"},
+		{"main", KindJira, nil, ""},
+		{"release-1.2", KindJira, nil, ""},
+		{"hotfix/release-1.2.3", KindJira, nil, ""},
+		{"feature/v2-3-PROJ-9", KindJira, []string{"proj"}, "PROJ-9"},
+		{"release-2-hotfix", KindJira, []string{"PROJ"}, ""},
+		{"42-fix-login", KindGitHub, nil, "42"},
+		{"feature/issue-7", KindGitLab, nil, "7"},
+		{"release-1.2", KindGitHub, nil, ""},
+		{"v2-migration", KindGitHub, nil, ""},
+	}
+	for _, tt := range tests {
+		if got := IssueKey(tt.branch, tt.kind, tt.projects); got != tt.want {
+			t.Errorf("IssueKey(%q, %q, %q) = %q, want %q", tt.branch, tt.kind, tt.projects, got, tt.want)
+		}
+	}
+}
+
+func TestIssueSummaryTruncation(t *testing.T) {
+	issue := &Issue{Key: "PROJ-1", Title: "Add login", Description: strings.Repeat("a", issueDescriptionLimit-1) + "é and more"}
+	summary := issue.Summary()
+	if !utf8.ValidString(summary) {
+		t.Fatalf("truncation split a rune: %q", summary[len(summary)-40:])
+	}
+	if !strings.HasSuffix(summary, strings.Repeat("a", 10)+"\n[description truncated]") {
+		t.Fatalf("expected the description cut before the multibyte rune, got %q", summary[len(summary)-40:])
+	}
+}
+
+func TestJiraIssue(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/2/issue/PROJ-1" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if user, pass, ok := r.BasicAuth(); !ok || user != "me@example.com" || pass != "secret" {
+			t.Errorf("unexpected basic auth %q %q", user, pass)
+		}
+		_, _ = w.Write([]byte(`{"fields": {"summary": "Add login", "description": "Users need SSO."}}`))
+	}))
+	defer srv.Close()
+
+	tracker, err := NewJira(srv.URL+"/", "me@example.com", "secret")
+	if err != nil {
+		t.Fatalf("NewJira failed: %v", err)
+	}
+	issue, err := tracker.Issue(context.Background(), "PROJ-1")
+	if err != nil {
+		t.Fatalf("Issue failed: %v", err)
+	}
+	if got, want := issue.Summary(), "PROJ-1: Add login\n\nUsers need SSO."; got != want {
+		t.Fatalf("Summary() = %q, want %q", got, want)
+	}
+	if issue.URL != srv.URL+"/browse/PROJ-1" {
+		t.Fatalf("unexpected URL %q", issue.URL)
+	}
+}
@@ -0,0 +1,146 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+)
+
+// GitHubCLIToken returns the token the gh CLI is logged in to github.com
+// with, or "" when gh is not installed or not logged in.
+func GitHubCLIToken(ctx context.Context) string {
+	if _, err := exec.LookPath("gh"); err != nil {
+		return ""
+	}
+	out, err := exec.CommandContext(ctx, "gh", "auth", "token", "--hostname", "github.com").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+type gitHub struct {
+	client  *client
+	baseURL string
+	owner   string
+	repo    string
+}
+
+type gitHubPullRequest struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	Head  string `json:"head,omitempty"`
+	Base  string `json:"base,omitempty"`
+}
+
+type gitHubPull struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+	Head    struct {
+		Ref string `json:"ref"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+func newGitHub(c *client, r *Remote) *gitHub {
+	c.auth = bearerAuth
+	baseURL := "https://api.github.com"
+	if r.Host != "github.com" {
+		// GitHub Enterprise Server serves the REST API under /api/v3.
+		baseURL = "https://" + r.Host + "/api/v3"
+	}
+	return &gitHub{client: c, baseURL: baseURL, owner: r.Owner, repo: r.Repo}
+}
+
+func (g *gitHub) Name() string { return "GitHub" }
+
+func (g *gitHub) pullsURL() string {
+	return fmt.Sprintf("%s/repos/%s/%s/pulls", g.baseURL, url.PathEscape(g.owner), url.PathEscape(g.repo))
+}
+
+func (g *gitHub) FindPullRequest(ctx context.Context, head string) (*PullRequest, error) {
+	var pulls []gitHubPull
+	query := url.Values{"state": {"open"}, "head": {g.owner + ":" + head}}
+	if err := g.client.do(ctx, http.MethodGet, g.pullsURL()+"?"+query.Encode(), nil, &pulls); err != nil {
+		return nil, err
+	}
+	if len(pulls) == 0 {
+		return nil, nil
+	}
+	p := pulls[0]
+	return &PullRequest{Number: p.Number, URL: p.HTMLURL, Title: p.Title, Body: p.Body, Head: p.Head.Ref, Base: p.Base.Ref}, nil
+}
+
+func (g *gitHub) CreatePullRequest(ctx context.Context, pr PullRequest) (*PullRequest, error) {
+	var out gitHubPull
+	in := gitHubPullRequest{Title: pr.Title, Body: pr.Body, Head: pr.Head, Base: pr.Base}
+	if err := g.client.do(ctx, http.MethodPost, g.pullsURL(), in, &out); err != nil {
+		return nil, err
+	}
+	pr.Number, pr.URL = out.Number, out.HTMLURL
+	return &pr, nil
+}
+
+func (g *gitHub) UpdatePullRequest(ctx context.Context, pr PullRequest) (*PullRequest, error) {
+	var out gitHubPull
+	in := gitHubPullRequest{Title: pr.Title, Body: pr.Body}
+	if err := g.client.do(ctx, http.MethodPatch, fmt.Sprintf("%s/%d", g.pullsURL(), pr.Number), in, &out); err != nil {
+		return nil, err
+	}
+	pr.URL = out.HTMLURL
+	return &pr, nil
+}
+
+func (g *gitHub) AddReviewers(ctx context.Context, number int, emails []string) ([]string, error) {
+	var logins []string
+	for _, email := range emails {
+		login, err := g.login(ctx, email)
+		if err != nil {
+			return nil, err
+		}
+		if login != "" {
+			logins = append(logins, login)
+		}
+	}
+	if len(logins) == 0 {
+		return nil, nil
+	}
+
+	in := map[string][]string{"reviewers": logins}
+	if err := g.client.do(ctx, http.MethodPost, fmt.Sprintf("%s/%d/requested_reviewers", g.pullsURL(), number), in, nil); err != nil {
+		return nil, err
+	}
+	return logins, nil
+}
+
+// login maps a commit email to a GitHub login: noreply addresses carry the
+// login, others go through user search, which only finds public emails.
+func (g *gitHub) login(ctx context.Context, email string) (string, error) {
+	if local, ok := strings.CutSuffix(email, "@users.noreply.github.com"); ok {
+		if _, login, found := strings.Cut(local, "+"); found {
+			return login, nil
+		}
+		return local, nil
+	}
+
+	var result struct {
+		Items []struct {
+			Login string `json:"login"`
+		} `json:"items"`
+	}
+	query := url.Values{"q": {email + " in:email"}}
+	if err := g.client.do(ctx, http.MethodGet, g.baseURL+"/search/users?"+query.Encode(), nil, &result); err != nil {
+		return "", err
+	}
+	if len(result.Items) == 0 {
+		return "", nil
+	}
+	return result.Items[0].Login, nil
+}
@@ -0,0 +1,126 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type gitLab struct {
+	client  *client
+	baseURL string
+	project string
+}
+
+type gitLabMergeRequestInput struct {
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	SourceBranch string `json:"source_branch,omitempty"`
+	TargetBranch string `json:"target_branch,omitempty"`
+}
+
+type gitLabMergeRequest struct {
+	IID          int    `json:"iid"`
+	WebURL       string `json:"web_url"`
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	SourceBranch string `json:"source_branch"`
+	TargetBranch string `json:"target_branch"`
+}
+
+func newGitLab(c *client, r *Remote) *gitLab {
+	c.auth = func(req *http.Request, token string) {
+		req.Header.Set("PRIVATE-TOKEN", token)
+	}
+	return &gitLab{
+		client:  c,
+		baseURL: "https://" + r.Host + "/api/v4",
+		project: r.Owner + "/" + r.Repo,
+	}
+}
+
+func (g *gitLab) Name() string { return "GitLab" }
+
+func (g *gitLab) mergeRequestsURL() string {
+	// The API addresses projects by their URL-encoded full path.
+	return fmt.Sprintf("%s/projects/%s/merge_requests", g.baseURL, url.PathEscape(g.project))
+}
+
+func (g *gitLab) FindPullRequest(ctx context.Context, head string) (*PullRequest, error) {
+	var mrs []gitLabMergeRequest
+	query := url.Values{"state": {"opened"}, "source_branch": {head}}
+	if err := g.client.do(ctx, http.MethodGet, g.mergeRequestsURL()+"?"+query.Encode(), nil, &mrs); err != nil {
+		return nil, err
+	}
+	if len(mrs) == 0 {
+		return nil, nil
+	}
+	return mrs[0].pullRequest(), nil
+}
+
+func (g *gitLab) CreatePullRequest(ctx context.Context, pr PullRequest) (*PullRequest, error) {
+	var out gitLabMergeRequest
+	in := gitLabMergeRequestInput{Title: pr.Title, Description: pr.Body, SourceBranch: pr.Head, TargetBranch: pr.Base}
+	if err := g.client.do(ctx, http.MethodPost, g.mergeRequestsURL(), in, &out); err != nil {
+		return nil, err
+	}
+	return out.pullRequest(), nil
+}
+
+func (g *gitLab) UpdatePullRequest(ctx context.Context, pr PullRequest) (*PullRequest, error) {
+	var out gitLabMergeRequest
+	in := gitLabMergeRequestInput{Title: pr.Title, Description: pr.Body}
+	if err := g.client.do(ctx, http.MethodPut, fmt.Sprintf("%s/%d", g.mergeRequestsURL(), pr.Number), in, &out); err != nil {
+		return nil, err
+	}
+	return out.pullRequest(), nil
+}
+
+func (mr gitLabMergeRequest) pullRequest() *PullRequest {
+	return &PullRequest{Number: mr.IID, URL: mr.WebURL, Title: mr.Title, Body: mr.Description, Head: mr.SourceBranch, Base: mr.TargetBranch}
+}
+
+func (g *gitLab) AddReviewers(ctx context.Context, number int, emails []string) ([]string, error) {
+	var current gitLabMergeRequestReviewers
+	if err := g.client.do(ctx, http.MethodGet, fmt.Sprintf("%s/%d", g.mergeRequestsURL(), number), nil, &current); err != nil {
+		return nil, err
+	}
+	ids := make([]int, 0, len(current.Reviewers)+len(emails))
+	for _, r := range current.Reviewers {
+		ids = append(ids, r.ID)
+	}
+
+	var added []string
+	for _, email := range emails {
+		var users []gitLabUser
+		query := url.Values{"search": {email}}
+		if err := g.client.do(ctx, http.MethodGet, g.baseURL+"/users?"+query.Encode(), nil, &users); err != nil {
+			return nil, err
+		}
+		if len(users) == 0 {
+			continue
+		}
+		ids = append(ids, users[0].ID)
+		added = append(added, users[0].Username)
+	}
+	if len(added) == 0 {
+		return nil, nil
+	}
+
+	// reviewer_ids replaces the list, so existing reviewers are sent back too.
+	in := map[string][]int{"reviewer_ids": ids}
+	if err := g.client.do(ctx, http.MethodPut, fmt.Sprintf("%s/%d", g.mergeRequestsURL(), number), in, nil); err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
+type gitLabUser struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+}
+
+type gitLabMergeRequestReviewers struct {
+	Reviewers []gitLabUser `json:"reviewers"`
+}
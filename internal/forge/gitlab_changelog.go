@@ -0,0 +1,18 @@
+package forge
+
+// gitLabChangelogCategories maps Conventional Commit types to the categories
+// GitLab's changelog generator reads from `Changelog:` trailers. Types that
+// are not user-facing get no trailer, which leaves them out of the changelog.
+var gitLabChangelogCategories = map[string]string{
+	"feat":     "added",
+	"fix":      "fixed",
+	"perf":     "performance",
+	"refactor": "changed",
+	"revert":   "removed",
+}
+
+// GitLabChangelogCategory returns the GitLab changelog category for a commit
+// type, or "" when commits of that type should stay out of the changelog.
+func GitLabChangelogCategory(commitType string) string {
+	return gitLabChangelogCategories[commitType]
+}
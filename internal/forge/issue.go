@@ -0,0 +1,178 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// KindJira is the Jira issue tracker. It hosts no code, so it only
+// implements IssueTracker.
+const KindJira = "jira"
+
+// issueDescriptionLimit caps how much of a ticket's description reaches the prompt.
+const issueDescriptionLimit = 2000
+
+// Issue is a ticket from an issue tracker.
+type Issue struct {
+	Key         string
+	Title       string
+	Description string
+	URL         string
+}
+
+// IssueTracker fetches tickets by key: "PROJ-123" on Jira, the issue number
+// on GitHub and GitLab.
+type IssueTracker interface {
+	Issue(ctx context.Context, key string) (*Issue, error)
+}
+
+var (
+	// jiraKeyPattern matches keys like PROJ-123; IssueKey also requires
+	// branch separators around them, so "release-1.2" is not a key. Branches
+	// are often lower-cased, so the match is case-insensitive.
+	jiraKeyPattern = regexp.MustCompile(`(?i)[a-z][a-z0-9]+-[0-9]+`)
+	// issueNumberPattern matches a number delimited by branch separators,
+	// as in "42-fix-login", "issue-42", or "feature/42".
+	issueNumberPattern = regexp.MustCompile(`(?:^|[/_-])([0-9]+)(?:[/_-]|$)`)
+)
+
+// IssueKey extracts the ticket key from a branch name for kind's key format,
+// or returns "" when the branch names no ticket. For Jira, a non-empty
+// projects list limits keys to those project keys.
+func IssueKey(branch, kind string, projects []string) string {
+	if kind == KindJira {
+		for _, m := range jiraKeyPattern.FindAllStringIndex(branch, -1) {
+			if !branchSeparator(branch, m[0]-1) || !branchSeparator(branch, m[1]) {
+				continue
+			}
+			key := strings.ToUpper(branch[m[0]:m[1]])
+			project, _, _ := strings.Cut(key, "-")
+			if len(projects) == 0 || slices.ContainsFunc(projects, func(p string) bool { return strings.EqualFold(p, project) }) {
+				return key
+			}
+		}
+		return ""
+	}
+	if m := issueNumberPattern.FindStringSubmatch(branch); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// branchSeparator reports whether branch[i] separates words of a branch
+// name; the ends of the name count as separators.
+func branchSeparator(branch string, i int) bool {
+	return i < 0 || i >= len(branch) || strings.ContainsRune("/_-", rune(branch[i]))
+}
+
+// Summary renders the issue for a prompt, truncating long descriptions.
+func (i *Issue) Summary() string {
+	text := i.Key + ": " + i.Title
+	desc := strings.TrimSpace(i.Description)
+	if len(desc) > issueDescriptionLimit {
+		cut := issueDescriptionLimit
+		for cut > 0 && !utf8.RuneStart(desc[cut]) {
+			cut--
+		}
+		desc = desc[:cut] + "\n[description truncated]"
+	}
+	if desc != "" {
+		text += "\n\n" + desc
+	}
+	return text
+}
+
+// NewIssueTracker returns the GitHub or GitLab issue tracker for remote.
+func NewIssueTracker(remote *Remote, token string) (IssueTracker, error) {
+	f, err := New(remote, token)
+	if err != nil {
+		return nil, err
+	}
+	tracker, ok := f.(IssueTracker)
+	if !ok {
+		return nil, fmt.Errorf("fetching issues from %s is not supported", f.Name())
+	}
+	return tracker, nil
+}
+
+type jira struct {
+	client  *client
+	baseURL string
+}
+
+// NewJira returns a Jira tracker for the site at baseURL. With an email,
+// token is a Jira Cloud API token; without one, a Data Center personal
+// access token.
+func NewJira(baseURL, email, token string) (IssueTracker, error) {
+	if baseURL == "" {
+		return nil, fmt.Errorf("no Jira URL configured; set jira_url under [Issues]")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("no API token for jira; set %s", strings.Join(tokenEnvVars[KindJira], " or "))
+	}
+
+	c := &client{http: &http.Client{Timeout: 30 * time.Second}, token: token, auth: bearerAuth}
+	if email != "" {
+		c.auth = func(req *http.Request, token string) {
+			req.SetBasicAuth(email, token)
+		}
+	}
+	return &jira{client: c, baseURL: strings.TrimSuffix(baseURL, "/")}, nil
+}
+
+func (j *jira) Issue(ctx context.Context, key string) (*Issue, error) {
+	var out struct {
+		Fields struct {
+			Summary string `json:"summary"`
+			// API v2 returns the description as wiki markup text.
+			Description string `json:"description"`
+		} `json:"fields"`
+	}
+	endpoint := fmt.Sprintf("%s/rest/api/2/issue/%s?fields=summary,description", j.baseURL, url.PathEscape(key))
+	if err := j.client.do(ctx, http.MethodGet, endpoint, nil, &out); err != nil {
+		return nil, err
+	}
+	return &Issue{Key: key, Title: out.Fields.Summary, Description: out.Fields.Description, URL: j.baseURL + "/browse/" + key}, nil
+}
+
+func (g *gitHub) Issue(ctx context.Context, key string) (*Issue, error) {
+	number, err := strconv.Atoi(strings.TrimPrefix(key, "#"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid GitHub issue number %q", key)
+	}
+	var out struct {
+		Title   string `json:"title"`
+		Body    string `json:"body"`
+		HTMLURL string `json:"html_url"`
+	}
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/issues/%d", g.baseURL, url.PathEscape(g.owner), url.PathEscape(g.repo), number)
+	if err := g.client.do(ctx, http.MethodGet, endpoint, nil, &out); err != nil {
+		return nil, err
+	}
+	return &Issue{Key: "#" + strconv.Itoa(number), Title: out.Title, Description: out.Body, URL: out.HTMLURL}, nil
+}
+
+func (g *gitLab) Issue(ctx context.Context, key string) (*Issue, error) {
+	number, err := strconv.Atoi(strings.TrimPrefix(key, "#"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid GitLab issue number %q", key)
+	}
+	var out struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		WebURL      string `json:"web_url"`
+	}
+	endpoint := fmt.Sprintf("%s/projects/%s/issues/%d", g.baseURL, url.PathEscape(g.project), number)
+	if err := g.client.do(ctx, http.MethodGet, endpoint, nil, &out); err != nil {
+		return nil, err
+	}
+	return &Issue{Key: "#" + strconv.Itoa(number), Title: out.Title, Description: out.Description, URL: out.WebURL}, nil
+}
@@ -0,0 +1,37 @@
+package git
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+// changeIDPattern matches a Gerrit Change-Id trailer line.
+var changeIDPattern = regexp.MustCompile(`(?m)^Change-Id: (I[0-9a-f]{40})\s*$`)
+
+// ChangeID returns the Gerrit Change-Id in msg, or "" when there is none.
+func ChangeID(msg string) string {
+	if m := changeIDPattern.FindStringSubmatch(msg); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// NewChangeID returns a fresh Change-Id. Gerrit only needs it to be unique,
+// so it hashes msg with random bytes instead of mirroring the commit-msg hook.
+func NewChangeID(msg string) string {
+	seed := make([]byte, 20)
+	_, _ = rand.Read(seed)
+	sum := sha1.Sum(append(seed, msg...))
+	return "I" + hex.EncodeToString(sum[:])
+}
+
+// WithChangeID adds a Change-Id trailer to msg unless it already has one.
+func WithChangeID(msg, id string) string {
+	if ChangeID(msg) != "" || id == "" {
+		return strings.TrimRight(msg, "\n")
+	}
+	return WithTrailer(msg, "Change-Id", id)
+}
@@ -0,0 +1,29 @@
+package git
+
+import (
+	"testing"
+)
+
+func TestWithChangeID(t *testing.T) {
+	id := NewChangeID("feat: add x")
+	if len(id) != 41 || id[0] != 'I' {
+		t.Fatalf("unexpected Change-Id %q", id)
+	}
+
+	got := WithChangeID("feat: add x\n\nBody.", id)
+	if got != "feat: add x\n\nBody.\n\nChange-Id: "+id {
+		t.Fatalf("unexpected message:\n%s", got)
+	}
+	if ChangeID(got) != id {
+		t.Fatalf("ChangeID did not find %q in:\n%s", id, got)
+	}
+
+	signed := "fix: y\n\nBody.\n\nSigned-off-by: A <a@example.com>"
+	if got := WithChangeID(signed, id); got != signed+"\nChange-Id: "+id {
+		t.Fatalf("expected trailer block to be extended:\n%s", got)
+	}
+
+	if got := WithChangeID("fix: y\n\nChange-Id: "+id+"\n", NewChangeID("other")); ChangeID(got) != id {
+		t.Fatalf("existing Change-Id was not preserved:\n%s", got)
+	}
+}
@@ -0,0 +1,205 @@
+package git
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/razobeckett/goco/internal/telemetry"
+)
+
+// MaxDiffSection is how much of one file's diff goco keeps in memory. Past
+// it, the rest of the file is only counted; a model gains nothing from
+// megabytes of generated data or assets.
+const MaxDiffSection = 1 << 20
+
+// MaxDiffTotal is how much diff goco keeps in memory across all files. Past
+// it, each further file is reduced to its header and a note that it was
+// left out, so memory no longer grows with the size of the change.
+const MaxDiffTotal = 8 << 20
+
+// ChunkDiff reads a unified diff from r one file section at a time and calls
+// fn with each section in order. No section holds more than limit bytes of
+// diff: the rest of a larger file, however long its lines, is read and
+// counted but never buffered, and a closing line reports what was cut.
+func ChunkDiff(r io.Reader, limit int, fn func(section string) error) error {
+	br := bufio.NewReaderSize(r, 64<<10)
+	var c diffChunk
+	for {
+		line, n, err := readLine(br, limit+1)
+		if n > 0 {
+			if strings.HasPrefix(line, "diff --git ") && c.b.Len() > 0 {
+				if err := fn(c.flush()); err != nil {
+					return err
+				}
+			}
+			c.add(line, n, limit)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if c.b.Len() > 0 {
+		return fn(c.flush())
+	}
+	return nil
+}
+
+// GroupSections splits a diff into parts of whole file sections, each at most
+// size bytes unless one file alone is larger.
+func GroupSections(diff string, size int) []string {
+	var parts []string
+	var b strings.Builder
+	for _, section := range splitDiff(diff) {
+		if b.Len() > 0 && b.Len()+len(section) > size {
+			parts = append(parts, b.String())
+			b.Reset()
+		}
+		b.WriteString(section)
+	}
+	if b.Len() > 0 {
+		parts = append(parts, b.String())
+	}
+	return parts
+}
+
+// diffChunk accumulates one file section.
+type diffChunk struct {
+	b      strings.Builder
+	path   string
+	inHunk bool
+	// Set once the section passes its limit.
+	truncated      bool
+	added, deleted int
+	droppedBytes   int
+}
+
+// add appends line, which was n bytes before readLine cut it.
+func (c *diffChunk) add(line string, n, limit int) {
+	switch {
+	case strings.HasPrefix(line, "@@"):
+		c.inHunk = true
+	case !c.inHunk && strings.HasPrefix(line, "+++ "):
+		if path, ok := newFilePath(line); ok {
+			c.path = path
+		}
+	}
+	if !c.truncated && c.b.Len()+n <= limit {
+		c.b.WriteString(line)
+		return
+	}
+	c.truncated = true
+	c.droppedBytes += n
+	if c.inHunk {
+		switch line[0] {
+		case '+':
+			c.added++
+		case '-':
+			c.deleted++
+		}
+	}
+}
+
+// flush returns the section and resets c for the next one.
+func (c *diffChunk) flush() string {
+	section := c.b.String()
+	if c.truncated {
+		if !strings.HasSuffix(section, "\n") {
+			section += "\n"
+		}
+		path := c.path
+		if path == "" {
+			path = diffSectionPath(section)
+		}
+		section += fmt.Sprintf("... %s: %d more bytes of diff omitted (+%d -%d lines)\n", path, c.droppedBytes, c.added, c.deleted)
+	}
+	*c = diffChunk{}
+	return section
+}
+
+// readLine returns the next line with its newline and its full length n,
+// keeping at most max bytes of it; the rest of a longer line is read and
+// discarded.
+func readLine(br *bufio.Reader, max int) (line string, n int, err error) {
+	var b []byte
+	for {
+		frag, err := br.ReadSlice('\n')
+		n += len(frag)
+		if room := max - len(b); room > 0 {
+			b = append(b, frag[:min(len(frag), room)]...)
+		}
+		if err != bufio.ErrBufferFull {
+			return string(b), n, err
+		}
+	}
+}
+
+// diffOutput runs a diff command and streams its output through ChunkDiff,
+// so a multi-hundred-megabyte diff never sits in memory whole.
+func (r *Repository) diffOutput(ctx context.Context, args ...string) (_ string, err error) {
+	ctx, end := telemetry.StartSpan(ctx, "git "+args[0], telemetry.Tags{"git.args": strings.Join(args, " ")})
+	defer func() { end(err) }()
+
+	cmd := r.command(ctx, args...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return "", err
+	}
+	if err := cmd.Start(); err != nil {
+		return "", err
+	}
+
+	c := diffCollector{limit: MaxDiffTotal}
+	chunkErr := ChunkDiff(stdout, MaxDiffSection, c.add)
+	// Drain what is left so git can exit.
+	_, _ = io.Copy(io.Discard, stdout)
+	if err := cmd.Wait(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	if chunkErr != nil {
+		return "", fmt.Errorf("read %s output: %w", args[0], chunkErr)
+	}
+	return c.b.String(), nil
+}
+
+// diffCollector joins diff sections while they fit in limit bytes.
+type diffCollector struct {
+	b     strings.Builder
+	limit int
+}
+
+func (c *diffCollector) add(section string) error {
+	if c.b.Len()+len(section) <= c.limit || !strings.HasPrefix(section, "diff --git ") {
+		c.b.WriteString(section)
+		return nil
+	}
+	header, _, _ := strings.Cut(section, "\n")
+	fmt.Fprintf(&c.b, "%s\n... %s: diff omitted, as the whole diff is over %s\n", header, diffSectionPath(section), FormatSize(int64(c.limit)))
+	return nil
+}
+
+// TruncatedFiles lists the files whose diff was cut short or left out
+// because it was too large to read whole.
+func TruncatedFiles(diff string) []string {
+	var paths []string
+	for _, line := range strings.Split(diff, "\n") {
+		rest, ok := strings.CutPrefix(line, "... ")
+		if !ok || !strings.Contains(rest, "omitted") {
+			continue
+		}
+		if i := strings.LastIndex(rest, ": "); i > 0 {
+			paths = append(paths, rest[:i])
+		}
+	}
+	return paths
+}
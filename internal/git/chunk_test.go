@@ -0,0 +1,209 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestChunkDiff(t *testing.T) {
+	small := "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-a\n+b\n"
+	large := "diff --git a/data.csv b/data.csv\n--- a/data.csv\n+++ b/data.csv\n@@ -1,3 +1,3 @@\n" +
+		"-1,2\n-3,4\n+++ b/fake\n+" + strings.Repeat("x", 200) + "\n"
+
+	tests := []struct {
+		name string
+		diff string
+		want []string
+	}{
+		{name: "empty"},
+		{name: "sections under the limit", diff: small + small, want: []string{small, small}},
+		{
+			name: "section over the limit",
+			diff: large + small,
+			want: []string{
+				"diff --git a/data.csv b/data.csv\n--- a/data.csv\n+++ b/data.csv\n@@ -1,3 +1,3 @@\n-1,2\n-3,4\n+++ b/fake\n" +
+					"... data.csv: 202 more bytes of diff omitted (+1 -0 lines)\n",
+				small,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			err := ChunkDiff(strings.NewReader(tt.diff), 100, func(section string) error {
+				got = append(got, section)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Fatalf("ChunkDiff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepositoryDiffOmitsHugeSections(t *testing.T) {
+	dir := t.TempDir()
+	run := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=goco", "-c", "user.email=goco@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("init", "-q")
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("small.txt", "one\n")
+	write("huge.txt", "")
+	run("add", ".")
+	run("commit", "-qm", "init")
+
+	// One line longer than the whole section budget.
+	write("huge.txt", strings.Repeat("x", 2*MaxDiffSection)+"\n")
+	write("small.txt", "two\n")
+
+	diff, err := NewRepository(dir).Diff(context.Background(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff) > MaxDiffSection {
+		t.Fatalf("diff is %d bytes, want at most %d", len(diff), MaxDiffSection)
+	}
+	if !strings.Contains(diff, "... huge.txt: ") || !strings.Contains(diff, "(+1 -0 lines)") {
+		t.Fatalf("huge section was not summarized:\n%.500s", diff)
+	}
+	if !strings.Contains(diff, "-one\n+two\n") {
+		t.Fatalf("small section was altered:\n%.500s", diff)
+	}
+}
+
+func TestGroupSections(t *testing.T) {
+	a := "diff --git a/a b/a\n+1\n"
+	b := "diff --git a/b b/b\n+22\n"
+	big := "diff --git a/c b/c\n" + strings.Repeat("+x\n", 20)
+
+	tests := []struct {
+		name string
+		diff string
+		size int
+		want []string
+	}{
+		{name: "empty", size: 10},
+		{name: "fits in one part", diff: a + b, size: 100, want: []string{a + b}},
+		{name: "one section per part", diff: a + b, size: len(a), want: []string{a, b}},
+		{name: "oversized section stands alone", diff: a + big + b, size: 30, want: []string{a, big, b}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GroupSections(tt.diff, tt.size)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") || len(got) != len(tt.want) {
+				t.Fatalf("GroupSections() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func FuzzChunkDiff(f *testing.F) {
+	f.Add("diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-a\n+b\n", uint8(20))
+	f.Add("diff --git a/x b/x\n@@ -1 +1 @@\n+"+strings.Repeat("y", 300)+"\ndiff --git a/z b/z\n", uint8(0))
+	f.Add("no newline at end", uint8(4))
+	f.Fuzz(func(t *testing.T, diff string, limit uint8) {
+		// A limit that fits the whole diff splits it without losing a byte.
+		var whole []string
+		if err := ChunkDiff(strings.NewReader(diff), len(diff), func(section string) error {
+			whole = append(whole, section)
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Join(whole, ""); got != diff {
+			t.Fatalf("sections rejoin to %q, want %q", got, diff)
+		}
+		for i, section := range whole[min(1, len(whole)):] {
+			if !strings.HasPrefix(section, "diff --git ") {
+				t.Fatalf("section %d does not start a file: %q", i+1, section)
+			}
+		}
+
+		// A small one keeps each section under it, plus the omission note.
+		err := ChunkDiff(strings.NewReader(diff), int(limit), func(section string) error {
+			kept := section
+			if i := strings.LastIndex(section, "\n... "); i >= 0 {
+				kept = section[:i+1]
+			}
+			if len(kept) > int(limit)+1 {
+				t.Fatalf("section keeps %d bytes, limit %d: %q", len(kept), limit, section)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func FuzzGroupSections(f *testing.F) {
+	f.Add("diff --git a/a b/a\n+1\ndiff --git a/b b/b\n+22\n", 10)
+	f.Add("\ndiff --git \ndiff --git ", 0)
+	f.Fuzz(func(t *testing.T, diff string, size int) {
+		parts := GroupSections(diff, size)
+		if got := strings.Join(parts, ""); got != diff {
+			t.Fatalf("parts rejoin to %q, want %q", got, diff)
+		}
+		for _, part := range parts {
+			if part == "" {
+				t.Fatalf("GroupSections(%q, %d) returned an empty part", diff, size)
+			}
+			if len(part) > size && len(splitDiff(part)) > 1 {
+				t.Fatalf("part of %d bytes holds several sections, size %d: %q", len(part), size, part)
+			}
+		}
+	})
+}
+
+func TestChunkDiffQuotedPath(t *testing.T) {
+	diff := "diff --git \"a/caf\\303\\251.txt\" \"b/caf\\303\\251.txt\"\n--- \"a/caf\\303\\251.txt\"\n+++ \"b/caf\\303\\251.txt\"\n@@ -1 +1 @@\n-" +
+		strings.Repeat("x", 200) + "\n"
+	var got string
+	if err := ChunkDiff(strings.NewReader(diff), 100, func(section string) error {
+		got = section
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "... café.txt: ") {
+		t.Fatalf("quoted path was not decoded:\n%s", got)
+	}
+	if path := diffSectionPath("diff --git \"a/caf\\303\\251.txt\" \"b/caf\\303\\251.txt\"\nBinary files differ\n"); path != "café.txt" {
+		t.Fatalf("diffSectionPath() = %q, want café.txt", path)
+	}
+}
+
+func TestDiffCollector(t *testing.T) {
+	a := "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-a\n+b\n"
+	b := "diff --git a/b.go b/b.go\n--- a/b.go\n+++ b/b.go\n@@ -1 +1 @@\n-a\n+b\n"
+
+	c := diffCollector{limit: len(a) + 10}
+	for _, section := range []string{a, b} {
+		if err := c.add(section); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := a + "diff --git a/b.go b/b.go\n... b.go: diff omitted, as the whole diff is over 75 B\n"
+	if got := c.b.String(); got != want {
+		t.Fatalf("collected %q, want %q", got, want)
+	}
+	if got := TruncatedFiles(want); len(got) != 1 || got[0] != "b.go" {
+		t.Fatalf("TruncatedFiles() = %q, want [b.go]", got)
+	}
+}
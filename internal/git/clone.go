@@ -0,0 +1,54 @@
+package git
+
+import (
+	"context"
+	"strings"
+)
+
+// CloneShape describes how much of the repository is present locally.
+type CloneShape struct {
+	// PartialRemote names the promisor remote of a partial clone, which git
+	// asks for missing objects on demand; empty for a full clone.
+	PartialRemote string
+	// Sparse is set for a sparse checkout; Cone lists its directories (or
+	// patterns, outside cone mode).
+	Sparse bool
+	Cone   []string
+}
+
+// Partial reports whether objects may be missing locally, so commands that
+// read old file contents, like blame, can trigger large downloads.
+func (c CloneShape) Partial() bool {
+	return c.PartialRemote != ""
+}
+
+// String describes a sparse checkout for prompts; empty otherwise.
+func (c CloneShape) String() string {
+	if !c.Sparse {
+		return ""
+	}
+	if len(c.Cone) == 0 {
+		return "sparse checkout: only part of the repository is checked out; paths outside it are unchanged"
+	}
+	return "sparse checkout of " + strings.Join(c.Cone, ", ") + "; paths outside it are not checked out and are unchanged"
+}
+
+// CloneShape reports whether the repository is a partial clone or a sparse
+// checkout. Only local config is read, so it never fetches.
+func (r *Repository) CloneShape(ctx context.Context) CloneShape {
+	var c CloneShape
+	if out, err := r.output(ctx, "config", "--get", "extensions.partialClone"); err == nil {
+		c.PartialRemote = strings.TrimSpace(out)
+	}
+	if out, err := r.output(ctx, "config", "--type=bool", "--get", "core.sparseCheckout"); err == nil && strings.TrimSpace(out) == "true" {
+		c.Sparse = true
+		if out, err := r.output(ctx, "sparse-checkout", "list"); err == nil {
+			for _, line := range strings.Split(out, "\n") {
+				if line = strings.TrimSpace(line); line != "" {
+					c.Cone = append(c.Cone, line)
+				}
+			}
+		}
+	}
+	return c
+}
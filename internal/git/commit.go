@@ -0,0 +1,146 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// commitHookNames are the hooks git commit runs, in order.
+var commitHookNames = []string{"pre-commit", "prepare-commit-msg", "commit-msg", "post-commit"}
+
+// CommitSettings describes what git commit will do beyond recording the
+// commit, as decided by the repository's hooks and config.
+type CommitSettings struct {
+	// Hooks lists the installed, executable commit hooks git will run.
+	Hooks []string
+	// Sign is set when commit.gpgsign asks git to sign every commit, in
+	// SignFormat ("openpgp", "ssh", or "x509").
+	Sign       bool
+	SignFormat string
+	// SigningKey is user.signingkey. With SSH it is a key file or an
+	// inline "key::" public key; DefaultKeyCommand picks a key when it is
+	// empty.
+	SigningKey        string
+	DefaultKeyCommand string
+}
+
+// CommitOverrides replace the author and author date git would record,
+// for importing or backfilling history, and let git record a commit without
+// changes. The zero value changes nothing.
+type CommitOverrides struct {
+	// Author is "Name <email>"; CheckAuthor validates it.
+	Author string
+	// Date is the author date; ParseCommitDate reads it from a flag.
+	Date time.Time
+	// AllowEmpty records the commit even when it changes nothing.
+	AllowEmpty bool
+}
+
+// args are the git commit options for o.
+func (o CommitOverrides) args() []string {
+	var args []string
+	if o.Author != "" {
+		args = append(args, "--author="+o.Author)
+	}
+	if !o.Date.IsZero() {
+		// git reads its own RFC 2822 form without guessing.
+		args = append(args, "--date="+o.Date.Format(time.RFC1123Z))
+	}
+	if o.AllowEmpty {
+		args = append(args, "--allow-empty")
+	}
+	return args
+}
+
+// authorPattern is "Name <email>", which git records as is. Anything else
+// git takes as a pattern to search earlier authors for.
+var authorPattern = regexp.MustCompile(`^[^<>]*[^<>\s]\s*<[^<>\s@]+@[^<>\s@]+>$`)
+
+// CheckAuthor reports an author that is not of the form "Name <email>".
+func CheckAuthor(author string) error {
+	if !authorPattern.MatchString(author) {
+		return fmt.Errorf("author %q is not of the form \"Name <email>\"", author)
+	}
+	return nil
+}
+
+// commitDateLayouts are the ISO 8601 and RFC 2822 forms ParseCommitDate
+// reads; those without a zone are in local time.
+var commitDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+}
+
+// ParseCommitDate reads a commit date in ISO 8601 or RFC 2822 form, or a
+// Unix timestamp written "@1700000000". git itself reads almost any text as
+// a date and falls back to now, so a typo would go unnoticed.
+func ParseCommitDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	var t time.Time
+	if seconds, ok := strings.CutPrefix(s, "@"); ok {
+		n, err := strconv.ParseInt(seconds, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("date %q is not a Unix timestamp", s)
+		}
+		t = time.Unix(n, 0)
+	} else {
+		for _, layout := range commitDateLayouts {
+			if parsed, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+				t = parsed
+				break
+			}
+		}
+		if t.IsZero() {
+			return time.Time{}, fmt.Errorf("date %q is not ISO 8601 (2006-01-02 15:04:05), RFC 2822, or @<unix-timestamp>", s)
+		}
+	}
+	if t.Unix() <= 0 {
+		return time.Time{}, errors.New("git cannot record dates before 1970")
+	}
+	return t, nil
+}
+
+// CommitSettings reads the hooks and signing config that apply to commits
+// made in the repository.
+func (r *Repository) CommitSettings(ctx context.Context) (CommitSettings, error) {
+	var s CommitSettings
+	dir, err := r.HooksDir(ctx)
+	if err != nil {
+		return s, err
+	}
+	for _, name := range commitHookNames {
+		if info, err := os.Stat(filepath.Join(dir, name)); err == nil && info.Mode().IsRegular() && info.Mode()&0o111 != 0 {
+			s.Hooks = append(s.Hooks, name)
+		}
+	}
+
+	// git config exits 1 for an unset key, which just means the default.
+	if out, err := r.output(ctx, "config", "--type=bool", "--get", "commit.gpgsign"); err == nil {
+		s.Sign = strings.TrimSpace(out) == "true"
+	}
+	if s.Sign {
+		s.SignFormat = "openpgp"
+		if out, err := r.output(ctx, "config", "--get", "gpg.format"); err == nil && strings.TrimSpace(out) != "" {
+			s.SignFormat = strings.TrimSpace(out)
+		}
+		if out, err := r.output(ctx, "config", "--get", "user.signingkey"); err == nil {
+			s.SigningKey = strings.TrimSpace(out)
+		}
+		if out, err := r.output(ctx, "config", "--get", "gpg.ssh.defaultKeyCommand"); err == nil && s.SignFormat == "ssh" {
+			s.DefaultKeyCommand = strings.TrimSpace(out)
+		}
+	}
+	return s, nil
+}
@@ -0,0 +1,51 @@
+package git
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCommitDate(t *testing.T) {
+	utc := time.Date(2024, 3, 5, 10, 20, 30, 0, time.UTC)
+	tests := []struct {
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{in: "2024-03-05T12:20:30+02:00", want: utc},
+		{in: "2024-03-05 05:20:30 -0500", want: utc},
+		{in: "Tue, 5 Mar 2024 10:20:30 +0000", want: utc},
+		{in: "@1709634030", want: utc},
+		{in: " 2024-03-05 ", want: time.Date(2024, 3, 5, 0, 0, 0, 0, time.Local)},
+		{in: "yesterday", wantErr: true},
+		{in: "2024-13-01", wantErr: true},
+		{in: "@soon", wantErr: true},
+		{in: "1969-12-31T23:00:00Z", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseCommitDate(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseCommitDate(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseCommitDate(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCheckAuthor(t *testing.T) {
+	for author, ok := range map[string]bool{
+		"A U Thor <author@example.com>": true,
+		"Jo<jo@example.com>":            true,
+		"<jo@example.com>":              false,
+		"Jo":                            false,
+		"Jo <jo>":                       false,
+		"Jo <jo@example.com> extra":     false,
+		"Jo <jo <jo@example.com>>":      false,
+	} {
+		if err := CheckAuthor(author); (err == nil) != ok {
+			t.Errorf("CheckAuthor(%q) = %v, want ok %v", author, err, ok)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package git
+
+import (
+	"path"
+	"strconv"
+	"strings"
+)
+
+// FileChange is one file's section of a unified diff.
+type FileChange struct {
+	Path    string
+	Added   int
+	Deleted int
+	// Section is the file's part of the diff, from its "diff --git" header.
+	Section string
+}
+
+// FileChanges splits a unified diff into its files, in diff order.
+func FileChanges(diff string) []FileChange {
+	var files []FileChange
+	for _, section := range splitDiff(diff) {
+		if !strings.HasPrefix(section, "diff --git ") {
+			continue
+		}
+		added, deleted := countChanges(section)
+		files = append(files, FileChange{Path: diffSectionPath(section), Added: added, Deleted: deleted, Section: section})
+	}
+	return files
+}
+
+// DiffHeaderPaths returns the old and new paths of a "diff --git a/<old>
+// b/<new>" header. Git quotes paths with unusual characters C-style, as in
+// "b/caf\303\251.txt", which it decodes; with core.quotepath off, as goco
+// runs it, non-ASCII paths arrive as they are.
+func DiffHeaderPaths(header string) (oldPath, newPath string, ok bool) {
+	rest, ok := strings.CutPrefix(header, "diff --git ")
+	if !ok {
+		return "", "", false
+	}
+	if strings.HasSuffix(rest, `"`) {
+		if i := strings.LastIndex(rest, ` "b/`); i >= 0 {
+			if path, err := strconv.Unquote(rest[i+1:]); err == nil {
+				return diffPath(rest[:i], "a/"), strings.TrimPrefix(path, "b/"), true
+			}
+		}
+	}
+	if i := strings.LastIndex(rest, " b/"); i >= 0 {
+		return diffPath(rest[:i], "a/"), rest[i+3:], true
+	}
+	return "", "", false
+}
+
+// diffPath strips prefix from a path in a diff header, unquoting it first
+// when git quoted it.
+func diffPath(s, prefix string) string {
+	if strings.HasPrefix(s, `"`) {
+		if unquoted, err := strconv.Unquote(s); err == nil {
+			s = unquoted
+		}
+	}
+	return strings.TrimPrefix(s, prefix)
+}
+
+// languages maps file extensions to the language they are written in.
+var languages = map[string]string{
+	".go":    "Go",
+	".py":    "Python",
+	".js":    "JavaScript",
+	".jsx":   "JavaScript",
+	".mjs":   "JavaScript",
+	".ts":    "TypeScript",
+	".tsx":   "TypeScript",
+	".rs":    "Rust",
+	".java":  "Java",
+	".kt":    "Kotlin",
+	".swift": "Swift",
+	".c":     "C",
+	".h":     "C",
+	".cc":    "C++",
+	".cpp":   "C++",
+	".hpp":   "C++",
+	".cs":    "C#",
+	".rb":    "Ruby",
+	".php":   "PHP",
+	".lua":   "Lua",
+	".sh":    "Shell",
+	".bash":  "Shell",
+	".zsh":   "Shell",
+	".sql":   "SQL",
+	".html":  "HTML",
+	".css":   "CSS",
+	".scss":  "SCSS",
+	".md":    "Markdown",
+	".json":  "JSON",
+	".yaml":  "YAML",
+	".yml":   "YAML",
+	".toml":  "TOML",
+	".xml":   "XML",
+	".proto": "Protobuf",
+	".nix":   "Nix",
+}
+
+// languageFiles maps file names without a telling extension to their
+// language.
+var languageFiles = map[string]string{
+	"Makefile":   "Makefile",
+	"Dockerfile": "Dockerfile",
+	"go.mod":     "Go module",
+	"go.sum":     "Go module",
+}
+
+// Language guesses the language of the file at path from its name, or
+// returns "" when it cannot tell.
+func Language(p string) string {
+	if lang, ok := languageFiles[path.Base(p)]; ok {
+		return lang
+	}
+	return languages[strings.ToLower(path.Ext(p))]
+}
@@ -0,0 +1,63 @@
+package git
+
+import "testing"
+
+func TestFileChanges(t *testing.T) {
+	diff := "diff --git a/main.go b/main.go\n" +
+		"--- a/main.go\n+++ b/main.go\n" +
+		"@@ -1,2 +1,3 @@\n package main\n-var a = 1\n+var a = 2\n+var b = 3\n" +
+		"diff --git a/docs/x.md b/docs/x.md\n" +
+		"--- a/docs/x.md\n+++ b/docs/x.md\n" +
+		"@@ -1 +0,0 @@\n-# x\n"
+
+	files := FileChanges(diff)
+	if len(files) != 2 {
+		t.Fatalf("expected 2 files, got %+v", files)
+	}
+	if f := files[0]; f.Path != "main.go" || f.Added != 2 || f.Deleted != 1 {
+		t.Errorf("unexpected first file %+v", f)
+	}
+	if f := files[1]; f.Path != "docs/x.md" || f.Added != 0 || f.Deleted != 1 || f.Section != diff[len(files[0].Section):] {
+		t.Errorf("unexpected second file %+v", f)
+	}
+}
+
+func TestLanguage(t *testing.T) {
+	tests := map[string]string{
+		"main.go":            "Go",
+		"web/App.TSX":        "TypeScript",
+		"build/Dockerfile":   "Dockerfile",
+		"go.sum":             "Go module",
+		"assets/logo.png":    "",
+		"LICENSE":            "",
+		"scripts/release.sh": "Shell",
+	}
+	for path, want := range tests {
+		if got := Language(path); got != want {
+			t.Errorf("Language(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestDiffHeaderPaths(t *testing.T) {
+	tests := []struct {
+		header  string
+		oldPath string
+		newPath string
+	}{
+		{header: "diff --git a/main.go b/main.go", oldPath: "main.go", newPath: "main.go"},
+		{header: "diff --git a/文档/说明.md b/文档/说明.md", oldPath: "文档/说明.md", newPath: "文档/说明.md"},
+		{header: "diff --git a/my notes.txt b/🎉 notes.txt", oldPath: "my notes.txt", newPath: "🎉 notes.txt"},
+		{header: `diff --git "a/caf\303\251.go" "b/caf\303\251.go"`, oldPath: "café.go", newPath: "café.go"},
+		{header: `diff --git a/old.txt "b/say \"hi\".txt"`, oldPath: "old.txt", newPath: `say "hi".txt`},
+	}
+	for _, tt := range tests {
+		oldPath, newPath, ok := DiffHeaderPaths(tt.header)
+		if !ok || oldPath != tt.oldPath || newPath != tt.newPath {
+			t.Errorf("DiffHeaderPaths(%q) = %q, %q, %v; want %q, %q", tt.header, oldPath, newPath, ok, tt.oldPath, tt.newPath)
+		}
+	}
+	if _, _, ok := DiffHeaderPaths("--- a/main.go"); ok {
+		t.Error("expected a line that is no diff header to be rejected")
+	}
+}
@@ -0,0 +1,141 @@
+package git
+
+import (
+	"bufio"
+	"context"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// HookVersion is stamped into every hook goco installs. Bump it whenever a
+// script under hooks/ changes so verify-install replaces old copies.
+const HookVersion = 1
+
+const hookVersionMarker = "# goco-hook-version: "
+
+//go:embed hooks
+var hookScripts embed.FS
+
+// HookNames lists the hooks goco installs.
+var HookNames = []string{"prepare-commit-msg", "pre-push"}
+
+// HookState describes an installed hook relative to the current version.
+type HookState int
+
+const (
+	HookMissing HookState = iota
+	HookCurrent
+	// HookOutdated is a goco hook with a different version marker.
+	HookOutdated
+	// HookForeign is a hook goco did not write; it is never overwritten.
+	HookForeign
+)
+
+func (s HookState) String() string {
+	switch s {
+	case HookCurrent:
+		return "up to date"
+	case HookOutdated:
+		return "outdated"
+	case HookForeign:
+		return "not managed by goco"
+	default:
+		return "missing"
+	}
+}
+
+// Hook is the state of one hook in a repository.
+type Hook struct {
+	Name  string
+	Path  string
+	State HookState
+	// Version is the installed version marker, or 0.
+	Version int
+}
+
+// HooksDir returns the directory git runs hooks from, honouring core.hooksPath.
+func (r *Repository) HooksDir(ctx context.Context) (string, error) {
+	out, err := r.output(ctx, "rev-parse", "--path-format=absolute", "--git-path", "hooks")
+	if err != nil {
+		return "", fmt.Errorf("locate hooks directory: %w", err)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// Hooks reports the state of every hook goco installs.
+func (r *Repository) Hooks(ctx context.Context) ([]Hook, error) {
+	dir, err := r.HooksDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+	hooks := make([]Hook, 0, len(HookNames))
+	for _, name := range HookNames {
+		h := Hook{Name: name, Path: filepath.Join(dir, name)}
+		h.State, h.Version, err = inspectHook(h.Path)
+		if err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, h)
+	}
+	return hooks, nil
+}
+
+// InstallHook writes the current version of hook h.
+func InstallHook(h Hook) error {
+	script, err := HookScript(h.Name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(h.Path), 0o755); err != nil {
+		return fmt.Errorf("create hooks directory: %w", err)
+	}
+	if err := os.WriteFile(h.Path, []byte(script), 0o755); err != nil {
+		return fmt.Errorf("install %s hook: %w", h.Name, err)
+	}
+	// WriteFile keeps the mode of an existing file, which may not be executable.
+	if err := os.Chmod(h.Path, 0o755); err != nil {
+		return fmt.Errorf("install %s hook: %w", h.Name, err)
+	}
+	return nil
+}
+
+// HookScript returns the current script for the named hook.
+func HookScript(name string) (string, error) {
+	data, err := hookScripts.ReadFile("hooks/" + name)
+	if err != nil {
+		return "", fmt.Errorf("unknown hook %q", name)
+	}
+	return strings.Replace(string(data), "{{VERSION}}", strconv.Itoa(HookVersion), 1), nil
+}
+
+// inspectHook classifies the hook file at path by its version marker, which
+// goco writes on the second line.
+func inspectHook(path string) (HookState, int, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return HookMissing, 0, nil
+	}
+	if err != nil {
+		return HookMissing, 0, fmt.Errorf("read hook: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for i := 0; i < 2 && scanner.Scan(); i++ {
+		if text, ok := strings.CutPrefix(scanner.Text(), hookVersionMarker); ok {
+			version, err := strconv.Atoi(strings.TrimSpace(text))
+			if err != nil {
+				return HookOutdated, 0, nil
+			}
+			if version == HookVersion {
+				return HookCurrent, version, nil
+			}
+			return HookOutdated, version, nil
+		}
+	}
+	return HookForeign, 0, nil
+}
@@ -0,0 +1,197 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// LargeFileThreshold is the size above which a binary committed without LFS
+// triggers a warning.
+const LargeFileThreshold = 5 << 20
+
+const lfsPointerVersion = "version https://git-lfs.github.com/spec/v1"
+
+// LargeFile is a big binary about to be committed directly into history.
+type LargeFile struct {
+	Path string
+	Size int64
+}
+
+// SummarizeLFSPointers replaces the diff of every Git LFS pointer file with a
+// one-line size summary, since pointer hashes tell the model nothing. It
+// returns the rewritten diff and the LFS paths it found.
+func SummarizeLFSPointers(diff string) (string, []string) {
+	sections := splitDiff(diff)
+
+	var b strings.Builder
+	var lfsPaths []string
+	for _, section := range sections {
+		if !strings.Contains(section, lfsPointerVersion) {
+			b.WriteString(section)
+			continue
+		}
+
+		header, _, _ := strings.Cut(section, "\n")
+		path := diffSectionPath(section)
+		oldSize, newSize := pointerSizes(section)
+		lfsPaths = append(lfsPaths, path)
+
+		fmt.Fprintf(&b, "%s\nGit LFS object %s: %s -> %s (pointer diff omitted)\n", header, path, oldSize, newSize)
+	}
+
+	return b.String(), lfsPaths
+}
+
+// LargeBinaries returns paths (relative to root) that are binary, larger than
+// LargeFileThreshold, and not tracked by Git LFS.
+func (r *Repository) LargeBinaries(ctx context.Context, root string, paths []string) ([]LargeFile, error) {
+	var candidates []LargeFile
+	for _, p := range paths {
+		full := filepath.Join(root, filepath.FromSlash(p))
+		info, err := os.Stat(full)
+		if err != nil || !info.Mode().IsRegular() || info.Size() < LargeFileThreshold {
+			continue
+		}
+		if isBinaryFile(full) {
+			candidates = append(candidates, LargeFile{Path: p, Size: info.Size()})
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	args := []string{"check-attr", "-z", "filter", "--"}
+	for _, c := range candidates {
+		args = append(args, c.Path)
+	}
+	out, err := r.output(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("check LFS attributes: %w", err)
+	}
+
+	// -z output is path NUL attribute NUL value NUL, repeated.
+	lfs := make(map[string]bool)
+	fields := strings.Split(out, "\x00")
+	for i := 0; i+2 < len(fields); i += 3 {
+		if fields[i+2] == "lfs" {
+			lfs[fields[i]] = true
+		}
+	}
+
+	var large []LargeFile
+	for _, c := range candidates {
+		if !lfs[c.Path] {
+			large = append(large, c)
+		}
+	}
+	return large, nil
+}
+
+// FormatSize renders a byte count with a binary unit, e.g. "12.5 MiB".
+func FormatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func splitDiff(diff string) []string {
+	var sections []string
+	start := 0
+	for i := 0; i < len(diff); {
+		next := strings.Index(diff[i:], "\ndiff --git ")
+		if next < 0 {
+			break
+		}
+		end := i + next + 1
+		sections = append(sections, diff[start:end])
+		start = end
+		i = end
+	}
+	return append(sections, diff[start:])
+}
+
+func diffSectionPath(section string) string {
+	for _, line := range strings.Split(section, "\n") {
+		if strings.HasPrefix(line, "@@") {
+			break
+		}
+		if path, ok := newFilePath(line); ok {
+			return path
+		}
+	}
+	header, _, _ := strings.Cut(section, "\n")
+	if _, path, ok := DiffHeaderPaths(header); ok {
+		return path
+	}
+	return header
+}
+
+// newFilePath returns the path in a "+++ b/<path>" header line. Git quotes
+// paths with unusual characters C-style, as in +++ "b/caf\303\251.txt",
+// which it decodes, and ends the line with a tab when the path holds a
+// space.
+func newFilePath(line string) (string, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\t"), "+++ ")
+	if !ok {
+		return "", false
+	}
+	if strings.HasPrefix(rest, `"`) {
+		unquoted, err := strconv.Unquote(rest)
+		if err != nil {
+			return "", false
+		}
+		rest = unquoted
+	}
+	return strings.CutPrefix(rest, "b/")
+}
+
+func pointerSizes(section string) (string, string) {
+	oldSize, newSize := "(none)", "(none)"
+	for _, line := range strings.Split(section, "\n") {
+		if len(line) < 1 {
+			continue
+		}
+		value, ok := strings.CutPrefix(line[1:], "size ")
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			continue
+		}
+		switch line[0] {
+		case '-':
+			oldSize = FormatSize(n)
+		case '+':
+			newSize = FormatSize(n)
+		case ' ':
+			oldSize, newSize = FormatSize(n), FormatSize(n)
+		}
+	}
+	return oldSize, newSize
+}
+
+// isBinaryFile applies git's heuristic: a NUL byte in the first 8000 bytes.
+func isBinaryFile(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	buf := make([]byte, 8000)
+	n, _ := f.Read(buf)
+	return bytes.IndexByte(buf[:n], 0) >= 0
+}
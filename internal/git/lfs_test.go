@@ -0,0 +1,42 @@
+package git
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSummarizeLFSPointers(t *testing.T) {
+	diff := strings.Join([]string{
+		"diff --git a/main.go b/main.go",
+		"--- a/main.go",
+		"+++ b/main.go",
+		"@@ -1 +1 @@",
+		"-package old",
+		"+package main",
+		"diff --git a/assets/logo.psd b/assets/logo.psd",
+		"index 1111111..2222222 100644",
+		"--- a/assets/logo.psd",
+		"+++ b/assets/logo.psd",
+		"@@ -1,3 +1,3 @@",
+		" version https://git-lfs.github.com/spec/v1",
+		"-oid sha256:aaaa",
+		"-size 1024",
+		"+oid sha256:bbbb",
+		"+size 3145728",
+		"",
+	}, "\n")
+
+	got, paths := SummarizeLFSPointers(diff)
+	if len(paths) != 1 || paths[0] != "assets/logo.psd" {
+		t.Fatalf("unexpected LFS paths: %v", paths)
+	}
+	if !strings.Contains(got, "+package main\n") {
+		t.Fatalf("regular diff was altered:\n%s", got)
+	}
+	if strings.Contains(got, "sha256") {
+		t.Fatalf("pointer diff was not summarized:\n%s", got)
+	}
+	if !strings.Contains(got, "Git LFS object assets/logo.psd: 1.0 KiB -> 3.0 MiB") {
+		t.Fatalf("missing size summary:\n%s", got)
+	}
+}
@@ -0,0 +1,221 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Minimize configures MinimizeDiff.
+type Minimize struct {
+	// Context is how many unchanged lines to keep on each side of a change.
+	Context int
+	// AddedRun is how many lines of a run of additions to keep before the
+	// rest are collapsed into a marker.
+	AddedRun int
+}
+
+// DefaultMinimize keeps one context line and the first 40 lines of each
+// block of additions, which is usually enough to tell what a new function or
+// file is for.
+var DefaultMinimize = Minimize{Context: 1, AddedRun: 40}
+
+// vendoredDirs are directory names whose contents are third-party code.
+var vendoredDirs = map[string]bool{
+	"vendor":           true,
+	"node_modules":     true,
+	"third_party":      true,
+	"third-party":      true,
+	"bower_components": true,
+}
+
+// Vendored reports whether path lies inside a vendored dependency directory.
+func Vendored(path string) bool {
+	dirs := strings.Split(path, "/")
+	for _, dir := range dirs[:len(dirs)-1] {
+		if vendoredDirs[dir] {
+			return true
+		}
+	}
+	return false
+}
+
+// MinimizeDiff shrinks a unified diff for a prompt: it drops unchanged lines
+// more than m.Context lines from a change, collapses runs of additions longer
+// than m.AddedRun, and replaces vendored files with a one-line summary. Hunk
+// headers are kept, so the model still sees where each change is.
+func MinimizeDiff(diff string, m Minimize) string {
+	var b strings.Builder
+	for _, section := range splitDiff(diff) {
+		if !strings.HasPrefix(section, "diff --git ") {
+			b.WriteString(section)
+			continue
+		}
+		if path := diffSectionPath(section); Vendored(path) {
+			header, _, _ := strings.Cut(section, "\n")
+			added, deleted := countChanges(section)
+			fmt.Fprintf(&b, "%s\nVendored file %s: +%d -%d lines (diff omitted)\n", header, path, added, deleted)
+			continue
+		}
+		b.WriteString(minimizeSection(section, m))
+	}
+	return b.String()
+}
+
+func minimizeSection(section string, m Minimize) string {
+	trailingNewline := strings.HasSuffix(section, "\n")
+	lines := strings.Split(strings.TrimSuffix(section, "\n"), "\n")
+
+	var out []string
+	var hunk []string
+	flush := func() {
+		out = append(out, minimizeHunk(hunk, m)...)
+		hunk = nil
+	}
+	inHunk := false
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			flush()
+			out = append(out, line)
+			inHunk = true
+		case inHunk:
+			hunk = append(hunk, line)
+		default:
+			out = append(out, line)
+		}
+	}
+	flush()
+
+	result := strings.Join(out, "\n")
+	if trailingNewline {
+		result += "\n"
+	}
+	return result
+}
+
+// minimizeHunk filters the body of one hunk.
+func minimizeHunk(lines []string, m Minimize) []string {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	// distance[i] is how far line i is from the nearest added or removed line.
+	distance := make([]int, len(lines))
+	last := -1
+	for i, line := range lines {
+		if isChange(line) {
+			last = i
+		}
+		distance[i] = len(lines)
+		if last >= 0 {
+			distance[i] = i - last
+		}
+	}
+	last = -1
+	for i := len(lines) - 1; i >= 0; i-- {
+		if isChange(lines[i]) {
+			last = i
+		}
+		if last >= 0 {
+			distance[i] = min(distance[i], last-i)
+		}
+	}
+
+	var out []string
+	skipped, added := 0, 0
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "+") {
+			if added > m.AddedRun {
+				out = append(out, fmt.Sprintf("+... (%d more added lines)", added-m.AddedRun))
+			}
+			added = 0
+		}
+		if isContext(line) && distance[i] > m.Context {
+			skipped++
+			continue
+		}
+		if skipped > 0 {
+			out = append(out, " ...")
+			skipped = 0
+		}
+		if strings.HasPrefix(line, "+") {
+			added++
+			if added > m.AddedRun {
+				continue
+			}
+		}
+		out = append(out, line)
+	}
+	if added > m.AddedRun {
+		out = append(out, fmt.Sprintf("+... (%d more added lines)", added-m.AddedRun))
+	}
+	if skipped > 0 {
+		out = append(out, " ...")
+	}
+	return out
+}
+
+// isContext also accepts empty lines, since some tools strip the single
+// space git writes before an empty context line.
+func isContext(line string) bool {
+	return line == "" || strings.HasPrefix(line, " ")
+}
+
+func isChange(line string) bool {
+	return strings.HasPrefix(line, "+") || strings.HasPrefix(line, "-")
+}
+
+func countChanges(section string) (added, deleted int) {
+	inHunk := false
+	for _, line := range strings.Split(section, "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+		case !inHunk:
+		case strings.HasPrefix(line, "+"):
+			added++
+		case strings.HasPrefix(line, "-"):
+			deleted++
+		}
+	}
+	return added, deleted
+}
+
+// DiffStat summarizes a unified diff as one line per file with its added and
+// deleted line counts, for when even a minimized diff is too large to send.
+func DiffStat(diff string) string {
+	var b strings.Builder
+	var totalAdded, totalDeleted int
+	files := FileChanges(diff)
+	for _, f := range files {
+		fmt.Fprintf(&b, "%s | +%d -%d\n", f.Path, f.Added, f.Deleted)
+		totalAdded += f.Added
+		totalDeleted += f.Deleted
+	}
+	fmt.Fprintf(&b, "%d file(s) changed, %d insertion(s)(+), %d deletion(s)(-)\n", len(files), totalAdded, totalDeleted)
+	return b.String()
+}
+
+// TruncateDiff keeps the leading whole file sections of diff that fit in size
+// bytes and lists the files it dropped. At least the first section is kept,
+// cut at a line boundary if it alone is too large.
+func TruncateDiff(diff string, size int) (string, []string) {
+	if len(diff) <= size {
+		return diff, nil
+	}
+	var b strings.Builder
+	var dropped []string
+	for _, section := range splitDiff(diff) {
+		switch {
+		case len(dropped) == 0 && b.Len()+len(section) <= size:
+			b.WriteString(section)
+		case b.Len() == 0:
+			cut := strings.LastIndex(section[:size], "\n") + 1
+			b.WriteString(section[:cut])
+			fmt.Fprintf(&b, "... %s: %d more bytes of diff omitted\n", diffSectionPath(section), len(section)-cut)
+		default:
+			dropped = append(dropped, diffSectionPath(section))
+		}
+	}
+	return b.String(), dropped
+}
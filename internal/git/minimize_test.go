@@ -0,0 +1,63 @@
+package git
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMinimizeDiff(t *testing.T) {
+	diff := "diff --git a/main.go b/main.go\n" +
+		"--- a/main.go\n+++ b/main.go\n" +
+		"@@ -1,9 +1,11 @@\n" +
+		" package main\n \n import \"fmt\"\n \n func main() {\n" +
+		"-\tfmt.Println(\"hi\")\n" +
+		"+\tfmt.Println(\"a\")\n+\tfmt.Println(\"b\")\n+\tfmt.Println(\"c\")\n+\tfmt.Println(\"d\")\n" +
+		" }\n \n // end\n" +
+		"diff --git a/vendor/x/x.go b/vendor/x/x.go\n" +
+		"--- a/vendor/x/x.go\n+++ b/vendor/x/x.go\n" +
+		"@@ -1 +1,2 @@\n package x\n+var X = 1\n"
+
+	got := MinimizeDiff(diff, Minimize{Context: 1, AddedRun: 2})
+	want := "diff --git a/main.go b/main.go\n" +
+		"--- a/main.go\n+++ b/main.go\n" +
+		"@@ -1,9 +1,11 @@\n" +
+		" ...\n func main() {\n" +
+		"-\tfmt.Println(\"hi\")\n" +
+		"+\tfmt.Println(\"a\")\n+\tfmt.Println(\"b\")\n+... (2 more added lines)\n" +
+		" }\n ...\n" +
+		"diff --git a/vendor/x/x.go b/vendor/x/x.go\n" +
+		"Vendored file vendor/x/x.go: +1 -0 lines (diff omitted)\n"
+	if got != want {
+		t.Fatalf("unexpected minimized diff:\n%s\nwant:\n%s", got, want)
+	}
+
+	if Vendored("vendor.go") || !Vendored("web/node_modules/react/index.js") {
+		t.Fatal("unexpected vendored path detection")
+	}
+}
+
+func TestTruncateDiff(t *testing.T) {
+	a := "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-a\n+b\n"
+	b := "diff --git a/b.go b/b.go\n--- a/b.go\n+++ b/b.go\n@@ -1 +1,2 @@\n a\n+b\n"
+
+	if got, dropped := TruncateDiff(a+b, len(a+b)); got != a+b || dropped != nil {
+		t.Fatalf("a fitting diff changed: %q, %v", got, dropped)
+	}
+	got, dropped := TruncateDiff(a+b, len(a)+10)
+	if got != a || len(dropped) != 1 || dropped[0] != "b.go" {
+		t.Fatalf("TruncateDiff() = %q, %v; want a.go alone", got, dropped)
+	}
+	got, _ = TruncateDiff(a+b, 40)
+	if !strings.HasPrefix(got, "diff --git a/a.go b/a.go\n") || !strings.HasSuffix(got, "a.go: 29 more bytes of diff omitted\n") {
+		t.Fatalf("an oversized first file was not cut at a line: %q", got)
+	}
+}
+
+func TestDiffStat(t *testing.T) {
+	diff := "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1,2 @@\n-a\n+b\n+c\n" +
+		"diff --git a/b.go b/b.go\n--- a/b.go\n+++ b/b.go\n@@ -1 +0,0 @@\n-a\n"
+	want := "a.go | +2 -1\nb.go | +0 -1\n2 file(s) changed, 2 insertion(s)(+), 2 deletion(s)(-)\n"
+	if got := DiffStat(diff); got != want {
+		t.Fatalf("DiffStat() = %q, want %q", got, want)
+	}
+}
@@ -7,43 +7,166 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"slices"
 	"strings"
+
+	"github.com/razobeckett/goco/internal/telemetry"
 )
 
 var ErrNoChanges = errors.New("no changes detected in the repository")
 
+// ErrBare reports that a command needs a working tree but the repository,
+// found via the directory or GIT_DIR, is bare.
+var ErrBare = errors.New("bare repository has no working tree; set GIT_WORK_TREE or use a checkout")
+
 type Repository struct {
 	dir string
+	// binary and extraArgs are the git executable and arguments placed
+	// before every subcommand, e.g. ["-c", "commit.gpgsign=false"].
+	binary    string
+	extraArgs []string
 }
 
 func NewRepository(dir string) *Repository {
-	return &Repository{dir: dir}
+	return &Repository{dir: dir, binary: "git"}
+}
+
+// SetDir changes the directory git runs in, like git -C.
+func (r *Repository) SetDir(dir string) {
+	r.dir = dir
+}
+
+// SetGit changes the git executable (empty keeps "git") and the arguments
+// placed before every subcommand.
+func (r *Repository) SetGit(binary string, extraArgs []string) {
+	if binary == "" {
+		binary = "git"
+	}
+	r.binary = binary
+	r.extraArgs = extraArgs
+}
+
+// At returns a repository for dir that runs git the same way as r.
+func (r *Repository) At(dir string) *Repository {
+	return &Repository{dir: dir, binary: r.binary, extraArgs: r.extraArgs}
 }
 
-func (r *Repository) Status(ctx context.Context) (string, error) {
-	return r.output(ctx, "status", "--short", "--branch")
+// Binary returns the git executable goco runs.
+func (r *Repository) Binary() string {
+	return r.binary
+}
+
+// Version returns the version of the git goco runs, e.g. "2.47.0".
+func (r *Repository) Version(ctx context.Context) (string, error) {
+	out, err := r.output(ctx, "version")
+	if err != nil {
+		return "", fmt.Errorf("read git version: %w", err)
+	}
+	return strings.TrimPrefix(strings.TrimSpace(out), "git version "), nil
+}
+
+func (r *Repository) Status(ctx context.Context) (*Status, error) {
+	out, err := r.output(ctx, "status", "--porcelain=v2", "--branch", "-z")
+	if err != nil {
+		return nil, r.worktreeErr(ctx, err)
+	}
+	return ParseStatus(out)
 }
 
-func (r *Repository) Diff(ctx context.Context, staged bool) (string, error) {
+// Diff returns the working tree or staged diff, optionally limited to paths
+// relative to the repository root.
+func (r *Repository) Diff(ctx context.Context, staged bool, paths ...string) (string, error) {
 	args := []string{"diff", "--no-color"}
 	if staged {
 		args = append(args, "--staged")
 	}
-	return r.output(ctx, args...)
+	if len(paths) > 0 {
+		args = append(args, "--")
+		args = append(args, topPathspecs(paths)...)
+	}
+	return r.diffOutput(ctx, args...)
+}
+
+// EmptyTreeDiff diffs the working tree's tracked files against the empty
+// tree. Before the first commit there is no HEAD, so this is what committing
+// every tracked change records.
+func (r *Repository) EmptyTreeDiff(ctx context.Context, paths ...string) (string, error) {
+	tree, err := r.output(ctx, "hash-object", "-t", "tree", "--stdin")
+	if err != nil {
+		return "", fmt.Errorf("hash empty tree: %w", err)
+	}
+	args := []string{"diff", "--no-color", strings.TrimSpace(tree), "--"}
+	return r.diffOutput(ctx, append(args, topPathspecs(paths)...)...)
+}
+
+// topPathspecs anchors root-relative paths, as status and diff report them,
+// at the top of the working tree so they match when goco runs in a
+// subdirectory.
+func topPathspecs(paths []string) []string {
+	specs := make([]string, len(paths))
+	for i, p := range paths {
+		specs[i] = ":(top)" + p
+	}
+	return specs
 }
 
-func (r *Repository) EnsureChanges(ctx context.Context) (string, error) {
+func (r *Repository) EnsureChanges(ctx context.Context) (*Status, error) {
 	status, err := r.Status(ctx)
 	if err != nil {
-		return "", fmt.Errorf("read git status: %w", err)
+		return nil, fmt.Errorf("read git status: %w", err)
 	}
-	lines := strings.Split(strings.TrimSpace(status), "\n")
-	if len(lines) <= 1 {
-		return "", ErrNoChanges
+	if !status.HasChanges() {
+		return nil, ErrNoChanges
 	}
 	return status, nil
 }
 
+// Root returns the top of the working tree, honouring GIT_DIR and
+// GIT_WORK_TREE; ErrBare when there is none.
+func (r *Repository) Root(ctx context.Context) (string, error) {
+	out, err := r.output(ctx, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", fmt.Errorf("locate repository root: %w", r.worktreeErr(ctx, err))
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// GitDir returns the absolute path of the repository's git directory.
+func (r *Repository) GitDir(ctx context.Context) (string, error) {
+	out, err := r.output(ctx, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return "", fmt.Errorf("locate git directory: %w", err)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// Name returns the repository's name: its working tree's directory name, or
+// for a bare repository the git directory's name without ".git".
+func (r *Repository) Name(ctx context.Context) (string, error) {
+	root, err := r.Root(ctx)
+	if err == nil {
+		return filepath.Base(root), nil
+	}
+	if !errors.Is(err, ErrBare) {
+		return "", err
+	}
+	gitDir, err := r.GitDir(ctx)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(filepath.Base(gitDir), ".git"), nil
+}
+
+// worktreeErr replaces err with ErrBare when it came from running a
+// working-tree command in a bare repository.
+func (r *Repository) worktreeErr(ctx context.Context, err error) error {
+	if out, bareErr := r.output(ctx, "rev-parse", "--is-bare-repository"); bareErr == nil && strings.TrimSpace(out) == "true" {
+		return ErrBare
+	}
+	return err
+}
+
 func (r *Repository) CurrentBranch(ctx context.Context) (string, error) {
 	out, err := r.output(ctx, "branch", "--show-current")
 	if err != nil {
@@ -52,46 +175,86 @@ func (r *Repository) CurrentBranch(ctx context.Context) (string, error) {
 	return strings.TrimSpace(out), nil
 }
 
+// CreateBranchArgs are the git arguments CreateBranch runs.
+func CreateBranchArgs(name string) []string {
+	return []string{"checkout", "-b", name}
+}
+
 func (r *Repository) CreateBranch(ctx context.Context, name string) error {
-	if _, err := r.output(ctx, "checkout", "-b", name); err != nil {
+	if _, err := r.output(ctx, CreateBranchArgs(name)...); err != nil {
 		return fmt.Errorf("create branch %q: %w", name, err)
 	}
 	return nil
 }
 
+// StageTrackedArgs are the git arguments StageTracked runs.
+func StageTrackedArgs() []string {
+	return []string{"add", "-u"}
+}
+
 func (r *Repository) StageTracked(ctx context.Context) error {
-	if _, err := r.output(ctx, "add", "-u"); err != nil {
+	if _, err := r.output(ctx, StageTrackedArgs()...); err != nil {
 		return fmt.Errorf("stage tracked changes: %w", err)
 	}
 	return nil
 }
 
 func (r *Repository) StagedFiles(ctx context.Context) ([]string, error) {
-	out, err := r.output(ctx, "diff", "--name-only", "--cached")
+	out, err := r.output(ctx, "diff", "--name-only", "--cached", "-z")
 	if err != nil {
 		return nil, fmt.Errorf("list staged files: %w", err)
 	}
-	files := strings.Fields(strings.TrimSpace(out))
+	files := strings.FieldsFunc(out, func(r rune) bool { return r == 0 })
 	if len(files) == 0 {
 		return nil, ErrNoChanges
 	}
 	return files, nil
 }
 
+// IgnoredDirs returns the directories whose contents git ignores, relative
+// to the repository root. Nested ignored directories are not listed.
+func (r *Repository) IgnoredDirs(ctx context.Context) ([]string, error) {
+	out, err := r.output(ctx, "ls-files", "-z", "--full-name", "--others", "--ignored", "--exclude-standard", "--directory", "--", ":/")
+	if err != nil {
+		return nil, fmt.Errorf("list ignored files: %w", err)
+	}
+	var dirs []string
+	for entry := range strings.SplitSeq(out, "\x00") {
+		if dir, ok := strings.CutSuffix(entry, "/"); ok {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs, nil
+}
+
+// Ignored reports whether git ignores path.
+func (r *Repository) Ignored(ctx context.Context, path string) bool {
+	_, err := r.output(ctx, "check-ignore", "-q", "--", path)
+	return err == nil
+}
+
 func (r *Repository) RecentLog(ctx context.Context, count int) (string, error) {
 	return r.output(ctx, "log", fmt.Sprintf("--max-count=%d", count),
 		"--pretty=format:%ad%n%s%n%b", "--date=iso")
 }
 
-func (r *Repository) Commit(ctx context.Context, message string, onlyFiles []string) error {
-	args := []string{"commit", "-m", message}
+// CommitArgs are the git arguments Commit runs. With onlyFiles, relative to
+// the repository root, just those paths are committed.
+func CommitArgs(message string, onlyFiles []string, o CommitOverrides) []string {
+	args := append([]string{"commit"}, o.args()...)
+	args = append(args, "-m", message)
 	if len(onlyFiles) > 0 {
 		args = append(args, "--only", "--")
-		args = append(args, onlyFiles...)
+		args = append(args, topPathspecs(onlyFiles)...)
 	}
+	return args
+}
 
-	cmd := exec.CommandContext(ctx, "git", args...)
-	cmd.Dir = r.dir
+func (r *Repository) Commit(ctx context.Context, message string, onlyFiles []string, o CommitOverrides) (err error) {
+	ctx, end := telemetry.StartSpan(ctx, "git commit", telemetry.Tags{"git.only_files": fmt.Sprint(len(onlyFiles))})
+	defer func() { end(err) }()
+
+	cmd := r.command(ctx, CommitArgs(message, onlyFiles, o)...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
@@ -101,9 +264,11 @@ func (r *Repository) Commit(ctx context.Context, message string, onlyFiles []str
 	return nil
 }
 
-func (r *Repository) output(ctx context.Context, args ...string) (string, error) {
-	cmd := exec.CommandContext(ctx, "git", args...)
-	cmd.Dir = r.dir
+func (r *Repository) output(ctx context.Context, args ...string) (_ string, err error) {
+	ctx, end := telemetry.StartSpan(ctx, "git "+args[0], telemetry.Tags{"git.args": strings.Join(args, " ")})
+	defer func() { end(err) }()
+
+	cmd := r.command(ctx, args...)
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
@@ -120,3 +285,12 @@ func (r *Repository) output(ctx context.Context, args ...string) (string, error)
 
 	return stdout.String(), nil
 }
+
+// command builds a git invocation in the repository's directory. Paths are
+// printed as they are rather than with non-ASCII bytes escaped, so the model
+// and the user read them as written; [Git] extra_args can turn it back on.
+func (r *Repository) command(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, r.binary, slices.Concat([]string{"-c", "core.quotepath=off"}, r.extraArgs, args)...)
+	cmd.Dir = r.dir
+	return cmd
+}
@@ -2,10 +2,14 @@ package git
 
 import (
 	"context"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestRepositoryStagedFiles(t *testing.T) {
@@ -51,3 +55,639 @@ func TestRepositoryStagedFiles(t *testing.T) {
 		t.Fatalf("unexpected staged file: %s", files[0])
 	}
 }
+
+func TestRepositoryState(t *testing.T) {
+	dir := t.TempDir()
+
+	initCmd := exec.Command("git", "init")
+	initCmd.Dir = dir
+	if out, err := initCmd.CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v, out: %s", err, out)
+	}
+
+	repo := NewRepository(dir)
+	state, err := repo.State(context.Background())
+	if err != nil {
+		t.Fatalf("State failed: %v", err)
+	}
+	if state != StateNone {
+		t.Fatalf("expected clean state, got %s", state)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ".git", "MERGE_HEAD"), []byte("deadbeef\n"), 0o644); err != nil {
+		t.Fatalf("write MERGE_HEAD: %v", err)
+	}
+
+	state, err = repo.State(context.Background())
+	if err != nil {
+		t.Fatalf("State failed: %v", err)
+	}
+	if state != StateMerging {
+		t.Fatalf("expected merging state, got %s", state)
+	}
+}
+
+func TestRepositoryLargeBinaries(t *testing.T) {
+	dir := t.TempDir()
+
+	initCmd := exec.Command("git", "init")
+	initCmd.Dir = dir
+	if out, err := initCmd.CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v, out: %s", err, out)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ".gitattributes"), []byte("*.psd filter=lfs diff=lfs merge=lfs -text\n"), 0o644); err != nil {
+		t.Fatalf("write .gitattributes: %v", err)
+	}
+
+	blob := make([]byte, LargeFileThreshold+1)
+	for _, name := range []string{"big.bin", "art.psd"} {
+		if err := os.WriteFile(filepath.Join(dir, name), blob, 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "small.bin"), []byte{0, 1, 2}, 0o644); err != nil {
+		t.Fatalf("write small.bin: %v", err)
+	}
+
+	repo := NewRepository(dir)
+	large, err := repo.LargeBinaries(context.Background(), dir, []string{"big.bin", "art.psd", "small.bin", "missing.bin"})
+	if err != nil {
+		t.Fatalf("LargeBinaries failed: %v", err)
+	}
+	if len(large) != 1 || large[0].Path != "big.bin" {
+		t.Fatalf("expected only big.bin, got %+v", large)
+	}
+}
+
+func TestRepositoryResolution(t *testing.T) {
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=goco", "-c", "user.email=goco@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil && args[0] != "merge" {
+			t.Fatalf("git %v failed: %v, out: %s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	git("init", "-b", "main")
+	write("a.txt", "base\n")
+	write("b.txt", "base\n")
+	git("add", ".")
+	git("commit", "-m", "base")
+
+	git("checkout", "-b", "topic")
+	write("a.txt", "topic\n")
+	write("b.txt", "topic\n")
+	git("commit", "-am", "topic change")
+
+	git("checkout", "main")
+	write("a.txt", "main\n")
+	write("b.txt", "main\n")
+	git("commit", "-am", "main change")
+
+	git("merge", "topic")
+	write("a.txt", "main\n")
+	write("b.txt", "main\ntopic\n")
+	git("add", ".")
+
+	res, err := NewRepository(dir).Resolution(context.Background())
+	if err != nil {
+		t.Fatalf("Resolution failed: %v", err)
+	}
+	if res.State != StateMerging || res.Theirs != "MERGE_HEAD" {
+		t.Fatalf("unexpected resolution: %+v", res)
+	}
+	if len(res.Files) != 2 || res.Files[0].Kept != SideOurs || res.Files[1].Kept != SideBoth {
+		t.Fatalf("unexpected resolved files: %+v", res.Files)
+	}
+	if res.OursDiff == "" || res.TheirsDiff == "" {
+		t.Fatalf("expected diffs against both parents: %+v", res)
+	}
+}
+
+func TestRepositorySeries(t *testing.T) {
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=goco", "-c", "user.email=goco@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v, out: %s", args, err, out)
+		}
+	}
+
+	git("init", "-b", "main")
+	git("commit", "--allow-empty", "-m", "base")
+	git("commit", "--allow-empty", "-m", "feat: first", "-m", "Body text.")
+	git("commit", "--allow-empty", "-m", "fix: second")
+
+	entries, err := NewRepository(dir).Series(context.Background(), "HEAD~2..HEAD")
+	if err != nil {
+		t.Fatalf("Series failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 commits, got %+v", entries)
+	}
+	if entries[0].Subject != "feat: first" || entries[0].Body != "Body text." || entries[1].Subject != "fix: second" {
+		t.Fatalf("unexpected series: %+v", entries)
+	}
+}
+
+// reviewersRepo commits a.txt as alice, then bob, then me, and leaves the
+// first two lines (alice's) modified in the working tree.
+func reviewersRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	commitAs := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte(content), 0o644); err != nil {
+			t.Fatalf("write a.txt: %v", err)
+		}
+		for _, args := range [][]string{{"add", "a.txt"}, {"commit", "-m", "change by " + name}} {
+			cmd := exec.Command("git", append([]string{"-c", "user.name=" + name, "-c", "user.email=" + name + "@example.com"}, args...)...)
+			cmd.Dir = dir
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("git %v failed: %v, out: %s", args, err, out)
+			}
+		}
+	}
+
+	initCmd := exec.Command("git", "init")
+	initCmd.Dir = dir
+	if out, err := initCmd.CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v, out: %s", err, out)
+	}
+	commitAs("alice", "one\ntwo\nthree\n")
+	commitAs("bob", "one\ntwo\nTHREE\n")
+	commitAs("me", "one\ntwo\nTHREE\nfour\n")
+
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("ONE\nTWO\nTHREE\nfour\n"), 0o644); err != nil {
+		t.Fatalf("write a.txt: %v", err)
+	}
+	return dir
+}
+
+func TestRepositorySuggestReviewers(t *testing.T) {
+	repo := NewRepository(reviewersRepo(t))
+	diff, err := repo.Diff(context.Background(), false)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+
+	reviewers, err := repo.SuggestReviewers(context.Background(), diff, "HEAD", "me@example.com", 5)
+	if err != nil {
+		t.Fatalf("SuggestReviewers failed: %v", err)
+	}
+	if len(reviewers) != 2 || reviewers[0].Email != "alice@example.com" || reviewers[0].Lines != 2 {
+		t.Fatalf("unexpected reviewers: %+v", reviewers)
+	}
+	if reviewers[1].Email != "bob@example.com" || reviewers[1].Lines != 0 || reviewers[1].Commits != 1 {
+		t.Fatalf("unexpected second reviewer: %+v", reviewers[1])
+	}
+}
+
+func TestRepositoryBlameContext(t *testing.T) {
+	repo := NewRepository(reviewersRepo(t))
+	diff, err := repo.Diff(context.Background(), false)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+
+	history, err := repo.BlameContext(context.Background(), diff, "HEAD")
+	if err != nil {
+		t.Fatalf("BlameContext failed: %v", err)
+	}
+	if !strings.HasPrefix(history, "a.txt:\n") || !strings.Contains(history, " change by alice") || strings.Contains(history, "bob") {
+		t.Fatalf("unexpected blame context:\n%s", history)
+	}
+}
+
+func TestRepositoryHooks(t *testing.T) {
+	dir := t.TempDir()
+	initCmd := exec.Command("git", "init")
+	initCmd.Dir = dir
+	if out, err := initCmd.CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v, out: %s", err, out)
+	}
+
+	repo := NewRepository(dir)
+	ctx := context.Background()
+	hooks, err := repo.Hooks(ctx)
+	if err != nil {
+		t.Fatalf("Hooks failed: %v", err)
+	}
+	if len(hooks) != len(HookNames) || hooks[0].State != HookMissing {
+		t.Fatalf("expected missing hooks, got %+v", hooks)
+	}
+
+	if err := InstallHook(hooks[0]); err != nil {
+		t.Fatalf("InstallHook failed: %v", err)
+	}
+	outdated := strings.Replace(mustHookScript(t, "pre-push"), hookVersionMarker+"1", hookVersionMarker+"0", 1)
+	if err := os.WriteFile(hooks[1].Path, []byte(outdated), 0o755); err != nil {
+		t.Fatalf("write outdated hook: %v", err)
+	}
+
+	hooks, err = repo.Hooks(ctx)
+	if err != nil {
+		t.Fatalf("Hooks failed: %v", err)
+	}
+	if hooks[0].State != HookCurrent || hooks[1].State != HookOutdated || hooks[1].Version != 0 {
+		t.Fatalf("unexpected hook states %+v", hooks)
+	}
+	if info, err := os.Stat(hooks[0].Path); err != nil || info.Mode()&0o111 == 0 {
+		t.Fatalf("expected an executable hook, got %v, %v", info, err)
+	}
+
+	if err := os.WriteFile(hooks[0].Path, []byte("#!/bin/sh\nmake lint\n"), 0o755); err != nil {
+		t.Fatalf("write foreign hook: %v", err)
+	}
+	hooks, _ = repo.Hooks(ctx)
+	if hooks[0].State != HookForeign {
+		t.Fatalf("expected foreign hook, got %v", hooks[0].State)
+	}
+}
+
+func mustHookScript(t *testing.T, name string) string {
+	t.Helper()
+	script, err := HookScript(name)
+	if err != nil {
+		t.Fatalf("HookScript failed: %v", err)
+	}
+	return script
+}
+
+func TestRepositoryCommitSettings(t *testing.T) {
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v, out: %s", args, err, out)
+		}
+	}
+	git("init")
+
+	repo := NewRepository(dir)
+	ctx := context.Background()
+	settings, err := repo.CommitSettings(ctx)
+	if err != nil {
+		t.Fatalf("CommitSettings failed: %v", err)
+	}
+	if settings.Sign || len(settings.Hooks) != 0 {
+		t.Fatalf("expected no hooks or signing, got %+v", settings)
+	}
+
+	hooksDir, _ := repo.HooksDir(ctx)
+	if err := os.WriteFile(filepath.Join(hooksDir, "commit-msg"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(hooksDir, "pre-commit"), []byte("#!/bin/sh\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	git("config", "commit.gpgsign", "yes")
+	git("config", "gpg.format", "ssh")
+	git("config", "user.signingkey", "~/.ssh/id_ed25519.pub")
+
+	settings, err = repo.CommitSettings(ctx)
+	if err != nil {
+		t.Fatalf("CommitSettings failed: %v", err)
+	}
+	if !settings.Sign || settings.SignFormat != "ssh" || settings.SigningKey != "~/.ssh/id_ed25519.pub" || len(settings.Hooks) != 1 || settings.Hooks[0] != "commit-msg" {
+		t.Fatalf("unexpected settings %+v", settings)
+	}
+}
+
+func TestRepositoryCommitOverrides(t *testing.T) {
+	dir := t.TempDir()
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v, out: %s", args, err, out)
+		}
+		return string(out)
+	}
+	git("init")
+	git("config", "user.name", "goco")
+	git("config", "user.email", "goco@example.com")
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	git("add", "a.txt")
+
+	date := time.Date(2019, 6, 1, 9, 30, 0, 0, time.FixedZone("", 2*60*60))
+	o := CommitOverrides{Author: "Imported Author <imported@example.com>", Date: date}
+	if err := NewRepository(dir).Commit(context.Background(), "chore: import", nil, o); err != nil {
+		t.Fatal(err)
+	}
+	got := strings.TrimSpace(git("log", "-1", "--format=%an <%ae>|%aI|%cn"))
+	if want := "Imported Author <imported@example.com>|2019-06-01T09:30:00+02:00|goco"; got != want {
+		t.Errorf("commit = %q, want %q", got, want)
+	}
+
+	if err := NewRepository(dir).Commit(context.Background(), "ci: trigger a rebuild", nil, CommitOverrides{AllowEmpty: true}); err != nil {
+		t.Fatalf("expected an empty commit to be allowed: %v", err)
+	}
+	if got := strings.TrimSpace(git("log", "-1", "--format=%s", "--stat")); got != "ci: trigger a rebuild" {
+		t.Errorf("empty commit = %q", got)
+	}
+}
+
+func TestRepositorySetGit(t *testing.T) {
+	dir := t.TempDir()
+	cmd := exec.Command("git", "init")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v, out: %s", err, out)
+	}
+
+	repo := NewRepository(dir)
+	repo.SetGit("", []string{"-c", "user.email=override@example.com"})
+	if got := repo.UserEmail(context.Background()); got != "override@example.com" {
+		t.Fatalf("expected extra args before the subcommand, got user.email %q", got)
+	}
+	if got := repo.At(dir).UserEmail(context.Background()); got != "override@example.com" {
+		t.Fatalf("expected At to keep the extra args, got user.email %q", got)
+	}
+
+	repo.SetGit(filepath.Join(dir, "no-such-git"), nil)
+	if _, err := repo.Version(context.Background()); err == nil {
+		t.Fatal("expected a missing git binary to fail")
+	}
+}
+
+func TestRepositoryBareAndGitDir(t *testing.T) {
+	base := t.TempDir()
+	src, bare, worktree := filepath.Join(base, "src"), filepath.Join(base, "app.git"), filepath.Join(base, "worktree")
+	for _, args := range [][]string{
+		{"init", "-b", "main", src},
+		{"-C", src, "-c", "user.name=goco", "-c", "user.email=goco@example.com", "commit", "--allow-empty", "-m", "feat: first"},
+		{"clone", "--bare", src, bare},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v, out: %s", args, err, out)
+		}
+	}
+	if err := os.Mkdir(worktree, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	repo := NewRepository(bare)
+	if _, err := repo.Root(ctx); !errors.Is(err, ErrBare) {
+		t.Fatalf("expected ErrBare from Root, got %v", err)
+	}
+	if _, err := repo.Status(ctx); !errors.Is(err, ErrBare) {
+		t.Fatalf("expected ErrBare from Status, got %v", err)
+	}
+	if name, err := repo.Name(ctx); err != nil || name != "app" {
+		t.Fatalf("Name() = %q, %v", name, err)
+	}
+	if history, err := repo.History(ctx, 1); err != nil || len(history) != 1 {
+		t.Fatalf("expected history from a bare repository, got %+v, %v", history, err)
+	}
+
+	// Wrappers point git at the repository without changing directory.
+	t.Setenv("GIT_DIR", bare)
+	t.Setenv("GIT_WORK_TREE", worktree)
+	root, err := NewRepository(base).Root(ctx)
+	if err != nil {
+		t.Fatalf("Root with GIT_DIR and GIT_WORK_TREE failed: %v", err)
+	}
+	if resolved, _ := filepath.EvalSymlinks(worktree); root != resolved && root != worktree {
+		t.Fatalf("Root() = %q, want %q", root, worktree)
+	}
+}
+
+func TestRepositoryEmptyTreeDiff(t *testing.T) {
+	dir := t.TempDir()
+	cmd := exec.Command("git", "init")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v, out: %s", err, out)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("hello\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	add := exec.Command("git", "add", "a.txt")
+	add.Dir = dir
+	if out, err := add.CombinedOutput(); err != nil {
+		t.Fatalf("git add failed: %v, out: %s", err, out)
+	}
+
+	repo := NewRepository(dir)
+	ctx := context.Background()
+	status, err := repo.Status(ctx)
+	if err != nil || !status.Initial {
+		t.Fatalf("expected an initial status, got %+v, %v", status, err)
+	}
+	if worktree, _ := repo.Diff(ctx, false); worktree != "" {
+		t.Fatalf("expected no unstaged diff, got %q", worktree)
+	}
+	diff, err := repo.EmptyTreeDiff(ctx)
+	if err != nil {
+		t.Fatalf("EmptyTreeDiff failed: %v", err)
+	}
+	if !strings.Contains(diff, "new file mode") || !strings.Contains(diff, "+hello") {
+		t.Fatalf("expected a.txt as a new file, got %q", diff)
+	}
+}
+
+func TestRepositoryCloneShape(t *testing.T) {
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=goco", "-c", "user.email=goco@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v, out: %s", args, err, out)
+		}
+	}
+	git("init")
+	for _, name := range []string{"api/main.go", "web/index.html"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(name+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git("add", ".")
+	git("commit", "-m", "initial")
+
+	repo := NewRepository(dir)
+	ctx := context.Background()
+	if shape := repo.CloneShape(ctx); shape.Partial() || shape.Sparse || shape.String() != "" {
+		t.Fatalf("expected a full checkout, got %+v", shape)
+	}
+
+	git("sparse-checkout", "set", "api")
+	git("config", "extensions.partialClone", "origin")
+	shape := repo.CloneShape(ctx)
+	if !shape.Partial() || shape.PartialRemote != "origin" || !shape.Sparse || len(shape.Cone) != 1 || shape.Cone[0] != "api" {
+		t.Fatalf("unexpected shape %+v", shape)
+	}
+	if !strings.Contains(shape.String(), "sparse checkout of api") {
+		t.Fatalf("unexpected description %q", shape.String())
+	}
+}
+
+func TestRepositoryRootRelativePaths(t *testing.T) {
+	dir := t.TempDir()
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=goco", "-c", "user.email=goco@example.com"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v, out: %s", args, err, out)
+		}
+		return string(out)
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, "pkg", "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"pkg/a.txt", "pkg/sub/b.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git("init", "-b", "main")
+	git("add", ".")
+
+	// Paths come from status relative to the root; goco runs in a subdirectory.
+	repo := NewRepository(filepath.Join(dir, "pkg", "sub"))
+	ctx := context.Background()
+	diff, err := repo.Diff(ctx, true, "pkg/a.txt")
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if !strings.Contains(diff, "+pkg/a.txt") || strings.Contains(diff, "b.txt") {
+		t.Fatalf("expected only pkg/a.txt in diff:\n%s", diff)
+	}
+
+	git("commit", "--allow-empty", "-m", "base")
+	if err := os.WriteFile(filepath.Join(dir, "pkg", "a.txt"), []byte("changed\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "pkg", "sub", "b.txt"), []byte("changed\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	git("add", ".")
+	cmd := exec.Command("git", append([]string{"-c", "user.name=goco", "-c", "user.email=goco@example.com"}, CommitArgs("fix: a", []string{"pkg/a.txt"}, CommitOverrides{})...)...)
+	cmd.Dir = filepath.Join(dir, "pkg", "sub")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("commit failed: %v, out: %s", err, out)
+	}
+	if files := git("show", "--name-only", "--format=", "HEAD"); strings.TrimSpace(files) != "pkg/a.txt" {
+		t.Fatalf("expected only pkg/a.txt committed, got %q", files)
+	}
+}
+
+func TestRepositoryIgnoredDirs(t *testing.T) {
+	dir := t.TempDir()
+	initCmd := exec.Command("git", "init")
+	initCmd.Dir = dir
+	if out, err := initCmd.CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v, out: %s", err, out)
+	}
+	for _, name := range []string{"node_modules/pkg/index.js", "src/build/out.o", "src/main.go"} {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("node_modules/\nbuild/\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Paths are relative to the root even from a subdirectory.
+	repo := NewRepository(filepath.Join(dir, "src"))
+	ctx := context.Background()
+	dirs, err := repo.IgnoredDirs(ctx)
+	if err != nil {
+		t.Fatalf("IgnoredDirs failed: %v", err)
+	}
+	if strings.Join(dirs, ",") != "node_modules,src/build" {
+		t.Fatalf("IgnoredDirs = %v, want node_modules and src/build", dirs)
+	}
+	if !repo.Ignored(ctx, filepath.Join(dir, "node_modules", "other")) || repo.Ignored(ctx, filepath.Join(dir, "src", "main.go")) {
+		t.Fatal("Ignored disagrees with .gitignore")
+	}
+}
+
+func TestRepositoryNonASCIIPaths(t *testing.T) {
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v, out: %s", args, err, out)
+		}
+	}
+	git("init")
+	paths := []string{"文档/说明.md", "🎉 party.txt", "café.go"}
+	for _, path := range paths {
+		if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(path)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, path), []byte("こんにちは 👋\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git(append([]string{"add", "--"}, paths...)...)
+
+	ctx := context.Background()
+	repo := NewRepository(dir)
+	staged, err := repo.StagedFiles(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(slices.Sorted(slices.Values(staged)), slices.Sorted(slices.Values(paths))) {
+		t.Errorf("StagedFiles() = %q, want %q", staged, paths)
+	}
+
+	status, err := repo.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var listed []string
+	for _, e := range status.Entries {
+		listed = append(listed, e.Path)
+	}
+	if !slices.Equal(slices.Sorted(slices.Values(listed)), slices.Sorted(slices.Values(paths))) {
+		t.Errorf("status paths = %q, want %q", listed, paths)
+	}
+
+	diff, err := repo.Diff(ctx, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(diff, `\3`) || !strings.Contains(diff, "+こんにちは 👋") {
+		t.Errorf("diff escapes non-ASCII text:\n%s", diff)
+	}
+	var changed []string
+	for _, f := range FileChanges(diff) {
+		changed = append(changed, f.Path)
+	}
+	if !slices.Equal(slices.Sorted(slices.Values(changed)), slices.Sorted(slices.Values(paths))) {
+		t.Errorf("diff paths = %q, want %q", changed, paths)
+	}
+}
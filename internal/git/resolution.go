@@ -0,0 +1,155 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Side names the parent a conflict resolution kept for a file.
+type Side int
+
+const (
+	SideBoth Side = iota
+	SideOurs
+	SideTheirs
+)
+
+func (s Side) String() string {
+	switch s {
+	case SideOurs:
+		return "kept ours"
+	case SideTheirs:
+		return "kept theirs"
+	default:
+		return "combined both sides"
+	}
+}
+
+// ResolvedFile is a path both sides changed, with the side its staged
+// resolution matches.
+type ResolvedFile struct {
+	Path string
+	Kept Side
+}
+
+// Resolution compares the staged result of a conflicted operation with the
+// two parents it was resolved from.
+type Resolution struct {
+	State State
+	// Theirs is the incoming revision, e.g. MERGE_HEAD.
+	Theirs string
+	// TheirsSummary is the abbreviated hash and subject of the incoming commit.
+	TheirsSummary string
+	Files         []ResolvedFile
+	// OursDiff and TheirsDiff are the staged resolution diffed against each
+	// parent, limited to Files.
+	OursDiff   string
+	TheirsDiff string
+}
+
+// String renders a per-file summary of what the resolution kept.
+func (res *Resolution) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "resolving: %s of %s (%s)\n", res.State, res.Theirs, res.TheirsSummary)
+	b.WriteString("files changed on both sides:")
+	for _, f := range res.Files {
+		fmt.Fprintf(&b, "\n  %s: %s", f.Path, f.Kept)
+	}
+	return b.String()
+}
+
+// Resolution inspects an in-progress merge, rebase, cherry-pick, or revert
+// and reports how the staged changes resolve the paths both sides touched.
+func (r *Repository) Resolution(ctx context.Context) (*Resolution, error) {
+	state, err := r.State(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// base is the common ancestor the two sides diverged from.
+	var theirs, base string
+	switch state {
+	case StateMerging:
+		theirs = "MERGE_HEAD"
+		out, err := r.output(ctx, "merge-base", "HEAD", theirs)
+		if err != nil {
+			return nil, fmt.Errorf("find merge base: %w", err)
+		}
+		base = strings.TrimSpace(out)
+	case StateRebasing:
+		theirs, base = "REBASE_HEAD", "REBASE_HEAD^"
+	case StateCherryPicking:
+		theirs, base = "CHERRY_PICK_HEAD", "CHERRY_PICK_HEAD^"
+	case StateReverting:
+		// A revert applies the inverse change: the reverted commit is the base.
+		theirs, base = "REVERT_HEAD^", "REVERT_HEAD"
+	default:
+		return nil, fmt.Errorf("no merge, rebase, cherry-pick, or revert is in progress")
+	}
+
+	ours, err := r.changedPaths(ctx, base, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	incoming, err := r.changedPaths(ctx, base, theirs)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, p := range incoming {
+		if slices.Contains(ours, p) {
+			paths = append(paths, p)
+		}
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no paths were changed on both sides; there is no conflict resolution to describe")
+	}
+
+	differsFromOurs, err := r.changedPaths(ctx, append([]string{"--cached", "HEAD", "--"}, paths...)...)
+	if err != nil {
+		return nil, err
+	}
+	differsFromTheirs, err := r.changedPaths(ctx, append([]string{"--cached", theirs, "--"}, paths...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Resolution{State: state, Theirs: theirs}
+	for _, p := range paths {
+		file := ResolvedFile{Path: p, Kept: SideBoth}
+		switch {
+		case !slices.Contains(differsFromOurs, p):
+			file.Kept = SideOurs
+		case !slices.Contains(differsFromTheirs, p):
+			file.Kept = SideTheirs
+		}
+		res.Files = append(res.Files, file)
+	}
+
+	if out, err := r.output(ctx, "log", "-1", "--format=%h %s", theirs); err == nil {
+		res.TheirsSummary = strings.TrimSpace(out)
+	}
+
+	diffArgs := append([]string{"diff", "--no-color", "--cached", "HEAD", "--"}, paths...)
+	if res.OursDiff, err = r.output(ctx, diffArgs...); err != nil {
+		return nil, fmt.Errorf("diff resolution against HEAD: %w", err)
+	}
+	diffArgs[3] = theirs
+	if res.TheirsDiff, err = r.output(ctx, diffArgs...); err != nil {
+		return nil, fmt.Errorf("diff resolution against %s: %w", theirs, err)
+	}
+
+	return res, nil
+}
+
+// changedPaths lists the NUL-separated output of `git diff --name-only` with args.
+func (r *Repository) changedPaths(ctx context.Context, args ...string) ([]string, error) {
+	out, err := r.output(ctx, append([]string{"diff", "--name-only", "-z"}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("list changed paths: %w", err)
+	}
+	return strings.FieldsFunc(out, func(r rune) bool { return r == 0 }), nil
+}
@@ -0,0 +1,226 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// reviewerLogDepth caps how many past commits per file count towards a suggestion.
+const reviewerLogDepth = 20
+
+// Reviewer is a past author of code a diff touches.
+type Reviewer struct {
+	Name  string
+	Email string
+	// Lines counts changed or deleted lines they last touched, per blame.
+	Lines int
+	// Commits counts their recent commits to the touched files.
+	Commits int
+}
+
+// lineRange is an inclusive range of line numbers in the old file.
+type lineRange struct{ start, end int }
+
+// SuggestReviewers ranks the authors of the code diff modifies. Lines that
+// the diff changes or deletes are blamed at rev (the diff's old side); files
+// are also credited to their recent committers. Authors whose email equals
+// exclude are skipped. At most limit reviewers are returned.
+func (r *Repository) SuggestReviewers(ctx context.Context, diff, rev, exclude string, limit int) ([]Reviewer, error) {
+	byEmail := make(map[string]*Reviewer)
+	credit := func(name, email string) *Reviewer {
+		key := strings.ToLower(email)
+		if key == strings.ToLower(exclude) && exclude != "" {
+			return nil
+		}
+		rv, ok := byEmail[key]
+		if !ok {
+			rv = &Reviewer{Name: name, Email: email}
+			byEmail[key] = rv
+		}
+		return rv
+	}
+
+	for _, section := range splitDiff(diff) {
+		path, ranges := oldPathAndRanges(section)
+		if path == "" {
+			continue // new file: no history to credit
+		}
+
+		for _, lr := range ranges {
+			out, err := r.output(ctx, "blame", "--line-porcelain", "-L", fmt.Sprintf("%d,%d", lr.start, lr.end), rev, "--", path)
+			if err != nil {
+				return nil, fmt.Errorf("blame %s: %w", path, err)
+			}
+			for _, a := range parseBlameAuthors(out) {
+				if rv := credit(a[0], a[1]); rv != nil {
+					rv.Lines++
+				}
+			}
+		}
+
+		out, err := r.output(ctx, "log", "--no-merges", fmt.Sprintf("--max-count=%d", reviewerLogDepth), "--format=%aN%x00%aE", rev, "--", path)
+		if err != nil {
+			return nil, fmt.Errorf("read history of %s: %w", path, err)
+		}
+		for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+			name, email, ok := strings.Cut(line, "\x00")
+			if !ok {
+				continue
+			}
+			if rv := credit(name, email); rv != nil {
+				rv.Commits++
+			}
+		}
+	}
+
+	reviewers := make([]Reviewer, 0, len(byEmail))
+	for _, rv := range byEmail {
+		reviewers = append(reviewers, *rv)
+	}
+	sort.Slice(reviewers, func(i, j int) bool {
+		a, b := reviewers[i], reviewers[j]
+		if a.Lines != b.Lines {
+			return a.Lines > b.Lines
+		}
+		if a.Commits != b.Commits {
+			return a.Commits > b.Commits
+		}
+		return a.Email < b.Email
+	})
+	if limit > 0 && len(reviewers) > limit {
+		reviewers = reviewers[:limit]
+	}
+	return reviewers, nil
+}
+
+// UserName returns the configured user.name, or "" when unset.
+func (r *Repository) UserName(ctx context.Context) string {
+	out, err := r.output(ctx, "config", "--get", "user.name")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(out)
+}
+
+// UserEmail returns the configured user.email, or "" when unset.
+func (r *Repository) UserEmail(ctx context.Context) string {
+	out, err := r.output(ctx, "config", "--get", "user.email")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(out)
+}
+
+// oldPathAndRanges returns the pre-image path of a diff section and the
+// ranges of old lines its hunks change or delete; context lines are skipped.
+func oldPathAndRanges(section string) (string, []lineRange) {
+	var path string
+	var ranges []lineRange
+	oldLine, inHunk := 0, false
+	for _, line := range strings.Split(section, "\n") {
+		if header, ok := strings.CutPrefix(line, "@@ -"); ok {
+			old, _, _ := strings.Cut(header, " ")
+			startText, _, _ := strings.Cut(old, ",")
+			start, err := strconv.Atoi(startText)
+			oldLine, inHunk = start, err == nil
+			continue
+		}
+		if !inHunk {
+			if p, ok := strings.CutPrefix(line, "--- a/"); ok {
+				path = p
+			}
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "-"):
+			if n := len(ranges); n > 0 && ranges[n-1].end == oldLine-1 {
+				ranges[n-1].end = oldLine
+			} else {
+				ranges = append(ranges, lineRange{oldLine, oldLine})
+			}
+			oldLine++
+		case strings.HasPrefix(line, " "):
+			oldLine++
+		}
+	}
+	return path, ranges
+}
+
+// parseBlameAuthors returns the (name, email) of every line in
+// `git blame --line-porcelain` output.
+func parseBlameAuthors(out string) [][2]string {
+	var authors [][2]string
+	var name string
+	for _, line := range strings.Split(out, "\n") {
+		switch {
+		case strings.HasPrefix(line, "author "):
+			name = strings.TrimPrefix(line, "author ")
+		case strings.HasPrefix(line, "author-mail "):
+			email := strings.Trim(strings.TrimPrefix(line, "author-mail "), "<>")
+			authors = append(authors, [2]string{name, email})
+		}
+	}
+	return authors
+}
+
+// blameContextPerFile caps how many prior commits are listed for one file.
+const blameContextPerFile = 5
+
+// BlameContext lists, per file, the commits that last touched the lines diff
+// changes or deletes, as "hash subject" lines blamed at rev. New files and
+// pure additions contribute nothing.
+func (r *Repository) BlameContext(ctx context.Context, diff, rev string) (string, error) {
+	var b strings.Builder
+	for _, section := range splitDiff(diff) {
+		path, ranges := oldPathAndRanges(section)
+		if path == "" || len(ranges) == 0 {
+			continue
+		}
+
+		args := []string{"blame", "--porcelain"}
+		for _, lr := range ranges {
+			args = append(args, "-L", fmt.Sprintf("%d,%d", lr.start, lr.end))
+		}
+		out, err := r.output(ctx, append(args, rev, "--", path)...)
+		if err != nil {
+			return "", fmt.Errorf("blame %s: %w", path, err)
+		}
+
+		commits := parseBlameSummaries(out)
+		if len(commits) == 0 {
+			continue
+		}
+		if len(commits) > blameContextPerFile {
+			commits = commits[:blameContextPerFile]
+		}
+		b.WriteString(path + ":\n")
+		for _, c := range commits {
+			fmt.Fprintf(&b, "  %s %s\n", c.Short(), c.Subject)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+// parseBlameSummaries returns the distinct commits in `git blame --porcelain`
+// output, in order of first appearance, skipping uncommitted lines.
+func parseBlameSummaries(out string) []LogEntry {
+	var commits []LogEntry
+	seen := make(map[string]bool)
+	var current string
+	for _, line := range strings.Split(out, "\n") {
+		if hash, _, ok := strings.Cut(line, " "); ok && len(hash) == 40 && !strings.HasPrefix(line, "\t") {
+			current = hash
+			continue
+		}
+		if subject, ok := strings.CutPrefix(line, "summary "); ok && !seen[current] {
+			seen[current] = true
+			if strings.Trim(current, "0") != "" {
+				commits = append(commits, LogEntry{Hash: current, Subject: subject})
+			}
+		}
+	}
+	return commits
+}
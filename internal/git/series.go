@@ -0,0 +1,212 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// LogEntry is one commit of a patch series.
+type LogEntry struct {
+	Hash    string
+	Subject string
+	Body    string
+}
+
+// Short returns the abbreviated commit hash.
+func (e LogEntry) Short() string {
+	if len(e.Hash) > 12 {
+		return e.Hash[:12]
+	}
+	return e.Hash
+}
+
+// logFormat separates fields with NUL and records with RS so bodies may
+// contain anything.
+const logFormat = "--format=%H%x00%s%x00%b%x1e"
+
+// seriesRange turns a bare revision into rev..HEAD, the commits
+// format-patch would send, so log and format-patch agree on the series.
+func seriesRange(revRange string) string {
+	if strings.Contains(revRange, "..") {
+		return revRange
+	}
+	return revRange + "..HEAD"
+}
+
+// diffRange turns A..B, or a bare revision, into A...B so the diff shows
+// only what the series changes, not what the base gained since it forked.
+func diffRange(revRange string) string {
+	if strings.Contains(revRange, "...") {
+		return revRange
+	}
+	if from, to, ok := strings.Cut(revRange, ".."); ok {
+		return from + "..." + to
+	}
+	return revRange + "...HEAD"
+}
+
+// Series lists the non-merge commits in revRange, oldest first. A bare
+// revision means rev..HEAD.
+func (r *Repository) Series(ctx context.Context, revRange string) ([]LogEntry, error) {
+	out, err := r.output(ctx, "log", "--reverse", "--no-merges", logFormat, seriesRange(revRange), "--")
+	if err != nil {
+		return nil, fmt.Errorf("list commits in %s: %w", revRange, err)
+	}
+	return parseLog(out)
+}
+
+// Activity lists non-merge commits on any local branch by author since the
+// given date (anything git's --since accepts, e.g. "yesterday"), oldest first.
+func (r *Repository) Activity(ctx context.Context, since, author string) ([]LogEntry, error) {
+	out, err := r.output(ctx, "log", "--branches", "--reverse", "--no-merges", "--since="+since, "--author="+author, logFormat)
+	if err != nil {
+		return nil, fmt.Errorf("list commits since %s: %w", since, err)
+	}
+	return parseLog(out)
+}
+
+// History lists the latest count non-merge commits reachable from HEAD,
+// newest first.
+func (r *Repository) History(ctx context.Context, count int) ([]LogEntry, error) {
+	out, err := r.output(ctx, "log", "--no-merges", fmt.Sprintf("--max-count=%d", count), logFormat)
+	if err != nil {
+		return nil, fmt.Errorf("list recent commits: %w", err)
+	}
+	return parseLog(out)
+}
+
+// Head returns the commit HEAD points at.
+func (r *Repository) Head(ctx context.Context) (string, error) {
+	out, err := r.output(ctx, "rev-parse", "--verify", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("resolve HEAD: %w", err)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// DiffSizes returns the size in bytes of the patches of the latest count
+// non-merge commits reachable from HEAD, newest first; none before the
+// first commit.
+func (r *Repository) DiffSizes(ctx context.Context, count int) ([]int, error) {
+	if _, err := r.output(ctx, "rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		return nil, nil
+	}
+	out, err := r.output(ctx, "log", "--no-merges", "--no-color", "--patch", fmt.Sprintf("--max-count=%d", count), "--format=%x1e")
+	if err != nil {
+		return nil, fmt.Errorf("read recent diffs: %w", err)
+	}
+	// The output starts with the first commit's separator.
+	records := strings.Split(out, "\x1e")[1:]
+	sizes := make([]int, len(records))
+	for i, record := range records {
+		sizes[i] = len(strings.TrimSpace(record))
+	}
+	return sizes, nil
+}
+
+func parseLog(out string) ([]LogEntry, error) {
+	var entries []LogEntry
+	for _, record := range strings.Split(out, "\x1e") {
+		record = strings.TrimLeft(record, "\n")
+		if record == "" {
+			continue
+		}
+		fields := strings.SplitN(record, "\x00", 3)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("malformed log record %q", record)
+		}
+		entries = append(entries, LogEntry{
+			Hash:    fields[0],
+			Subject: fields[1],
+			Body:    strings.TrimSpace(fields[2]),
+		})
+	}
+	return entries, nil
+}
+
+// CommitDiff returns the patch introduced by a single commit.
+func (r *Repository) CommitDiff(ctx context.Context, rev string) (string, error) {
+	out, err := r.output(ctx, "show", "--no-color", "--format=", rev)
+	if err != nil {
+		return "", fmt.Errorf("show %s: %w", rev, err)
+	}
+	return out, nil
+}
+
+// RangeDiff returns the combined diff of revRange against the merge base,
+// with a diffstat when stat is true.
+func (r *Repository) RangeDiff(ctx context.Context, revRange string, stat bool) (string, error) {
+	args := []string{"diff", "--no-color"}
+	if stat {
+		args = append(args, "--stat")
+	}
+	out, err := r.output(ctx, append(args, diffRange(revRange), "--")...)
+	if err != nil {
+		return "", fmt.Errorf("diff %s: %w", revRange, err)
+	}
+	return out, nil
+}
+
+// FormatPatch runs `git format-patch` for revRange and returns the written files.
+func (r *Repository) FormatPatch(ctx context.Context, revRange, outDir string, coverLetter bool) ([]string, error) {
+	args := []string{"format-patch"}
+	if coverLetter {
+		args = append(args, "--cover-letter")
+	}
+	if outDir != "" {
+		args = append(args, "--output-directory", outDir)
+	}
+	out, err := r.output(ctx, append(args, seriesRange(revRange))...)
+	if err != nil {
+		return nil, fmt.Errorf("format patches: %w", err)
+	}
+	var files []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// RemoteURL returns the fetch URL of the named remote.
+func (r *Repository) RemoteURL(ctx context.Context, remote string) (string, error) {
+	out, err := r.output(ctx, "remote", "get-url", remote)
+	if err != nil {
+		return "", fmt.Errorf("read URL of remote %q: %w", remote, err)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// DefaultBranch returns the branch the remote's HEAD points at, or "main"
+// when it is unknown (e.g. the clone never fetched refs/remotes/<remote>/HEAD).
+func (r *Repository) DefaultBranch(ctx context.Context, remote string) string {
+	out, err := r.output(ctx, "symbolic-ref", "--short", "refs/remotes/"+remote+"/HEAD")
+	if err != nil {
+		return "main"
+	}
+	return strings.TrimPrefix(strings.TrimSpace(out), remote+"/")
+}
+
+// BranchRemote returns the remote the current branch tracks, or "origin".
+func (r *Repository) BranchRemote(ctx context.Context) string {
+	branch, err := r.CurrentBranch(ctx)
+	if err != nil || branch == "" {
+		return "origin"
+	}
+	out, err := r.output(ctx, "config", "--get", "branch."+branch+".remote")
+	if err != nil || strings.TrimSpace(out) == "" || strings.TrimSpace(out) == "." {
+		return "origin"
+	}
+	return strings.TrimSpace(out)
+}
+
+// MergeBase returns the best common ancestor of a and b.
+func (r *Repository) MergeBase(ctx context.Context, a, b string) (string, error) {
+	out, err := r.output(ctx, "merge-base", a, b)
+	if err != nil {
+		return "", fmt.Errorf("find merge base of %s and %s: %w", a, b, err)
+	}
+	return strings.TrimSpace(out), nil
+}
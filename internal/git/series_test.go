@@ -0,0 +1,143 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSeriesRanges(t *testing.T) {
+	tests := []struct {
+		in, series, diff string
+	}{
+		{"main", "main..HEAD", "main...HEAD"},
+		{"main..topic", "main..topic", "main...topic"},
+		{"main..", "main..", "main..."},
+		{"main...topic", "main...topic", "main...topic"},
+	}
+	for _, tt := range tests {
+		if got := seriesRange(tt.in); got != tt.series {
+			t.Errorf("seriesRange(%q) = %q, want %q", tt.in, got, tt.series)
+		}
+		if got := diffRange(tt.in); got != tt.diff {
+			t.Errorf("diffRange(%q) = %q, want %q", tt.in, got, tt.diff)
+		}
+	}
+}
+
+func TestRepositoryFormatPatch(t *testing.T) {
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=goco", "-c", "user.email=goco@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v, out: %s", args, err, out)
+		}
+	}
+
+	git("init", "-b", "main")
+	git("commit", "--allow-empty", "-m", "base")
+	git("tag", "base")
+	git("commit", "--allow-empty", "-m", "feat: first")
+	git("commit", "--allow-empty", "-m", "fix: second")
+
+	repo := NewRepository(dir)
+	ctx := context.Background()
+	entries, err := repo.Series(ctx, "base")
+	if err != nil {
+		t.Fatalf("Series failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected a bare revision to mean base..HEAD, got %+v", entries)
+	}
+
+	outDir := filepath.Join(t.TempDir(), "patch series")
+	files, err := repo.FormatPatch(ctx, "base", outDir, false)
+	if err != nil {
+		t.Fatalf("FormatPatch failed: %v", err)
+	}
+	if len(files) != 2 || !strings.HasPrefix(files[0], outDir) {
+		t.Fatalf("expected two patches in %q, got %q", outDir, files)
+	}
+}
+
+// seriesRepo creates a repository with the commits base, "feat: first"
+// (with a body), and "fix: second", all by goco@example.com.
+func seriesRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=goco", "-c", "user.email=goco@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v, out: %s", args, err, out)
+		}
+	}
+
+	git("init", "-b", "main")
+	git("commit", "--allow-empty", "-m", "base")
+	git("commit", "--allow-empty", "-m", "feat: first", "-m", "Body text.")
+	git("commit", "--allow-empty", "-m", "fix: second")
+	return dir
+}
+
+func TestRepositoryActivity(t *testing.T) {
+	repo := NewRepository(seriesRepo(t))
+
+	activity, err := repo.Activity(context.Background(), "1 hour ago", "goco@example.com")
+	if err != nil {
+		t.Fatalf("Activity failed: %v", err)
+	}
+	if len(activity) != 3 || activity[0].Subject != "base" {
+		t.Fatalf("unexpected activity: %+v", activity)
+	}
+	if none, _ := repo.Activity(context.Background(), "1 hour ago", "someone-else"); len(none) != 0 {
+		t.Fatalf("expected no activity for another author, got %+v", none)
+	}
+}
+
+func TestRepositoryHistory(t *testing.T) {
+	history, err := NewRepository(seriesRepo(t)).History(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+	if len(history) != 2 || history[0].Subject != "fix: second" || history[1].Body != "Body text." {
+		t.Fatalf("unexpected history: %+v", history)
+	}
+}
+
+func TestRepositoryDiffSizes(t *testing.T) {
+	ctx := context.Background()
+	dir := seriesRepo(t)
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("hello\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{{"add", "a.txt"}, {"commit", "-qm", "feat: add a.txt"}} {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=goco", "-c", "user.email=goco@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v, out: %s", args, err, out)
+		}
+	}
+
+	sizes, err := NewRepository(dir).DiffSizes(ctx, 3)
+	if err != nil {
+		t.Fatalf("DiffSizes failed: %v", err)
+	}
+	if len(sizes) != 3 || sizes[0] == 0 || sizes[1] != 0 || sizes[2] != 0 {
+		t.Fatalf("expected the newest commit's patch and two empty ones, got %v", sizes)
+	}
+
+	empty := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", empty).CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v, out: %s", err, out)
+	}
+	if sizes, err := NewRepository(empty).DiffSizes(ctx, 3); err != nil || len(sizes) != 0 {
+		t.Fatalf("expected no sizes before the first commit, got %v, %v", sizes, err)
+	}
+}
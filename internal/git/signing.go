@@ -0,0 +1,107 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// sshAddList lists the public keys ssh-agent holds, one per line, as
+// ssh-add -L prints them. Tests replace it.
+var sshAddList = func(ctx context.Context) (string, error) {
+	cmd := exec.CommandContext(ctx, "ssh-add", "-L")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	err := cmd.Run()
+	// ssh-add exits 1 when the agent holds no keys.
+	var exit *exec.ExitError
+	if errors.As(err, &exit) && exit.ExitCode() == 1 {
+		return "", nil
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	return stdout.String(), nil
+}
+
+// CheckSSHSigning reports, before git tries, why signing a commit with the
+// SSH key in s would fail: no key configured, or a public key that
+// ssh-agent must hold but does not. A private key file needs no agent, and
+// a gpg.ssh.defaultKeyCommand picks its own key, so both pass.
+func CheckSSHSigning(ctx context.Context, s CommitSettings) error {
+	if !s.Sign || s.SignFormat != "ssh" {
+		return nil
+	}
+	if s.SigningKey == "" {
+		if s.DefaultKeyCommand != "" {
+			return nil
+		}
+		return errors.New("commits are signed with SSH (gpg.format=ssh), but no key is set; run git config user.signingkey ~/.ssh/id_ed25519.pub or set signing_key under [Git]")
+	}
+
+	public, err := sshPublicKey(s.SigningKey)
+	if err != nil || public == "" {
+		return err
+	}
+
+	if os.Getenv("SSH_AUTH_SOCK") == "" {
+		return fmt.Errorf("git signs with the public key %s through ssh-agent, but no agent is running (SSH_AUTH_SOCK is unset); start one with eval \"$(ssh-agent)\" and run ssh-add", s.SigningKey)
+	}
+	listed, err := sshAddList(ctx)
+	if err != nil {
+		return fmt.Errorf("ask ssh-agent for the signing key %s: %w", s.SigningKey, err)
+	}
+	for line := range strings.Lines(listed) {
+		if sameSSHKey(line, public) {
+			return nil
+		}
+	}
+	return fmt.Errorf("ssh-agent does not hold the signing key %s; add its private key with ssh-add, e.g. ssh-add %s", s.SigningKey, strings.TrimSuffix(s.SigningKey, ".pub"))
+}
+
+// sshPublicKey returns the public key user.signingkey names, or "" when it
+// names a private key file, which signs without the agent. The key is
+// either inline after "key::", or a file path.
+func sshPublicKey(signingKey string) (string, error) {
+	if key, ok := strings.CutPrefix(signingKey, "key::"); ok {
+		return key, nil
+	}
+	// Older git accepts a bare public key too.
+	if isSSHPublicKey(signingKey) {
+		return signingKey, nil
+	}
+	path := signingKey
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read the SSH signing key (user.signingkey): %w", err)
+	}
+	key := strings.TrimSpace(string(data))
+	if !isSSHPublicKey(key) {
+		return "", nil
+	}
+	return key, nil
+}
+
+func isSSHPublicKey(key string) bool {
+	return strings.HasPrefix(key, "ssh-") || strings.HasPrefix(key, "ecdsa-") || strings.HasPrefix(key, "sk-")
+}
+
+// sameSSHKey compares the type and key data of two public keys, ignoring
+// their comments.
+func sameSSHKey(a, b string) bool {
+	fa, fb := strings.Fields(a), strings.Fields(b)
+	return len(fa) >= 2 && len(fb) >= 2 && fa[0] == fb[0] && fa[1] == fb[1]
+}
@@ -0,0 +1,72 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+)
+
+// State describes an in-progress git operation that affects how a commit should be made.
+type State int
+
+const (
+	StateNone State = iota
+	StateMerging
+	StateRebasing
+	StateCherryPicking
+	StateReverting
+	StateBisecting
+)
+
+func (s State) String() string {
+	switch s {
+	case StateMerging:
+		return "merging"
+	case StateRebasing:
+		return "rebasing"
+	case StateCherryPicking:
+		return "cherry-picking"
+	case StateReverting:
+		return "reverting"
+	case StateBisecting:
+		return "bisecting"
+	default:
+		return "clean"
+	}
+}
+
+// Sequenced reports whether the state is a merge-like operation whose
+// commit must include the whole index rather than a subset of paths.
+func (s State) Sequenced() bool {
+	return s == StateMerging || s == StateCherryPicking || s == StateReverting
+}
+
+// stateMarkers are checked in order; rebase markers come first because an
+// interactive rebase can also leave CHERRY_PICK_HEAD behind.
+var stateMarkers = []struct {
+	path  string
+	state State
+}{
+	{"rebase-merge", StateRebasing},
+	{"rebase-apply", StateRebasing},
+	{"MERGE_HEAD", StateMerging},
+	{"CHERRY_PICK_HEAD", StateCherryPicking},
+	{"REVERT_HEAD", StateReverting},
+	{"BISECT_LOG", StateBisecting},
+}
+
+// State inspects the git directory for markers of an in-progress operation.
+func (r *Repository) State(ctx context.Context) (State, error) {
+	gitDir, err := r.GitDir(ctx)
+	if err != nil {
+		return StateNone, err
+	}
+
+	for _, marker := range stateMarkers {
+		if _, err := os.Stat(filepath.Join(gitDir, marker.path)); err == nil {
+			return marker.state, nil
+		}
+	}
+
+	return StateNone, nil
+}
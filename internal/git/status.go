@@ -0,0 +1,184 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// StatusEntry is a single changed path reported by `git status --porcelain=v2`.
+type StatusEntry struct {
+	// Index and Worktree hold the XY state codes ('.' means unchanged).
+	Index    byte
+	Worktree byte
+	Path     string
+	// OrigPath is set for renames and copies.
+	OrigPath string
+	// Conflicted marks unmerged entries.
+	Conflicted bool
+	Untracked  bool
+}
+
+// Code returns the two-letter XY state code, using "??" for untracked paths.
+func (e StatusEntry) Code() string {
+	if e.Untracked {
+		return "??"
+	}
+	return string([]byte{e.Index, e.Worktree})
+}
+
+// Staged reports whether the entry has changes in the index.
+func (e StatusEntry) Staged() bool {
+	return !e.Untracked && e.Index != '.'
+}
+
+// Status is the parsed form of `git status --porcelain=v2 --branch -z`.
+type Status struct {
+	Head     string
+	Branch   string
+	Upstream string
+	Ahead    int
+	Behind   int
+	Detached bool
+	Initial  bool
+	Entries  []StatusEntry
+}
+
+// HasChanges reports whether any path differs from HEAD or is untracked.
+func (s *Status) HasChanges() bool {
+	return len(s.Entries) > 0
+}
+
+// Paths lists the paths a commit would include: staged entries only when
+// staged is true, otherwise every tracked change. Untracked paths are never
+// included because goco only stages tracked files.
+func (s *Status) Paths(staged bool) []string {
+	var paths []string
+	for _, e := range s.Entries {
+		if e.Untracked {
+			continue
+		}
+		if staged && !e.Staged() {
+			continue
+		}
+		paths = append(paths, e.Path)
+	}
+	return paths
+}
+
+// String renders a compact, locale-independent summary suitable for prompts.
+func (s *Status) String() string {
+	var b strings.Builder
+
+	switch {
+	case s.Detached:
+		b.WriteString("branch: (detached HEAD)")
+	case s.Branch != "":
+		b.WriteString("branch: " + s.Branch)
+	}
+	if s.Initial {
+		b.WriteString(" (no commits yet)")
+	}
+	if s.Upstream != "" {
+		fmt.Fprintf(&b, " [%s ahead %d, behind %d]", s.Upstream, s.Ahead, s.Behind)
+	}
+
+	for _, e := range s.Entries {
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(e.Code() + " " + e.Path)
+		if e.OrigPath != "" {
+			b.WriteString(" <- " + e.OrigPath)
+		}
+	}
+
+	return b.String()
+}
+
+// ParseStatus parses NUL-delimited `git status --porcelain=v2 --branch -z` output.
+func ParseStatus(out string) (*Status, error) {
+	status := &Status{}
+	fields := strings.Split(out, "\x00")
+
+	for i := 0; i < len(fields); i++ {
+		line := fields[i]
+		if line == "" {
+			continue
+		}
+
+		switch line[0] {
+		case '#':
+			parseStatusHeader(status, line)
+		case '1':
+			parts := strings.SplitN(line, " ", 9)
+			if len(parts) != 9 || len(parts[1]) != 2 {
+				return nil, fmt.Errorf("malformed status entry %q", line)
+			}
+			status.Entries = append(status.Entries, StatusEntry{
+				Index:    parts[1][0],
+				Worktree: parts[1][1],
+				Path:     parts[8],
+			})
+		case '2':
+			parts := strings.SplitN(line, " ", 10)
+			if len(parts) != 10 || len(parts[1]) != 2 || i+1 >= len(fields) {
+				return nil, fmt.Errorf("malformed rename entry %q", line)
+			}
+			i++
+			status.Entries = append(status.Entries, StatusEntry{
+				Index:    parts[1][0],
+				Worktree: parts[1][1],
+				Path:     parts[9],
+				OrigPath: fields[i],
+			})
+		case 'u':
+			parts := strings.SplitN(line, " ", 11)
+			if len(parts) != 11 || len(parts[1]) != 2 {
+				return nil, fmt.Errorf("malformed unmerged entry %q", line)
+			}
+			status.Entries = append(status.Entries, StatusEntry{
+				Index:      parts[1][0],
+				Worktree:   parts[1][1],
+				Path:       parts[10],
+				Conflicted: true,
+			})
+		case '?':
+			status.Entries = append(status.Entries, StatusEntry{
+				Index:     '?',
+				Worktree:  '?',
+				Path:      strings.TrimPrefix(line, "? "),
+				Untracked: true,
+			})
+		case '!':
+			// Ignored paths are only reported with --ignored; skip them.
+		default:
+			return nil, fmt.Errorf("unknown status entry %q", line)
+		}
+	}
+
+	return status, nil
+}
+
+func parseStatusHeader(status *Status, line string) {
+	key, value, _ := strings.Cut(strings.TrimPrefix(line, "# "), " ")
+
+	switch key {
+	case "branch.oid":
+		status.Initial = value == "(initial)"
+		if !status.Initial {
+			status.Head = value
+		}
+	case "branch.head":
+		status.Detached = value == "(detached)"
+		if !status.Detached {
+			status.Branch = value
+		}
+	case "branch.upstream":
+		status.Upstream = value
+	case "branch.ab":
+		ahead, behind, _ := strings.Cut(value, " ")
+		status.Ahead, _ = strconv.Atoi(strings.TrimPrefix(ahead, "+"))
+		status.Behind, _ = strconv.Atoi(strings.TrimPrefix(behind, "-"))
+	}
+}
@@ -0,0 +1,75 @@
+package git
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseStatus(t *testing.T) {
+	out := strings.Join([]string{
+		"# branch.oid 1234567890abcdef1234567890abcdef12345678",
+		"# branch.head main",
+		"# branch.upstream origin/main",
+		"# branch.ab +2 -1",
+		"1 M. N... 100644 100644 100644 aaaaaaa bbbbbbb internal/git/status.go",
+		"1 .M N... 100644 100644 100644 aaaaaaa aaaaaaa path with spaces.txt",
+		"2 R. N... 100644 100644 100644 aaaaaaa aaaaaaa R100 new.go",
+		"old.go",
+		"u UU N... 100644 100644 100644 100644 aaaaaaa bbbbbbb ccccccc conflict.go",
+		"? untracked.txt",
+		"",
+	}, "\x00")
+
+	status, err := ParseStatus(out)
+	if err != nil {
+		t.Fatalf("ParseStatus failed: %v", err)
+	}
+
+	if status.Branch != "main" || status.Upstream != "origin/main" {
+		t.Fatalf("unexpected branch info: %+v", status)
+	}
+	if status.Ahead != 2 || status.Behind != 1 {
+		t.Fatalf("unexpected ahead/behind: %d/%d", status.Ahead, status.Behind)
+	}
+	if len(status.Entries) != 5 {
+		t.Fatalf("expected 5 entries, got %d: %+v", len(status.Entries), status.Entries)
+	}
+
+	rename := status.Entries[2]
+	if rename.Path != "new.go" || rename.OrigPath != "old.go" || rename.Code() != "R." {
+		t.Fatalf("unexpected rename entry: %+v", rename)
+	}
+	if !status.Entries[3].Conflicted {
+		t.Fatalf("expected conflicted entry: %+v", status.Entries[3])
+	}
+	if status.Entries[1].Path != "path with spaces.txt" {
+		t.Fatalf("unexpected path: %q", status.Entries[1].Path)
+	}
+
+	if got := status.Paths(true); strings.Join(got, ",") != "internal/git/status.go,new.go,conflict.go" {
+		t.Fatalf("unexpected staged paths: %v", got)
+	}
+	if got := status.Paths(false); len(got) != 4 {
+		t.Fatalf("unexpected tracked paths: %v", got)
+	}
+
+	expected := "branch: main [origin/main ahead 2, behind 1]\n" +
+		"M. internal/git/status.go\n" +
+		".M path with spaces.txt\n" +
+		"R. new.go <- old.go\n" +
+		"UU conflict.go\n" +
+		"?? untracked.txt"
+	if got := status.String(); got != expected {
+		t.Fatalf("unexpected summary:\n%s\nexpected:\n%s", got, expected)
+	}
+}
+
+func TestParseStatusDetachedInitial(t *testing.T) {
+	status, err := ParseStatus("# branch.oid (initial)\x00# branch.head (detached)\x00")
+	if err != nil {
+		t.Fatalf("ParseStatus failed: %v", err)
+	}
+	if !status.Initial || !status.Detached || status.HasChanges() {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+}
@@ -0,0 +1,102 @@
+package git
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// trailerPattern matches a "Token: value" git trailer line. Conventional
+// Commits also allows the key "BREAKING CHANGE" with its space.
+var trailerPattern = regexp.MustCompile(`^([A-Za-z0-9-]+|BREAKING CHANGE): \S`)
+
+// HasTrailer reports whether the trailer block of msg contains key.
+func HasTrailer(msg, key string) bool {
+	paragraphs := strings.Split(strings.TrimRight(msg, "\n"), "\n\n")
+	last := paragraphs[len(paragraphs)-1]
+	if len(paragraphs) == 1 || !IsTrailerBlock(strings.Split(last, "\n")) {
+		return false
+	}
+	for _, line := range strings.Split(last, "\n") {
+		if strings.HasPrefix(strings.ToLower(line), strings.ToLower(key)+": ") {
+			return true
+		}
+	}
+	return false
+}
+
+// WithTrailer appends "key: value" to msg. The trailer joins an existing
+// trailer block or starts a new paragraph.
+func WithTrailer(msg, key, value string) string {
+	msg = strings.TrimRight(msg, "\n")
+	trailer := key + ": " + value
+
+	paragraphs := strings.Split(msg, "\n\n")
+	last := paragraphs[len(paragraphs)-1]
+	if len(paragraphs) > 1 && IsTrailerBlock(strings.Split(last, "\n")) {
+		return msg + "\n" + trailer
+	}
+	return msg + "\n\n" + trailer
+}
+
+// IsTrailerBlock reports whether every line of a paragraph is a trailer.
+func IsTrailerBlock(lines []string) bool {
+	for _, line := range lines {
+		if !trailerPattern.MatchString(line) {
+			return false
+		}
+	}
+	return true
+}
+
+// Trailers returns the lines of msg's trailer block, or nil when it has none.
+func Trailers(msg string) []string {
+	paragraphs := strings.Split(strings.TrimRight(msg, "\n"), "\n\n")
+	last := strings.Split(paragraphs[len(paragraphs)-1], "\n")
+	if len(paragraphs) == 1 || !IsTrailerBlock(last) {
+		return nil
+	}
+	return last
+}
+
+// trailerKey returns the key of a trailer line.
+func trailerKey(line string) string {
+	key, _, _ := strings.Cut(line, ": ")
+	return key
+}
+
+// AddedTrailerKeys compares the message goco committed with the one git
+// recorded and returns the keys of the trailers a commit hook added, e.g.
+// "Signed-off-by" or "Change-Id", in order.
+func AddedTrailerKeys(sent, committed string) []string {
+	had := make(map[string]bool)
+	for _, line := range Trailers(sent) {
+		had[line] = true
+	}
+	var keys []string
+	for _, line := range Trailers(committed) {
+		key := trailerKey(line)
+		if !had[line] && !slices.ContainsFunc(keys, func(k string) bool { return strings.EqualFold(k, key) }) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// CarryTrailers appends to msg the trailers of original whose key is one of
+// keys, except lines msg already has, so a regenerated message keeps what
+// commit hooks added to the one it replaces.
+func CarryTrailers(msg, original string, keys []string) string {
+	msg = strings.TrimRight(msg, "\n")
+	have := Trailers(msg)
+	for _, line := range Trailers(original) {
+		key := trailerKey(line)
+		if slices.Contains(have, line) || !slices.ContainsFunc(keys, func(k string) bool { return strings.EqualFold(k, key) }) {
+			continue
+		}
+		_, value, _ := strings.Cut(line, ": ")
+		msg = WithTrailer(msg, key, value)
+		have = append(have, line)
+	}
+	return msg
+}
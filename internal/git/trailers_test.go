@@ -0,0 +1,93 @@
+package git
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestWithTrailer(t *testing.T) {
+	msg := WithTrailer("feat: add x\n\nBody.", "Changelog", "added")
+	if msg != "feat: add x\n\nBody.\n\nChangelog: added" {
+		t.Fatalf("unexpected message:\n%s", msg)
+	}
+	if !HasTrailer(msg, "changelog") {
+		t.Fatalf("HasTrailer missed the trailer in:\n%s", msg)
+	}
+	if HasTrailer("feat: add x\n\nChangelog: in the body, not a trailer block.\nMore text", "Changelog") {
+		t.Fatal("HasTrailer matched a body line")
+	}
+
+	breaking := WithTrailer("feat!: drop v1\n\nBREAKING CHANGE: v1 is gone", "Changelog", "removed")
+	if breaking != "feat!: drop v1\n\nBREAKING CHANGE: v1 is gone\nChangelog: removed" {
+		t.Fatalf("expected the trailer to join the BREAKING CHANGE block:\n%s", breaking)
+	}
+}
+
+func TestIsTrailerBlock(t *testing.T) {
+	tests := []struct {
+		lines []string
+		want  bool
+	}{
+		{[]string{"Refs: #1", "Signed-off-by: A <a@example.com>"}, true},
+		{[]string{"BREAKING CHANGE: config moved"}, true},
+		{[]string{"BREAKING-CHANGE: config moved"}, true},
+		{[]string{"Refs: #1", "plain text"}, false},
+		{[]string{"Two words: not a key"}, false},
+	}
+	for _, tt := range tests {
+		if got := IsTrailerBlock(tt.lines); got != tt.want {
+			t.Errorf("IsTrailerBlock(%q) = %v, want %v", tt.lines, got, tt.want)
+		}
+	}
+}
+
+func TestAddedTrailerKeys(t *testing.T) {
+	tests := []struct {
+		name      string
+		sent      string
+		committed string
+		want      []string
+	}{
+		{name: "no hook", sent: "feat: add x\n\nBody.", committed: "feat: add x\n\nBody.", want: nil},
+		{
+			name:      "sign-off and change-id",
+			sent:      "feat: add x\n\nBody.",
+			committed: "feat: add x\n\nBody.\n\nSigned-off-by: A <a@example.com>\nChange-Id: I0123",
+			want:      []string{"Signed-off-by", "Change-Id"},
+		},
+		{
+			name:      "joins goco's trailers",
+			sent:      "feat: add x\n\nRefs: #1",
+			committed: "feat: add x\n\nRefs: #1\nSigned-off-by: A <a@example.com>\nsigned-off-by: B <b@example.com>",
+			want:      []string{"Signed-off-by"},
+		},
+	}
+	for _, tt := range tests {
+		if got := AddedTrailerKeys(tt.sent, tt.committed); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: AddedTrailerKeys() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCarryTrailers(t *testing.T) {
+	original := "fix stuff\n\nRefs: #1\nSigned-off-by: A <a@example.com>\nChange-Id: I0123"
+	keys := []string{"signed-off-by", "Change-Id"}
+
+	tests := []struct {
+		name string
+		msg  string
+		want string
+	}{
+		{name: "new trailer block", msg: "fix: handle nil config\n", want: "fix: handle nil config\n\nSigned-off-by: A <a@example.com>\nChange-Id: I0123"},
+		{name: "joins the model's trailers", msg: "fix: handle nil config\n\nRefs: #2", want: "fix: handle nil config\n\nRefs: #2\nSigned-off-by: A <a@example.com>\nChange-Id: I0123"},
+		{name: "already there", msg: "fix: handle nil config\n\nChange-Id: I0123", want: "fix: handle nil config\n\nChange-Id: I0123\nSigned-off-by: A <a@example.com>"},
+	}
+	for _, tt := range tests {
+		if got := CarryTrailers(tt.msg, original, keys); got != tt.want {
+			t.Errorf("%s: CarryTrailers() =\n%s\nwant\n%s", tt.name, got, tt.want)
+		}
+	}
+	if got := CarryTrailers("fix: x", original, nil); got != "fix: x" {
+		t.Errorf("CarryTrailers() without keys = %q, want the message unchanged", got)
+	}
+}
@@ -0,0 +1,81 @@
+package git
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+)
+
+// packageManifests mark the root directory of a workspace package.
+var packageManifests = []string{
+	"go.mod",
+	"package.json",
+	"Cargo.toml",
+	"pyproject.toml",
+	"setup.py",
+	"pom.xml",
+	"build.gradle",
+	"build.gradle.kts",
+	"composer.json",
+	"mix.exs",
+}
+
+// Package is a workspace package and the changed paths that belong to it.
+type Package struct {
+	// Dir is the package directory relative to the repository root; "." is the root.
+	Dir   string
+	Paths []string
+}
+
+// Name returns a short name suitable for a commit scope, or "" for the root.
+func (p Package) Name() string {
+	if p.Dir == "." {
+		return ""
+	}
+	return path.Base(p.Dir)
+}
+
+// GroupByPackage assigns each path (relative to root) to the nearest
+// ancestor directory containing a package manifest. Paths outside any
+// nested package belong to the root package.
+func GroupByPackage(root string, paths []string) []Package {
+	byDir := make(map[string][]string)
+	known := make(map[string]bool)
+
+	for _, p := range paths {
+		dir := packageDir(root, path.Dir(p), known)
+		byDir[dir] = append(byDir[dir], p)
+	}
+
+	packages := make([]Package, 0, len(byDir))
+	for dir, files := range byDir {
+		packages = append(packages, Package{Dir: dir, Paths: files})
+	}
+	sort.Slice(packages, func(i, j int) bool { return packages[i].Dir < packages[j].Dir })
+	return packages
+}
+
+func packageDir(root, dir string, known map[string]bool) string {
+	for dir != "." && dir != "/" {
+		isPackage, ok := known[dir]
+		if !ok {
+			isPackage = hasManifest(filepath.Join(root, filepath.FromSlash(dir)))
+			known[dir] = isPackage
+		}
+		if isPackage {
+			return dir
+		}
+		dir = path.Dir(dir)
+	}
+	return "."
+}
+
+func hasManifest(dir string) bool {
+	for _, name := range packageManifests {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGroupByPackage(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"services/api", "web/app"} {
+		if err := os.MkdirAll(filepath.Join(root, dir, "src"), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "services", "api", "go.mod"), []byte("module api\n"), 0o644); err != nil {
+		t.Fatalf("write go.mod: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "web", "app", "package.json"), []byte("{}"), 0o644); err != nil {
+		t.Fatalf("write package.json: %v", err)
+	}
+
+	packages := GroupByPackage(root, []string{
+		"README.md",
+		"services/api/main.go",
+		"web/app/src/index.ts",
+		"services/api/src/handler.go",
+		"web/shared.css",
+	})
+
+	if len(packages) != 3 {
+		t.Fatalf("expected 3 packages, got %+v", packages)
+	}
+	if packages[0].Dir != "." || len(packages[0].Paths) != 2 || packages[0].Name() != "" {
+		t.Fatalf("unexpected root package: %+v", packages[0])
+	}
+	if packages[1].Dir != "services/api" || len(packages[1].Paths) != 2 || packages[1].Name() != "api" {
+		t.Fatalf("unexpected api package: %+v", packages[1])
+	}
+	if packages[2].Dir != "web/app" || packages[2].Paths[0] != "web/app/src/index.ts" {
+		t.Fatalf("unexpected web package: %+v", packages[2])
+	}
+}
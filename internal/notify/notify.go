@@ -0,0 +1,63 @@
+// Package notify shows desktop notifications with the tools each platform
+// ships with, so goco needs no extra dependencies for them.
+package notify
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// Notifier shows a desktop notification.
+type Notifier interface {
+	Notify(title, message string) error
+}
+
+// Desktop notifies with osascript on macOS, notify-send elsewhere on Unix,
+// and PowerShell on Windows. Notify starts the tool and returns without
+// waiting for it, so a slow notification daemon never holds goco up.
+type Desktop struct{}
+
+func (Desktop) Notify(title, message string) error {
+	cmd := command(runtime.GOOS, title, message)
+	if cmd == nil {
+		return fmt.Errorf("desktop notifications are not supported on %s", runtime.GOOS)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("show notification: %w", err)
+	}
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
+
+// windowsScript shows a balloon tip; it reads the text from the environment
+// so nothing has to be quoted for PowerShell.
+const windowsScript = `Add-Type -AssemblyName System.Windows.Forms
+$n = New-Object System.Windows.Forms.NotifyIcon
+$n.Icon = [System.Drawing.SystemIcons]::Information
+$n.Visible = $true
+$n.ShowBalloonTip(10000, $env:GOCO_NOTIFY_TITLE, $env:GOCO_NOTIFY_MESSAGE, 'Info')
+Start-Sleep -Seconds 10
+$n.Dispose()`
+
+// command returns the command that notifies on goos, or nil if there is none.
+func command(goos, title, message string) *exec.Cmd {
+	switch goos {
+	case "darwin":
+		// Arguments, unlike an inline string, need no AppleScript escaping.
+		return exec.Command("osascript",
+			"-e", "on run argv",
+			"-e", "display notification (item 2 of argv) with title (item 1 of argv)",
+			"-e", "end run",
+			title, message)
+	case "windows":
+		cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", windowsScript)
+		cmd.Env = append(os.Environ(), "GOCO_NOTIFY_TITLE="+title, "GOCO_NOTIFY_MESSAGE="+message)
+		return cmd
+	case "linux", "freebsd", "openbsd", "netbsd", "dragonfly":
+		return exec.Command("notify-send", "--app-name=goco", title, message)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,47 @@
+package notify
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	tests := []struct {
+		goos     string
+		wantPath string
+		wantArgs []string
+		wantEnv  string
+	}{
+		{goos: "darwin", wantPath: "osascript", wantArgs: []string{"goco", `say "hi"`}},
+		{goos: "linux", wantPath: "notify-send", wantArgs: []string{"--app-name=goco", "goco", `say "hi"`}},
+		{goos: "freebsd", wantPath: "notify-send", wantArgs: []string{"--app-name=goco", "goco", `say "hi"`}},
+		{goos: "windows", wantPath: "powershell", wantEnv: `GOCO_NOTIFY_MESSAGE=say "hi"`},
+		{goos: "plan9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goos, func(t *testing.T) {
+			cmd := command(tt.goos, "goco", `say "hi"`)
+			if tt.wantPath == "" {
+				if cmd != nil {
+					t.Fatalf("expected no command, got %v", cmd.Args)
+				}
+				return
+			}
+			if cmd == nil {
+				t.Fatal("expected a command")
+			}
+			if name := cmd.Args[0]; !strings.HasSuffix(name, tt.wantPath) {
+				t.Errorf("runs %q, want %q", name, tt.wantPath)
+			}
+			// Title and message are passed as whole arguments, never spliced into a script.
+			if tt.wantArgs != nil && !slices.Equal(cmd.Args[len(cmd.Args)-len(tt.wantArgs):], tt.wantArgs) {
+				t.Errorf("args = %q, want them to end with %q", cmd.Args, tt.wantArgs)
+			}
+			if tt.wantEnv != "" && !slices.Contains(cmd.Env, tt.wantEnv) {
+				t.Errorf("env lacks %q", tt.wantEnv)
+			}
+		})
+	}
+}
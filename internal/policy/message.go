@@ -0,0 +1,119 @@
+package policy
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Message parts a rule can require a pattern in.
+const (
+	PartSubject = "subject"
+	PartFooter  = "footer"
+)
+
+// MessageRules are patterns every commit message must match, e.g. a ticket
+// reference in the subject or a sign-off footer.
+type MessageRules struct {
+	subject []*regexp.Regexp
+	footer  []*regexp.Regexp
+}
+
+// Violation is a required pattern a message does not match.
+type Violation struct {
+	Part    string
+	Pattern string
+}
+
+func (v Violation) String() string {
+	if v.Part == PartFooter {
+		return fmt.Sprintf("a footer line matching %s", v.Pattern)
+	}
+	return fmt.Sprintf("a subject matching %s", v.Pattern)
+}
+
+// CompileMessageRules compiles subject patterns, which must each match the
+// first line, and footer patterns, which must each match a line of the
+// closing paragraph. It returns nil when there are no patterns.
+func CompileMessageRules(subject, footer []string) (*MessageRules, error) {
+	if len(subject) == 0 && len(footer) == 0 {
+		return nil, nil
+	}
+	r := &MessageRules{}
+	for _, s := range subject {
+		re, err := regexp.Compile(s)
+		if err != nil {
+			return nil, fmt.Errorf("compile subject pattern %q: %w", s, err)
+		}
+		r.subject = append(r.subject, re)
+	}
+	for _, s := range footer {
+		re, err := regexp.Compile(s)
+		if err != nil {
+			return nil, fmt.Errorf("compile footer pattern %q: %w", s, err)
+		}
+		r.footer = append(r.footer, re)
+	}
+	return r, nil
+}
+
+// Check returns the patterns msg does not match. A nil rule set passes everything.
+func (r *MessageRules) Check(msg string) []Violation {
+	if r == nil {
+		return nil
+	}
+	msg = strings.TrimSpace(msg)
+	subject, _, _ := strings.Cut(msg, "\n")
+
+	// Footers live in the last paragraph; a subject-only message has none.
+	var footer []string
+	if i := strings.LastIndex(msg, "\n\n"); i >= 0 {
+		footer = strings.Split(msg[i+2:], "\n")
+	}
+
+	var violations []Violation
+	for _, re := range r.subject {
+		if !re.MatchString(subject) {
+			violations = append(violations, Violation{Part: PartSubject, Pattern: re.String()})
+		}
+	}
+	for _, re := range r.footer {
+		if !matchesLine(re, footer) {
+			violations = append(violations, Violation{Part: PartFooter, Pattern: re.String()})
+		}
+	}
+	return violations
+}
+
+// Err reports the first violation of msg as an error wrapping ErrDenied.
+func (r *MessageRules) Err(msg string) error {
+	violations := r.Check(msg)
+	if len(violations) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: commit message needs %s", ErrDenied, violations[0])
+}
+
+// Instructions describes the rules for a prompt, or "" for a nil rule set.
+func (r *MessageRules) Instructions() string {
+	if r == nil {
+		return ""
+	}
+	var lines []string
+	for _, re := range r.subject {
+		lines = append(lines, fmt.Sprintf("The subject line must match the regular expression %s.", re))
+	}
+	for _, re := range r.footer {
+		lines = append(lines, fmt.Sprintf("End the message with a footer line matching the regular expression %s.", re))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func matchesLine(re *regexp.Regexp, lines []string) bool {
+	for _, line := range lines {
+		if re.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMessageRules(t *testing.T) {
+	r, err := CompileMessageRules([]string{`\[JIRA-\d+\]`}, []string{`^Refs: [A-Z]+-\d+$`})
+	if err != nil {
+		t.Fatalf("CompileMessageRules failed: %v", err)
+	}
+
+	if v := r.Check("feat: add login [JIRA-12]\n\nBody.\n\nRefs: JIRA-12"); len(v) != 0 {
+		t.Fatalf("expected compliant message, got %v", v)
+	}
+
+	v := r.Check("feat: add login\n\nRefs: JIRA-12 and more")
+	if len(v) != 2 || v[0].Part != PartSubject || v[1].Part != PartFooter {
+		t.Fatalf("unexpected violations %v", v)
+	}
+	if got := v[1].String(); got != `a footer line matching ^Refs: [A-Z]+-\d+$` {
+		t.Fatalf("unexpected description %q", got)
+	}
+
+	// A subject-only message has no footer to match.
+	if v := r.Check("feat: Refs: JIRA-1 [JIRA-1]"); len(v) != 1 || v[0].Part != PartFooter {
+		t.Fatalf("unexpected violations %v", v)
+	}
+	if err := r.Err("feat: add login"); !errors.Is(err, ErrDenied) {
+		t.Fatalf("expected ErrDenied, got %v", err)
+	}
+}
+
+func TestNilMessageRules(t *testing.T) {
+	r, err := CompileMessageRules(nil, nil)
+	if err != nil || r != nil {
+		t.Fatalf("expected nil rules, got %v, %v", r, err)
+	}
+	if r.Check("anything") != nil || r.Err("anything") != nil || r.Instructions() != "" {
+		t.Fatal("expected nil rules to pass everything")
+	}
+	if _, err := CompileMessageRules([]string{"("}, nil); err == nil {
+		t.Fatal("expected invalid pattern error")
+	}
+}
@@ -0,0 +1,111 @@
+// Package policy enforces an organization-managed allow-list for which AI
+// providers, models, and endpoints goco may send repository data to.
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// EnvVar names the environment variable holding the policy file path.
+const EnvVar = "GOCO_POLICY_FILE"
+
+// ErrDenied is wrapped by every policy violation.
+var ErrDenied = errors.New("denied by policy")
+
+// Policy restricts where diffs may be sent. Empty lists allow everything.
+type Policy struct {
+	// AllowRemote set to false forbids sending diffs off the machine at all.
+	AllowRemote *bool `toml:"allow_remote"`
+	// AllowedProviders lists provider names, e.g. ["gemini"].
+	AllowedProviders []string `toml:"allowed_providers"`
+	// AllowedModels lists glob patterns, e.g. ["gemini-2.5-*"].
+	AllowedModels []string `toml:"allowed_models"`
+	// AllowedEndpoints lists URL prefixes requests may be sent to.
+	AllowedEndpoints []string `toml:"allowed_endpoints"`
+	// FallbackProvider and FallbackModel are used instead of a denied request
+	// when they are themselves allowed.
+	FallbackProvider string `toml:"fallback_provider"`
+	FallbackModel    string `toml:"fallback_model"`
+
+	path string
+}
+
+// Request describes an outgoing provider call.
+type Request struct {
+	Provider string
+	Model    string
+	Endpoint string
+}
+
+// Load reads the policy named by $GOCO_POLICY_FILE. It returns nil when the
+// variable is unset. A set but unreadable policy is an error so that a
+// missing file can never silently lift restrictions.
+func Load() (*Policy, error) {
+	path := os.Getenv(EnvVar)
+	if path == "" {
+		return nil, nil
+	}
+
+	p := &Policy{path: path}
+	if _, err := toml.DecodeFile(path, p); err != nil {
+		return nil, fmt.Errorf("load policy %q: %w", path, err)
+	}
+	return p, nil
+}
+
+// Path returns the file the policy was loaded from.
+func (p *Policy) Path() string {
+	if p == nil {
+		return ""
+	}
+	return p.path
+}
+
+// Check returns an error wrapping ErrDenied when req violates the policy.
+// A nil policy allows every request.
+func (p *Policy) Check(req Request) error {
+	if p == nil {
+		return nil
+	}
+
+	// Every supported provider is a hosted API, so all requests are remote.
+	if p.AllowRemote != nil && !*p.AllowRemote {
+		return fmt.Errorf("%w: sending diffs off this machine is not allowed", ErrDenied)
+	}
+	if len(p.AllowedProviders) > 0 && !slices.Contains(p.AllowedProviders, req.Provider) {
+		return fmt.Errorf("%w: provider %q is not allowed (allowed: %s)", ErrDenied, req.Provider, strings.Join(p.AllowedProviders, ", "))
+	}
+	if len(p.AllowedModels) > 0 && !matchesAny(p.AllowedModels, req.Model) {
+		return fmt.Errorf("%w: model %q is not allowed (allowed: %s)", ErrDenied, req.Model, strings.Join(p.AllowedModels, ", "))
+	}
+	if len(p.AllowedEndpoints) > 0 && !hasPrefixAny(p.AllowedEndpoints, req.Endpoint) {
+		return fmt.Errorf("%w: endpoint %q is not allowed", ErrDenied, req.Endpoint)
+	}
+
+	return nil
+}
+
+func matchesAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func hasPrefixAny(prefixes []string, value string) bool {
+	for _, prefix := range prefixes {
+		if value != "" && strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPolicyCheck(t *testing.T) {
+	p := &Policy{
+		AllowedProviders: []string{"gemini"},
+		AllowedModels:    []string{"gemini-2.5-*"},
+		AllowedEndpoints: []string{"https://generativelanguage.googleapis.com"},
+	}
+
+	allowed := Request{Provider: "gemini", Model: "gemini-2.5-flash", Endpoint: "https://generativelanguage.googleapis.com"}
+	if err := p.Check(allowed); err != nil {
+		t.Fatalf("expected request to be allowed, got %v", err)
+	}
+
+	for _, req := range []Request{
+		{Provider: "groq", Model: "gemini-2.5-flash", Endpoint: allowed.Endpoint},
+		{Provider: "gemini", Model: "gemini-1.5-pro", Endpoint: allowed.Endpoint},
+		{Provider: "gemini", Model: "gemini-2.5-flash", Endpoint: "https://proxy.example.com"},
+	} {
+		if err := p.Check(req); !errors.Is(err, ErrDenied) {
+			t.Fatalf("expected %+v to be denied, got %v", req, err)
+		}
+	}
+}
+
+func TestPolicyDenyRemote(t *testing.T) {
+	deny := false
+	p := &Policy{AllowRemote: &deny}
+	if err := p.Check(Request{Provider: "gemini"}); !errors.Is(err, ErrDenied) {
+		t.Fatalf("expected remote request to be denied, got %v", err)
+	}
+}
+
+func TestNilPolicyAllows(t *testing.T) {
+	var p *Policy
+	if err := p.Check(Request{Provider: "groq"}); err != nil {
+		t.Fatalf("expected nil policy to allow, got %v", err)
+	}
+}
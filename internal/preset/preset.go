@@ -0,0 +1,191 @@
+// Package preset installs and loads shareable bundles of commit conventions.
+// A preset is a TOML document a team publishes at a URL; installing it into a
+// repository (.goco/preset.toml) or the user config directory lets everyone
+// pick up updates by installing again.
+package preset
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// FileName is the preset file inside .goco/ or the user config directory.
+const FileName = "preset.toml"
+
+// maxSize caps a downloaded preset; real ones are a few kilobytes.
+const maxSize = 1 << 20
+
+// Preset is a bundle of conventions. It deliberately carries no provider,
+// telemetry, or audit settings, so installing one cannot redirect data.
+type Preset struct {
+	Name string `toml:"name"`
+	// Source is the URL the preset was installed from, used by update.
+	Source string `toml:"source,omitempty"`
+	// Instructions are added to every prompt.
+	Instructions string `toml:"instructions,omitempty"`
+	// Scopes lists the commit scopes the team uses.
+	Scopes []string `toml:"scopes,omitempty"`
+	// Spec replaces the bundled Conventional Commits rules.
+	Spec string `toml:"spec,omitempty"`
+	// Template replaces the whole prompt, like .goco/prompt.tmpl.
+	Template string `toml:"template,omitempty"`
+	// TypeHints maps file patterns to likely commit types.
+	TypeHints map[string]string `toml:"TypeHints,omitempty"`
+}
+
+// RepoPath returns where a repository's preset is installed.
+func RepoPath(root string) string {
+	return filepath.Join(root, ".goco", FileName)
+}
+
+// UserPath returns where the user's preset is installed, next to configPath.
+func UserPath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), FileName)
+}
+
+// Parse decodes a preset, rejecting keys it does not know so typos and
+// settings presets may not carry are reported instead of ignored.
+func Parse(data []byte) (*Preset, error) {
+	var p Preset
+	md, err := toml.NewDecoder(bytes.NewReader(data)).Decode(&p)
+	if err != nil {
+		return nil, fmt.Errorf("parse preset: %w", err)
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, len(undecoded))
+		for i, k := range undecoded {
+			keys[i] = k.String()
+		}
+		return nil, fmt.Errorf("parse preset: unsupported keys: %s", strings.Join(keys, ", "))
+	}
+	if p.Name == "" {
+		return nil, fmt.Errorf("parse preset: missing name")
+	}
+	return &p, nil
+}
+
+// Load reads the preset at path. A missing file returns nil and no error.
+func Load(path string) (*Preset, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read preset: %w", err)
+	}
+	p, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return p, nil
+}
+
+// Fetch downloads and parses the preset at rawURL, which must use HTTPS.
+func Fetch(ctx context.Context, rawURL string) (*Preset, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse preset URL: %w", err)
+	}
+	if u.Scheme != "https" {
+		return nil, fmt.Errorf("preset URL must use https, got %q", rawURL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch preset: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch preset: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("fetch preset: %w", err)
+	}
+	if len(data) > maxSize {
+		return nil, fmt.Errorf("fetch preset: larger than %d bytes", maxSize)
+	}
+
+	p, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	p.Source = rawURL
+	return p, nil
+}
+
+// Write saves p to path, creating its directory.
+func Write(path string, p *Preset) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create preset directory: %w", err)
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Installed by goco preset install; reinstall to update.\n")
+	if err := toml.NewEncoder(&buf).Encode(p); err != nil {
+		return fmt.Errorf("encode preset: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("write preset: %w", err)
+	}
+	return nil
+}
+
+// Merge layers over on top of p: set fields of over win and type hints are
+// combined. Either may be nil.
+func Merge(p, over *Preset) *Preset {
+	if p == nil {
+		return over
+	}
+	if over == nil {
+		return p
+	}
+	merged := *over
+	if merged.Instructions == "" {
+		merged.Instructions = p.Instructions
+	}
+	if len(merged.Scopes) == 0 {
+		merged.Scopes = p.Scopes
+	}
+	if merged.Spec == "" {
+		merged.Spec = p.Spec
+	}
+	if merged.Template == "" {
+		merged.Template = p.Template
+	}
+	merged.TypeHints = make(map[string]string, len(p.TypeHints)+len(over.TypeHints))
+	maps.Copy(merged.TypeHints, p.TypeHints)
+	maps.Copy(merged.TypeHints, over.TypeHints)
+	return &merged
+}
+
+// PromptInstructions renders the preset's instructions and scopes for a prompt.
+func (p *Preset) PromptInstructions() string {
+	if p == nil {
+		return ""
+	}
+	var parts []string
+	if p.Instructions != "" {
+		parts = append(parts, strings.TrimSpace(p.Instructions))
+	}
+	if len(p.Scopes) > 0 {
+		parts = append(parts, "When a scope fits, use one of: "+strings.Join(p.Scopes, ", ")+".")
+	}
+	return strings.Join(parts, "\n")
+}
@@ -0,0 +1,70 @@
+package preset
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	p, err := Parse([]byte(`
+name = "acme"
+instructions = "Reference the ticket."
+scopes = ["api", "web"]
+
+[TypeHints]
+"deploy/**" = "ci"
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if p.Name != "acme" || p.TypeHints["deploy/**"] != "ci" {
+		t.Fatalf("unexpected preset %+v", p)
+	}
+	if got, want := p.PromptInstructions(), "Reference the ticket.\nWhen a scope fits, use one of: api, web."; got != want {
+		t.Fatalf("PromptInstructions() = %q, want %q", got, want)
+	}
+
+	if _, err := Parse([]byte("name = \"acme\"\n[Telemetry]\nendpoint = \"https://example.com\"\n")); err == nil || !strings.Contains(err.Error(), "Telemetry") {
+		t.Fatalf("expected unsupported key error, got %v", err)
+	}
+	if _, err := Parse([]byte(`scopes = ["api"]`)); err == nil {
+		t.Fatal("expected error for preset without a name")
+	}
+}
+
+func TestWriteAndLoad(t *testing.T) {
+	path := RepoPath(t.TempDir())
+	want := &Preset{Name: "acme", Source: "https://example.com/acme.toml", Template: "{{.Diff}}\n", TypeHints: map[string]string{"*.md": "docs"}}
+	if err := Write(path, want); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Load() = %+v, want %+v", got, want)
+	}
+
+	if p, err := Load(filepath.Join(t.TempDir(), FileName)); p != nil || err != nil {
+		t.Fatalf("expected nil preset for missing file, got %+v, %v", p, err)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	user := &Preset{Name: "mine", Instructions: "Be brief.", Spec: "user spec", TypeHints: map[string]string{"*.md": "docs", "Makefile": "build"}}
+	repo := &Preset{Name: "team", Spec: "team spec", TypeHints: map[string]string{"Makefile": "chore"}}
+
+	got := Merge(user, repo)
+	if got.Name != "team" || got.Spec != "team spec" || got.Instructions != "Be brief." {
+		t.Fatalf("unexpected merge %+v", got)
+	}
+	if got.TypeHints["*.md"] != "docs" || got.TypeHints["Makefile"] != "chore" {
+		t.Fatalf("unexpected merged type hints %v", got.TypeHints)
+	}
+	if Merge(nil, repo) != repo || Merge(user, nil) != user {
+		t.Fatal("expected nil side to be skipped")
+	}
+}
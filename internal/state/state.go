@@ -0,0 +1,234 @@
+// Package state manages files goco keeps between runs under
+// $XDG_STATE_HOME/goco.
+package state
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrNoLastPrompt is returned when no prompt has been saved yet.
+var ErrNoLastPrompt = errors.New("no saved prompt; enable save_last_prompt under [Prompt] and run goco generate")
+
+// ErrNoDraft is returned when no draft has been saved for a repository.
+var ErrNoDraft = errors.New("no draft for this repository")
+
+// Dir returns goco's state directory, or "" if no home directory can be
+// determined.
+func Dir() string {
+	stateDir := os.Getenv("XDG_STATE_HOME")
+	if stateDir == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		stateDir = filepath.Join(homeDir, ".local", "state")
+	}
+	return filepath.Join(stateDir, "goco")
+}
+
+// Path returns the location of name inside goco's state directory, or "" if
+// no home directory can be determined.
+func Path(name string) string {
+	dir := Dir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, name)
+}
+
+// LastPrompt is the most recent prompt sent to a provider and its raw reply.
+type LastPrompt struct {
+	Time     time.Time `json:"time"`
+	Provider string    `json:"provider"`
+	Model    string    `json:"model"`
+	Prompt   string    `json:"prompt"`
+	Response string    `json:"response"`
+	Error    string    `json:"error,omitempty"`
+}
+
+func lastPromptPath() string {
+	return Path("last-prompt.json")
+}
+
+// SaveLastPrompt overwrites the saved prompt. The file is private to the user
+// because prompts contain source code.
+func SaveLastPrompt(lp LastPrompt) error {
+	data, err := json.MarshalIndent(lp, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode last prompt: %w", err)
+	}
+	if err := writePrivate(lastPromptPath(), data); err != nil {
+		return fmt.Errorf("write last prompt: %w", err)
+	}
+	return nil
+}
+
+// writePrivate atomically replaces path with data, readable only by the user.
+func writePrivate(path string, data []byte) error {
+	if path == "" {
+		return fmt.Errorf("cannot determine state directory")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create state directory: %w", err)
+	}
+
+	// Write atomically via temp file + rename.
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// LoadLastPrompt reads the saved prompt, returning ErrNoLastPrompt if none exists.
+func LoadLastPrompt() (*LastPrompt, error) {
+	data, err := os.ReadFile(lastPromptPath())
+	if os.IsNotExist(err) {
+		return nil, ErrNoLastPrompt
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read last prompt: %w", err)
+	}
+
+	var lp LastPrompt
+	if err := json.Unmarshal(data, &lp); err != nil {
+		return nil, fmt.Errorf("parse last prompt: %w", err)
+	}
+	return &lp, nil
+}
+
+// Draft is the latest message goco drafted for a repository and has not
+// committed yet, for shell prompts and status lines.
+type Draft struct {
+	Time    time.Time `json:"time"`
+	Root    string    `json:"root"`
+	Message string    `json:"message"`
+}
+
+// draftPath returns the draft file for the repository at root. Each
+// repository has its own, so watching one does not disturb another.
+func draftPath(root string) string {
+	return repoPath("drafts", root)
+}
+
+// repoPath returns the file under dir that holds state for the repository
+// at root.
+func repoPath(dir, root string) string {
+	sum := sha256.Sum256([]byte(filepath.Clean(root)))
+	return Path(filepath.Join(dir, hex.EncodeToString(sum[:8])+".json"))
+}
+
+// SaveDraft replaces the draft for d.Root.
+func SaveDraft(d Draft) error {
+	d.Root = filepath.Clean(d.Root)
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode draft: %w", err)
+	}
+	if err := writePrivate(draftPath(d.Root), data); err != nil {
+		return fmt.Errorf("write draft: %w", err)
+	}
+	return nil
+}
+
+// LoadDraft reads the draft for the repository at root, returning ErrNoDraft
+// if there is none.
+func LoadDraft(root string) (*Draft, error) {
+	data, err := os.ReadFile(draftPath(root))
+	if os.IsNotExist(err) {
+		return nil, ErrNoDraft
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read draft: %w", err)
+	}
+
+	var d Draft
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("parse draft: %w", err)
+	}
+	// Two roots could share a file name only by a hash collision.
+	if d.Root != filepath.Clean(root) {
+		return nil, ErrNoDraft
+	}
+	return &d, nil
+}
+
+// ClearDraft removes the draft for the repository at root, if any.
+func ClearDraft(root string) error {
+	if err := os.Remove(draftPath(root)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove draft: %w", err)
+	}
+	return nil
+}
+
+// HookTrailers lists the keys of the trailers a repository's commit hooks
+// add, learned by comparing goco's commits with the messages git recorded.
+type HookTrailers struct {
+	Root string   `json:"root"`
+	Keys []string `json:"keys"`
+}
+
+func hookTrailersPath(root string) string {
+	return repoPath("hook-trailers", root)
+}
+
+// LoadHookTrailers returns the trailer keys learned for the repository at
+// root, or none.
+func LoadHookTrailers(root string) ([]string, error) {
+	data, err := os.ReadFile(hookTrailersPath(root))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read hook trailers: %w", err)
+	}
+
+	var h HookTrailers
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, fmt.Errorf("parse hook trailers: %w", err)
+	}
+	if h.Root != filepath.Clean(root) {
+		return nil, nil
+	}
+	return h.Keys, nil
+}
+
+// AddHookTrailers adds keys to those learned for the repository at root.
+func AddHookTrailers(root string, keys []string) error {
+	known, err := LoadHookTrailers(root)
+	if err != nil {
+		return err
+	}
+	added := false
+	for _, key := range keys {
+		if !slices.ContainsFunc(known, func(k string) bool { return strings.EqualFold(k, key) }) {
+			known = append(known, key)
+			added = true
+		}
+	}
+	if !added {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(HookTrailers{Root: filepath.Clean(root), Keys: known}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode hook trailers: %w", err)
+	}
+	if err := writePrivate(hookTrailersPath(root), data); err != nil {
+		return fmt.Errorf("write hook trailers: %w", err)
+	}
+	return nil
+}
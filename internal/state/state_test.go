@@ -0,0 +1,98 @@
+package state
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestLastPromptRoundTrip(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	if _, err := LoadLastPrompt(); !errors.Is(err, ErrNoLastPrompt) {
+		t.Fatalf("expected ErrNoLastPrompt, got %v", err)
+	}
+
+	saved := LastPrompt{Provider: "groq", Model: "llama-3.3-70b-versatile", Prompt: "prompt", Response: "feat: add thing"}
+	if err := SaveLastPrompt(saved); err != nil {
+		t.Fatalf("SaveLastPrompt failed: %v", err)
+	}
+
+	loaded, err := LoadLastPrompt()
+	if err != nil {
+		t.Fatalf("LoadLastPrompt failed: %v", err)
+	}
+	if *loaded != saved {
+		t.Fatalf("expected %+v, got %+v", saved, *loaded)
+	}
+
+	info, err := os.Stat(lastPromptPath())
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Fatalf("expected 0600 permissions, got %o", perm)
+	}
+}
+
+func TestDraftRoundTrip(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	root, other := filepath.Join(t.TempDir(), "repo"), filepath.Join(t.TempDir(), "other")
+
+	if _, err := LoadDraft(root); !errors.Is(err, ErrNoDraft) {
+		t.Fatalf("expected ErrNoDraft, got %v", err)
+	}
+
+	saved := Draft{Time: time.Now().Truncate(time.Second), Root: root, Message: "feat(api): add retries"}
+	if err := SaveDraft(saved); err != nil {
+		t.Fatalf("SaveDraft failed: %v", err)
+	}
+	loaded, err := LoadDraft(root + string(filepath.Separator))
+	if err != nil {
+		t.Fatalf("LoadDraft failed: %v", err)
+	}
+	if !loaded.Time.Equal(saved.Time) || loaded.Root != saved.Root || loaded.Message != saved.Message {
+		t.Fatalf("expected %+v, got %+v", saved, *loaded)
+	}
+	if _, err := LoadDraft(other); !errors.Is(err, ErrNoDraft) {
+		t.Fatalf("another repository's draft: expected ErrNoDraft, got %v", err)
+	}
+
+	if err := ClearDraft(root); err != nil {
+		t.Fatalf("ClearDraft failed: %v", err)
+	}
+	if _, err := LoadDraft(root); !errors.Is(err, ErrNoDraft) {
+		t.Fatalf("after ClearDraft: expected ErrNoDraft, got %v", err)
+	}
+	if err := ClearDraft(root); err != nil {
+		t.Fatalf("ClearDraft without a draft: %v", err)
+	}
+}
+
+func TestHookTrailers(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	root, other := filepath.Join(t.TempDir(), "repo"), filepath.Join(t.TempDir(), "other")
+
+	if keys, err := LoadHookTrailers(root); err != nil || keys != nil {
+		t.Fatalf("LoadHookTrailers() before any commit = %q, %v", keys, err)
+	}
+	if err := AddHookTrailers(root, []string{"Signed-off-by"}); err != nil {
+		t.Fatalf("AddHookTrailers failed: %v", err)
+	}
+	if err := AddHookTrailers(root, []string{"signed-off-by", "Change-Id"}); err != nil {
+		t.Fatalf("AddHookTrailers failed: %v", err)
+	}
+	keys, err := LoadHookTrailers(root)
+	if err != nil {
+		t.Fatalf("LoadHookTrailers failed: %v", err)
+	}
+	if !slices.Equal(keys, []string{"Signed-off-by", "Change-Id"}) {
+		t.Fatalf("LoadHookTrailers() = %q, want Signed-off-by and Change-Id", keys)
+	}
+	if keys, err := LoadHookTrailers(other); err != nil || keys != nil {
+		t.Fatalf("another repository's trailers: %q, %v", keys, err)
+	}
+}
@@ -0,0 +1,206 @@
+package style
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/razobeckett/goco/internal/ai"
+	"github.com/razobeckett/goco/internal/git"
+)
+
+// DefaultCommits is how many commits learn reads by default.
+const DefaultCommits = 200
+
+// maxScopes caps the scopes recorded in a profile.
+const maxScopes = 10
+
+var (
+	conventionalSubject = regexp.MustCompile(`^(\w+)(?:\(([^)]+)\))?!?: (.*)$`)
+	shortcode           = regexp.MustCompile(`:[a-z0-9_+-]+:`)
+)
+
+// stopwords are frequent words that tell common commit languages apart.
+var stopwords = map[string][]string{
+	"English":    {"the", "and", "to", "for", "of", "in", "with", "on", "from", "when", "is"},
+	"Spanish":    {"el", "los", "las", "del", "para", "con", "que", "por", "una", "se"},
+	"German":     {"der", "die", "das", "und", "mit", "für", "von", "nicht", "zu", "ist"},
+	"French":     {"le", "les", "des", "du", "pour", "avec", "et", "dans", "une", "sur"},
+	"Portuguese": {"os", "do", "da", "para", "com", "em", "não", "uma", "dos", "ao"},
+}
+
+// Learn builds a profile from entries; nil when there are none.
+func Learn(entries []git.LogEntry) *Profile {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	var conventional, capitalized, emoji, subjectLen, bodies, bodyLines int
+	moods := map[string]int{}
+	scopes := map[string]int{}
+	languages := map[string]int{}
+	for _, e := range entries {
+		subject := strings.TrimSpace(e.Subject)
+		subjectLen += len([]rune(subject))
+		if hasEmoji(subject) {
+			emoji++
+		}
+
+		desc := subject
+		if m := conventionalSubject.FindStringSubmatch(subject); m != nil {
+			conventional++
+			if m[2] != "" {
+				scopes[m[2]]++
+			}
+			desc = m[3]
+		}
+		desc = strings.TrimLeftFunc(shortcode.ReplaceAllString(desc, ""), func(r rune) bool { return !unicode.IsLetter(r) })
+		if r := []rune(desc); len(r) > 0 && unicode.IsUpper(r[0]) {
+			capitalized++
+		}
+		if mood := ai.SubjectMood(desc); mood != "" {
+			moods[mood]++
+		}
+
+		if e.Body != "" {
+			bodies++
+			bodyLines += strings.Count(e.Body, "\n") + 1
+		}
+		countLanguages(languages, subject+" "+e.Body)
+	}
+
+	n := len(entries)
+	p := &Profile{
+		Commits:       n,
+		Language:      topKey(languages),
+		Tense:         dominantMood(moods),
+		Conventional:  conventional*2 > n,
+		Capitalized:   capitalized*2 > n,
+		Emoji:         emoji*2 > n,
+		SubjectLength: subjectLen / n,
+		BodyPercent:   bodies * 100 / n,
+	}
+	if bodies > 0 {
+		p.BodyLines = bodyLines / bodies
+	}
+	// A scope used once is noise, not vocabulary.
+	for scope, count := range scopes {
+		if count < 2 {
+			delete(scopes, scope)
+		}
+	}
+	p.Scopes = rank(scopes)
+	if len(p.Scopes) > maxScopes {
+		p.Scopes = p.Scopes[:maxScopes]
+	}
+	return p
+}
+
+// Instructions renders p as prompt guidance; empty for a nil profile.
+func (p *Profile) Instructions() string {
+	if p == nil {
+		return ""
+	}
+	lines := []string{"Match the style of this repository's past commits:"}
+	if p.Language != "" {
+		lines = append(lines, fmt.Sprintf("- Write in %s.", p.Language))
+	}
+	switch p.Tense {
+	case "imperative":
+		lines = append(lines, `- Write the subject in the imperative mood ("add").`)
+	case "past":
+		lines = append(lines, `- Write the subject in the past tense ("added").`)
+	case "third-person":
+		lines = append(lines, `- Write the subject in the third person ("adds").`)
+	case "gerund":
+		lines = append(lines, `- Open the subject with a gerund ("adding").`)
+	}
+	if !p.Conventional {
+		lines = append(lines, "- Past commits rarely use a Conventional Commits prefix; follow their subject format.")
+	}
+	if p.Capitalized {
+		lines = append(lines, "- Start the subject description with a capital letter.")
+	} else {
+		lines = append(lines, "- Start the subject description with a lowercase letter.")
+	}
+	if p.Emoji {
+		lines = append(lines, "- Include an emoji in the subject, as past commits do.")
+	} else {
+		lines = append(lines, "- Do not use emoji.")
+	}
+	if p.SubjectLength > 0 {
+		lines = append(lines, fmt.Sprintf("- Keep the subject around %d characters.", p.SubjectLength))
+	}
+	if p.BodyPercent < 25 {
+		lines = append(lines, "- Most commits have no body; add one only when the change needs explaining.")
+	} else if p.BodyLines > 0 {
+		lines = append(lines, fmt.Sprintf("- Include a body of about %d lines.", p.BodyLines))
+	}
+	if len(p.Scopes) > 0 {
+		lines = append(lines, "- Prefer these scopes: "+strings.Join(p.Scopes, ", ")+".")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func hasEmoji(s string) bool {
+	if shortcode.MatchString(s) {
+		return true
+	}
+	for _, r := range s {
+		if r >= 0x1F000 || (r >= 0x2600 && r <= 0x27BF) {
+			return true
+		}
+	}
+	return false
+}
+
+func countLanguages(counts map[string]int, text string) {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !unicode.IsLetter(r) })
+	for lang, list := range stopwords {
+		for _, w := range words {
+			if slices.Contains(list, w) {
+				counts[lang]++
+			}
+		}
+	}
+}
+
+// dominantMood returns the mood of at least 60% of the classified subjects,
+// "mixed" when none dominates, and "" when none were classified.
+func dominantMood(moods map[string]int) string {
+	total := 0
+	for _, n := range moods {
+		total += n
+	}
+	if total == 0 {
+		return ""
+	}
+	if top := topKey(moods); moods[top]*10 >= total*6 {
+		return top
+	}
+	return "mixed"
+}
+
+// topKey returns the most frequent key, or "" when counts is empty.
+func topKey(counts map[string]int) string {
+	if ranked := rank(counts); len(ranked) > 0 {
+		return ranked[0]
+	}
+	return ""
+}
+
+// rank orders keys by descending count, then name.
+func rank(counts map[string]int) []string {
+	keys := slices.Collect(maps.Keys(counts))
+	slices.SortFunc(keys, func(a, b string) int {
+		if c := cmp.Compare(counts[b], counts[a]); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	})
+	return keys
+}
@@ -0,0 +1,76 @@
+package style
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/razobeckett/goco/internal/git"
+)
+
+func TestLearn(t *testing.T) {
+	entries := []git.LogEntry{
+		{Subject: "feat(cli): add the env command", Body: "Print the paths goco resolves.\nSecrets are never shown."},
+		{Subject: "fix(cli): handle a missing config"},
+		{Subject: "fix(git): parse bare repositories"},
+		{Subject: "docs: document the presets"},
+		{Subject: "chore(git): bump the minimum version"},
+	}
+	p := Learn(entries)
+	if p.Commits != 5 || p.Language != "English" || p.Tense != "imperative" || !p.Conventional || p.Capitalized || p.Emoji {
+		t.Fatalf("unexpected profile: %+v", p)
+	}
+	if p.BodyPercent != 20 || p.BodyLines != 2 {
+		t.Fatalf("unexpected body stats: %+v", p)
+	}
+	if strings.Join(p.Scopes, ",") != "cli,git" {
+		t.Fatalf("expected scopes used twice, most used first, got %v", p.Scopes)
+	}
+
+	text := p.Instructions()
+	for _, want := range []string{"Write in English.", "imperative mood", "lowercase", "Do not use emoji.", "have no body", "Prefer these scopes: cli, git."} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Instructions() missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestLearnGitmojiPastTense(t *testing.T) {
+	entries := []git.LogEntry{
+		{Subject: "✨ Added the export page"},
+		{Subject: ":bug: Fixed the login redirect"},
+		{Subject: "🔥 Removed the old API"},
+	}
+	p := Learn(entries)
+	if p.Tense != "past" || p.Conventional || !p.Capitalized || !p.Emoji {
+		t.Fatalf("unexpected profile: %+v", p)
+	}
+	if Learn(nil) != nil {
+		t.Fatal("expected no profile without commits")
+	}
+}
+
+func TestSurvey(t *testing.T) {
+	entries := []git.LogEntry{
+		{Subject: "feat(api): add users endpoint"},
+		{Subject: "fix(api): handle empty body"},
+		{Subject: "fix(apis): retry on 503"},
+		{Subject: "feat(Config): load presets"},
+		{Subject: "chore(config): bump version"},
+		{Subject: "fix(confg): typo"},
+		{Subject: "docs(cli): document flags"},
+		{Subject: "Merge branch 'main'"},
+	}
+	v := Survey(entries)
+	if v.Commits != 8 || v.Conventional != 7 {
+		t.Fatalf("unexpected counts: %+v", v)
+	}
+	if v.Types[0] != (Count{"fix", 3}) || len(v.Types) != 4 {
+		t.Fatalf("unexpected types: %+v", v.Types)
+	}
+	if len(v.Duplicates) != 2 || strings.Join(v.Duplicates[0], ",") != "api,apis" || len(v.Duplicates[1]) != 3 {
+		t.Fatalf("unexpected duplicates: %v", v.Duplicates)
+	}
+	if strings.Join(v.Canonical, ",") != "api,config" {
+		t.Fatalf("unexpected canonical scopes: %v", v.Canonical)
+	}
+}
@@ -0,0 +1,78 @@
+// Package style learns a repository's commit message conventions from its
+// history. The profile is written to .goco/style.toml, where it can be
+// reviewed and committed, and is added to every prompt.
+package style
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+)
+
+// FileName is the style profile inside .goco/.
+const FileName = "style.toml"
+
+// Profile summarizes how a repository's commit messages are written.
+type Profile struct {
+	// Commits is how many commits the profile was learned from.
+	Commits int `toml:"commits"`
+	// Language is the language messages are written in, e.g. "English".
+	Language string `toml:"language,omitempty"`
+	// Tense is "imperative", "past", "third-person", "gerund", or "mixed".
+	Tense string `toml:"tense,omitempty"`
+	// Conventional is set when most subjects carry a "type(scope): " prefix.
+	Conventional bool `toml:"conventional"`
+	// Capitalized is set when most subject descriptions start upper case.
+	Capitalized bool `toml:"capitalized"`
+	// Emoji is set when most subjects contain an emoji or :shortcode:.
+	Emoji bool `toml:"emoji"`
+	// SubjectLength is the average subject length in characters.
+	SubjectLength int `toml:"subject_length"`
+	// BodyPercent is the share of commits with a body.
+	BodyPercent int `toml:"body_percent"`
+	// BodyLines is the average body length of commits that have one.
+	BodyLines int `toml:"body_lines"`
+	// Scopes are the most used scopes, most frequent first.
+	Scopes []string `toml:"scopes,omitempty"`
+}
+
+// Path returns where a repository's style profile is stored.
+func Path(root string) string {
+	return filepath.Join(root, ".goco", FileName)
+}
+
+// Load reads the profile at path; a missing file is not an error and
+// returns nil.
+func Load(path string) (*Profile, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read style profile: %w", err)
+	}
+	var p Profile
+	if _, err := toml.Decode(string(data), &p); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &p, nil
+}
+
+// Write stores p at path, creating .goco/ if needed.
+func Write(path string, p *Profile) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create style directory: %w", err)
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Learned by goco style learn; edit freely or run it again to refresh.\n")
+	if err := toml.NewEncoder(&buf).Encode(p); err != nil {
+		return fmt.Errorf("encode style profile: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("write style profile: %w", err)
+	}
+	return nil
+}
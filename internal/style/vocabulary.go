@@ -0,0 +1,145 @@
+package style
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/razobeckett/goco/internal/git"
+)
+
+// Count is how often a commit type or scope appears.
+type Count struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// Vocabulary reports the commit types and scopes used in a history.
+type Vocabulary struct {
+	// Commits is how many commits were surveyed; Conventional how many of
+	// them carry a "type(scope): " prefix.
+	Commits      int `json:"commits"`
+	Conventional int `json:"conventional"`
+	// Types and Scopes are ordered by descending count.
+	Types  []Count `json:"types"`
+	Scopes []Count `json:"scopes"`
+	// Duplicates groups scopes that are probably the same, such as "api" and
+	// "apis"; each group starts with the most used spelling.
+	Duplicates [][]string `json:"duplicates"`
+	// Canonical is the suggested scope list: the most used spelling of each
+	// group, lower-cased, for scopes used more than once.
+	Canonical []string `json:"canonical"`
+}
+
+// Survey counts the types and scopes in entries and groups near-duplicate
+// scopes.
+func Survey(entries []git.LogEntry) Vocabulary {
+	v := Vocabulary{Commits: len(entries)}
+	types := map[string]int{}
+	scopes := map[string]int{}
+	for _, e := range entries {
+		m := conventionalSubject.FindStringSubmatch(strings.TrimSpace(e.Subject))
+		if m == nil {
+			continue
+		}
+		v.Conventional++
+		types[strings.ToLower(m[1])]++
+		if m[2] != "" {
+			scopes[m[2]]++
+		}
+	}
+	v.Types = counts(types)
+	v.Scopes = counts(scopes)
+
+	for _, group := range groupScopes(v.Scopes) {
+		total := 0
+		for _, name := range group {
+			total += scopes[name]
+		}
+		if len(group) > 1 {
+			v.Duplicates = append(v.Duplicates, group)
+		}
+		if total > 1 {
+			v.Canonical = append(v.Canonical, strings.ToLower(group[0]))
+		}
+	}
+	return v
+}
+
+func counts(m map[string]int) []Count {
+	out := make([]Count, 0, len(m))
+	for _, name := range rank(m) {
+		out = append(out, Count{name, m[name]})
+	}
+	return out
+}
+
+// groupScopes partitions scopes, given most used first, into groups of
+// near-duplicates. Groups keep that order, so each starts with its most used
+// spelling and the groups are ordered by their first member.
+func groupScopes(scopes []Count) [][]string {
+	var groups [][]string
+	var keys [][]string
+	for _, s := range scopes {
+		key := scopeKey(s.Name)
+		i := slices.IndexFunc(keys, func(members []string) bool {
+			return slices.ContainsFunc(members, func(k string) bool { return similar(k, key) })
+		})
+		if i < 0 {
+			groups = append(groups, []string{s.Name})
+			keys = append(keys, []string{key})
+			continue
+		}
+		groups[i] = append(groups[i], s.Name)
+		keys[i] = append(keys[i], key)
+	}
+	return groups
+}
+
+// scopeKey normalizes case, separators, and plurals so spellings of one
+// scope compare equal.
+func scopeKey(scope string) string {
+	key := strings.Map(func(r rune) rune {
+		if strings.ContainsRune("-_./ ", r) {
+			return -1
+		}
+		return r
+	}, strings.ToLower(scope))
+	switch {
+	case strings.HasSuffix(key, "ies") && len(key) > 4:
+		return key[:len(key)-3] + "y"
+	case strings.HasSuffix(key, "ses") || strings.HasSuffix(key, "xes") || strings.HasSuffix(key, "ches") || strings.HasSuffix(key, "shes"):
+		return key[:len(key)-2]
+	case strings.HasSuffix(key, "s") && !strings.HasSuffix(key, "ss") && len(key) > 3:
+		return key[:len(key)-1]
+	}
+	return key
+}
+
+// similar reports whether two scope keys are equal or, when long enough to
+// make a typo likely, one edit apart.
+func similar(a, b string) bool {
+	if a == b {
+		return true
+	}
+	if len(a) < 5 || len(b) < 5 {
+		return false
+	}
+	return withinOneEdit(a, b)
+}
+
+func withinOneEdit(a, b string) bool {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	if len(b)-len(a) > 1 {
+		return false
+	}
+	i := 0
+	for i < len(a) && a[i] == b[i] {
+		i++
+	}
+	if len(a) == len(b) {
+		return a[i+1:] == b[i+1:]
+	}
+	return a[i:] == b[i+1:]
+}
@@ -0,0 +1,179 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// otlpSink buffers measurements and exports them once, on Close, using the
+// OTLP/HTTP JSON encoding so no collector SDK is needed.
+type otlpSink struct {
+	endpoint string
+	prefix   string
+
+	mu      sync.Mutex
+	metrics []otlpMetric
+}
+
+func newOTLPSink(endpoint, prefix string) *otlpSink {
+	return &otlpSink{endpoint: endpoint, prefix: prefix}
+}
+
+func (s *otlpSink) Count(name string, value int64, tags Tags) {
+	s.add(otlpMetric{
+		Name: s.prefix + "." + name,
+		Sum: &otlpSum{
+			DataPoints:             []otlpDataPoint{newDataPoint(tags, strconv.FormatInt(value, 10), nil)},
+			AggregationTemporality: 1, // delta
+			IsMonotonic:            true,
+		},
+	})
+}
+
+func (s *otlpSink) Timing(name string, d time.Duration, tags Tags) {
+	ms := float64(d) / float64(time.Millisecond)
+	s.add(otlpMetric{
+		Name:  s.prefix + "." + name,
+		Unit:  "ms",
+		Gauge: &otlpGauge{DataPoints: []otlpDataPoint{newDataPoint(tags, "", &ms)}},
+	})
+}
+
+func (s *otlpSink) add(m otlpMetric) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.metrics = append(s.metrics, m)
+}
+
+func (s *otlpSink) Close() error {
+	s.mu.Lock()
+	metrics := s.metrics
+	s.metrics = nil
+	s.mu.Unlock()
+
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(otlpRequest{ResourceMetrics: []otlpResourceMetrics{{
+		Resource: otlpResource{Attributes: []otlpAttribute{stringAttribute("service.name", s.prefix)}},
+		ScopeMetrics: []otlpScopeMetrics{{
+			Scope:   otlpScope{Name: "goco"},
+			Metrics: metrics,
+		}},
+	}}})
+	if err != nil {
+		return fmt.Errorf("encode OTLP metrics: %w", err)
+	}
+
+	return postOTLP(s.endpoint, body)
+}
+
+// postOTLP sends an OTLP/HTTP JSON payload with a short timeout.
+func postOTLP(endpoint string, body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build OTLP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("export to %s: %w", endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("export to %s: collector returned %d", endpoint, resp.StatusCode)
+	}
+	return nil
+}
+
+type otlpRequest struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+type otlpResourceMetrics struct {
+	Resource     otlpResource       `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeMetrics struct {
+	Scope   otlpScope    `json:"scope"`
+	Metrics []otlpMetric `json:"metrics"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpMetric struct {
+	Name  string     `json:"name"`
+	Unit  string     `json:"unit,omitempty"`
+	Sum   *otlpSum   `json:"sum,omitempty"`
+	Gauge *otlpGauge `json:"gauge,omitempty"`
+}
+
+type otlpSum struct {
+	DataPoints             []otlpDataPoint `json:"dataPoints"`
+	AggregationTemporality int             `json:"aggregationTemporality"`
+	IsMonotonic            bool            `json:"isMonotonic"`
+}
+
+type otlpGauge struct {
+	DataPoints []otlpDataPoint `json:"dataPoints"`
+}
+
+type otlpDataPoint struct {
+	Attributes   []otlpAttribute `json:"attributes,omitempty"`
+	TimeUnixNano string          `json:"timeUnixNano"`
+	AsInt        string          `json:"asInt,omitempty"`
+	AsDouble     *float64        `json:"asDouble,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+func stringAttribute(key, value string) otlpAttribute {
+	return otlpAttribute{Key: key, Value: otlpValue{StringValue: value}}
+}
+
+func newDataPoint(tags Tags, asInt string, asDouble *float64) otlpDataPoint {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]otlpAttribute, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, stringAttribute(k, tags[k]))
+	}
+
+	return otlpDataPoint{
+		Attributes:   attrs,
+		TimeUnixNano: strconv.FormatInt(time.Now().UnixNano(), 10),
+		AsInt:        asInt,
+		AsDouble:     asDouble,
+	}
+}
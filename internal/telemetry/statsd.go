@@ -0,0 +1,54 @@
+package telemetry
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+)
+
+// statsdSink writes DogStatsD-style datagrams over UDP. Send errors are
+// ignored so an unreachable agent never slows down a commit.
+type statsdSink struct {
+	conn   net.Conn
+	prefix string
+}
+
+func newStatsdSink(endpoint, prefix string) (*statsdSink, error) {
+	conn, err := net.Dial("udp", endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("dial statsd %q: %w", endpoint, err)
+	}
+	return &statsdSink{conn: conn, prefix: prefix}, nil
+}
+
+func (s *statsdSink) Count(name string, value int64, tags Tags) {
+	s.send(fmt.Sprintf("%s.%s:%d|c%s", s.prefix, name, value, formatStatsdTags(tags)))
+}
+
+func (s *statsdSink) Timing(name string, d time.Duration, tags Tags) {
+	s.send(fmt.Sprintf("%s.%s:%d|ms%s", s.prefix, name, d.Milliseconds(), formatStatsdTags(tags)))
+}
+
+func (s *statsdSink) Close() error {
+	return s.conn.Close()
+}
+
+func (s *statsdSink) send(line string) {
+	_, _ = s.conn.Write([]byte(line))
+}
+
+func formatStatsdTags(tags Tags) string {
+	if len(tags) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, 0, len(tags))
+	for k, v := range tags {
+		pairs = append(pairs, k+":"+v)
+	}
+	sort.Strings(pairs)
+
+	return "|#" + strings.Join(pairs, ",")
+}
@@ -0,0 +1,61 @@
+// Package telemetry provides opt-in metrics with pluggable sinks. Nothing is
+// recorded or sent unless a sink is configured explicitly.
+package telemetry
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	SinkNone   = ""
+	SinkStatsd = "statsd"
+	SinkOTLP   = "otlp"
+
+	DefaultStatsdEndpoint = "127.0.0.1:8125"
+	DefaultOTLPEndpoint   = "http://localhost:4318/v1/metrics"
+	DefaultPrefix         = "goco"
+)
+
+// Tags are dimensions attached to a measurement.
+type Tags map[string]string
+
+// Sink receives measurements. Implementations must be safe to call from
+// multiple goroutines and must never block the command on network failures.
+type Sink interface {
+	Count(name string, value int64, tags Tags)
+	Timing(name string, d time.Duration, tags Tags)
+	// Close flushes buffered measurements.
+	Close() error
+}
+
+// New returns the sink named by kind, or a no-op sink when kind is empty.
+func New(kind, endpoint, prefix string) (Sink, error) {
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+
+	switch kind {
+	case SinkNone:
+		return Nop{}, nil
+	case SinkStatsd:
+		if endpoint == "" {
+			endpoint = DefaultStatsdEndpoint
+		}
+		return newStatsdSink(endpoint, prefix)
+	case SinkOTLP:
+		if endpoint == "" {
+			endpoint = DefaultOTLPEndpoint
+		}
+		return newOTLPSink(endpoint, prefix), nil
+	default:
+		return nil, fmt.Errorf("unsupported telemetry sink %q (supported: statsd, otlp)", kind)
+	}
+}
+
+// Nop discards every measurement.
+type Nop struct{}
+
+func (Nop) Count(string, int64, Tags)          {}
+func (Nop) Timing(string, time.Duration, Tags) {}
+func (Nop) Close() error                       { return nil }
@@ -0,0 +1,125 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStatsdSink(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer conn.Close()
+
+	sink, err := New(SinkStatsd, conn.LocalAddr().String(), "")
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer sink.Close()
+
+	sink.Count("provider.calls", 1, Tags{"provider": "groq", "outcome": "ok"})
+
+	buf := make([]byte, 512)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("read datagram: %v", err)
+	}
+
+	expected := "goco.provider.calls:1|c|#outcome:ok,provider:groq"
+	if got := string(buf[:n]); got != expected {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestOTLPSinkFlushesOnClose(t *testing.T) {
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	sink, err := New(SinkOTLP, server.URL, "")
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	sink.Count("commits.accepted", 1, nil)
+	sink.Timing("provider.latency", 1500*time.Millisecond, Tags{"provider": "gemini"})
+	if body != nil {
+		t.Fatal("expected no export before Close")
+	}
+
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if !json.Valid(body) {
+		t.Fatalf("expected JSON payload, got %s", body)
+	}
+	if !strings.Contains(string(body), `"goco.commits.accepted"`) || !strings.Contains(string(body), `"asDouble":1500`) {
+		t.Fatalf("unexpected payload: %s", body)
+	}
+}
+
+func TestNewRejectsUnknownSink(t *testing.T) {
+	if _, err := New("carrier-pigeon", "", ""); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestTracerExportsNestedSpans(t *testing.T) {
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	tracer := NewTracer("goco")
+	ctx := WithTracer(context.Background(), tracer)
+
+	ctx, endRoot := StartSpan(ctx, "goco.generate", nil)
+	_, endChild := StartSpan(ctx, "git status", Tags{"git.args": "status"})
+	endChild(nil)
+	endRoot(errors.New("boom"))
+
+	tracer.SetEndpoint(server.URL)
+	if err := tracer.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	var req otlpTraceRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+
+	child, root := spans[0], spans[1]
+	if child.TraceID != root.TraceID || child.ParentSpanID != root.SpanID {
+		t.Fatalf("expected child of root, got %+v / %+v", child, root)
+	}
+	if root.Status == nil || root.Status.Code != 2 {
+		t.Fatalf("expected error status on root span, got %+v", root.Status)
+	}
+}
+
+func TestTracerDisabledWithoutEndpoint(t *testing.T) {
+	tracer := NewTracer("goco")
+	_, end := StartSpan(WithTracer(context.Background(), tracer), "git diff", nil)
+	end(nil)
+
+	if err := tracer.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+}
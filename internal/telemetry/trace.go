@@ -0,0 +1,161 @@
+package telemetry
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Tracer collects spans for a single command and exports them as OTLP/HTTP
+// JSON on Close. Spans are only sent when an endpoint has been configured.
+type Tracer struct {
+	service string
+
+	mu       sync.Mutex
+	endpoint string
+	spans    []otlpSpan
+}
+
+// NewTracer returns a tracer that records spans in memory until it is given
+// an endpoint with SetEndpoint.
+func NewTracer(service string) *Tracer {
+	return &Tracer{service: service}
+}
+
+// SetEndpoint enables export to an OTLP/HTTP traces endpoint.
+func (t *Tracer) SetEndpoint(endpoint string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.endpoint = endpoint
+}
+
+// Close exports recorded spans, or discards them when no endpoint is set.
+func (t *Tracer) Close() error {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	endpoint := t.endpoint
+	spans := t.spans
+	t.spans = nil
+	t.mu.Unlock()
+
+	if endpoint == "" || len(spans) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(otlpTraceRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource: otlpResource{Attributes: []otlpAttribute{stringAttribute("service.name", t.service)}},
+		ScopeSpans: []otlpScopeSpans{{
+			Scope: otlpScope{Name: "goco"},
+			Spans: spans,
+		}},
+	}}})
+	if err != nil {
+		return fmt.Errorf("encode OTLP spans: %w", err)
+	}
+
+	return postOTLP(endpoint, body)
+}
+
+func (t *Tracer) record(span otlpSpan) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.spans = append(t.spans, span)
+}
+
+type tracerKey struct{}
+
+type spanKey struct{}
+
+type spanContext struct {
+	traceID string
+	spanID  string
+}
+
+// WithTracer attaches t to ctx so StartSpan calls below it are recorded.
+func WithTracer(ctx context.Context, t *Tracer) context.Context {
+	return context.WithValue(ctx, tracerKey{}, t)
+}
+
+// StartSpan begins a span named name as a child of any span in ctx. The
+// returned function ends the span, marking it failed when err is non-nil.
+// Without a tracer in ctx it does nothing.
+func StartSpan(ctx context.Context, name string, attrs Tags) (context.Context, func(err error)) {
+	tracer, _ := ctx.Value(tracerKey{}).(*Tracer)
+	if tracer == nil {
+		return ctx, func(error) {}
+	}
+
+	parent, _ := ctx.Value(spanKey{}).(spanContext)
+	current := spanContext{traceID: parent.traceID, spanID: randomHex(8)}
+	if current.traceID == "" {
+		current.traceID = randomHex(16)
+	}
+
+	start := time.Now()
+	ctx = context.WithValue(ctx, spanKey{}, current)
+
+	return ctx, func(err error) {
+		span := otlpSpan{
+			TraceID:           current.traceID,
+			SpanID:            current.spanID,
+			ParentSpanID:      parent.spanID,
+			Name:              name,
+			Kind:              1, // internal
+			StartTimeUnixNano: strconv.FormatInt(start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(time.Now().UnixNano(), 10),
+			Attributes:        newDataPoint(attrs, "", nil).Attributes,
+		}
+		if err != nil {
+			span.Status = &otlpStatus{Code: 2, Message: err.Error()}
+		}
+		tracer.record(span)
+	}
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+type otlpTraceRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            *otlpStatus     `json:"status,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
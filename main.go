@@ -2,7 +2,11 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"os"
+	"syscall"
 
 	"charm.land/fang/v2"
 	"github.com/razobeckett/goco/internal/cli"
@@ -13,15 +17,65 @@ var (
 	commit  = ""
 )
 
+// Exit codes. Declining a commit is not a failure and exits 0.
+const (
+	exitFailure     = 1   // the command failed
+	exitUsage       = 2   // unknown command or flag, or wrong arguments
+	exitInterrupted = 130 // stopped by Ctrl-C or SIGTERM, as shells report SIGINT
+)
+
 func main() {
+	os.Exit(run())
+}
+
+// run executes goco and returns its exit code, restoring the terminal
+// however the command ends, panics included. It returns rather than exits
+// because os.Exit skips deferred calls.
+func run() int {
+	cli.SaveTerminal()
+	defer cli.RestoreTerminal()
+	cli.LoadTheme()
+
+	root := cli.NewRootCmd()
+	args, err := cli.ExpandArgs(root, os.Args[1:])
+	if err != nil {
+		if cli.JSONOutput(os.Args[1:]) {
+			cli.WriteJSONError(os.Stderr, err, exitUsage)
+		} else {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+		}
+		return exitUsage
+	}
+	jsonErrors := cli.JSONOutput(args)
+	root.SetArgs(args)
+
 	if err := fang.Execute(
 		context.Background(),
-		cli.NewRootCmd(),
+		root,
 		fang.WithVersion(version),
 		fang.WithCommit(commit),
 		fang.WithColorSchemeFunc(cli.FangColorScheme),
-		fang.WithNotifySignal(os.Interrupt),
+		fang.WithNotifySignal(os.Interrupt, syscall.SIGTERM),
+		fang.WithErrorHandler(func(w io.Writer, styles fang.Styles, err error) {
+			if jsonErrors {
+				cli.WriteJSONError(w, err, exitCode(err))
+				return
+			}
+			fang.DefaultErrorHandler(w, styles, err)
+		}),
 	); err != nil {
-		os.Exit(1)
+		return exitCode(err)
+	}
+	return 0
+}
+
+func exitCode(err error) int {
+	switch {
+	case cli.IsUsageError(err):
+		return exitUsage
+	case errors.Is(err, context.Canceled):
+		return exitInterrupted
+	default:
+		return exitFailure
 	}
 }